@@ -0,0 +1,173 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/kaskol10/github-project-agent/bot"
+	"github.com/kaskol10/github-project-agent/github"
+	"github.com/kaskol10/github-project-agent/markdown"
+	"github.com/kaskol10/github-project-agent/store"
+)
+
+// ChecklistMonitor nudges assignees of issues whose checklist has not advanced
+type ChecklistMonitor struct {
+	githubClient  github.UnifiedClient
+	store         *store.FileStore
+	thresholdDays int
+	minItems      int
+	now           func() time.Time
+	options       ChecklistMonitorOptions
+}
+
+// ChecklistMonitorOptions configures optional checklist monitor behavior
+type ChecklistMonitorOptions struct {
+	// Identity signs the nudges the monitor posts (default bot.Default)
+	Identity *bot.Identity
+
+	// SkipLabels protect issues: an issue carrying one is never nudged
+	SkipLabels []string
+}
+
+// checklistState is the per-issue checklist snapshot kept in the store
+type checklistState struct {
+	Checked        []string  `json:"checked"`
+	LastProgressAt time.Time `json:"last_progress_at"`
+	LastNudgedAt   time.Time `json:"last_nudged_at,omitempty"`
+}
+
+func NewChecklistMonitor(ghClient github.UnifiedClient, stateStore *store.FileStore, thresholdDays, minItems int) *ChecklistMonitor {
+	return NewChecklistMonitorWithOptions(ghClient, stateStore, thresholdDays, minItems, ChecklistMonitorOptions{})
+}
+
+// NewChecklistMonitorWithOptions creates a checklist monitor with optional behavior configured
+func NewChecklistMonitorWithOptions(ghClient github.UnifiedClient, stateStore *store.FileStore, thresholdDays, minItems int, options ChecklistMonitorOptions) *ChecklistMonitor {
+	return &ChecklistMonitor{
+		githubClient:  ghClient,
+		store:         stateStore,
+		thresholdDays: thresholdDays,
+		minItems:      minItems,
+		now:           time.Now,
+		options:       options,
+	}
+}
+
+// CheckStaleChecklists compares each assigned issue's checklist with the last
+// recorded snapshot and nudges when no new items were checked within the
+// threshold. Snapshots of issues it didn't check, such as closed or
+// unassigned ones, are dropped so a reopened issue starts afresh.
+func (m *ChecklistMonitor) CheckStaleChecklists(ctx context.Context) error {
+	issues, err := m.githubClient.ListIssues(ctx, "open")
+	if err != nil {
+		return fmt.Errorf("failed to list issues: %w", err)
+	}
+
+	checked := make(map[string]bool)
+	for _, issue := range issues {
+		if issue.Assignee == "" {
+			continue
+		}
+		if label, ok := ProtectedBy(issue, m.options.SkipLabels); ok {
+			slog.Info("skipping protected issue", "issue", issue.Number, "label", label)
+			continue
+		}
+
+		checklist := markdown.ParseChecklist(issue.Body)
+		if checklist.Total() < m.minItems || len(checklist.Remaining()) == 0 {
+			m.store.Delete(checklistStateKey(issue))
+			continue
+		}
+
+		checked[checklistStateKey(issue)] = true
+		if err := m.checkIssue(ctx, issue, checklist); err != nil {
+			slog.Error("failed to check checklist", "issue", issue.Number, "error", err)
+		}
+	}
+
+	for _, key := range m.store.Keys(checklistStatePrefix) {
+		if !checked[key] {
+			m.store.Delete(key)
+		}
+	}
+	return m.store.Save()
+}
+
+func (m *ChecklistMonitor) checkIssue(ctx context.Context, issue *github.Issue, checklist markdown.Checklist) error {
+	now := m.now()
+	key := checklistStateKey(issue)
+
+	var state checklistState
+	found, err := m.store.Get(key, &state)
+	if err != nil {
+		return err
+	}
+
+	checked := checkedItems(checklist)
+	if !found || hasNewlyChecked(state.Checked, checked) {
+		// First time we see this checklist, or it advanced - restart the clock
+		state.LastProgressAt = now
+	}
+	state.Checked = checked
+
+	threshold := time.Duration(m.thresholdDays) * 24 * time.Hour
+	stalled := now.Sub(state.LastProgressAt) >= threshold
+	alreadyNudged := state.LastNudgedAt.After(state.LastProgressAt) && now.Sub(state.LastNudgedAt) < threshold
+
+	if stalled && !alreadyNudged {
+		owner, repo := github.ParseRepoFromURL(issue.URL)
+		message := m.options.Identity.Format("", formatChecklistNudge(issue, checklist, int(now.Sub(state.LastProgressAt).Hours()/24)))
+		if err := addComment(ctx, m.githubClient, owner, repo, issue.Number, message); err != nil {
+			return fmt.Errorf("failed to add comment: %w", err)
+		}
+		state.LastNudgedAt = now
+	}
+
+	return m.store.Set(key, state)
+}
+
+// checklistStatePrefix starts the store key of every checklist snapshot
+const checklistStatePrefix = "checklist:"
+
+func checklistStateKey(issue *github.Issue) string {
+	if issue.URL != "" {
+		return checklistStatePrefix + issue.URL
+	}
+	return fmt.Sprintf("%s#%d", checklistStatePrefix, issue.Number)
+}
+
+func checkedItems(checklist markdown.Checklist) []string {
+	var checked []string
+	for _, item := range checklist.Items {
+		if item.Checked {
+			checked = append(checked, item.Text)
+		}
+	}
+	return checked
+}
+
+// hasNewlyChecked reports whether current contains a checked item missing from previous
+func hasNewlyChecked(previous, current []string) bool {
+	seen := make(map[string]bool, len(previous))
+	for _, item := range previous {
+		seen[item] = true
+	}
+	for _, item := range current {
+		if !seen[item] {
+			return true
+		}
+	}
+	return false
+}
+
+func formatChecklistNudge(issue *github.Issue, checklist markdown.Checklist, daysStalled int) string {
+	var remaining strings.Builder
+	for _, item := range checklist.Remaining() {
+		remaining.WriteString(fmt.Sprintf("- [ ] %s\n", item.Text))
+	}
+
+	return fmt.Sprintf("👋 @%s, the checklist on this task hasn't moved in %d days (%d/%d items done). Still outstanding:\n\n%s\nCould you tick off what's done or share what's blocking the rest? Thanks! 🙏",
+		issue.Assignee, daysStalled, checklist.Done(), checklist.Total(), remaining.String())
+}
@@ -0,0 +1,141 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaskol10/github-project-agent/config"
+	"github.com/kaskol10/github-project-agent/github"
+	"github.com/kaskol10/github-project-agent/github/githubtest"
+	"github.com/kaskol10/github-project-agent/store"
+)
+
+func TestChecklistMonitor_NudgesStalledChecklist(t *testing.T) {
+	mockGH := githubtest.NewFakeClient()
+	mockGH.Issues = []*github.Issue{
+		{
+			Number:   7,
+			Title:    "Roll out service mesh",
+			Body:     "## Tasks\n- [x] Install control plane\n- [ ] Enable mTLS\n- [ ] Migrate services",
+			Assignee: "octocat",
+			URL:      "https://github.com/testorg/testrepo/issues/7",
+		},
+	}
+
+	stateStore, err := store.NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	m := NewChecklistMonitor(mockGH, stateStore, 5, 3)
+	m.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	// First run records a snapshot without nudging
+	if err := m.CheckStaleChecklists(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(mockGH.Comments[7]) != 0 {
+		t.Fatalf("expected no nudge on first run, got %v", mockGH.Comments[7])
+	}
+
+	// Past the threshold with no progress: nudge listing remaining items
+	now = now.AddDate(0, 0, 6)
+	if err := m.CheckStaleChecklists(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(mockGH.Comments[7]) != 1 {
+		t.Fatalf("expected one nudge, got %d", len(mockGH.Comments[7]))
+	}
+	for _, want := range []string{"@octocat", "- [ ] Enable mTLS", "- [ ] Migrate services", "1/3"} {
+		if !strings.Contains(mockGH.Comments[7][0], want) {
+			t.Errorf("nudge missing %q:\n%s", want, mockGH.Comments[7][0])
+		}
+	}
+
+	// Next day: already nudged, no repeat
+	now = now.AddDate(0, 0, 1)
+	if err := m.CheckStaleChecklists(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(mockGH.Comments[7]) != 1 {
+		t.Errorf("expected no repeated nudge, got %d comments", len(mockGH.Comments[7]))
+	}
+
+	// Progress resets the clock
+	mockGH.Issues[0].Body = "## Tasks\n- [x] Install control plane\n- [x] Enable mTLS\n- [ ] Migrate services"
+	now = now.AddDate(0, 0, 1)
+	if err := m.CheckStaleChecklists(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(mockGH.Comments[7]) != 1 {
+		t.Errorf("expected no nudge after progress, got %d comments", len(mockGH.Comments[7]))
+	}
+}
+
+func TestChecklistMonitor_SkipsProtected(t *testing.T) {
+	mockGH := githubtest.NewFakeClient(&github.Issue{
+		Number:   7,
+		Body:     "- [x] Install control plane\n- [ ] Enable mTLS\n- [ ] Migrate services",
+		Assignee: "octocat",
+		Labels:   []string{"No-Agent"},
+		URL:      "https://github.com/testorg/testrepo/issues/7",
+	})
+	stateStore, err := store.NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	m := NewChecklistMonitorWithOptions(mockGH, stateStore, 5, 3, ChecklistMonitorOptions{SkipLabels: []string{config.DefaultSkipLabel}})
+	m.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if err := m.CheckStaleChecklists(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		now = now.AddDate(0, 0, 6)
+	}
+	if len(mockGH.Comments[7]) != 0 {
+		t.Errorf("expected no nudge on a protected issue, got %v", mockGH.Comments[7])
+	}
+}
+
+func TestChecklistMonitor_PrunesUncheckedIssues(t *testing.T) {
+	body := "- [x] Install control plane\n- [ ] Enable mTLS\n- [ ] Migrate services"
+	mockGH := githubtest.NewFakeClient(
+		&github.Issue{Number: 7, Body: body, Assignee: "octocat", URL: "https://github.com/testorg/testrepo/issues/7"},
+		&github.Issue{Number: 8, Body: body, Assignee: "hubot", URL: "https://github.com/testorg/testrepo/issues/8"},
+	)
+	path := filepath.Join(t.TempDir(), "state.json")
+	stateStore, err := store.NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewChecklistMonitor(mockGH, stateStore, 5, 3)
+	if err := m.CheckStaleChecklists(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if keys := stateStore.Keys(checklistStatePrefix); len(keys) != 2 {
+		t.Fatalf("expected both snapshots recorded, got %v", keys)
+	}
+
+	// #7 was closed and #8 unassigned: neither snapshot is kept
+	mockGH.Issues = mockGH.Issues[1:]
+	mockGH.Issues[0].Assignee = ""
+	if err := m.CheckStaleChecklists(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := store.NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys := reloaded.Keys(checklistStatePrefix); len(keys) != 0 {
+		t.Errorf("expected stale snapshots pruned, got %v", keys)
+	}
+}
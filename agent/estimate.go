@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"github.com/kaskol10/github-project-agent/github"
+)
+
+// Rough token accounting used for cost estimates
+const (
+	charsPerToken       = 4    // Average characters per token for English text
+	promptOverheadChars = 2000 // Instructions and guidelines sent with every fix prompt
+	minCompletionTokens = 256  // Smallest rewrite we expect back from the LLM
+)
+
+// IssueEstimate is the projected LLM usage for fixing one issue
+type IssueEstimate struct {
+	Issue            *github.Issue
+	Violations       []string
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// CostEstimate is the projected LLM usage for validating a set of issues
+type CostEstimate struct {
+	Checked int // Issues checked
+	Issues  []IssueEstimate
+}
+
+// Calls returns the number of LLM calls the validation would make
+func (e *CostEstimate) Calls() int {
+	return len(e.Issues)
+}
+
+// Tokens returns the total projected prompt and completion tokens
+func (e *CostEstimate) Tokens() int {
+	total := 0
+	for _, issue := range e.Issues {
+		total += issue.PromptTokens + issue.CompletionTokens
+	}
+	return total
+}
+
+// Cost returns the projected cost for a price per 1k tokens
+func (e *CostEstimate) Cost(pricePer1K float64) float64 {
+	return float64(e.Tokens()) / 1000 * pricePer1K
+}
+
+// Estimate projects the LLM calls ValidateAndFix would make for the issues.
+// Only the format rules are checked; the LLM is never called.
+func (v *Validator) Estimate(issues []*github.Issue) *CostEstimate {
+	estimate := &CostEstimate{Checked: len(issues)}
+
+	for _, issue := range issues {
+		scoped, _ := v.forIssue(issue)
+		violations := scoped.checkFormat(issue)
+		if !needsRewrite(violations) {
+			continue
+		}
+
+		bodyTokens := (len(issue.Title) + len(issue.Body)) / charsPerToken
+		completionTokens := bodyTokens
+		if completionTokens < minCompletionTokens {
+			completionTokens = minCompletionTokens
+		}
+
+		estimate.Issues = append(estimate.Issues, IssueEstimate{
+			Issue:            issue,
+			Violations:       violationMessages(violations),
+			PromptTokens:     bodyTokens + promptOverheadChars/charsPerToken,
+			CompletionTokens: completionTokens,
+		})
+	}
+
+	return estimate
+}
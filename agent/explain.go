@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/kaskol10/github-project-agent/github"
+)
+
+// Explanation describes how each validation rule applies to an issue
+type Explanation struct {
+	Issue    *github.Issue
+	Profiles []string // Guidelines profiles applied to the issue
+	Results  []RuleResult
+}
+
+// Failed returns the number of rules the issue fails
+func (e *Explanation) Failed() int {
+	failed := 0
+	for _, result := range e.Results {
+		if !result.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Explain evaluates every rule against the issue without modifying anything
+func (v *Validator) Explain(issue *github.Issue) *Explanation {
+	scoped, profiles := v.forIssue(issue)
+	return &Explanation{
+		Issue:    issue,
+		Profiles: profiles,
+		Results:  scoped.evaluateRules(issue),
+	}
+}
+
+// FixPreview is the body the validator would write for an issue
+type FixPreview struct {
+	Issue      *github.Issue
+	Profiles   []string
+	Violations []Violation
+	Original   string
+	Proposed   string   // Equal to Original when the body needs no rewrite
+	Remaining  []string // Violations the proposed body still has; Validate wouldn't write it
+}
+
+// Changed reports whether the proposed body differs from the original
+func (p *FixPreview) Changed() bool {
+	return p.Proposed != p.Original
+}
+
+// Preview checks an issue and generates the LLM fix like Validate, but
+// returns the proposed body instead of updating the issue or commenting
+func (v *Validator) Preview(ctx context.Context, issue *github.Issue) (*FixPreview, error) {
+	v, profiles := v.forIssue(issue)
+	violations := v.checkFormat(issue)
+	preview := &FixPreview{
+		Issue:      issue,
+		Profiles:   profiles,
+		Violations: violations,
+		Original:   issue.Body,
+		Proposed:   issue.Body,
+	}
+	if !needsRewrite(violations) {
+		return preview, nil
+	}
+
+	var fixed []string
+	for _, violation := range violations {
+		if violation.AffectsBody() {
+			fixed = append(fixed, violation.Message)
+		}
+	}
+	fixedBody, remaining, err := v.rewriteBody(ctx, issue, fixed)
+	if err != nil {
+		return preview, &LLMError{Err: err}
+	}
+	preview.Proposed = v.preserveOriginalWithModifications(issue.Body, fixedBody, fixed)
+	preview.Remaining = remaining
+	return preview, nil
+}
@@ -0,0 +1,85 @@
+package agent
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata/golden")
+
+// assertGolden compares got with testdata/golden/<name>.golden, rewriting the
+// file instead when the tests run with -update
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name+".golden")
+
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run go test ./agent -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (run go test ./agent -update if the change is intended)\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
+
+func TestPreserveOriginalWithModifications_Golden(t *testing.T) {
+	v := &Validator{}
+	firstFix := v.preserveOriginalWithModifications(
+		"Login button doesn't work on mobile",
+		"## Description\n\nThe login button doesn't respond on mobile browsers.\n\n## Acceptance Criteria\n\n- [ ] Login works on iOS Safari\n- [ ] Login works on Android Chrome",
+		[]string{"Description too short (minimum 50 characters)", "Missing required section: Description"},
+	)
+
+	tests := []struct {
+		name       string
+		original   string
+		fixed      string
+		violations []string
+		maxLength  int
+	}{
+		{
+			name:       "first_fix",
+			original:   "Login button doesn't work on mobile",
+			fixed:      "## Description\n\nThe login button doesn't respond on mobile browsers.\n\n## Acceptance Criteria\n\n- [ ] Login works on iOS Safari\n- [ ] Login works on Android Chrome",
+			violations: []string{"Description too short (minimum 50 characters)", "Missing required section: Description"},
+		},
+		{
+			name:       "refix",
+			original:   firstFix,
+			fixed:      "## Description\n\nThe login button doesn't respond on mobile browsers.\n\n## Acceptance Criteria\n\n- [ ] Login works on iOS Safari\n- [ ] Login works on Android Chrome\n\n## Priority\n\npriority:high",
+			violations: []string{"Missing priority label (should start with 'priority:')"},
+		},
+		{
+			name:       "near_size_limit",
+			original:   strings.Repeat("Steps to reproduce: open the app, tap login, nothing happens.\n", 20),
+			fixed:      "## Description\n\nLogin does nothing when tapped.",
+			violations: []string{"Missing required section: Acceptance Criteria"},
+			maxLength:  1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.maxLength > 0 {
+				defer func(prev int) { maxIssueBodyLength = prev }(maxIssueBodyLength)
+				maxIssueBodyLength = tt.maxLength
+			}
+
+			got := v.preserveOriginalWithModifications(tt.original, tt.fixed, tt.violations)
+			if len(got) > maxIssueBodyLength {
+				t.Errorf("composed body is %d bytes, over the %d limit", len(got), maxIssueBodyLength)
+			}
+			assertGolden(t, "preserve_"+tt.name, got)
+		})
+	}
+}
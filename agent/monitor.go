@@ -3,56 +3,582 @@ package agent
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/kaskol10/github-project-agent/bot"
 	"github.com/kaskol10/github-project-agent/github"
 	"github.com/kaskol10/github-project-agent/llm"
+	"github.com/kaskol10/github-project-agent/metrics"
 	"github.com/kaskol10/github-project-agent/prompts"
 )
 
 type Monitor struct {
 	githubClient      github.UnifiedClient
-	llmClient         *llm.Client
+	llmClient          llm.LLMClient
 	staleThresholdDays int
 	promptLoader      *prompts.Loader
+	options            MonitorOptions
+	login              string // The agent's own login, looked up for ActivityFromEvents
 }
 
-func NewMonitor(ghClient github.UnifiedClient, llmClient *llm.Client, staleThresholdDays int) *Monitor {
+// MonitorOptions configures optional monitor behavior
+type MonitorOptions struct {
+	// Output is "comments" (one comment per stale issue, the default) or
+	// "digest" (a single, continuously updated digest issue)
+	Output string
+
+	// DigestAssignee is assigned to a newly created digest issue
+	DigestAssignee string
+
+	// AutoCloseAfterDays closes reminded issues with no human activity for
+	// this many days. Zero disables auto-closing.
+	AutoCloseAfterDays int
+
+	// EscalateAfterReminders escalates a stale task instead of reminding the
+	// assignee again once this many reminders went unanswered. Zero disables
+	// escalation. Only applies to the comments output.
+	EscalateAfterReminders int
+
+	// EscalateTo is the fallback user to escalate to, or "author" for the
+	// user who opened the issue
+	EscalateTo string
+
+	// EscalateAction is "mention" (@-mention the fallback user, the default)
+	// or "reassign" (make the fallback user the assignee)
+	EscalateAction string
+
+	// Identity signs the comments the monitor posts and recognizes its
+	// earlier ones (default bot.Default)
+	Identity *bot.Identity
+
+	// GracePeriodDays keeps issues out of stale checks for this many days
+	// beyond the stale threshold after they are created
+	GracePeriodDays int
+
+	// BusinessDaysOnly counts the stale threshold, grace period and
+	// auto-close window in weekdays, skipping Saturdays, Sundays and Holidays
+	BusinessDaysOnly bool
+
+	// Holidays are dates not counted as business days
+	Holidays []time.Time
+
+	// ActivityFromEvents bases staleness on the last human comment,
+	// assignment or reopen in the issue's timeline instead of UpdatedAt,
+	// which label edits and the agent's own comments also bump
+	ActivityFromEvents bool
+
+	// SkipLabels protect issues: an issue carrying one is never reminded,
+	// escalated, closed or listed in the digest
+	SkipLabels []string
+
+	// StrictPrompts fails rendering the reminder prompt when it references
+	// a missing key, so the built-in prompt is used instead
+	StrictPrompts bool
+}
+
+// Monitor output strategies
+const (
+	MonitorOutputComments = "comments"
+	MonitorOutputDigest   = "digest"
+)
+
+// Escalation actions
+const (
+	EscalateMention  = "mention"
+	EscalateReassign = "reassign"
+)
+
+// EscalateToAuthor escalates to the user who opened the issue
+const EscalateToAuthor = "author"
+
+// escalationMarker starts the body of escalation comments, after the agent
+// prefix, so reminders are counted from the last escalation
+const escalationMarker = "⏫"
+
+// digestLabel marks the issue holding the stale task digest
+const digestLabel = "stale-digest"
+
+func NewMonitor(ghClient github.UnifiedClient, llmClient llm.LLMClient, staleThresholdDays int) *Monitor {
+	return NewMonitorWithOptions(ghClient, llmClient, staleThresholdDays, MonitorOptions{})
+}
+
+// NewMonitorWithOptions creates a monitor with optional behavior configured
+func NewMonitorWithOptions(ghClient github.UnifiedClient, llmClient llm.LLMClient, staleThresholdDays int, options MonitorOptions) *Monitor {
 	// Try to load prompts from prompts/ directory
 	promptPath := getPromptPath("prompts")
-	promptLoader, _ := prompts.NewLoader(promptPath) // Ignore error, will use fallback
+	promptLoader, _ := prompts.NewLoaderWithOptions(promptPath, prompts.LoaderOptions{Strict: options.StrictPrompts}) // Ignore error, will use fallback
 	
+	if options.Output == "" {
+		options.Output = MonitorOutputComments
+	}
+	if options.EscalateAction == "" {
+		options.EscalateAction = EscalateMention
+	}
+
 	return &Monitor{
 		githubClient:       ghClient,
 		llmClient:          llmClient,
 		staleThresholdDays: staleThresholdDays,
 		promptLoader:        promptLoader,
+		options:            options,
+	}
+}
+
+// ReloadPrompts re-reads the monitor's prompt templates
+func (m *Monitor) ReloadPrompts() error {
+	if m.promptLoader == nil {
+		return nil
+	}
+	return m.promptLoader.Reload()
+}
+
+// MonitorResult is the structured outcome of a stale task check
+type MonitorResult struct {
+	Checked   int      `json:"checked"`          // Open issues checked
+	Stale     []int    `json:"stale"`            // Assigned issues without recent updates
+	Reminded  []int    `json:"reminded"`         // Stale issues the assignee was reminded on
+	Escalated []int    `json:"escalated"`        // Stale issues escalated after unanswered reminders
+	Skipped   []int    `json:"skipped"`          // Stale issues with an active PR or a recent reminder
+	Closed    []int    `json:"closed"`           // Stale issues closed after the auto-close window
+	Protected []int    `json:"protected"`        // Issues skipped: protected by a skip label
+	Digest    int      `json:"digest,omitempty"` // Digest issue created or updated, in digest mode
+	Errors    []string `json:"errors"`
+}
+
+// IsStale reports whether issue has had no updates for thresholdDays. Issues
+// created within the threshold plus graceDays can't have gone stale yet and
+// never are, and an issue never updated counts from its creation.
+func IsStale(issue *github.Issue, now time.Time, thresholdDays, graceDays int) bool {
+	if !issue.CreatedAt.IsZero() && issue.CreatedAt.After(now.AddDate(0, 0, -(thresholdDays+graceDays))) {
+		return false
 	}
+	return lastActivity(issue).Before(now.AddDate(0, 0, -thresholdDays))
+}
+
+// isStale applies IsStale, counting business days when configured
+func (m *Monitor) isStale(issue *github.Issue, now time.Time) bool {
+	if !m.options.BusinessDaysOnly {
+		return IsStale(issue, now, m.staleThresholdDays, m.options.GracePeriodDays)
+	}
+	if !issue.CreatedAt.IsZero() && businessDaysBetween(issue.CreatedAt, now, m.options.Holidays) < m.staleThresholdDays+m.options.GracePeriodDays {
+		return false
+	}
+	return businessDaysBetween(lastActivity(issue), now, m.options.Holidays) >= m.staleThresholdDays
+}
+
+// cutoff returns the time days before now, counted in business days when
+// configured: activity before it is more than days old
+func (m *Monitor) cutoff(now time.Time, days int) time.Time {
+	if !m.options.BusinessDaysOnly {
+		return now.AddDate(0, 0, -days)
+	}
+	return businessDaysAgo(now, days, m.options.Holidays)
+}
+
+// lastActivity is when issue was last updated, or created if never updated
+func lastActivity(issue *github.Issue) time.Time {
+	if issue.UpdatedAt.IsZero() {
+		return issue.CreatedAt
+	}
+	return issue.UpdatedAt
+}
+
+// humanActivityEvents are the timeline events that show someone working on
+// an issue
+var humanActivityEvents = map[string]bool{
+	"commented": true,
+	"assigned":  true,
+	"reopened":  true,
+}
+
+// withEventActivity returns a copy of issue whose UpdatedAt is its last human
+// activity according to its timeline. If the timeline can't be read, issue
+// is returned unchanged.
+func (m *Monitor) withEventActivity(ctx context.Context, issue *github.Issue) *github.Issue {
+	owner, repo := github.ParseRepoFromURL(issue.URL)
+	events, err := m.githubClient.ListIssueEvents(ctx, owner, repo, issue.Number)
+	if err != nil {
+		slog.Warn("failed to read issue events, using its update time", "issue", issue.Number, "error", err)
+		return issue
+	}
+
+	if m.login == "" {
+		if login, _, err := m.githubClient.WhoAmI(ctx); err == nil {
+			m.login = login
+		}
+	}
+	activity := lastHumanActivity(issue, events, m.login)
+	if activity.Equal(lastActivity(issue)) {
+		return issue
+	}
+	updated := *issue
+	updated.UpdatedAt = activity
+	return &updated
+}
+
+// lastHumanActivity is the time of the latest comment, assignment or reopen
+// by someone other than a bot or the agent (login), or the issue's creation
+// when there is none
+func lastHumanActivity(issue *github.Issue, events []github.IssueEvent, login string) time.Time {
+	latest := issue.CreatedAt
+	for _, event := range events {
+		if !humanActivityEvents[event.Event] || strings.HasSuffix(event.Actor, "[bot]") ||
+			(login != "" && strings.EqualFold(event.Actor, login)) {
+			continue
+		}
+		if event.CreatedAt.After(latest) {
+			latest = event.CreatedAt
+		}
+	}
+	if latest.IsZero() {
+		return lastActivity(issue)
+	}
+	return latest
+}
+
+// businessDaysBetween counts the weekdays after a's date up to and including
+// b's date, skipping holidays. Dates are compared in a's time zone.
+func businessDaysBetween(a, b time.Time, holidays []time.Time) int {
+	skip := make(map[string]bool, len(holidays))
+	for _, holiday := range holidays {
+		skip[holiday.Format("2006-01-02")] = true
+	}
+
+	b = b.In(a.Location())
+	end := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, a.Location())
+	days := 0
+	for day := time.Date(a.Year(), a.Month(), a.Day()+1, 0, 0, 0, 0, a.Location()); !day.After(end); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday || skip[day.Format("2006-01-02")] {
+			continue
+		}
+		days++
+	}
+	return days
+}
+
+// businessDaysAgo returns the earliest time t for which
+// businessDaysBetween(t, now, holidays) < days, i.e. the start of the day
+// after the last date that is days business days before now
+func businessDaysAgo(now time.Time, days int, holidays []time.Time) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for businessDaysBetween(day, now, holidays) < days {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day.AddDate(0, 0, 1)
 }
 
 func (m *Monitor) CheckStaleTasks(ctx context.Context) error {
+	_, err := m.Check(ctx)
+	return err
+}
+
+// Check reminds assignees of stale tasks (or updates the digest) and returns
+// what was done
+func (m *Monitor) Check(ctx context.Context) (*MonitorResult, error) {
+	result := &MonitorResult{Stale: []int{}, Reminded: []int{}, Escalated: []int{}, Skipped: []int{}, Closed: []int{}, Protected: []int{}, Errors: []string{}}
+
 	issues, err := m.githubClient.ListIssues(ctx, "open")
 	if err != nil {
-		return fmt.Errorf("failed to list issues: %w", err)
+		return result, fmt.Errorf("failed to list issues: %w", err)
 	}
-	
-	threshold := time.Now().AddDate(0, 0, -m.staleThresholdDays)
-	
+	result.Checked = len(issues)
+	metrics.IssuesProcessed.Add(float64(len(issues)))
+
+	now := time.Now()
+	threshold := m.cutoff(now, m.staleThresholdDays)
+
+	var staleIssues []*github.Issue
 	for _, issue := range issues {
+		if label, ok := ProtectedBy(issue, m.options.SkipLabels); ok {
+			slog.Info("skipping protected issue", "issue", issue.Number, "label", label)
+			result.Protected = append(result.Protected, issue.Number)
+			continue
+		}
+
 		// Only check issues that are assigned and haven't been updated recently
 		if issue.Assignee == "" {
 			continue
 		}
-		
-		if issue.UpdatedAt.Before(threshold) {
-			if err := m.handleStaleTask(ctx, issue); err != nil {
-				fmt.Printf("Error handling stale task #%d: %v\n", issue.Number, err)
+
+		// Events can only move the last activity back, so they are only
+		// needed when UpdatedAt says the issue is fresh
+		if m.options.ActivityFromEvents && !m.isStale(issue, now) {
+			issue = m.withEventActivity(ctx, issue)
+		}
+
+		if m.isStale(issue, now) {
+			result.Stale = append(result.Stale, issue.Number)
+			if pr := m.activePullRequest(ctx, issue, threshold); pr != nil {
+				slog.Info("skipping issue: actively worked in a pull request", "issue", issue.Number, "pr", pr.Number)
+				result.Skipped = append(result.Skipped, issue.Number)
+				continue
+			}
+			if m.options.AutoCloseAfterDays > 0 && m.closeAbandoned(ctx, issue, now) {
+				result.Closed = append(result.Closed, issue.Number)
 				continue
 			}
+			staleIssues = append(staleIssues, issue)
+		}
+	}
+
+	if m.options.Output == MonitorOutputDigest {
+		result.Digest, err = m.postDigest(ctx, issues, staleIssues)
+		return result, err
+	}
+
+	for _, issue := range staleIssues {
+		if m.recentlyReminded(ctx, issue, threshold) {
+			slog.Info("skipping issue: already reminded", "issue", issue.Number, "within_days", m.staleThresholdDays)
+			result.Skipped = append(result.Skipped, issue.Number)
+			continue
+		}
+		if m.options.EscalateAfterReminders > 0 {
+			escalated, err := m.escalate(ctx, issue)
+			if err != nil {
+				slog.Error("failed to escalate stale task", "issue", issue.Number, "error", err)
+				result.Errors = append(result.Errors, fmt.Sprintf("#%d: %v", issue.Number, err))
+				continue
+			}
+			if escalated {
+				result.Escalated = append(result.Escalated, issue.Number)
+				continue
+			}
+		}
+		if err := m.handleStaleTask(ctx, issue); err != nil {
+			slog.Error("failed to handle stale task", "issue", issue.Number, "error", err)
+			result.Errors = append(result.Errors, fmt.Sprintf("#%d: %v", issue.Number, err))
+			continue
+		}
+		result.Reminded = append(result.Reminded, issue.Number)
+	}
+
+	return result, nil
+}
+
+// recentlyReminded reports whether the agent already commented on the issue
+// since the given time, so reruns don't post the same reminder or escalation
+// again. It is the monitor's only reminder dedupe check. If the comments
+// can't be read, the reminder is posted anyway.
+func (m *Monitor) recentlyReminded(ctx context.Context, issue *github.Issue, since time.Time) bool {
+	owner, repo := github.ParseRepoFromURL(issue.URL)
+	comments, err := m.githubClient.GetIssueComments(ctx, owner, repo, issue.Number)
+	if err != nil {
+		slog.Warn("failed to read comments, reminding anyway", "issue", issue.Number, "error", err)
+		return false
+	}
+	return agentCommentedSince(comments, m.commentPrefix(), since)
+}
+
+// commentPrefix starts every comment the monitor posts
+func (m *Monitor) commentPrefix() string {
+	return m.options.Identity.Prefix("")
+}
+
+// agentCommentedSince reports whether any comment posted by the agent, i.e.
+// starting with prefix, is newer than since
+func agentCommentedSince(comments []github.Comment, prefix string, since time.Time) bool {
+	for _, comment := range comments {
+		if strings.HasPrefix(comment.Body, prefix) && comment.CreatedAt.After(since) {
+			return true
+		}
+	}
+	return false
+}
+
+// escalate hands a stale task to the fallback user once the assignee has
+// ignored enough reminders. It returns false, without error, when the issue
+// should just be reminded again.
+func (m *Monitor) escalate(ctx context.Context, issue *github.Issue) (bool, error) {
+	owner, repo := github.ParseRepoFromURL(issue.URL)
+	comments, err := m.githubClient.GetIssueComments(ctx, owner, repo, issue.Number)
+	if err != nil {
+		slog.Warn("not escalating issue, failed to read comments", "issue", issue.Number, "error", err)
+		return false, nil
+	}
+
+	reminders := unansweredReminders(issue, comments, m.commentPrefix())
+	if reminders < m.options.EscalateAfterReminders {
+		return false, nil
+	}
+
+	target := m.options.EscalateTo
+	if strings.EqualFold(target, EscalateToAuthor) {
+		target = issue.Author
+	}
+	if target == "" || strings.EqualFold(target, issue.Assignee) {
+		slog.Warn("not escalating issue, no fallback user other than the assignee", "issue", issue.Number, "assignee", issue.Assignee)
+		return false, nil
+	}
+
+	var message string
+	if m.options.EscalateAction == EscalateReassign {
+		if err := m.githubClient.SetAssignee(ctx, owner, repo, issue.Number, target); err != nil {
+			return false, err
+		}
+		message = fmt.Sprintf("%s Reassigning this task to @%s after @%s didn't respond to %d reminders.",
+			escalationMarker, target, issue.Assignee, reminders)
+	} else {
+		message = fmt.Sprintf("%s @%s, @%s hasn't responded to %d reminders on this task. Could you follow up or reassign it?",
+			escalationMarker, target, issue.Assignee, reminders)
+	}
+	message = m.options.Identity.Format("", message)
+	if err := addComment(ctx, m.githubClient, owner, repo, issue.Number, message); err != nil {
+		return false, err
+	}
+	slog.Info("escalated stale task", "issue", issue.Number, "to", target, "unanswered_reminders", reminders)
+	return true, nil
+}
+
+// unansweredReminders counts agent reminders, comments starting with prefix,
+// posted since the assignee's last comment and since the last escalation
+func unansweredReminders(issue *github.Issue, comments []github.Comment, prefix string) int {
+	count := 0
+	for _, comment := range comments {
+		switch {
+		case strings.HasPrefix(comment.Body, prefix+": "+escalationMarker):
+			count = 0
+		case strings.HasPrefix(comment.Body, prefix):
+			count++
+		case strings.EqualFold(comment.Author, issue.Assignee):
+			count = 0
+		}
+	}
+	return count
+}
+
+// closeAbandoned closes a stale issue that was already reminded and has had
+// no human activity within the auto-close window. It returns true if the
+// issue was closed.
+func (m *Monitor) closeAbandoned(ctx context.Context, issue *github.Issue, now time.Time) bool {
+	owner, repo := github.ParseRepoFromURL(issue.URL)
+	comments, err := m.githubClient.GetIssueComments(ctx, owner, repo, issue.Number)
+	if err != nil {
+		slog.Warn("not auto-closing issue, failed to read comments", "issue", issue.Number, "error", err)
+		return false
+	}
+
+	closeBefore := m.cutoff(now, m.options.AutoCloseAfterDays)
+	if !shouldAutoClose(issue, comments, m.commentPrefix(), closeBefore) {
+		return false
+	}
+
+	message := m.options.Identity.Format("", fmt.Sprintf("Closing this task after %d days without activity. @%s, please reopen it if you're still working on it.",
+		m.options.AutoCloseAfterDays, issue.Assignee))
+	if err := addComment(ctx, m.githubClient, owner, repo, issue.Number, message); err != nil {
+		slog.Warn("not auto-closing issue, failed to comment", "issue", issue.Number, "error", err)
+		return false
+	}
+	if err := m.githubClient.CloseIssue(ctx, owner, repo, issue.Number); err != nil {
+		slog.Error("failed to close stale task", "issue", issue.Number, "error", err)
+		return false
+	}
+	slog.Info("closed stale task", "issue", issue.Number, "inactive_days", m.options.AutoCloseAfterDays)
+	return true
+}
+
+// shouldAutoClose reports whether an issue was reminded by the agent, the
+// assignee hasn't replied since the last reminder, and nobody but the agent
+// has been active since closeBefore. Agent comments start with prefix.
+func shouldAutoClose(issue *github.Issue, comments []github.Comment, prefix string, closeBefore time.Time) bool {
+	var lastReminder time.Time
+	lastHumanActivity := issue.CreatedAt
+	for _, comment := range comments {
+		if strings.HasPrefix(comment.Body, prefix) {
+			if comment.CreatedAt.After(lastReminder) {
+				lastReminder = comment.CreatedAt
+			}
+			continue
+		}
+		if comment.CreatedAt.After(lastHumanActivity) {
+			lastHumanActivity = comment.CreatedAt
+		}
+	}
+
+	if lastReminder.IsZero() {
+		// Never close without warning first
+		return false
+	}
+	for _, comment := range comments {
+		if strings.EqualFold(comment.Author, issue.Assignee) && comment.CreatedAt.After(lastReminder) {
+			return false
+		}
+	}
+	return lastHumanActivity.Before(closeBefore)
+}
+
+// postDigest creates or updates the single stale task digest issue.
+// The existing digest (found by label among open issues) is rewritten in place
+// so running daily doesn't produce a new issue each time. It returns the
+// digest issue number, or 0 if there was nothing to report.
+func (m *Monitor) postDigest(ctx context.Context, openIssues, staleIssues []*github.Issue) (int, error) {
+	body := formatStaleDigest(staleIssues, m.staleThresholdDays, time.Now())
+
+	// Without stale tasks only an existing digest is refreshed
+	digest, created, err := github.UpdateOrCreateIssue(ctx, m.githubClient, openIssues, "", "", "🤖 Stale Task Digest", body,
+		[]string{AgentGeneratedLabel, digestLabel}, m.options.DigestAssignee, len(staleIssues) > 0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to publish stale task digest: %w", err)
+	}
+	if digest == nil {
+		return 0, nil
+	}
+	slog.Info("published stale task digest", "issue", digest.Number, "created", created, "stale_tasks", len(staleIssues), "assignee", digest.Assignee)
+	return digest.Number, nil
+}
+
+// formatStaleDigest renders the digest body grouped by assignee
+func formatStaleDigest(staleIssues []*github.Issue, thresholdDays int, now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString("# 🤖 Stale Task Digest\n\n")
+	sb.WriteString(fmt.Sprintf("_Last updated: %s_\n\n", now.Format("2006-01-02 15:04 MST")))
+
+	if len(staleIssues) == 0 {
+		sb.WriteString(fmt.Sprintf("No assigned tasks have gone %d+ days without updates. 🎉\n", thresholdDays))
+		return sb.String()
+	}
+
+	byAssignee := make(map[string][]*github.Issue)
+	for _, issue := range staleIssues {
+		byAssignee[issue.Assignee] = append(byAssignee[issue.Assignee], issue)
+	}
+	assignees := make([]string, 0, len(byAssignee))
+	for assignee := range byAssignee {
+		assignees = append(assignees, assignee)
+	}
+	sort.Strings(assignees)
+
+	sb.WriteString(fmt.Sprintf("**%d stale tasks** across **%d assignees** (no updates in %d+ days)\n",
+		len(staleIssues), len(assignees), thresholdDays))
+
+	for _, assignee := range assignees {
+		issues := byAssignee[assignee]
+		sb.WriteString(fmt.Sprintf("\n## @%s (%d)\n\n", assignee, len(issues)))
+		for _, issue := range issues {
+			daysStale := int(now.Sub(issue.UpdatedAt).Hours() / 24)
+			sb.WriteString(fmt.Sprintf("- [#%d %s](%s) — %d days without updates\n", issue.Number, issue.Title, issue.URL, daysStale))
+		}
+	}
+
+	return sb.String()
+}
+
+// activePullRequest returns an open pull request linked to the issue that was
+// updated after the stale threshold, meaning work is happening outside the issue
+func (m *Monitor) activePullRequest(ctx context.Context, issue *github.Issue, threshold time.Time) *github.PullRequest {
+	owner, repo := github.ParseRepoFromURL(issue.URL)
+	prs, err := m.githubClient.GetLinkedPullRequests(ctx, owner, repo, issue.Number)
+	if err != nil {
+		// Linkage is best-effort; treat the issue as stale
+		return nil
+	}
+	for _, pr := range prs {
+		if pr.State == "open" && pr.UpdatedAt.After(threshold) {
+			return pr
 		}
 	}
-	
 	return nil
 }
 
@@ -72,7 +598,9 @@ func (m *Monitor) handleStaleTask(ctx context.Context, issue *github.Issue) erro
 		}
 		
 		rendered, err := m.promptLoader.Render("monitor", data)
-		if err == nil {
+		if err != nil {
+			slog.Warn("failed to render prompt template, using the built-in prompt", "template", "monitor", "error", err)
+		} else {
 			prompt = rendered
 		}
 	}
@@ -99,7 +627,7 @@ The task has been in progress for %d days without updates. Ask for a status upda
 		)
 	}
 	
-	message, err := m.llmClient.Prompt(prompt)
+	message, err := m.llmClient.PromptContext(ctx, prompt)
 	if err != nil {
 		// Fallback to a simple message
 		message = fmt.Sprintf("👋 Hey @%s! This task has been in progress for %d days. Could you share a quick status update? Thanks! 🙏", 
@@ -113,23 +641,18 @@ The task has been in progress for %d days without updates. Ask for a status upda
 				message = strings.Join(lines[1:len(lines)-1], "\n")
 			}
 		}
-		message = fmt.Sprintf("🤖 **Agent**: %s", message)
 	}
+	message = m.options.Identity.Format("", message)
 	
-	owner, repo := extractRepoFromURL(issue.URL)
-	return m.githubClient.AddComment(ctx, owner, repo, issue.Number, message)
+	owner, repo := github.ParseRepoFromURL(issue.URL)
+	return addComment(ctx, m.githubClient, owner, repo, issue.Number, message)
 }
 
-// extractRepoFromURL extracts owner and repo from GitHub issue URL
-func extractRepoFromURL(url string) (owner, repo string) {
-	parts := strings.Split(url, "/")
-	if len(parts) >= 4 {
-		for i, part := range parts {
-			if part == "github.com" && i+2 < len(parts) {
-				return parts[i+1], parts[i+2]
-			}
-		}
+// addComment posts a comment and counts it in the metrics
+func addComment(ctx context.Context, client github.UnifiedClient, owner, repo string, number int, body string) error {
+	if err := client.AddComment(ctx, owner, repo, number, body); err != nil {
+		return err
 	}
-	return "", ""
+	metrics.CommentsPosted.Inc()
+	return nil
 }
-
@@ -0,0 +1,542 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaskol10/github-project-agent/bot"
+	"github.com/kaskol10/github-project-agent/config"
+	"github.com/kaskol10/github-project-agent/github"
+	"github.com/kaskol10/github-project-agent/github/githubtest"
+	"github.com/kaskol10/github-project-agent/llm"
+)
+
+func TestMonitor_DigestCreatedThenUpdated(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -10)
+	mockGH := githubtest.NewFakeClient()
+	mockGH.Issues = []*github.Issue{
+		{Number: 1, Title: "Migrate DB", Assignee: "alice", UpdatedAt: old, URL: "https://github.com/o/r/issues/1"},
+		{Number: 2, Title: "Fix login", Assignee: "bob", UpdatedAt: old, URL: "https://github.com/o/r/issues/2"},
+		{Number: 3, Title: "Fresh task", Assignee: "alice", UpdatedAt: time.Now(), URL: "https://github.com/o/r/issues/3"},
+	}
+
+	m := NewMonitorWithOptions(mockGH, nil, 7, MonitorOptions{Output: MonitorOutputDigest})
+	if err := m.CheckStaleTasks(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mockGH.CreatedIssues) != 1 {
+		t.Fatalf("expected one digest issue, got %d", len(mockGH.CreatedIssues))
+	}
+	digest := mockGH.CreatedIssues[0]
+	for _, want := range []string{"**2 stale tasks** across **2 assignees**", "## @alice (1)", "## @bob (1)", "#1 Migrate DB"} {
+		if !strings.Contains(digest.Body, want) {
+			t.Errorf("digest missing %q:\n%s", want, digest.Body)
+		}
+	}
+	if strings.Contains(digest.Body, "Fresh task") {
+		t.Error("digest should not list fresh tasks")
+	}
+	if len(mockGH.Comments) != 0 {
+		t.Errorf("digest mode should not comment on issues, got %v", mockGH.Comments)
+	}
+
+	// Second run updates the existing digest instead of creating another,
+	// even if someone relabeled it with different casing
+	digest.URL = "https://github.com/o/r/issues/1000"
+	digest.Labels = []string{"Agent-Generated", "Stale-Digest"}
+	mockGH.Issues = append(mockGH.Issues, digest)
+	if err := m.CheckStaleTasks(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(mockGH.CreatedIssues) != 1 {
+		t.Errorf("expected digest to be updated in place, got %d created issues", len(mockGH.CreatedIssues))
+	}
+	if _, ok := mockGH.UpdatedIssues[digest.Number]; !ok {
+		t.Error("expected existing digest issue to be updated")
+	}
+}
+
+func TestMonitor_AutoClose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	reminder := github.Comment{Author: "agent[bot]", Body: "🤖 **Agent**: Any update?", CreatedAt: daysAgo(10)}
+
+	tests := []struct {
+		name       string
+		autoClose  int
+		comments   []github.Comment
+		wantClosed bool
+	}{
+		{
+			name:       "reminded and abandoned",
+			autoClose:  30,
+			comments:   []github.Comment{{Author: "alice", Body: "Starting on this", CreatedAt: daysAgo(45)}, reminder},
+			wantClosed: true,
+		},
+		{
+			name:      "disabled by default",
+			comments:  []github.Comment{reminder},
+			autoClose: 0,
+		},
+		{
+			name:      "never reminded",
+			autoClose: 30,
+		},
+		{
+			name:      "assignee replied after the reminder",
+			autoClose: 30,
+			comments:  []github.Comment{reminder, {Author: "Alice", Body: "Still on it", CreatedAt: daysAgo(9)}},
+		},
+		{
+			name:      "recent human activity",
+			autoClose: 30,
+			comments:  []github.Comment{{Author: "bob", Body: "Blocked on review", CreatedAt: daysAgo(20)}, reminder},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGH := githubtest.NewFakeClient()
+			mockGH.Issues = []*github.Issue{
+				{Number: 1, Title: "Migrate DB", Assignee: "alice", CreatedAt: daysAgo(60), UpdatedAt: daysAgo(10), URL: "https://github.com/o/r/issues/1"},
+			}
+			mockGH.IssueComments[1] = tt.comments
+
+			llmClient := llm.NewClient(server.URL, "test-model", "", time.Second)
+			m := NewMonitorWithOptions(mockGH, llmClient, 7, MonitorOptions{AutoCloseAfterDays: tt.autoClose})
+			if err := m.CheckStaleTasks(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			if mockGH.Closed[1] != tt.wantClosed {
+				t.Errorf("closed = %v, want %v", mockGH.Closed[1], tt.wantClosed)
+			}
+			if len(mockGH.Comments[1]) != 1 {
+				t.Fatalf("expected exactly one comment (reminder or closing notice), got %v", mockGH.Comments[1])
+			}
+			isClosingNotice := strings.Contains(mockGH.Comments[1][0], "Closing this task")
+			if isClosingNotice != tt.wantClosed {
+				t.Errorf("unexpected comment %q", mockGH.Comments[1][0])
+			}
+		})
+	}
+}
+
+func TestMonitor_SkipsRecentlyReminded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	tests := []struct {
+		name       string
+		comments   []github.Comment
+		wantRemind bool
+	}{
+		{
+			name:     "reminded within threshold",
+			comments: []github.Comment{{Author: "agent[bot]", Body: "🤖 **Agent**: Any update?", CreatedAt: daysAgo(3)}},
+		},
+		{
+			name:       "reminder older than threshold",
+			comments:   []github.Comment{{Author: "agent[bot]", Body: "🤖 **Agent**: Any update?", CreatedAt: daysAgo(8)}},
+			wantRemind: true,
+		},
+		{
+			name:       "recent human comment only",
+			comments:   []github.Comment{{Author: "bob", Body: "Any update?", CreatedAt: daysAgo(1)}},
+			wantRemind: true,
+		},
+		{
+			name:       "no comments",
+			wantRemind: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGH := githubtest.NewFakeClient()
+			mockGH.Issues = []*github.Issue{
+				{Number: 1, Title: "Migrate DB", Assignee: "alice", CreatedAt: daysAgo(60), UpdatedAt: daysAgo(10), URL: "https://github.com/o/r/issues/1"},
+			}
+			mockGH.IssueComments[1] = tt.comments
+
+			llmClient := llm.NewClient(server.URL, "test-model", "", time.Second)
+			m := NewMonitor(mockGH, llmClient, 7)
+			if err := m.CheckStaleTasks(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			reminded := len(mockGH.Comments[1]) > 0
+			if reminded != tt.wantRemind {
+				t.Errorf("reminded = %v, want %v (comments: %v)", reminded, tt.wantRemind, mockGH.Comments[1])
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	mockGH := githubtest.NewFakeClient()
+	mockGH.Issues = []*github.Issue{
+		{Number: 1, Assignee: "alice", UpdatedAt: daysAgo(10), URL: "https://github.com/o/r/issues/1"},
+		{Number: 2, Assignee: "bob", UpdatedAt: daysAgo(10), URL: "https://github.com/o/r/issues/2"},
+		{Number: 3, Assignee: "carol", UpdatedAt: daysAgo(1), URL: "https://github.com/o/r/issues/3"},
+		{Number: 4, UpdatedAt: daysAgo(30), URL: "https://github.com/o/r/issues/4"},
+	}
+	mockGH.IssueComments[2] = []github.Comment{{Author: "agent[bot]", Body: "🤖 **Agent**: Any update?", CreatedAt: daysAgo(2)}}
+
+	m := NewMonitor(mockGH, llm.NewClient(server.URL, "test-model", "", time.Second), 7)
+	result, err := m.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Checked != 4 {
+		t.Errorf("expected 4 checked issues, got %d", result.Checked)
+	}
+	if !reflect.DeepEqual(result.Stale, []int{1, 2}) || !reflect.DeepEqual(result.Reminded, []int{1}) || !reflect.DeepEqual(result.Skipped, []int{2}) {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(result.Closed) != 0 || len(result.Errors) != 0 || result.Digest != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestMonitor_Identity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	identity, err := bot.New("Acme Bot", "**{{.Name}}**")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	mockGH := githubtest.NewFakeClient()
+	mockGH.Issues = []*github.Issue{
+		{Number: 1, Assignee: "alice", UpdatedAt: daysAgo(10), URL: "https://github.com/o/r/issues/1"},
+		{Number: 2, Assignee: "bob", UpdatedAt: daysAgo(10), URL: "https://github.com/o/r/issues/2"},
+	}
+	// Only comments signed by the configured identity count as reminders
+	mockGH.IssueComments[1] = []github.Comment{{Author: "agent[bot]", Body: "🤖 **Agent**: Any update?", CreatedAt: daysAgo(2)}}
+	mockGH.IssueComments[2] = []github.Comment{{Author: "agent[bot]", Body: "**Acme Bot**: Any update?", CreatedAt: daysAgo(2)}}
+
+	m := NewMonitorWithOptions(mockGH, llm.NewClient(server.URL, "test-model", "", time.Second), 7, MonitorOptions{Identity: identity})
+	result, err := m.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(result.Reminded, []int{1}) || !reflect.DeepEqual(result.Skipped, []int{2}) {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(mockGH.Comments[1]) != 1 || !strings.HasPrefix(mockGH.Comments[1][0], "**Acme Bot**: ") {
+		t.Errorf("expected a reminder signed by Acme Bot, got %v", mockGH.Comments[1])
+	}
+}
+
+func TestMonitor_Escalate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	reminder := func(days int) github.Comment {
+		return github.Comment{Author: "agent[bot]", Body: "🤖 **Agent**: Any update?", CreatedAt: daysAgo(days)}
+	}
+
+	tests := []struct {
+		name           string
+		options        MonitorOptions
+		comments       []github.Comment
+		wantEscalated  bool
+		wantReassigned string
+		wantComment    string
+	}{
+		{
+			name:          "mentions the fallback user",
+			options:       MonitorOptions{EscalateAfterReminders: 2, EscalateTo: "lead"},
+			comments:      []github.Comment{reminder(30), reminder(20)},
+			wantEscalated: true,
+			wantComment:   "@lead, @alice hasn't responded to 2 reminders",
+		},
+		{
+			name:           "reassigns to the issue author",
+			options:        MonitorOptions{EscalateAfterReminders: 2, EscalateTo: EscalateToAuthor, EscalateAction: EscalateReassign},
+			comments:       []github.Comment{reminder(30), reminder(20)},
+			wantEscalated:  true,
+			wantReassigned: "carol",
+			wantComment:    "Reassigning this task to @carol",
+		},
+		{
+			name:     "disabled by default",
+			comments: []github.Comment{reminder(30), reminder(20)},
+		},
+		{
+			name:     "too few reminders",
+			options:  MonitorOptions{EscalateAfterReminders: 3, EscalateTo: "lead"},
+			comments: []github.Comment{reminder(30), reminder(20)},
+		},
+		{
+			name:    "assignee replied",
+			options: MonitorOptions{EscalateAfterReminders: 2, EscalateTo: "lead"},
+			comments: []github.Comment{
+				reminder(30), {Author: "Alice", Body: "Still on it", CreatedAt: daysAgo(25)}, reminder(20),
+			},
+		},
+		{
+			name:    "already escalated",
+			options: MonitorOptions{EscalateAfterReminders: 2, EscalateTo: "lead"},
+			comments: []github.Comment{
+				reminder(40), reminder(30),
+				{Author: "agent[bot]", Body: "🤖 **Agent**: ⏫ @lead, @alice hasn't responded to 2 reminders on this task.", CreatedAt: daysAgo(20)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGH := githubtest.NewFakeClient()
+			mockGH.Issues = []*github.Issue{
+				{Number: 1, Title: "Migrate DB", Assignee: "alice", Author: "carol", CreatedAt: daysAgo(60), UpdatedAt: daysAgo(10), URL: "https://github.com/o/r/issues/1"},
+			}
+			mockGH.IssueComments[1] = tt.comments
+
+			llmClient := llm.NewClient(server.URL, "test-model", "", time.Second)
+			m := NewMonitorWithOptions(mockGH, llmClient, 7, tt.options)
+			result, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if escalated := len(result.Escalated) == 1; escalated != tt.wantEscalated {
+				t.Errorf("escalated = %v, want %v (result %+v)", result.Escalated, tt.wantEscalated, result)
+			}
+			if tt.wantEscalated == (len(result.Reminded) == 1) {
+				t.Errorf("expected either a reminder or an escalation, got reminded %v", result.Reminded)
+			}
+			if mockGH.Reassigned[1] != tt.wantReassigned {
+				t.Errorf("reassigned to %q, want %q", mockGH.Reassigned[1], tt.wantReassigned)
+			}
+			if len(mockGH.Comments[1]) != 1 {
+				t.Fatalf("expected exactly one comment, got %v", mockGH.Comments[1])
+			}
+			if tt.wantComment != "" && !strings.Contains(mockGH.Comments[1][0], tt.wantComment) {
+				t.Errorf("comment %q does not contain %q", mockGH.Comments[1][0], tt.wantComment)
+			}
+		})
+	}
+}
+
+func TestMonitor_SkipsNewIssues(t *testing.T) {
+	now := time.Now()
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	mockGH := githubtest.NewFakeClient()
+	mockGH.Issues = []*github.Issue{
+		// Created two days ago and never updated since
+		{Number: 1, Assignee: "alice", CreatedAt: daysAgo(2), URL: "https://github.com/o/r/issues/1"},
+		// Past the threshold but still within the grace period
+		{Number: 2, Assignee: "bob", CreatedAt: daysAgo(9), UpdatedAt: daysAgo(9), URL: "https://github.com/o/r/issues/2"},
+		{Number: 3, Assignee: "carol", CreatedAt: daysAgo(20), UpdatedAt: daysAgo(12), URL: "https://github.com/o/r/issues/3"},
+	}
+
+	m := NewMonitorWithOptions(mockGH, nil, 7, MonitorOptions{Output: MonitorOutputDigest, GracePeriodDays: 3})
+	result, err := m.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Stale, []int{3}) {
+		t.Errorf("expected only the old issue to be stale, got %v", result.Stale)
+	}
+}
+
+func TestIsStale(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	tests := []struct {
+		name  string
+		issue *github.Issue
+		grace int
+		want  bool
+	}{
+		{"recently updated", &github.Issue{CreatedAt: daysAgo(30), UpdatedAt: daysAgo(1)}, 0, false},
+		{"not updated within threshold", &github.Issue{CreatedAt: daysAgo(30), UpdatedAt: daysAgo(10)}, 0, true},
+		{"fresh and never updated", &github.Issue{CreatedAt: daysAgo(2)}, 0, false},
+		{"old and never updated", &github.Issue{CreatedAt: daysAgo(10)}, 0, true},
+		{"within grace period", &github.Issue{CreatedAt: daysAgo(9), UpdatedAt: daysAgo(9)}, 5, false},
+		{"past grace period", &github.Issue{CreatedAt: daysAgo(13), UpdatedAt: daysAgo(13)}, 5, true},
+		{"unknown creation time", &github.Issue{UpdatedAt: daysAgo(10)}, 5, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsStale(tt.issue, now, 7, tt.grace); got != tt.want {
+				t.Errorf("IsStale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBusinessDaysBetween(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 12, d, 15, 0, 0, 0, time.UTC) } // Dec 2024 starts on a Sunday
+	christmas := time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		a, b     time.Time
+		holidays []time.Time
+		want     int
+	}{
+		{"same day", day(2), day(2).Add(2 * time.Hour), nil, 0},
+		{"monday to friday", day(2), day(6), nil, 4},
+		{"friday to monday skips the weekend", day(6), day(9), nil, 1},
+		{"full week", day(2), day(9), nil, 5},
+		{"saturday to sunday", day(7), day(8), nil, 0},
+		{"holiday is skipped", day(23), day(27), []time.Time{christmas}, 3},
+		{"b before a", day(9), day(2), nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := businessDaysBetween(tt.a, tt.b, tt.holidays); got != tt.want {
+				t.Errorf("businessDaysBetween() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonitor_BusinessDaysOnly(t *testing.T) {
+	now := time.Date(2024, 12, 30, 10, 0, 0, 0, time.UTC) // Monday
+	issue := &github.Issue{Number: 1, Assignee: "alice", CreatedAt: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2024, 12, 23, 10, 0, 0, 0, time.UTC)}
+	holidays := []time.Time{time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC)}
+
+	calendar := NewMonitorWithOptions(nil, nil, 5, MonitorOptions{})
+	if !calendar.isStale(issue, now) {
+		t.Error("expected a week without updates to be stale in calendar days")
+	}
+
+	business := NewMonitorWithOptions(nil, nil, 5, MonitorOptions{BusinessDaysOnly: true, Holidays: holidays})
+	if business.isStale(issue, now) {
+		t.Error("expected three business days without updates not to be stale")
+	}
+	if !business.isStale(issue, now.AddDate(0, 0, 2)) {
+		t.Error("expected five business days without updates to be stale")
+	}
+
+	// Pull request activity, reminders and auto-closing use the same count
+	if got, want := calendar.cutoff(now, 5), time.Date(2024, 12, 25, 10, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("calendar cutoff = %v, want %v", got, want)
+	}
+	if got, want := business.cutoff(now, 5), time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("business cutoff = %v, want %v", got, want)
+	}
+	for _, activity := range []time.Time{time.Date(2024, 12, 19, 23, 0, 0, 0, time.UTC), time.Date(2024, 12, 20, 9, 0, 0, 0, time.UTC)} {
+		stale := businessDaysBetween(activity, now, holidays) >= 5
+		if before := activity.Before(business.cutoff(now, 5)); before != stale {
+			t.Errorf("activity at %v: before cutoff = %v, but stale = %v", activity, before, stale)
+		}
+	}
+}
+
+func TestMonitor_ActivityFromEvents(t *testing.T) {
+	now := time.Now()
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	newClient := func() *githubtest.FakeClient {
+		mockGH := githubtest.NewFakeClient(
+			// A label edit yesterday bumped UpdatedAt, but nobody has worked on it for 20 days
+			&github.Issue{Number: 1, Title: "Relabeled", Assignee: "alice", CreatedAt: daysAgo(30), UpdatedAt: daysAgo(1), URL: "https://github.com/o/r/issues/1"},
+			&github.Issue{Number: 2, Title: "Discussed", Assignee: "bob", CreatedAt: daysAgo(30), UpdatedAt: daysAgo(1), URL: "https://github.com/o/r/issues/2"},
+			&github.Issue{Number: 3, Title: "Old", Assignee: "carol", CreatedAt: daysAgo(30), UpdatedAt: daysAgo(10), URL: "https://github.com/o/r/issues/3"},
+		)
+		mockGH.IssueEvents[1] = []github.IssueEvent{
+			{Event: "assigned", Actor: "pm", CreatedAt: daysAgo(20)},
+			{Event: "commented", Actor: "agent-bot", CreatedAt: daysAgo(3)},
+			{Event: "commented", Actor: "triage[bot]", CreatedAt: daysAgo(2)},
+			{Event: "labeled", Actor: "pm", CreatedAt: daysAgo(1)},
+		}
+		mockGH.IssueEvents[2] = []github.IssueEvent{
+			{Event: "commented", Actor: "bob", CreatedAt: daysAgo(3)},
+		}
+		return mockGH
+	}
+
+	mockGH := newClient()
+	m := NewMonitorWithOptions(mockGH, nil, 7, MonitorOptions{Output: MonitorOutputDigest, ActivityFromEvents: true})
+	result, err := m.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Stale, []int{1, 3}) {
+		t.Errorf("expected #1 to be stale from its timeline, got %v", result.Stale)
+	}
+	for _, call := range mockGH.CallsTo("ListIssueEvents") {
+		if call.Number == 3 {
+			t.Error("expected no timeline lookup for an issue already stale by its update time")
+		}
+	}
+	if body := mockGH.CreatedIssues[0].Body; !strings.Contains(body, "#1 Relabeled") || !strings.Contains(body, "20 days") {
+		t.Errorf("expected the digest to count #1 from its last human activity:\n%s", body)
+	}
+	if mockGH.Issues[0].UpdatedAt.Before(daysAgo(2)) {
+		t.Error("expected the listed issue to be left unchanged")
+	}
+
+	// Without the option only UpdatedAt counts
+	mockGH = newClient()
+	result, err = NewMonitorWithOptions(mockGH, nil, 7, MonitorOptions{Output: MonitorOutputDigest}).Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Stale, []int{3}) || len(mockGH.CallsTo("ListIssueEvents")) != 0 {
+		t.Errorf("expected only #3 to be stale without timeline lookups, got %v", result.Stale)
+	}
+}
+
+func TestMonitor_SkipsProtected(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -30)
+	mockGH := githubtest.NewFakeClient(
+		&github.Issue{Number: 1, Title: "Pinned epic", Assignee: "alice", Labels: []string{"no-agent"}, CreatedAt: old, UpdatedAt: old, URL: "https://github.com/o/r/issues/1"},
+		&github.Issue{Number: 2, Title: "Forgotten", Assignee: "bob", CreatedAt: old, UpdatedAt: old, URL: "https://github.com/o/r/issues/2"},
+	)
+	m := NewMonitorWithOptions(mockGH, nil, 7, MonitorOptions{Output: MonitorOutputDigest, SkipLabels: []string{config.DefaultSkipLabel}})
+
+	result, err := m.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Protected, []int{1}) || !reflect.DeepEqual(result.Stale, []int{2}) {
+		t.Errorf("expected #1 protected and #2 stale, got protected %v, stale %v", result.Protected, result.Stale)
+	}
+	if body := mockGH.CreatedIssues[0].Body; strings.Contains(body, "Pinned epic") {
+		t.Errorf("expected the digest to leave out the protected issue:\n%s", body)
+	}
+}
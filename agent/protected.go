@@ -0,0 +1,46 @@
+package agent
+
+import (
+	"strings"
+
+	"github.com/kaskol10/github-project-agent/github"
+)
+
+// SkipProtected is how an issue carrying a skip label is reported
+const SkipProtected = "skipped: protected"
+
+// ProtectedBy returns the first of skipLabels on the issue, ignoring case.
+// Agents leave issues carrying one of them alone entirely.
+func ProtectedBy(issue *github.Issue, skipLabels []string) (string, bool) {
+	return ProtectedByLabels(issue.Labels, skipLabels)
+}
+
+// IsAgentGenerated reports whether an agent created the issue, such as a
+// report or digest. Those are never validated or counted as checked.
+func IsAgentGenerated(issue *github.Issue) bool {
+	return github.HasLabels(issue.Labels, []string{AgentGeneratedLabel})
+}
+
+// ExcludeAgentGenerated returns the issues not created by an agent
+func ExcludeAgentGenerated(issues []*github.Issue) []*github.Issue {
+	filtered := make([]*github.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if !IsAgentGenerated(issue) {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
+
+// ProtectedByLabels is ProtectedBy for anything else that carries labels,
+// such as pull requests
+func ProtectedByLabels(labels, skipLabels []string) (string, bool) {
+	for _, skip := range skipLabels {
+		for _, label := range labels {
+			if strings.EqualFold(label, skip) {
+				return label, true
+			}
+		}
+	}
+	return "", false
+}
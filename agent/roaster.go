@@ -2,44 +2,125 @@ package agent
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/kaskol10/github-project-agent/github"
 	"github.com/kaskol10/github-project-agent/llm"
 	"github.com/kaskol10/github-project-agent/prompts"
+	"github.com/kaskol10/github-project-agent/sampling"
 )
 
+// roasterTemperature favors varied, opinionated analysis
+const roasterTemperature = 0.9
+
+// roastStaleDays is how long an open issue can go without updates before the
+// roast calls it out
+const roastStaleDays = 30
+
+// maxRoastStaleItems caps the stale issues listed in the prompt
+const maxRoastStaleItems = 10
+
+// Roast issues carry both labels, so later runs can find them
+const (
+	RoastLabel          = "roast"
+	AgentGeneratedLabel = "agent-generated"
+)
+
+// roastBacklogMarker records the backlog a roast covered in its body
+const roastBacklogMarker = "<!-- roast-backlog: %s -->"
+
+var roastBacklogPattern = regexp.MustCompile(`<!-- roast-backlog: ([0-9a-f]+) -->`)
+
+// RoastSkipped is the status of a roast skipped during the cooldown
+const RoastSkipped = "skipped (recent roast exists)"
+
 type Roaster struct {
 	githubClient github.UnifiedClient
-	llmClient    *llm.Client
+	llmClient    llm.LLMClient
 	promptLoader *prompts.Loader
+	options      RoasterOptions
+}
+
+// RoasterOptions configures optional roaster behavior
+type RoasterOptions struct {
+	// Sample bounds the issues analyzed on large projects
+	Sample sampling.Strategy
+
+	// Assignee is assigned to the created roast issue so the suggestions
+	// have an owner
+	Assignee string
+
+	// Cooldown skips the roast when one created within this period covered
+	// the same backlog (the same issues in the same states); zero disables it
+	Cooldown time.Duration
+
+	// StrictPrompts fails rendering the roast prompt when it references a
+	// missing key, so the built-in prompt is used instead
+	StrictPrompts bool
+}
+
+func NewRoaster(ghClient github.UnifiedClient, llmClient llm.LLMClient) *Roaster {
+	return NewRoasterWithOptions(ghClient, llmClient, RoasterOptions{})
 }
 
-func NewRoaster(ghClient github.UnifiedClient, llmClient *llm.Client) *Roaster {
+// NewRoasterWithOptions creates a roaster with optional behavior configured
+func NewRoasterWithOptions(ghClient github.UnifiedClient, llmClient llm.LLMClient, options RoasterOptions) *Roaster {
 	// Try to load prompts from prompts/ directory
 	promptPath := getPromptPath("prompts")
-	promptLoader, _ := prompts.NewLoader(promptPath) // Ignore error, will use fallback
+	promptLoader, _ := prompts.NewLoaderWithOptions(promptPath, prompts.LoaderOptions{Strict: options.StrictPrompts}) // Ignore error, will use fallback
 
 	return &Roaster{
 		githubClient: ghClient,
 		llmClient:    llmClient,
 		promptLoader: promptLoader,
+		options:      options,
 	}
 }
 
+// RoastResult is the structured outcome of a roast
+type RoastResult struct {
+	Status   string `json:"status"`   // "created" or RoastSkipped
+	Analyzed int    `json:"analyzed"` // Issues sent to the LLM
+	Total    int    `json:"total"`    // Issues before sampling
+	Issue    int    `json:"issue"`    // Roast issue created, or the recent one when skipped
+	URL      string `json:"url"`
+	Assignee string `json:"assignee,omitempty"`
+}
+
 func (r *Roaster) RoastAndSuggest(ctx context.Context) error {
+	_, err := r.Roast(ctx)
+	return err
+}
+
+// Roast analyzes the project, creates the roast issue and returns it
+func (r *Roaster) Roast(ctx context.Context) (*RoastResult, error) {
 	// Get all issues
 	allIssues, err := r.githubClient.ListIssues(ctx, "all")
 	if err != nil {
-		return fmt.Errorf("failed to list issues: %w", err)
+		return nil, fmt.Errorf("failed to list issues: %w", err)
 	}
 
+	fingerprint := backlogFingerprint(allIssues)
+	if recent := r.recentRoast(allIssues, fingerprint, time.Now()); recent != nil {
+		slog.Info("skipping roast: a recent roast covers the same backlog", "issue", recent.Number)
+		return &RoastResult{Status: RoastSkipped, Total: len(allIssues), Issue: recent.Number, URL: recent.URL, Assignee: recent.Assignee}, nil
+	}
+
+	issues := r.options.Sample.Apply(allIssues)
+	sampleDescription := r.options.Sample.Describe(len(issues), len(allIssues))
+	slog.Info("analyzing issues", "sample", sampleDescription)
+
 	// Analyze the product/roadmap
-	analysis, suggestions, err := r.analyzeProduct(ctx, allIssues)
+	analysis, suggestions, err := r.analyzeProduct(ctx, issues)
 	if err != nil {
-		return fmt.Errorf("failed to analyze product: %w", err)
+		return nil, fmt.Errorf("failed to analyze product: %w", err)
 	}
 
 	// Create a new issue with the roast and suggestions
@@ -55,28 +136,75 @@ func (r *Roaster) RoastAndSuggest(ctx context.Context) error {
 %s
 
 ---
-*Generated by the GitHub Project Agent on %s*`,
+*Generated by the GitHub Project Agent on %s from %s*
+%s`,
 		analysis,
 		suggestions,
 		time.Now().Format("2006-01-02 15:04:05"),
+		sampleDescription,
+		fmt.Sprintf(roastBacklogMarker, fingerprint),
 	)
 
-	labels := []string{"agent-generated", "roadmap", "analysis"}
+	labels := []string{AgentGeneratedLabel, RoastLabel, "roadmap", "analysis"}
 
 	// In project mode, CreateIssue will use the first repository if owner/repo are empty
 	// In repo mode, owner/repo are ignored
 	owner, repo := "", ""
-	_, err = r.githubClient.CreateIssue(ctx, owner, repo, title, body, labels)
+	created, err := github.CreateAssignedIssue(ctx, r.githubClient, owner, repo, title, body, labels, r.options.Assignee)
 	if err != nil {
-		return fmt.Errorf("failed to create roast issue: %w", err)
+		return nil, fmt.Errorf("failed to create roast issue: %w", err)
 	}
+	slog.Info("created roast issue", "issue", created.Number, "assignee", created.Assignee)
+
+	return &RoastResult{
+		Status:   "created",
+		Analyzed: len(issues),
+		Total:    len(allIssues),
+		Issue:    created.Number,
+		URL:      created.URL,
+		Assignee: created.Assignee,
+	}, nil
+}
 
+// recentRoast returns the roast issue created within the cooldown that
+// covered the backlog with fingerprint, if any
+func (r *Roaster) recentRoast(issues []*github.Issue, fingerprint string, now time.Time) *github.Issue {
+	if r.options.Cooldown <= 0 {
+		return nil
+	}
+	for _, issue := range issues {
+		if !github.HasLabels(issue.Labels, roastLabels) || now.Sub(issue.CreatedAt) > r.options.Cooldown {
+			continue
+		}
+		if matches := roastBacklogPattern.FindStringSubmatch(issue.Body); matches != nil && matches[1] == fingerprint {
+			return issue
+		}
+	}
 	return nil
 }
 
+// roastLabels mark a roast created by the agent
+var roastLabels = []string{AgentGeneratedLabel, RoastLabel}
+
+// backlogFingerprint hashes the number and state of every issue except the
+// roasts themselves, so it changes when issues are opened, closed or reopened
+func backlogFingerprint(issues []*github.Issue) string {
+	var entries []string
+	for _, issue := range issues {
+		if !github.HasLabels(issue.Labels, roastLabels) {
+			entries = append(entries, fmt.Sprintf("%s#%d:%s", issue.URL, issue.Number, issue.State))
+		}
+	}
+	sort.Strings(entries)
+	sum := sha256.Sum256([]byte(strings.Join(entries, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
 func (r *Roaster) analyzeProduct(ctx context.Context, issues []*github.Issue) (string, string, error) {
 	// Prepare context about the issues
 	issueSummary := r.summarizeIssues(issues)
+	gaps := summarizeGaps(issues)
+	staleItems := summarizeStaleItems(issues, time.Now())
 
 	// Try to use template, fallback to hardcoded prompt
 	var prompt string
@@ -86,19 +214,23 @@ func (r *Roaster) analyzeProduct(ctx context.Context, issues []*github.Issue) (s
 			"OpenIssues":   r.countByState(issues, "open"),
 			"ClosedIssues": r.countByState(issues, "closed"),
 			"IssueSummary": issueSummary,
+			"Gaps":         gaps,
+			"StaleItems":   staleItems,
 		}
 
 		rendered, err := r.promptLoader.Render("roaster", data)
-		if err == nil {
+		if err != nil {
+			slog.Warn("failed to render prompt template, using the built-in prompt", "template", "roaster", "error", err)
+		} else {
 			prompt = rendered
 		}
 	}
 
 	// Fallback to hardcoded prompt if template not available
 	if prompt == "" {
-		prompt = fmt.Sprintf(`You are a brutally honest product advisor. Analyze this GitHub project and provide:
+		prompt = fmt.Sprintf(`You are a brutally honest product advisor with a sense of humor. Analyze this GitHub project and provide:
 
-1. A "roast" - honest, constructive criticism about the product, roadmap, and task management. Be direct but professional. Point out gaps, inconsistencies, and areas for improvement.
+1. A "roast" - funny but constructive criticism about the product, roadmap, and task management. Be direct but professional. Point out gaps, stale work, inconsistencies, and areas for improvement. Every joke should point at something the team can fix.
 
 2. Specific, actionable task suggestions for the roadmap. Format each suggestion as:
    - **Title**: [Task title]
@@ -111,6 +243,10 @@ Project context:
 - Open issues: %d
 - Closed issues: %d
 - Issue breakdown: %s
+- Backlog gaps: %s
+
+Stale items (open, no updates in %d+ days):
+%s
 
 Provide your analysis in this exact format:
 
@@ -125,10 +261,13 @@ Be specific, actionable, and honest.`,
 			r.countByState(issues, "open"),
 			r.countByState(issues, "closed"),
 			issueSummary,
+			gaps,
+			roastStaleDays,
+			staleItems,
 		)
 	}
 
-	response, err := r.llmClient.Prompt(prompt)
+	response, err := r.llmClient.PromptWithOptionsContext(ctx, prompt, llm.ChatOptions{Temperature: roasterTemperature})
 	if err != nil {
 		return "", "", err
 	}
@@ -158,12 +297,20 @@ func (r *Roaster) summarizeIssues(issues []*github.Issue) string {
 		}
 	}
 
-	// Get top labels
-	topLabels := make([]string, 0, 5)
-	for label, count := range labelCounts {
-		if count > 0 {
-			topLabels = append(topLabels, fmt.Sprintf("%s (%d)", label, count))
+	// Get top labels, most used first
+	labels := make([]string, 0, len(labelCounts))
+	for label := range labelCounts {
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if labelCounts[labels[i]] != labelCounts[labels[j]] {
+			return labelCounts[labels[i]] > labelCounts[labels[j]]
 		}
+		return labels[i] < labels[j]
+	})
+	topLabels := make([]string, 0, 5)
+	for _, label := range labels {
+		topLabels = append(topLabels, fmt.Sprintf("%s (%d)", label, labelCounts[label]))
 		if len(topLabels) >= 5 {
 			break
 		}
@@ -186,6 +333,62 @@ func (r *Roaster) summarizeIssues(issues []*github.Issue) string {
 		strings.Join(topLabels, ", "), avgAgeDays)
 }
 
+// summarizeGaps describes open issues missing basic triage
+func summarizeGaps(issues []*github.Issue) string {
+	open, unlabeled, unassigned, noDescription := 0, 0, 0, 0
+	for _, issue := range issues {
+		if issue.State != "open" {
+			continue
+		}
+		open++
+		if len(issue.Labels) == 0 {
+			unlabeled++
+		}
+		if issue.Assignee == "" {
+			unassigned++
+		}
+		if strings.TrimSpace(issue.Body) == "" {
+			noDescription++
+		}
+	}
+	if open == 0 {
+		return "No open issues"
+	}
+
+	return fmt.Sprintf("%d of %d open issues unlabeled, %d unassigned, %d without a description",
+		unlabeled, open, unassigned, noDescription)
+}
+
+// summarizeStaleItems lists the open issues that haven't been updated
+// recently, oldest first
+func summarizeStaleItems(issues []*github.Issue, now time.Time) string {
+	threshold := now.AddDate(0, 0, -roastStaleDays)
+	var stale []*github.Issue
+	for _, issue := range issues {
+		if issue.State == "open" && issue.UpdatedAt.Before(threshold) {
+			stale = append(stale, issue)
+		}
+	}
+	if len(stale) == 0 {
+		return "None"
+	}
+
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].UpdatedAt.Before(stale[j].UpdatedAt)
+	})
+
+	var lines []string
+	for i, issue := range stale {
+		if i == maxRoastStaleItems {
+			lines = append(lines, fmt.Sprintf("- ...and %d more", len(stale)-maxRoastStaleItems))
+			break
+		}
+		days := int(now.Sub(issue.UpdatedAt).Hours() / 24)
+		lines = append(lines, fmt.Sprintf("- #%d %s (%d days since last update)", issue.Number, issue.Title, days))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (r *Roaster) countByState(issues []*github.Issue, state string) int {
 	count := 0
 	for _, issue := range issues {
@@ -0,0 +1,136 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaskol10/github-project-agent/github"
+	"github.com/kaskol10/github-project-agent/github/githubtest"
+	"github.com/kaskol10/github-project-agent/llm"
+)
+
+func TestRoaster_RoastAndSuggest(t *testing.T) {
+	var prompt string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req llm.ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		prompt = req.Messages[len(req.Messages)-1].Content
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"role": "assistant", "content": "## ROAST:\nYour backlog is a museum.\n\n## SUGGESTIONS:\n- **Title**: Triage old issues"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	mockGH := githubtest.NewFakeClient()
+	mockGH.Issues = []*github.Issue{
+		{Number: 1, Title: "Ancient bug", State: "open", CreatedAt: now.AddDate(0, -6, 0), UpdatedAt: now.AddDate(0, -3, 0)},
+		{Number: 2, Title: "Fresh feature", State: "open", Labels: []string{"feature"}, Assignee: "alice", Body: "Do it", CreatedAt: now, UpdatedAt: now},
+		{Number: 3, Title: "Done", State: "closed", Labels: []string{"feature"}, CreatedAt: now, UpdatedAt: now},
+	}
+
+	roaster := NewRoasterWithOptions(mockGH, llm.NewClient(server.URL, "test-model", "", time.Second), RoasterOptions{Assignee: "techlead"})
+	result, err := roaster.Roast(context.Background())
+	if err != nil {
+		t.Fatalf("Roast failed: %v", err)
+	}
+	if result.Analyzed != 3 || result.Total != 3 || result.Issue != mockGH.CreatedIssues[0].Number || result.Assignee != "techlead" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	for _, want := range []string{"#1 Ancient bug", "1 of 2 open issues unlabeled"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "#2 Fresh feature") {
+		t.Errorf("recently updated issue listed as stale:\n%s", prompt)
+	}
+
+	if len(mockGH.CreatedIssues) != 1 {
+		t.Fatalf("expected 1 roast issue, got %d", len(mockGH.CreatedIssues))
+	}
+	created := mockGH.CreatedIssues[0]
+	if !strings.Contains(created.Title, "Product Roast") || !strings.Contains(created.Title, now.Format("2006-01-02")) {
+		t.Errorf("unexpected title %q", created.Title)
+	}
+	if created.Assignee != "techlead" {
+		t.Errorf("expected roast assigned to techlead, got %q", created.Assignee)
+	}
+	if !strings.Contains(created.Body, "Your backlog is a museum.") || !strings.Contains(created.Body, "Triage old issues") {
+		t.Errorf("roast or suggestions missing from body:\n%s", created.Body)
+	}
+}
+
+func TestRoaster_LLMFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	mockGH := githubtest.NewFakeClient()
+	mockGH.Issues = []*github.Issue{{Number: 1, Title: "Bug", State: "open"}}
+
+	roaster := NewRoaster(mockGH, llm.NewClient(server.URL, "test-model", "", time.Second))
+	if err := roaster.RoastAndSuggest(context.Background()); err == nil {
+		t.Fatal("expected an error when the LLM fails")
+	}
+	if len(mockGH.CreatedIssues) != 0 {
+		t.Errorf("expected no issue to be created, got %d", len(mockGH.CreatedIssues))
+	}
+}
+
+func TestRoaster_Cooldown(t *testing.T) {
+	mockLLM := &mockLLMClient{reply: "## ROAST:\nStill a museum.\n\n## SUGGESTIONS:\n- **Title**: Triage"}
+	now := time.Now()
+	mockGH := githubtest.NewFakeClient(
+		&github.Issue{Number: 1, Title: "Ancient bug", State: "open", CreatedAt: now.AddDate(0, -6, 0)},
+		&github.Issue{Number: 2, Title: "Done", State: "closed", CreatedAt: now.AddDate(0, -1, 0)},
+	)
+	roaster := NewRoasterWithOptions(mockGH, mockLLM, RoasterOptions{Cooldown: 7 * 24 * time.Hour})
+
+	first, err := roaster.Roast(context.Background())
+	if err != nil || first.Status != "created" {
+		t.Fatalf("first Roast() = %+v, %v", first, err)
+	}
+	roast := mockGH.CreatedIssues[0]
+	if !github.HasLabels(roast.Labels, roastLabels) {
+		t.Fatalf("expected the roast issue to carry the roast labels, got %v", roast.Labels)
+	}
+	roast.CreatedAt = now.AddDate(0, 0, -2)
+	mockGH.Issues = append(mockGH.Issues, roast)
+
+	// Same backlog within the cooldown: skipped without calling the LLM
+	second, err := roaster.Roast(context.Background())
+	if err != nil {
+		t.Fatalf("second Roast() error = %v", err)
+	}
+	if second.Status != RoastSkipped || second.Issue != roast.Number || len(mockGH.CreatedIssues) != 1 || len(mockLLM.prompts) != 1 {
+		t.Errorf("expected the roast to be skipped, got %+v with %d issues created", second, len(mockGH.CreatedIssues))
+	}
+
+	// A closed issue changes the backlog
+	mockGH.Issues[0].State = "closed"
+	third, err := roaster.Roast(context.Background())
+	if err != nil || third.Status != "created" || len(mockGH.CreatedIssues) != 2 {
+		t.Errorf("expected a new roast after the backlog changed, got %+v, %v", third, err)
+	}
+
+	// Outside the cooldown the backlog doesn't matter
+	mockGH.Issues = mockGH.Issues[:2]
+	mockGH.Issues = append(mockGH.Issues, mockGH.CreatedIssues[1])
+	mockGH.CreatedIssues[1].CreatedAt = now.AddDate(0, 0, -8)
+	fourth, err := roaster.Roast(context.Background())
+	if err != nil || fourth.Status != "created" {
+		t.Errorf("expected a new roast after the cooldown, got %+v, %v", fourth, err)
+	}
+}
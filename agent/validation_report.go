@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kaskol10/github-project-agent/github"
+)
+
+// Validate output strategies
+const (
+	ValidateOutputInline = "inline"
+	ValidateOutputReport = "report"
+)
+
+// validationReportLabel marks the issue holding the consolidated validation report
+const validationReportLabel = "validation-report"
+
+// nonCompliantIssue is an issue with the format violations found in it
+type nonCompliantIssue struct {
+	Issue      *github.Issue
+	Violations []string
+}
+
+// PostReport checks the issues without modifying them and creates or updates
+// the single validation report issue listing every non-compliant issue.
+// It returns the number of non-compliant issues.
+func (v *Validator) PostReport(ctx context.Context, issues []*github.Issue) (int, error) {
+	var nonCompliant []nonCompliantIssue
+	checked := 0
+
+	for _, issue := range issues {
+		if IsAgentGenerated(issue) {
+			continue
+		}
+		checked++
+		if _, ok := ProtectedBy(issue, v.options.SkipLabels); ok {
+			continue
+		}
+
+		scoped, _ := v.forIssue(issue)
+		if violations := scoped.checkFormat(issue); len(violations) > 0 {
+			nonCompliant = append(nonCompliant, nonCompliantIssue{Issue: issue, Violations: violationMessages(violations)})
+		}
+	}
+
+	body := formatValidationReport(nonCompliant, checked, time.Now())
+
+	// Without non-compliant issues only an existing report is refreshed
+	report, created, err := github.UpdateOrCreateIssue(ctx, v.githubClient, issues, "", "", "🤖 Validation Report", body,
+		[]string{AgentGeneratedLabel, validationReportLabel}, v.options.ReportAssignee, len(nonCompliant) > 0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to publish validation report: %w", err)
+	}
+	if report != nil {
+		slog.Info("published validation report", "issue", report.Number, "created", created, "non_compliant", len(nonCompliant), "assignee", report.Assignee)
+	}
+	return len(nonCompliant), nil
+}
+
+// formatValidationReport renders the report body, oldest issue numbers first
+func formatValidationReport(nonCompliant []nonCompliantIssue, checked int, now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString("# 🤖 Validation Report\n\n")
+	sb.WriteString(fmt.Sprintf("_Last updated: %s_\n\n", now.Format("2006-01-02 15:04 MST")))
+
+	if len(nonCompliant) == 0 {
+		sb.WriteString(fmt.Sprintf("All %d open issues follow the format guidelines. 🎉\n", checked))
+		return sb.String()
+	}
+
+	sort.Slice(nonCompliant, func(i, j int) bool {
+		return nonCompliant[i].Issue.Number < nonCompliant[j].Issue.Number
+	})
+
+	sb.WriteString(fmt.Sprintf("**%d of %d open issues** don't follow the format guidelines.\n\n", len(nonCompliant), checked))
+	for _, item := range nonCompliant {
+		sb.WriteString(fmt.Sprintf("- [ ] [#%d %s](%s)\n", item.Issue.Number, item.Issue.Title, item.Issue.URL))
+		for _, violation := range item.Violations {
+			sb.WriteString(fmt.Sprintf("  - %s\n", violation))
+		}
+	}
+
+	return sb.String()
+}
@@ -2,128 +2,707 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"regexp"
 	"strings"
+	"unicode/utf8"
 
+	"github.com/kaskol10/github-project-agent/bot"
 	"github.com/kaskol10/github-project-agent/github"
 	"github.com/kaskol10/github-project-agent/guidelines"
 	"github.com/kaskol10/github-project-agent/llm"
+	"github.com/kaskol10/github-project-agent/markdown"
+	"github.com/kaskol10/github-project-agent/metrics"
 	"github.com/kaskol10/github-project-agent/prompts"
 )
 
 type Validator struct {
 	githubClient github.UnifiedClient
-	llmClient    *llm.Client
+	llmClient    llm.LLMClient
 	rules        TaskFormatRules
+	titlePattern *regexp.Regexp // Compiled rules.TitlePattern, nil when unset
+	defaultRules TaskFormatRules
 	guidelines   *guidelines.Guidelines
 	promptLoader *prompts.Loader
+	options      ValidatorOptions
+
+	appliedProfiles []string // Guidelines profiles this validator was scoped to
 }
 
 // TaskFormatRules defines the rules for task format validation
 type TaskFormatRules struct {
-	RequiredSections     []string
+	RequiredSections     []guidelines.Section
 	MinDescriptionLength int
 	RequireLabels        bool
 	LabelPrefix          string
+	TitlePattern         string // Regexp issue titles must match; empty disables the check
+	DefaultPriorityLabel string // Added when the priority label is missing and the body is fine; empty reports it instead
+	BodyTemplate         string // Markdown skeleton a rewritten body must follow; empty lets the LLM choose
+}
+
+// Validate reports configuration errors in the rules
+func (r TaskFormatRules) Validate() error {
+	if r.TitlePattern != "" {
+		if _, err := regexp.Compile(r.TitlePattern); err != nil {
+			return fmt.Errorf("invalid title pattern %q: %w", r.TitlePattern, err)
+		}
+	}
+	return nil
+}
+
+// ValidatorOptions configures optional validator behavior
+type ValidatorOptions struct {
+	// Profiles are label-selected guidelines merged on top of the base guidelines
+	Profiles []guidelines.Profile
+
+	// OnLLMFailure controls what happens when the LLM can't produce a fix:
+	// "skip" (the default) returns the error, "comment" posts a plain comment
+	// listing the violations, "label" applies the needs-format label.
+	// "comment,label" does both.
+	OnLLMFailure string
+
+	// Output is "inline" (fix and comment on each issue, the default) or
+	// "report" (a single, continuously updated validation report issue)
+	Output string
+
+	// ReportAssignee is assigned to a newly created validation report issue
+	ReportAssignee string
+
+	// Identity signs the comments the validator posts (default bot.Default)
+	Identity *bot.Identity
+
+	// SkipLabels protect issues: an issue carrying one is neither validated
+	// nor listed in the validation report
+	SkipLabels []string
+
+	// StrictPrompts fails rendering the fix prompt when it references a
+	// missing key, so the built-in prompt is used instead
+	StrictPrompts bool
+}
+
+// LLM failure behaviors
+const (
+	LLMFailureSkip    = "skip"
+	LLMFailureComment = "comment"
+	LLMFailureLabel   = "label"
+)
+
+// NeedsFormatLabel marks issues that violate the format guidelines but
+// couldn't be fixed automatically
+const NeedsFormatLabel = "needs-format"
+
+func NewValidator(ghClient github.UnifiedClient, llmClient llm.LLMClient, rules TaskFormatRules, guidelines *guidelines.Guidelines) *Validator {
+	return NewValidatorWithOptions(ghClient, llmClient, rules, guidelines, ValidatorOptions{})
 }
 
-func NewValidator(ghClient github.UnifiedClient, llmClient *llm.Client, rules TaskFormatRules, guidelines *guidelines.Guidelines) *Validator {
+// NewValidatorWithOptions creates a validator with optional behavior configured
+func NewValidatorWithOptions(ghClient github.UnifiedClient, llmClient llm.LLMClient, rules TaskFormatRules, guidelines *guidelines.Guidelines, options ValidatorOptions) *Validator {
 	// Try to load prompts from prompts/ directory
 	promptPath := getPromptPath("prompts")
-	promptLoader, _ := prompts.NewLoader(promptPath) // Ignore error, will use fallback
+	promptLoader, _ := prompts.NewLoaderWithOptions(promptPath, prompts.LoaderOptions{Strict: options.StrictPrompts}) // Ignore error, will use fallback
+
+	if options.Output == "" {
+		options.Output = ValidateOutputInline
+	}
 
 	v := &Validator{
 		githubClient: ghClient,
 		llmClient:    llmClient,
-		rules:        rules,
+		defaultRules: rules,
 		guidelines:   guidelines,
 		promptLoader: promptLoader,
+		options:      options,
 	}
+	v.setRules(applyGuidelines(rules, guidelines))
+	return v
+}
 
-	// Override rules with guidelines if available
-	if guidelines != nil {
-		v.rules.RequiredSections = guidelines.FormatRules.RequiredSections
-		if len(v.rules.RequiredSections) == 0 {
-			v.rules.RequiredSections = rules.RequiredSections // Fallback to defaults
-		}
-		if guidelines.FormatRules.MinDescriptionLength > 0 {
-			v.rules.MinDescriptionLength = guidelines.FormatRules.MinDescriptionLength
-		}
-		v.rules.RequireLabels = guidelines.FormatRules.RequireLabels || rules.RequireLabels
-		if guidelines.FormatRules.LabelPrefix != "" {
-			v.rules.LabelPrefix = guidelines.FormatRules.LabelPrefix
-		}
+// setRules sets the rules and compiles their title pattern once
+func (v *Validator) setRules(rules TaskFormatRules) {
+	v.rules = rules
+	v.titlePattern = nil
+	if rules.TitlePattern == "" {
+		return
+	}
+	re, err := regexp.Compile(rules.TitlePattern)
+	if err != nil {
+		// Validate rejects this at startup; don't blame every issue for it
+		slog.Error("invalid title pattern, skipping the title check", "pattern", rules.TitlePattern, "error", err)
+		return
+	}
+	v.titlePattern = re
+}
+
+// applyGuidelines overrides rules with the format rules from guidelines, if any
+func applyGuidelines(rules TaskFormatRules, g *guidelines.Guidelines) TaskFormatRules {
+	if g == nil {
+		return rules
 	}
 
-	return v
+	result := rules
+	result.RequiredSections = g.FormatRules.RequiredSections
+	if len(result.RequiredSections) == 0 {
+		result.RequiredSections = rules.RequiredSections // Fallback to defaults
+	}
+	if g.FormatRules.MinDescriptionLength > 0 {
+		result.MinDescriptionLength = g.FormatRules.MinDescriptionLength
+	}
+	result.RequireLabels = g.FormatRules.RequireLabels || rules.RequireLabels
+	if g.FormatRules.LabelPrefix != "" {
+		result.LabelPrefix = g.FormatRules.LabelPrefix
+	}
+	if g.FormatRules.TitlePattern != "" {
+		result.TitlePattern = g.FormatRules.TitlePattern
+	}
+	if g.FormatRules.DefaultPriorityLabel != "" {
+		result.DefaultPriorityLabel = g.FormatRules.DefaultPriorityLabel
+	}
+	if g.FormatRules.BodyTemplate != "" {
+		result.BodyTemplate = g.FormatRules.BodyTemplate
+	}
+	return result
+}
+
+// forIssue returns a validator scoped to the guidelines profiles matching the
+// issue's labels, and the names of the profiles applied
+func (v *Validator) forIssue(issue *github.Issue) (*Validator, []string) {
+	if len(v.options.Profiles) == 0 {
+		return v, nil
+	}
+
+	resolver := &guidelines.Resolver{Base: v.guidelines, Profiles: v.options.Profiles}
+	effective, applied := resolver.Resolve(issue.Labels)
+	if len(applied) == 0 {
+		return v, nil
+	}
+
+	scoped := *v
+	scoped.guidelines = effective
+	rules := applyGuidelines(v.defaultRules, effective)
+	// Profiles add sections to the ones every issue needs, never replace them
+	rules.RequiredSections = guidelines.UnionSections(v.rules.RequiredSections, rules.RequiredSections)
+	scoped.setRules(rules)
+	scoped.appliedProfiles = applied
+	return &scoped, applied
+}
+
+// ReportOnly reports whether violations should go to the consolidated
+// validation report instead of being fixed on each issue
+func (v *Validator) ReportOnly() bool {
+	return v.options.Output == ValidateOutputReport
+}
+
+// ValidationResult is the structured outcome of validating one issue
+type ValidationResult struct {
+	Issue       *github.Issue
+	Valid       bool        // The issue already followed the format
+	Fixed       bool        // The agent fixed the issue, by rewriting the body or adding labels
+	NeedsHuman  bool        // Violations were reported for the author to fix
+	FixFailed   bool        // The LLM rewrite still broke the rules, so the body was left unchanged
+	Violations  []Violation // Violations found before any fix
+	LabelsAdded []string    // Labels added instead of rewriting the body
+	Profiles    []string    // Guidelines profiles applied
+	Comment     string      // Comment posted on the issue, if any
+	Skipped     string      // Why the issue was left alone, e.g. SkipProtected
+}
+
+// LLMError reports that the LLM couldn't produce a fix
+type LLMError struct {
+	Err error
+}
+
+func (e *LLMError) Error() string {
+	return fmt.Sprintf("failed to fix with LLM: %v", e.Err)
+}
+
+func (e *LLMError) Unwrap() error {
+	return e.Err
 }
 
 func (v *Validator) ValidateAndFix(ctx context.Context, issue *github.Issue) (bool, string, error) {
+	result, err := v.Validate(ctx, issue)
+	if err != nil {
+		return false, "", err
+	}
+	return result.Valid, result.Comment, nil
+}
+
+// Validate checks an issue and fixes it with the LLM if needed, returning a
+// structured result. The result is non-nil even when an error is returned.
+func (v *Validator) Validate(ctx context.Context, issue *github.Issue) (*ValidationResult, error) {
+	if label, ok := ProtectedBy(issue, v.options.SkipLabels); ok {
+		slog.Info("skipping protected issue", "issue", issue.Number, "label", label)
+		return &ValidationResult{Issue: issue, Skipped: SkipProtected}, nil
+	}
+
+	v, profiles := v.forIssue(issue)
+	if len(profiles) > 0 {
+		slog.Info("applying guidelines profiles", "issue", issue.Number, "profiles", profiles)
+	}
+
+	metrics.IssuesProcessed.Inc()
 	violations := v.checkFormat(issue)
+	result := &ValidationResult{Issue: issue, Violations: violations, Profiles: profiles}
 
 	if len(violations) == 0 {
-		return true, "", nil
+		result.Valid = true
+		return result, nil
+	}
+
+	// Rewriting the body can't fix labels or titles, so only call the LLM
+	// when the body itself is wrong
+	if !needsRewrite(violations) {
+		return result, v.fixWithoutRewrite(ctx, result)
+	}
+
+	var bodyViolations, otherViolations []Violation
+	for _, violation := range violations {
+		if violation.AffectsBody() {
+			bodyViolations = append(bodyViolations, violation)
+		} else {
+			otherViolations = append(otherViolations, violation)
+		}
+	}
+	fixed := violationMessages(bodyViolations)
+
+	// A previous rewrite that left these violations behind won't do better
+	// this time, so hand the issue to a human instead of rewriting it again
+	if previous, ok := noticeViolations(issue.Body); ok && containsAll(previous, fixed) {
+		slog.Info("skipping rewrite: a previous rewrite left the same violations", "issue", issue.Number)
+		return result, v.requestManualAttention(ctx, result)
+	}
+	// Likewise when an earlier attempt gave up without writing the body
+	if v.gaveUpOn(ctx, issue, fixed) {
+		slog.Info("skipping rewrite: a previous attempt could not fix the same violations", "issue", issue.Number)
+		result.NeedsHuman = true
+		return result, nil
 	}
 
 	// Use LLM to fix the issue
-	fixedBody, err := v.fixWithLLM(ctx, issue, violations)
+	fixedBody, remaining, err := v.rewriteBody(ctx, issue, fixed)
 	if err != nil {
-		return false, "", fmt.Errorf("failed to fix with LLM: %w", err)
+		return result, v.handleLLMFailure(ctx, result, err)
+	}
+	if len(remaining) > 0 {
+		slog.Warn("could not auto-fix issue, leaving body unchanged", "issue", issue.Number, "violations", remaining)
+		result.FixFailed = true
+		return result, v.requestManualAttention(ctx, result)
 	}
 
 	// Preserve original content and add agent modification notice
-	updatedBody := v.preserveOriginalWithModifications(issue.Body, fixedBody, violations)
+	updatedBody := v.preserveOriginalWithModifications(issue.Body, fixedBody, fixed)
 
 	// Extract owner and repo from issue URL if in project mode
-	owner, repo := extractRepoFromURL(issue.URL)
+	owner, repo := github.ParseRepoFromURL(issue.URL)
 
 	// Update the issue
 	if err := v.githubClient.UpdateIssue(ctx, owner, repo, issue.Number, nil, &updatedBody); err != nil {
-		return false, "", fmt.Errorf("failed to update issue: %w", err)
+		return result, fmt.Errorf("failed to update issue: %w", err)
 	}
+	result.Fixed = true
 
-	comment := fmt.Sprintf("🤖 **Agent**: I've updated this task to follow our format guidelines.\n\nIssues fixed:\n%s",
-		strings.Join(violations, "\n- "))
+	comment := v.options.Identity.Format("", fmt.Sprintf("I've updated this task to follow our format guidelines.\n\nIssues fixed:\n%s",
+		strings.Join(fixed, "\n- ")))
+	if len(otherViolations) > 0 {
+		comment += fmt.Sprintf("\n\nPlease also address:\n%s", formatViolations(otherViolations))
+	}
+	if len(profiles) > 0 {
+		comment += fmt.Sprintf("\n\n_Guidelines profile applied: %s_", strings.Join(profiles, ", "))
+	}
+	result.Comment = comment
 
-	if err := v.githubClient.AddComment(ctx, owner, repo, issue.Number, comment); err != nil {
+	if err := addComment(ctx, v.githubClient, owner, repo, issue.Number, comment); err != nil {
 		// Log error but don't fail
-		fmt.Printf("Warning: failed to add comment: %v\n", err)
+		slog.Warn("failed to add comment", "issue", issue.Number, "error", err)
 	}
 
-	return false, comment, nil
+	return result, nil
 }
 
-func (v *Validator) checkFormat(issue *github.Issue) []string {
-	var violations []string
+// rewriteBody asks the LLM to fix the body violations fixed, returning the
+// new body and the violations it still has. The LLM doesn't always manage,
+// so it retries once, insisting on what was missed.
+func (v *Validator) rewriteBody(ctx context.Context, issue *github.Issue, fixed []string) (string, []string, error) {
+	fixedBody, err := v.fixWithLLM(ctx, issue, fixed)
+	if err != nil {
+		return "", nil, err
+	}
+
+	remaining := v.bodyViolations(issue, fixedBody)
+	if len(remaining) == 0 {
+		return fixedBody, nil, nil
+	}
+	slog.Info("rewrite left violations, retrying", "issue", issue.Number, "violations", remaining)
+	retry := *issue
+	retry.Body = fixedBody
+	fixedBody, err = v.fixWithLLM(ctx, &retry, insistOn(remaining))
+	if err != nil {
+		return "", nil, err
+	}
+	return fixedBody, v.bodyViolations(issue, fixedBody), nil
+}
+
+// bodyViolations returns the body violations issue would still have with body
+func (v *Validator) bodyViolations(issue *github.Issue, body string) []string {
+	candidate := *issue
+	candidate.Body = body
+	var violations []Violation
+	for _, violation := range v.checkFormat(&candidate) {
+		if violation.AffectsBody() {
+			violations = append(violations, violation)
+		}
+	}
+	return violationMessages(violations)
+}
+
+// insistOn rewords violations left by a rewrite for a second attempt
+func insistOn(violations []string) []string {
+	insisted := make([]string, len(violations))
+	for i, violation := range violations {
+		insisted[i] = fmt.Sprintf("STILL NOT FIXED by the previous rewrite, you MUST fix this: %s", violation)
+	}
+	return insisted
+}
+
+// fixWithoutRewrite handles violations that don't affect the body: a missing
+// priority label gets the default priority label, if configured, and other
+// errors are reported to the author once. Warnings alone aren't worth a
+// comment; they're listed only alongside errors.
+func (v *Validator) fixWithoutRewrite(ctx context.Context, result *ValidationResult) error {
+	issue := result.Issue
+	owner, repo := github.ParseRepoFromURL(issue.URL)
+
+	var remaining []Violation
+	for _, violation := range result.Violations {
+		label := v.rules.DefaultPriorityLabel
+		if violation.Kind != KindLabel || violation.Label != v.rules.LabelPrefix || label == "" {
+			remaining = append(remaining, violation)
+			continue
+		}
+		if err := v.githubClient.AddLabel(ctx, owner, repo, issue.Number, label); err != nil {
+			return fmt.Errorf("failed to add %s label: %w", label, err)
+		}
+		result.LabelsAdded = append(result.LabelsAdded, label)
+	}
+
+	hasErrors := false
+	for _, violation := range remaining {
+		if violation.Severity == SeverityError {
+			hasErrors = true
+		}
+	}
+
+	var parts []string
+	for _, label := range result.LabelsAdded {
+		parts = append(parts, fmt.Sprintf("I've applied the default priority label `%s` because this task had no priority label. Please replace it with the right priority.", label))
+	}
+	if messages := violationMessages(remaining); hasErrors && !v.commentedOn(ctx, issue, formatNoticeMarker, messages) {
+		parts = append(parts, fmt.Sprintf("This task doesn't follow our format guidelines yet.\n\nPlease address:\n%s\n\n%s\n%s",
+			formatViolations(remaining), formatNoticeMarker, violationsComment(messages)))
+	}
+	result.Fixed = len(result.LabelsAdded) > 0 && !hasErrors
+	result.NeedsHuman = hasErrors
+	if len(parts) == 0 {
+		return nil
+	}
+
+	comment := v.options.Identity.Format("", strings.Join(parts, "\n\n"))
+	if err := addComment(ctx, v.githubClient, owner, repo, issue.Number, comment); err != nil {
+		// Log error but don't fail
+		slog.Warn("failed to add comment", "issue", issue.Number, "error", err)
+	}
+	result.Comment = comment
+	return nil
+}
+
+// handleLLMFailure degrades gracefully when the LLM is unavailable by
+// reporting the violations without rewriting the issue, as configured
+func (v *Validator) handleLLMFailure(ctx context.Context, result *ValidationResult, llmErr error) error {
+	issue := result.Issue
+	postComment, addLabel := v.llmFailureActions()
+	if !postComment && !addLabel {
+		return &LLMError{Err: llmErr}
+	}
+
+	slog.Warn("LLM unavailable, reporting violations instead", "issue", issue.Number, "error", llmErr)
+	owner, repo := github.ParseRepoFromURL(issue.URL)
+
+	if postComment {
+		comment := v.options.Identity.Format("", fmt.Sprintf("This task doesn't follow our format guidelines yet.\n\nPlease address:\n%s",
+			formatViolations(result.Violations)))
+		if err := addComment(ctx, v.githubClient, owner, repo, issue.Number, comment); err != nil {
+			return fmt.Errorf("failed to add comment: %w", err)
+		}
+		result.Comment = comment
+	}
+
+	if addLabel {
+		if err := v.githubClient.AddLabel(ctx, owner, repo, issue.Number, NeedsFormatLabel); err != nil {
+			return fmt.Errorf("failed to add %s label: %w", NeedsFormatLabel, err)
+		}
+	}
+
+	result.NeedsHuman = true
+	return nil
+}
+
+// llmFailureActions reports which ON_LLM_FAILURE actions are configured
+func (v *Validator) llmFailureActions() (postComment, addLabel bool) {
+	for _, action := range strings.Split(v.options.OnLLMFailure, ",") {
+		switch strings.TrimSpace(action) {
+		case LLMFailureComment:
+			postComment = true
+		case LLMFailureLabel:
+			addLabel = true
+		}
+	}
+	return postComment, addLabel
+}
+
+// NeedsHumanLabel returns the label added to issues that need human input,
+// or "" if LLM failures aren't labeled
+func (v *Validator) NeedsHumanLabel() string {
+	if _, addLabel := v.llmFailureActions(); addLabel {
+		return NeedsFormatLabel
+	}
+	return ""
+}
+
+// RuleResult is the outcome of evaluating a single format rule against an issue
+type RuleResult struct {
+	Rule      string // Human-readable rule, e.g. "Required section: Description"
+	Source    string // Where the rule came from: "defaults", "guidelines" or a profile
+	Passed    bool
+	Evidence  string // What was found in the issue
+	Violation string // Violation message when the rule failed
+	Kind      ViolationKind
+	Severity  Severity
+	Label     string // Label prefix checked, for label rules
+}
+
+func (v *Validator) checkFormat(issue *github.Issue) []Violation {
+	var violations []Violation
+	for _, result := range v.evaluateRules(issue) {
+		if !result.Passed {
+			violations = append(violations, Violation{Message: result.Violation, Severity: result.Severity, Kind: result.Kind, Label: result.Label})
+		}
+	}
+	return violations
+}
+
+// evaluateRules checks every configured rule and records the evidence for each
+func (v *Validator) evaluateRules(issue *github.Issue) []RuleResult {
+	var results []RuleResult
+
+	// The agent notice lists the violations it fixed, so it must not count
+	// towards the body's length or sections
+	body := v.removeExistingAgentNotice(issue.Body)
+
+	// Check title pattern
+	if v.rules.TitlePattern != "" {
+		titleResult := RuleResult{
+			Kind:     KindTitle,
+			Severity: SeverityWarning, // The agent never renames issues
+			Rule:     fmt.Sprintf("Title matches %s", v.rules.TitlePattern),
+			Source:   v.ruleSource(v.guidelines != nil && v.guidelines.FormatRules.TitlePattern != ""),
+			Evidence: fmt.Sprintf("title is %q", issue.Title),
+		}
+		if v.titlePattern == nil {
+			// Invalid, which setRules logged; never blame the issue for it
+			titleResult.Passed = true
+			titleResult.Evidence = "skipped: invalid pattern"
+		} else if v.titlePattern.MatchString(issue.Title) {
+			titleResult.Passed = true
+		} else {
+			titleResult.Violation = fmt.Sprintf("Title does not match required pattern %s", v.rules.TitlePattern)
+		}
+		results = append(results, titleResult)
+	}
 
 	// Check description length
-	if len(issue.Body) < v.rules.MinDescriptionLength {
-		violations = append(violations, fmt.Sprintf("Description too short (minimum %d characters)", v.rules.MinDescriptionLength))
+	lengthResult := RuleResult{
+		Kind:     KindLength,
+		Severity: SeverityError,
+		Rule:     fmt.Sprintf("Min description length %d", v.rules.MinDescriptionLength),
+		Source:   v.ruleSource(v.guidelines != nil && v.guidelines.FormatRules.MinDescriptionLength > 0),
+		Passed:   len(body) >= v.rules.MinDescriptionLength,
+		Evidence: fmt.Sprintf("body has %d chars", len(body)),
 	}
+	if !lengthResult.Passed {
+		lengthResult.Violation = fmt.Sprintf("Description too short (minimum %d characters)", v.rules.MinDescriptionLength)
+	}
+	results = append(results, lengthResult)
 
 	// Check required sections
-	bodyLower := strings.ToLower(issue.Body)
+	sectionSource := v.ruleSource(v.guidelines != nil && len(v.guidelines.FormatRules.RequiredSections) > 0)
 	for _, section := range v.rules.RequiredSections {
-		if !strings.Contains(bodyLower, strings.ToLower(section)) {
-			violations = append(violations, fmt.Sprintf("Missing required section: %s", section))
+		sectionResult := RuleResult{
+			Kind:     KindSection,
+			Severity: SeverityError,
+			Rule:     fmt.Sprintf("Required section: %s", section),
+			Source:   sectionSource,
+		}
+		if heading, ok := markdown.FindSection(body, section.Name, section.Aliases...); ok {
+			sectionResult.Passed = true
+			sectionResult.Evidence = fmt.Sprintf("FOUND at line %d", heading.Line)
+			if len(section.Aliases) > 0 {
+				sectionResult.Evidence = fmt.Sprintf("FOUND as %q at line %d", heading.Text, heading.Line)
+			}
+		} else {
+			sectionResult.Evidence = "no heading found in body"
+			sectionResult.Violation = fmt.Sprintf("Missing required section: %s", section.Name)
 		}
+		results = append(results, sectionResult)
 	}
 
 	// Check labels if required
 	if v.rules.RequireLabels {
-		hasPriorityLabel := false
+		labelResult := RuleResult{
+			Kind:     KindLabel,
+			Severity: SeverityError,
+			Label:    v.rules.LabelPrefix,
+			Rule:     fmt.Sprintf("Priority label starting with '%s'", v.rules.LabelPrefix),
+			Source:   v.ruleSource(v.guidelines != nil && v.guidelines.FormatRules.RequireLabels),
+		}
 		for _, label := range issue.Labels {
 			if strings.HasPrefix(label, v.rules.LabelPrefix) {
-				hasPriorityLabel = true
+				labelResult.Passed = true
+				labelResult.Evidence = fmt.Sprintf("has '%s'", label)
 				break
 			}
 		}
-		if !hasPriorityLabel {
-			violations = append(violations, fmt.Sprintf("Missing priority label (should start with '%s')", v.rules.LabelPrefix))
+		if !labelResult.Passed {
+			labelResult.Evidence = fmt.Sprintf("labels: [%s]", strings.Join(issue.Labels, ", "))
+			labelResult.Violation = fmt.Sprintf("Missing priority label (should start with '%s')", v.rules.LabelPrefix)
 		}
+		results = append(results, labelResult)
 	}
 
-	return violations
+	// Check label requirements from the guidelines (e.g. type:, team:)
+	for _, req := range v.labelRequirements() {
+		results = append(results, v.evaluateLabelRequirement(issue, req)...)
+	}
+
+	return results
+}
+
+// labelRequirements returns the guidelines' label requirements, one per
+// label type; a later requirement (e.g. from a profile) replaces an earlier one
+func (v *Validator) labelRequirements() []guidelines.LabelRequirement {
+	if v.guidelines == nil {
+		return nil
+	}
+
+	var reqs []guidelines.LabelRequirement
+	index := make(map[string]int)
+	for _, req := range v.guidelines.FormatRules.LabelRequirements {
+		if req.Type == "" {
+			continue
+		}
+		if i, ok := index[req.Type]; ok {
+			reqs[i] = req
+			continue
+		}
+		index[req.Type] = len(reqs)
+		reqs = append(reqs, req)
+	}
+	return reqs
+}
+
+// evaluateLabelRequirement checks that a required label type is present and
+// that labels of that type use an allowed value
+func (v *Validator) evaluateLabelRequirement(issue *github.Issue, req guidelines.LabelRequirement) []RuleResult {
+	prefix := req.Type + ":"
+	var values []string
+	for _, label := range issue.Labels {
+		if strings.HasPrefix(strings.ToLower(label), strings.ToLower(prefix)) {
+			values = append(values, label)
+		}
+	}
+
+	var results []RuleResult
+
+	// The priority label rule already reports a missing priority label
+	duplicate := v.rules.RequireLabels && strings.EqualFold(prefix, v.rules.LabelPrefix)
+	if req.Required && !duplicate {
+		result := RuleResult{
+			Kind:     KindLabel,
+			Severity: SeverityError,
+			Label:    prefix,
+			Rule:     fmt.Sprintf("Required label starting with '%s'", prefix),
+			Source:   v.ruleSource(true),
+			Passed:   len(values) > 0,
+		}
+		if result.Passed {
+			result.Evidence = fmt.Sprintf("has '%s'", values[0])
+		} else {
+			result.Evidence = fmt.Sprintf("labels: [%s]", strings.Join(issue.Labels, ", "))
+			result.Violation = fmt.Sprintf("Missing required %s label (should start with '%s')", req.Type, prefix)
+		}
+		results = append(results, result)
+	}
+
+	allowed := allowedLabelValues(req.AllowedValues, prefix)
+	if len(allowed) > 0 && len(values) > 0 {
+		result := RuleResult{
+			Kind:     KindLabel,
+			Severity: SeverityError,
+			Label:    prefix,
+			Rule:     fmt.Sprintf("'%s' labels use one of: %s", prefix, strings.Join(allowed, ", ")),
+			Source:   v.ruleSource(true),
+			Passed:   true,
+			Evidence: fmt.Sprintf("has [%s]", strings.Join(values, ", ")),
+		}
+		for _, label := range values {
+			if !containsFold(allowed, label[len(prefix):]) {
+				result.Passed = false
+				result.Violation = fmt.Sprintf("Label '%s' is not allowed (%s labels must be one of: %s)", label, req.Type, strings.Join(allowed, ", "))
+				break
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// allowedLabelValues normalizes the allowed values of a label requirement,
+// which guidelines may write as "bug", "`bug`" or "type:bug"
+func allowedLabelValues(values []string, prefix string) []string {
+	var allowed []string
+	for _, value := range values {
+		value = strings.Trim(strings.TrimSpace(value), "`\"'")
+		if len(value) >= len(prefix) && strings.EqualFold(value[:len(prefix)], prefix) {
+			value = value[len(prefix):]
+		}
+		if value != "" {
+			allowed = append(allowed, value)
+		}
+	}
+	return allowed
+}
+
+// containsFold reports whether values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleSource describes where a rule came from for explanations
+func (v *Validator) ruleSource(fromGuidelines bool) string {
+	if !fromGuidelines {
+		return "defaults"
+	}
+	if len(v.appliedProfiles) > 0 {
+		return fmt.Sprintf("guidelines + profile %s", strings.Join(v.appliedProfiles, ", "))
+	}
+	return "guidelines"
 }
 
 func (v *Validator) fixWithLLM(ctx context.Context, issue *github.Issue, violations []string) (string, error) {
@@ -142,14 +721,17 @@ func (v *Validator) fixWithLLM(ctx context.Context, issue *github.Issue, violati
 			"Body":                 issue.Body,
 			"Violations":           violations,
 			"MinDescriptionLength": v.rules.MinDescriptionLength,
-			"RequiredSections":     strings.Join(v.rules.RequiredSections, ", "),
+			"RequiredSections":     strings.Join(guidelines.SectionNames(v.rules.RequiredSections), ", "),
 			"LabelPrefix":          v.rules.LabelPrefix,
 			"Guidelines":           guidelinesText,
 			"Instructions":         instructionsText,
+			"BodyTemplate":         v.rules.BodyTemplate,
 		}
 
 		rendered, err := v.promptLoader.Render("validator", data)
-		if err == nil {
+		if err != nil {
+			slog.Warn("failed to render prompt template, using the built-in prompt", "template", "validator", "error", err)
+		} else {
 			prompt = rendered
 		}
 	}
@@ -163,6 +745,10 @@ func (v *Validator) fixWithLLM(ctx context.Context, issue *github.Issue, violati
 				guidelinesText = fmt.Sprintf("\n\nInstructions:\n%s", v.guidelines.Instructions)
 			}
 		}
+		templateText := ""
+		if v.rules.BodyTemplate != "" {
+			templateText = fmt.Sprintf("\n\nRequired body template (keep every heading, in this order, and only write prose under each heading):\n%s", v.rules.BodyTemplate)
+		}
 
 		prompt = fmt.Sprintf(`You are a task format enforcer for a GitHub project. Fix the following task to comply with the format guidelines.%s
 
@@ -176,7 +762,7 @@ Format violations:
 Required format:
 - Description: At least %d characters
 - Required sections: %s
-- Priority label: Must have a label starting with "%s"
+- Priority label: Must have a label starting with "%s"%s
 
 Please rewrite the task body to fix all violations while preserving the original intent and information. Return ONLY the fixed body text, no explanations.`,
 			guidelinesText,
@@ -184,12 +770,16 @@ Please rewrite the task body to fix all violations while preserving the original
 			issue.Body,
 			strings.Join(violations, "\n"),
 			v.rules.MinDescriptionLength,
-			strings.Join(v.rules.RequiredSections, ", "),
+			strings.Join(guidelines.SectionNames(v.rules.RequiredSections), ", "),
 			v.rules.LabelPrefix,
+			templateText,
 		)
 	}
 
-	fixedBody, err := v.llmClient.Prompt(prompt)
+	fixedBody, err := v.llmClient.PromptWithOptionsContext(ctx, prompt, llm.ChatOptions{
+		Temperature: validatorTemperature,
+		System:      validatorSystemPrompt,
+	})
 	if err != nil {
 		return "", err
 	}
@@ -203,18 +793,187 @@ Please rewrite the task body to fix all violations while preserving the original
 		}
 	}
 
-	return fixedBody, nil
+	return applyBodyTemplate(fixedBody, v.rules.BodyTemplate), nil
+}
+
+var markdownHeading = regexp.MustCompile(`^#{1,6}\s+\S`)
+
+// applyBodyTemplate appends every heading of template missing from body,
+// with the template's placeholder text, so the structure doesn't depend on
+// the LLM following the skeleton
+func applyBodyTemplate(body, template string) string {
+	if template == "" {
+		return body
+	}
+
+	present := make(map[string]bool)
+	for _, line := range strings.Split(body, "\n") {
+		if markdownHeading.MatchString(strings.TrimSpace(line)) {
+			present[headingText(line)] = true
+		}
+	}
+
+	var missing []string
+	var current []string
+	flush := func() {
+		if len(current) > 0 && !present[headingText(current[0])] {
+			missing = append(missing, strings.TrimSpace(strings.Join(current, "\n")))
+		}
+		current = nil
+	}
+	for _, line := range strings.Split(template, "\n") {
+		if markdownHeading.MatchString(strings.TrimSpace(line)) {
+			flush()
+		}
+		if current != nil || markdownHeading.MatchString(strings.TrimSpace(line)) {
+			current = append(current, line)
+		}
+	}
+	flush()
+
+	if len(missing) == 0 {
+		return body
+	}
+	return strings.TrimSpace(body) + "\n\n" + strings.Join(missing, "\n\n")
+}
+
+// headingText returns a markdown heading without its level, for comparison
+func headingText(line string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#")))
+}
+
+// validatorTemperature keeps rewrites close to deterministic
+const validatorTemperature = 0.1
+
+// validatorSystemPrompt keeps the model to returning just the rewritten body
+const validatorSystemPrompt = "You are a strict GitHub task formatter; output only the corrected body."
+
+// maxIssueBodyLength is GitHub's limit on issue body size
+var maxIssueBodyLength = 65536
+
+// truncatedNotice ends a preserved original that was cut to fit the size limit
+const truncatedNotice = "\n\n_… original content truncated to fit GitHub's issue size limit_"
+
+// Markers around the notice the agent adds to issue bodies it rewrites
+const (
+	agentNoticeStart = "<!-- 🤖 Agent Modified -->"
+	agentNoticeEnd   = "<!-- /Agent Modified -->"
+)
+
+// agentNoticeViolations records, hidden inside the notice, the violations a
+// rewrite tried to fix as a JSON list
+const agentNoticeViolations = "<!-- agent-violations: %s -->"
+
+var agentNoticeViolationsPattern = regexp.MustCompile(`<!-- agent-violations: (\[.*?\]) -->`)
+
+// manualAttentionMarker tags the comment asking a human to take over an issue
+// the agent couldn't fix, so it is posted only once
+const manualAttentionMarker = "<!-- agent-needs-manual-attention -->"
+
+// formatNoticeMarker tags the comment reporting violations the agent doesn't
+// fix itself, so the same violations are reported only once
+const formatNoticeMarker = "<!-- agent-format-notice -->"
+
+// violationsComment renders the hidden list of violations for the notice.
+// JSON escapes < and >, so messages can't end the HTML comment early.
+func violationsComment(violations []string) string {
+	data, _ := json.Marshal(violations)
+	return fmt.Sprintf(agentNoticeViolations, data)
+}
+
+// noticeViolations returns the violations recorded in the agent notice of
+// body, if any
+func noticeViolations(body string) ([]string, bool) {
+	match := agentNoticeViolationsPattern.FindStringSubmatch(body)
+	if match == nil {
+		return nil, false
+	}
+	var violations []string
+	if err := json.Unmarshal([]byte(match[1]), &violations); err != nil {
+		return nil, false
+	}
+	return violations, true
+}
+
+// containsAll reports whether every item of subset is in set
+func containsAll(set, subset []string) bool {
+	present := make(map[string]bool, len(set))
+	for _, item := range set {
+		present[item] = true
+	}
+	for _, item := range subset {
+		if !present[item] {
+			return false
+		}
+	}
+	return true
+}
+
+// gaveUpOn reports whether a manual attention comment on issue already
+// covers violations
+func (v *Validator) gaveUpOn(ctx context.Context, issue *github.Issue, violations []string) bool {
+	return v.commentedOn(ctx, issue, manualAttentionMarker, violations)
+}
+
+// commentedOn reports whether a comment on issue tagged with marker already
+// lists violations
+func (v *Validator) commentedOn(ctx context.Context, issue *github.Issue, marker string, violations []string) bool {
+	owner, repo := github.ParseRepoFromURL(issue.URL)
+	comments, err := v.githubClient.GetIssueComments(ctx, owner, repo, issue.Number)
+	if err != nil {
+		slog.Warn("failed to read comments", "issue", issue.Number, "error", err)
+		return false
+	}
+	for _, comment := range comments {
+		if !strings.Contains(comment.Body, marker) {
+			continue
+		}
+		if previous, ok := noticeViolations(comment.Body); ok && containsAll(previous, violations) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestManualAttention reports violations the agent already failed to fix.
+// The comment is posted at most once per issue.
+func (v *Validator) requestManualAttention(ctx context.Context, result *ValidationResult) error {
+	issue := result.Issue
+	result.NeedsHuman = true
+
+	owner, repo := github.ParseRepoFromURL(issue.URL)
+	comments, err := v.githubClient.GetIssueComments(ctx, owner, repo, issue.Number)
+	if err != nil {
+		return fmt.Errorf("failed to read comments: %w", err)
+	}
+	for _, comment := range comments {
+		if strings.Contains(comment.Body, manualAttentionMarker) {
+			return nil
+		}
+	}
+
+	comment := v.options.Identity.Format("", fmt.Sprintf("This task still needs manual attention: I already tried to fix it, but it doesn't follow our format guidelines yet.\n\nPlease address:\n%s\n\n%s\n%s",
+		formatViolations(result.Violations), manualAttentionMarker, violationsComment(violationMessages(result.Violations))))
+	if err := addComment(ctx, v.githubClient, owner, repo, issue.Number, comment); err != nil {
+		return fmt.Errorf("failed to add comment: %w", err)
+	}
+	result.Comment = comment
+	return nil
+}
+
+// agentNoticeStartVariants are the start markers recognized when stripping a
+// previous notice, including those written by older versions
+var agentNoticeStartVariants = []string{
+	agentNoticeStart,
+	"<!--  Agent Modified -->",
+	"<!-- Agent Modified -->",
 }
 
 // preserveOriginalWithModifications preserves the original issue body and adds
 // a clear indication of what was modified by the agent
 func (v *Validator) preserveOriginalWithModifications(originalBody, fixedBody string, violations []string) string {
-	// Check if the body already has an agent modification notice
-	agentNoticeStart := "<!-- 🤖 Agent Modified -->"
-	agentNoticeEnd := "<!-- /Agent Modified -->"
-
 	// Remove any existing agent notice from original body
-	cleanedOriginal := v.removeExistingAgentNotice(originalBody, agentNoticeStart, agentNoticeEnd)
+	cleanedOriginal := v.removeExistingAgentNotice(originalBody)
 
 	// Create the modification notice
 	violationsList := ""
@@ -223,7 +982,9 @@ func (v *Validator) preserveOriginalWithModifications(originalBody, fixedBody st
 	}
 
 	// Format: Agent notice at top (collapsible), then fixed content, then original preserved
-	modificationNotice := fmt.Sprintf(`%s
+	compose := func(original string) string {
+		return fmt.Sprintf(`%s
+%s
 <details>
 <summary>🤖 <strong>Automatically modified by Agent</strong> - Click to see what changed</summary>
 
@@ -246,35 +1007,57 @@ This issue was automatically updated to comply with format guidelines.
 %s
 
 </details>
-`, agentNoticeStart, violationsList, agentNoticeEnd, fixedBody, cleanedOriginal)
+`, agentNoticeStart, violationsComment(violations), violationsList, agentNoticeEnd, fixedBody, original)
+	}
+
+	modificationNotice := compose(cleanedOriginal)
+	if overflow := len(modificationNotice) - maxIssueBodyLength; overflow > 0 {
+		// Trim the preserved original, which is the least important part
+		keep := len(cleanedOriginal) - overflow - len(truncatedNotice)
+		if keep < 0 {
+			keep = 0
+		}
+		for keep > 0 && !utf8.RuneStart(cleanedOriginal[keep]) {
+			keep--
+		}
+		modificationNotice = compose(cleanedOriginal[:keep] + truncatedNotice)
+	}
 
 	return modificationNotice
 }
 
-// removeExistingAgentNotice removes any existing agent modification notice
-func (v *Validator) removeExistingAgentNotice(body, startMarker, endMarker string) string {
-	startIdx := strings.Index(body, startMarker)
-	if startIdx == -1 {
-		return body // No existing notice
-	}
+// removeExistingAgentNotice removes any existing agent modification notices,
+// whichever marker variant they were written with
+func (v *Validator) removeExistingAgentNotice(body string) string {
+	for {
+		startIdx := -1
+		for _, marker := range agentNoticeStartVariants {
+			if idx := strings.Index(body, marker); idx != -1 && (startIdx == -1 || idx < startIdx) {
+				startIdx = idx
+			}
+		}
+		if startIdx == -1 {
+			return body // No existing notice
+		}
 
-	endIdx := strings.Index(body[startIdx:], endMarker)
-	if endIdx == -1 {
-		return body // Malformed notice, keep as is
-	}
+		endIdx := strings.Index(body[startIdx:], agentNoticeEnd)
+		if endIdx == -1 {
+			return body // Malformed notice, keep as is
+		}
 
-	endIdx += startIdx + len(endMarker)
+		endIdx += startIdx + len(agentNoticeEnd)
 
-	// Remove the notice and any trailing newlines
-	before := strings.TrimRight(body[:startIdx], "\n")
-	after := strings.TrimLeft(body[endIdx:], "\n")
+		// Remove the notice and any trailing newlines
+		before := strings.TrimRight(body[:startIdx], "\n")
+		after := strings.TrimLeft(body[endIdx:], "\n")
 
-	if before == "" {
-		return after
-	}
-	if after == "" {
-		return before
+		switch {
+		case before == "":
+			body = after
+		case after == "":
+			body = before
+		default:
+			body = before + "\n\n" + after
+		}
 	}
-
-	return before + "\n\n" + after
 }
@@ -2,66 +2,21 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/kaskol10/github-project-agent/config"
 	"github.com/kaskol10/github-project-agent/github"
+	"github.com/kaskol10/github-project-agent/github/githubtest"
+	"github.com/kaskol10/github-project-agent/guidelines"
+	"github.com/kaskol10/github-project-agent/llm"
 )
 
-// mockLLMClient is a mock implementation of the LLM client for testing
-// We'll need to create a wrapper that matches the actual llm.Client structure
-// For now, we'll test without the LLM integration or use a different approach
-
-// mockGitHubClient is a mock implementation of the GitHub client for testing
-type mockGitHubClient struct {
-	updatedIssues map[int]*github.Issue
-	comments      map[int][]string
-}
-
-func newMockGitHubClient() *mockGitHubClient {
-	return &mockGitHubClient{
-		updatedIssues: make(map[int]*github.Issue),
-		comments:      make(map[int][]string),
-	}
-}
-
-func (m *mockGitHubClient) ListIssues(ctx context.Context, state string) ([]*github.Issue, error) {
-	return nil, nil
-}
-
-func (m *mockGitHubClient) GetIssue(ctx context.Context, owner, repo string, number int) (*github.Issue, error) {
-	return nil, nil
-}
-
-func (m *mockGitHubClient) UpdateIssue(ctx context.Context, owner, repo string, number int, title, body *string) error {
-	if m.updatedIssues[number] == nil {
-		m.updatedIssues[number] = &github.Issue{Number: number}
-	}
-	if body != nil {
-		m.updatedIssues[number].Body = *body
-	}
-	if title != nil {
-		m.updatedIssues[number].Title = *title
-	}
-	return nil
-}
-
-func (m *mockGitHubClient) AddComment(ctx context.Context, owner, repo string, number int, comment string) error {
-	if m.comments[number] == nil {
-		m.comments[number] = []string{}
-	}
-	m.comments[number] = append(m.comments[number], comment)
-	return nil
-}
-
-func (m *mockGitHubClient) CreateIssue(ctx context.Context, owner, repo, title, body string, labels []string) (*github.Issue, error) {
-	return nil, nil
-}
-
-func (m *mockGitHubClient) GetMode() string {
-	return "repo"
-}
-
 func TestValidator_CheckFormat(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -77,7 +32,7 @@ func TestValidator_CheckFormat(t *testing.T) {
 				Labels: []string{"priority:high"},
 			},
 			rules: TaskFormatRules{
-				RequiredSections:     []string{"Description", "Acceptance Criteria"},
+				RequiredSections:     guidelines.Sections("Description", "Acceptance Criteria"),
 				MinDescriptionLength: 50,
 				RequireLabels:        true,
 				LabelPrefix:          "priority:",
@@ -92,7 +47,7 @@ func TestValidator_CheckFormat(t *testing.T) {
 				Labels: []string{"priority:high"},
 			},
 			rules: TaskFormatRules{
-				RequiredSections:     []string{"Description", "Acceptance Criteria"},
+				RequiredSections:     guidelines.Sections("Description", "Acceptance Criteria"),
 				MinDescriptionLength: 50,
 				RequireLabels:        true,
 				LabelPrefix:          "priority:",
@@ -111,7 +66,7 @@ func TestValidator_CheckFormat(t *testing.T) {
 				Labels: []string{"priority:high"},
 			},
 			rules: TaskFormatRules{
-				RequiredSections:     []string{"Description", "Acceptance Criteria"},
+				RequiredSections:     guidelines.Sections("Description", "Acceptance Criteria"),
 				MinDescriptionLength: 50,
 				RequireLabels:        true,
 				LabelPrefix:          "priority:",
@@ -129,7 +84,7 @@ func TestValidator_CheckFormat(t *testing.T) {
 				Labels: []string{"bug"},
 			},
 			rules: TaskFormatRules{
-				RequiredSections:     []string{"Description", "Acceptance Criteria"},
+				RequiredSections:     guidelines.Sections("Description", "Acceptance Criteria"),
 				MinDescriptionLength: 50,
 				RequireLabels:        true,
 				LabelPrefix:          "priority:",
@@ -146,7 +101,7 @@ func TestValidator_CheckFormat(t *testing.T) {
 				Labels: []string{},
 			},
 			rules: TaskFormatRules{
-				RequiredSections:     []string{"Description", "Acceptance Criteria"},
+				RequiredSections:     guidelines.Sections("Description", "Acceptance Criteria"),
 				MinDescriptionLength: 50,
 				RequireLabels:        true,
 				LabelPrefix:          "priority:",
@@ -157,15 +112,40 @@ func TestValidator_CheckFormat(t *testing.T) {
 				"Missing priority label (should start with 'priority:')",
 			},
 		},
+		{
+			name: "title matching required pattern",
+			issue: &github.Issue{
+				Title: "[infra] Service Mesh on K8s Cluster",
+				Body:  "Deploy a Service Mesh on K8s clusters with the aim to improve service-to-service communication reliability and observability.",
+			},
+			rules: TaskFormatRules{
+				MinDescriptionLength: 50,
+				TitlePattern:         `^\[(infra|api)\] `,
+			},
+			wantErrors: []string{},
+		},
+		{
+			name: "title not matching required pattern",
+			issue: &github.Issue{
+				Title: "Service Mesh on K8s Cluster",
+				Body:  "Deploy a Service Mesh on K8s clusters with the aim to improve service-to-service communication reliability and observability.",
+			},
+			rules: TaskFormatRules{
+				MinDescriptionLength: 50,
+				TitlePattern:         `^\[(infra|api)\] `,
+			},
+			wantErrors: []string{
+				`Title does not match required pattern ^\[(infra|api)\] `,
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			v := &Validator{
-				rules: tt.rules,
-			}
+			v := &Validator{}
+			v.setRules(tt.rules)
 
-			gotErrors := v.checkFormat(tt.issue)
+			gotErrors := violationMessages(v.checkFormat(tt.issue))
 
 			if len(gotErrors) != len(tt.wantErrors) {
 				t.Errorf("checkFormat() returned %d errors, want %d", len(gotErrors), len(tt.wantErrors))
@@ -189,6 +169,35 @@ func TestValidator_CheckFormat(t *testing.T) {
 	}
 }
 
+func TestTaskFormatRules_Validate(t *testing.T) {
+	if err := (TaskFormatRules{}).Validate(); err != nil {
+		t.Errorf("empty title pattern should disable the check, got %v", err)
+	}
+	if err := (TaskFormatRules{TitlePattern: `^\[api\]`}).Validate(); err != nil {
+		t.Errorf("valid title pattern rejected: %v", err)
+	}
+
+	err := (TaskFormatRules{TitlePattern: `^[api`}).Validate()
+	if err == nil {
+		t.Fatal("expected an error for an invalid title pattern")
+	}
+	if !strings.Contains(err.Error(), "invalid title pattern") {
+		t.Errorf("expected a clear config error, got %v", err)
+	}
+}
+
+func TestNewValidator_CompilesTitlePattern(t *testing.T) {
+	v := NewValidator(nil, nil, TaskFormatRules{TitlePattern: `^\[api\] `}, nil)
+	if v.titlePattern == nil || !v.titlePattern.MatchString("[api] Add endpoint") {
+		t.Fatalf("expected the title pattern compiled when the validator is built, got %v", v.titlePattern)
+	}
+
+	invalid := NewValidator(nil, nil, TaskFormatRules{TitlePattern: `^[api`}, nil)
+	if invalid.titlePattern != nil {
+		t.Errorf("expected no compiled pattern for an invalid one")
+	}
+}
+
 func TestValidator_PreserveOriginalWithModifications(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -288,12 +297,22 @@ func TestValidator_RemoveExistingAgentNotice(t *testing.T) {
 			body: "Content\n<!-- 🤖 Agent Modified -->\nNotice",
 			want: "Content\n<!-- 🤖 Agent Modified -->\nNotice", // Should remain unchanged
 		},
+		{
+			name: "legacy marker without emoji",
+			body: "<!--  Agent Modified -->\nNotice\n<!-- /Agent Modified -->\nContent",
+			want: "Content",
+		},
+		{
+			name: "multiple accumulated notices",
+			body: "<!-- 🤖 Agent Modified -->\nNew\n<!-- /Agent Modified -->\nContent\n<!-- Agent Modified -->\nOld\n<!-- /Agent Modified -->",
+			want: "Content",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			v := &Validator{}
-			got := v.removeExistingAgentNotice(tt.body, "<!-- 🤖 Agent Modified -->", "<!-- /Agent Modified -->")
+			got := v.removeExistingAgentNotice(tt.body)
 			if got != tt.want {
 				t.Errorf("removeExistingAgentNotice() = %q, want %q", got, tt.want)
 			}
@@ -301,18 +320,112 @@ func TestValidator_RemoveExistingAgentNotice(t *testing.T) {
 	}
 }
 
+// mockLLMClient answers every prompt with reply
+type mockLLMClient struct {
+	llm.LLMClient
+	reply   string
+	prompts []string
+}
+
+func (m *mockLLMClient) PromptWithOptionsContext(ctx context.Context, prompt string, opts llm.ChatOptions) (string, error) {
+	m.prompts = append(m.prompts, prompt)
+	return m.reply, nil
+}
+
 func TestValidator_ValidateAndFix_Integration(t *testing.T) {
-	// Skip integration test that requires LLM client
-	// This would require setting up a proper mock or test LLM service
-	// In a real scenario, you'd use an interface for the LLM client to enable mocking
-	t.Skip("Integration test requires LLM client interface for proper mocking")
+	mockGH := githubtest.NewFakeClient()
+	mockLLM := &mockLLMClient{
+		reply: "## Description\n\nLogin fails on mobile devices after the upgrade.\n\n## Acceptance Criteria\n\n- Users can log in on mobile",
+	}
+	v := NewValidator(mockGH, mockLLM, TaskFormatRules{
+		RequiredSections:     guidelines.Sections("Description", "Acceptance Criteria"),
+		MinDescriptionLength: 10,
+	}, nil)
+
+	issue := &github.Issue{Number: 1, Title: "Fix login", Body: "broken on mobile", URL: "https://github.com/o/r/issues/1"}
+	result, err := v.Validate(context.Background(), issue)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if len(mockLLM.prompts) != 1 || !strings.Contains(mockLLM.prompts[0], "broken on mobile") {
+		t.Fatalf("expected one rewrite prompt with the original body, got %q", mockLLM.prompts)
+	}
+	if !result.Fixed || result.FixFailed {
+		t.Errorf("expected the issue to be fixed, got %+v", result)
+	}
+	updated := mockGH.UpdatedIssues[1]
+	if updated == nil || !strings.Contains(updated.Body, "## Acceptance Criteria") {
+		t.Fatalf("expected the rewritten body to be saved, got %+v", updated)
+	}
+	if len(mockGH.Comments[1]) != 1 {
+		t.Errorf("expected one comment explaining the fix, got %v", mockGH.Comments[1])
+	}
+}
+
+func TestValidator_CheckFormat_SectionHeadings(t *testing.T) {
+	v := &Validator{rules: TaskFormatRules{RequiredSections: guidelines.Sections("Description")}}
+
+	mention := &github.Issue{Body: "The description of this bug is that login fails on mobile."}
+	if violations := v.checkFormat(mention); len(violations) != 1 || violations[0].Message != "Missing required section: Description" {
+		t.Errorf("expected an inline mention not to count as the section, got %v", violations)
+	}
+
+	heading := &github.Issue{Body: "### Description\n\nLogin fails on mobile."}
+	if violations := v.checkFormat(heading); len(violations) != 0 {
+		t.Errorf("expected a heading to satisfy the section, got %v", violations)
+	}
+}
+
+func TestValidator_CheckFormat_SectionAliases(t *testing.T) {
+	v := &Validator{rules: TaskFormatRules{RequiredSections: []guidelines.Section{
+		{Name: "Acceptance Criteria", Aliases: []string{"AC", "Definition of Done"}},
+	}}}
+
+	alias := &github.Issue{Body: "## Definition of Done\n\n- Users can log in on mobile"}
+	if violations := v.checkFormat(alias); len(violations) != 0 {
+		t.Errorf("expected an alias heading to satisfy the section, got %v", violations)
+	}
+	if evidence := v.evaluateRules(alias)[1].Evidence; evidence != `FOUND as "Definition of Done" at line 1` {
+		t.Errorf("unexpected evidence %q", evidence)
+	}
+
+	missing := &github.Issue{Body: "## Notes\n\nThe AC are in the linked doc."}
+	if violations := v.checkFormat(missing); len(violations) != 1 || violations[0].Message != "Missing required section: Acceptance Criteria" {
+		t.Errorf("expected the section to be reported by name, got %v", violations)
+	}
+}
+
+func TestValidator_Preview(t *testing.T) {
+	mockGH := githubtest.NewFakeClient()
+	mockLLM := &mockLLMClient{
+		reply: "## Description\n\nLogin fails on mobile devices after the upgrade.\n\n## Acceptance Criteria\n\n- Users can log in on mobile",
+	}
+	v := NewValidator(mockGH, mockLLM, TaskFormatRules{
+		RequiredSections:     guidelines.Sections("Description", "Acceptance Criteria"),
+		MinDescriptionLength: 10,
+	}, nil)
+
+	issue := &github.Issue{Number: 1, Title: "Fix login", Body: "broken on mobile", URL: "https://github.com/o/r/issues/1"}
+	preview, err := v.Preview(context.Background(), issue)
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if !preview.Changed() || !strings.Contains(preview.Proposed, "## Acceptance Criteria") || preview.Original != "broken on mobile" {
+		t.Errorf("unexpected preview %+v", preview)
+	}
+	if len(preview.Violations) != 2 || len(preview.Remaining) != 0 {
+		t.Errorf("expected 2 violations and none remaining, got %v and %v", preview.Violations, preview.Remaining)
+	}
+	if len(mockGH.CallsTo("UpdateIssue")) != 0 || len(mockGH.CallsTo("AddComment")) != 0 {
+		t.Errorf("expected no writes, got calls %+v", mockGH.Calls)
+	}
 }
 
 func TestValidator_ValidateAndFix_ValidIssue(t *testing.T) {
-	mockGH := newMockGitHubClient()
+	mockGH := githubtest.NewFakeClient()
 	
 	rules := TaskFormatRules{
-		RequiredSections:     []string{"Description", "Acceptance Criteria"},
+		RequiredSections:     guidelines.Sections("Description", "Acceptance Criteria"),
 		MinDescriptionLength: 50,
 		RequireLabels:        true,
 		LabelPrefix:          "priority:",
@@ -340,3 +453,743 @@ func TestValidator_ValidateAndFix_ValidIssue(t *testing.T) {
 	}
 }
 
+func TestValidator_Explain(t *testing.T) {
+	v := NewValidator(githubtest.NewFakeClient(), nil, TaskFormatRules{
+		RequiredSections:     guidelines.Sections("Description", "Acceptance Criteria"),
+		MinDescriptionLength: 10,
+		RequireLabels:        true,
+		LabelPrefix:          "priority:",
+	}, nil)
+
+	issue := &github.Issue{
+		Number: 1,
+		Body:   "## Description\nSomething is broken",
+		Labels: []string{"priority:high"},
+	}
+
+	explanation := v.Explain(issue)
+	if len(explanation.Results) != 4 {
+		t.Fatalf("expected 4 rule results, got %d", len(explanation.Results))
+	}
+	if explanation.Failed() != 1 {
+		t.Errorf("expected 1 failed rule, got %d", explanation.Failed())
+	}
+
+	section := explanation.Results[1]
+	if !section.Passed || section.Evidence != "FOUND at line 1" || section.Source != "defaults" {
+		t.Errorf("unexpected description result: %+v", section)
+	}
+	criteria := explanation.Results[2]
+	if criteria.Passed || criteria.Violation != "Missing required section: Acceptance Criteria" {
+		t.Errorf("unexpected acceptance criteria result: %+v", criteria)
+	}
+	label := explanation.Results[3]
+	if !label.Passed || label.Evidence != "has 'priority:high'" {
+		t.Errorf("unexpected label result: %+v", label)
+	}
+}
+
+func TestValidator_ValidateAndFix_LLMFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	rules := TaskFormatRules{
+		RequiredSections:     guidelines.Sections("Description"),
+		MinDescriptionLength: 10,
+	}
+	issue := &github.Issue{Number: 7, Body: "too short"}
+
+	tests := []struct {
+		name        string
+		onFailure   string
+		wantErr     bool
+		wantComment bool
+		wantLabel   bool
+	}{
+		{name: "skip", onFailure: LLMFailureSkip, wantErr: true},
+		{name: "default", onFailure: "", wantErr: true},
+		{name: "comment", onFailure: LLMFailureComment, wantComment: true},
+		{name: "label", onFailure: LLMFailureLabel, wantLabel: true},
+		{name: "comment and label", onFailure: "comment,label", wantComment: true, wantLabel: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGH := githubtest.NewFakeClient()
+			llmClient := llm.NewClient(server.URL, "test-model", "", time.Second)
+			v := NewValidatorWithOptions(mockGH, llmClient, rules, nil, ValidatorOptions{OnLLMFailure: tt.onFailure})
+
+			valid, _, err := v.ValidateAndFix(context.Background(), issue)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateAndFix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if valid {
+				t.Error("ValidateAndFix() should not report an invalid issue as valid")
+			}
+			if len(mockGH.UpdatedIssues) != 0 {
+				t.Error("issue body should not be rewritten without the LLM")
+			}
+
+			comments := mockGH.Comments[issue.Number]
+			if tt.wantComment != (len(comments) == 1) {
+				t.Errorf("expected comment = %v, got %v", tt.wantComment, comments)
+			}
+			if tt.wantComment && !strings.Contains(comments[0], "Missing required section: Description") {
+				t.Errorf("comment should list violations, got: %s", comments[0])
+			}
+			if tt.wantLabel != github.HasLabels(mockGH.Labels[issue.Number], []string{NeedsFormatLabel}) {
+				t.Errorf("expected %s label = %v, got %v", NeedsFormatLabel, tt.wantLabel, mockGH.Labels[issue.Number])
+			}
+		})
+	}
+}
+
+func TestValidator_Estimate(t *testing.T) {
+	v := NewValidator(githubtest.NewFakeClient(), nil, TaskFormatRules{
+		RequiredSections:     guidelines.Sections("Description"),
+		MinDescriptionLength: 20,
+	}, nil)
+
+	issues := []*github.Issue{
+		{Number: 1, Title: "Valid", Body: "## Description\nEverything needed is here"},
+		{Number: 2, Title: "Short", Body: "todo"},
+		{Number: 3, Title: "Long", Body: strings.Repeat("no sections here ", 200)},
+	}
+
+	estimate := v.Estimate(issues)
+	if estimate.Checked != 3 {
+		t.Errorf("Checked = %d, want 3", estimate.Checked)
+	}
+	if estimate.Calls() != 2 {
+		t.Fatalf("Calls() = %d, want 2", estimate.Calls())
+	}
+	if estimate.Issues[0].Issue.Number != 2 || estimate.Issues[1].Issue.Number != 3 {
+		t.Errorf("unexpected issues in estimate: #%d, #%d", estimate.Issues[0].Issue.Number, estimate.Issues[1].Issue.Number)
+	}
+	if estimate.Issues[0].CompletionTokens != minCompletionTokens {
+		t.Errorf("short issue completion tokens = %d, want %d", estimate.Issues[0].CompletionTokens, minCompletionTokens)
+	}
+	if estimate.Issues[1].PromptTokens <= estimate.Issues[0].PromptTokens {
+		t.Error("longer issue should need more prompt tokens")
+	}
+	if cost := estimate.Cost(1.0); cost != float64(estimate.Tokens())/1000 {
+		t.Errorf("Cost(1.0) = %f, want %f", cost, float64(estimate.Tokens())/1000)
+	}
+}
+
+func TestValidator_PostReport(t *testing.T) {
+	mockGH := githubtest.NewFakeClient()
+	v := NewValidatorWithOptions(mockGH, nil, TaskFormatRules{
+		RequiredSections:     guidelines.Sections("Description"),
+		MinDescriptionLength: 10,
+	}, nil, ValidatorOptions{Output: ValidateOutputReport})
+
+	issues := []*github.Issue{
+		{Number: 2, Title: "Short", Body: "todo", URL: "https://github.com/o/r/issues/2"},
+		{Number: 1, Title: "Valid", Body: "## Description\nAll good here", URL: "https://github.com/o/r/issues/1"},
+		// Agents' own issues are neither listed nor counted
+		{Number: 3, Title: "Stale digest", Body: "todo", Labels: []string{AgentGeneratedLabel, "stale-digest"}, URL: "https://github.com/o/r/issues/3"},
+	}
+
+	count, err := v.PostReport(context.Background(), issues)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 non-compliant issue, got %d", count)
+	}
+	if len(mockGH.CreatedIssues) != 1 {
+		t.Fatalf("expected one report issue, got %d", len(mockGH.CreatedIssues))
+	}
+	report := mockGH.CreatedIssues[0]
+	for _, want := range []string{"**1 of 2 open issues**", "#2 Short", "Missing required section: Description"} {
+		if !strings.Contains(report.Body, want) {
+			t.Errorf("report missing %q:\n%s", want, report.Body)
+		}
+	}
+	if strings.Contains(report.Body, "#1 Valid") || strings.Contains(report.Body, "#3 Stale digest") {
+		t.Error("report should not list compliant or agent-generated issues")
+	}
+	if len(mockGH.Comments) != 0 || len(mockGH.UpdatedIssues) != 0 {
+		t.Error("report mode should not comment on or edit issues")
+	}
+
+	// Second run updates the existing report instead of creating another
+	report.URL = "https://github.com/o/r/issues/1000"
+	if _, err := v.PostReport(context.Background(), append(issues, report)); err != nil {
+		t.Fatal(err)
+	}
+	if len(mockGH.CreatedIssues) != 1 {
+		t.Errorf("expected report to be updated in place, got %d created issues", len(mockGH.CreatedIssues))
+	}
+	if updated, ok := mockGH.UpdatedIssues[report.Number]; !ok || !strings.Contains(updated.Body, "#2 Short") || !strings.Contains(updated.Body, "**1 of 2 open issues**") {
+		t.Errorf("expected existing report issue to be updated, counting only people's issues")
+	}
+}
+
+func TestValidator_PreserveOriginalTwiceKeepsOneNotice(t *testing.T) {
+	v := &Validator{}
+	first := v.preserveOriginalWithModifications("Short body", "## Description\n\nFixed once", []string{"Missing required section: Description"})
+	second := v.preserveOriginalWithModifications(first, "## Description\n\nFixed twice", []string{"Missing priority label"})
+
+	if n := strings.Count(second, agentNoticeStart); n != 1 {
+		t.Errorf("expected 1 notice start marker, got %d:\n%s", n, second)
+	}
+	if n := strings.Count(second, agentNoticeEnd); n != 1 {
+		t.Errorf("expected 1 notice end marker, got %d:\n%s", n, second)
+	}
+	if strings.Contains(second, "Missing required section") {
+		t.Error("previous notice's violations should be stripped")
+	}
+
+	// Bodies rewritten by older versions used a marker without the emoji
+	legacy := strings.Replace(first, agentNoticeStart, "<!--  Agent Modified -->", 1)
+	third := v.preserveOriginalWithModifications(legacy, "## Description\n\nFixed again", []string{"Missing priority label"})
+	if strings.Contains(third, "<!--  Agent Modified -->") || strings.Count(third, agentNoticeEnd) != 1 {
+		t.Errorf("legacy notice should be replaced:\n%s", third)
+	}
+}
+
+func TestValidator_CheckFormat_Severity(t *testing.T) {
+	v := &Validator{}
+	v.setRules(TaskFormatRules{
+		RequiredSections:     guidelines.Sections("Description"),
+		MinDescriptionLength: 50,
+		RequireLabels:        true,
+		LabelPrefix:          "priority:",
+		TitlePattern:         `^\[api\] `,
+	})
+
+	violations := v.checkFormat(&github.Issue{Title: "Fix login", Body: "short"})
+	want := map[ViolationKind]Severity{
+		KindTitle:   SeverityWarning,
+		KindLength:  SeverityError,
+		KindSection: SeverityError,
+		KindLabel:   SeverityError,
+	}
+	if len(violations) != len(want) {
+		t.Fatalf("expected %d violations, got %v", len(want), violations)
+	}
+	for _, violation := range violations {
+		if want[violation.Kind] != violation.Severity {
+			t.Errorf("%s violation has severity %s, want %s", violation.Kind, violation.Severity, want[violation.Kind])
+		}
+	}
+	if !needsRewrite(violations) {
+		t.Error("body errors should need a rewrite")
+	}
+}
+
+func TestValidator_Validate_WithoutRewrite(t *testing.T) {
+	var llmCalls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		llmCalls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	rules := TaskFormatRules{
+		RequiredSections:     guidelines.Sections("Description"),
+		MinDescriptionLength: 10,
+		RequireLabels:        true,
+		LabelPrefix:          "priority:",
+		TitlePattern:         `^\[api\] `,
+		DefaultPriorityLabel: "priority:unset",
+	}
+	body := "## Description\n\nLogin fails on mobile."
+
+	tests := []struct {
+		name           string
+		issue          *github.Issue
+		wantLabels     []string
+		wantFixed      bool
+		wantNeedsHuman bool
+		wantComment    string
+	}{
+		{
+			name:        "missing priority label only",
+			issue:       &github.Issue{Number: 1, Title: "[api] Fix login", Body: body},
+			wantLabels:  []string{"priority:unset"},
+			wantFixed:   true,
+			wantComment: "`priority:unset`",
+		},
+		{
+			// A warning alone isn't worth a comment
+			name:  "title warning only",
+			issue: &github.Issue{Number: 2, Title: "Fix login", Body: body, Labels: []string{"priority:high"}},
+		},
+		{
+			name:        "label and title",
+			issue:       &github.Issue{Number: 3, Title: "Fix login", Body: body},
+			wantLabels:  []string{"priority:unset"},
+			wantFixed:   true,
+			wantComment: "`priority:unset`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGH := githubtest.NewFakeClient()
+			llmClient := llm.NewClient(server.URL, "test-model", "", time.Second)
+			v := NewValidator(mockGH, llmClient, rules, nil)
+
+			result, err := v.Validate(context.Background(), tt.issue)
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if llmCalls != 0 {
+				t.Errorf("expected no LLM calls, got %d", llmCalls)
+			}
+			if len(mockGH.UpdatedIssues) != 0 {
+				t.Error("issue body should not be rewritten")
+			}
+			if !reflect.DeepEqual(mockGH.Labels[tt.issue.Number], tt.wantLabels) {
+				t.Errorf("labels = %v, want %v", mockGH.Labels[tt.issue.Number], tt.wantLabels)
+			}
+			if result.Fixed != tt.wantFixed || result.NeedsHuman != tt.wantNeedsHuman {
+				t.Errorf("Fixed = %v, NeedsHuman = %v, want %v, %v", result.Fixed, result.NeedsHuman, tt.wantFixed, tt.wantNeedsHuman)
+			}
+			comments := mockGH.Comments[tt.issue.Number]
+			if tt.wantComment == "" {
+				if len(comments) != 0 {
+					t.Errorf("expected no comment, got %v", comments)
+				}
+				return
+			}
+			if len(comments) != 1 || !strings.Contains(comments[0], tt.wantComment) {
+				t.Errorf("expected one comment containing %q, got %v", tt.wantComment, comments)
+			}
+		})
+	}
+}
+
+func TestValidator_FormatNoticePostedOnce(t *testing.T) {
+	rules := TaskFormatRules{
+		RequiredSections:     guidelines.Sections("Description"),
+		MinDescriptionLength: 10,
+		RequireLabels:        true,
+		LabelPrefix:          "priority:",
+	}
+	issue := &github.Issue{Number: 5, Title: "Fix login", Body: "## Description\n\nLogin fails on mobile.", URL: "https://github.com/o/r/issues/5"}
+
+	mockGH := githubtest.NewFakeClient()
+	v := NewValidator(mockGH, nil, rules, nil)
+	result, err := v.Validate(context.Background(), issue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.NeedsHuman || len(mockGH.Comments[5]) != 1 || !strings.Contains(mockGH.Comments[5][0], formatNoticeMarker) {
+		t.Fatalf("expected one tagged notice, got %v", mockGH.Comments[5])
+	}
+
+	// The next run finds the notice and doesn't repeat it
+	mockGH.IssueComments[5] = []github.Comment{{Body: mockGH.Comments[5][0]}}
+	result, err = v.Validate(context.Background(), issue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.NeedsHuman || len(mockGH.Comments[5]) != 1 {
+		t.Errorf("expected the notice not to be repeated, got %v", mockGH.Comments[5])
+	}
+
+	// A new violation is reported again
+	rules.TitlePattern = `^\[api\] `
+	result, err = NewValidator(mockGH, nil, rules, nil).Validate(context.Background(), issue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mockGH.Comments[5]) != 2 || !strings.Contains(result.Comment, "Title does not match") {
+		t.Errorf("expected a new notice listing the title warning, got %v", mockGH.Comments[5])
+	}
+}
+
+func TestValidator_DefaultPriorityLabel(t *testing.T) {
+	rules := TaskFormatRules{
+		RequiredSections:     guidelines.Sections("Description"),
+		MinDescriptionLength: 10,
+		RequireLabels:        true,
+		LabelPrefix:          "priority:",
+	}
+	issue := &github.Issue{Number: 4, Title: "Fix login", Body: "## Description\n\nLogin fails on mobile."}
+
+	// Without a default label the missing label is reported instead
+	mockGH := githubtest.NewFakeClient()
+	result, err := NewValidator(mockGH, nil, rules, nil).Validate(context.Background(), issue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mockGH.Labels[issue.Number]) != 0 || !result.NeedsHuman {
+		t.Errorf("expected the violation to be reported, got labels %v", mockGH.Labels[issue.Number])
+	}
+
+	// Guidelines override the configured default
+	mockGH = githubtest.NewFakeClient()
+	rules.DefaultPriorityLabel = "priority:unset"
+	gd := &guidelines.Guidelines{FormatRules: guidelines.FormatRules{DefaultPriorityLabel: "priority:triage"}}
+	result, err = NewValidator(mockGH, nil, rules, gd).Validate(context.Background(), issue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(mockGH.Labels[issue.Number], []string{"priority:triage"}) || !result.Fixed {
+		t.Errorf("expected priority:triage to be applied, got %v", mockGH.Labels[issue.Number])
+	}
+	if !strings.Contains(result.Comment, "default priority label `priority:triage`") {
+		t.Errorf("comment should explain the default, got %q", result.Comment)
+	}
+}
+
+func TestValidator_LabelRequirements(t *testing.T) {
+	gd := &guidelines.Guidelines{FormatRules: guidelines.FormatRules{
+		LabelRequirements: []guidelines.LabelRequirement{
+			{Type: "type", Required: true},
+			{Type: "team", AllowedValues: []string{"`team:infra`", "api"}},
+		},
+	}}
+	body := "## Description\n\nLogin fails on mobile browsers and needs a fix."
+
+	tests := []struct {
+		name       string
+		labels     []string
+		wantErrors []string
+	}{
+		{
+			name:       "missing required type label",
+			labels:     []string{"team:api"},
+			wantErrors: []string{"Missing required type label (should start with 'type:')"},
+		},
+		{
+			name:       "disallowed team value",
+			labels:     []string{"type:bug", "team:design"},
+			wantErrors: []string{"Label 'team:design' is not allowed (team labels must be one of: infra, api)"},
+		},
+		{
+			name:   "compliant",
+			labels: []string{"type:bug", "Team:Infra"},
+		},
+		{
+			name:       "optional team label may be absent",
+			labels:     []string{},
+			wantErrors: []string{"Missing required type label (should start with 'type:')"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator(githubtest.NewFakeClient(), nil, TaskFormatRules{MinDescriptionLength: 10}, gd)
+			violations := v.checkFormat(&github.Issue{Body: body, Labels: tt.labels})
+			got := violationMessages(violations)
+			if len(got) == 0 {
+				got = nil
+			}
+			var want []string
+			if len(tt.wantErrors) > 0 {
+				want = tt.wantErrors
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("checkFormat() = %v, want %v", got, want)
+			}
+			for _, violation := range violations {
+				if violation.Kind != KindLabel || violation.AffectsBody() {
+					t.Errorf("expected a label violation, got %+v", violation)
+				}
+			}
+		})
+	}
+}
+
+func TestValidator_LabelRequirementIgnoresTypeCase(t *testing.T) {
+	gd := &guidelines.Guidelines{FormatRules: guidelines.FormatRules{
+		LabelRequirements: []guidelines.LabelRequirement{
+			{Type: "Type", Required: true, AllowedValues: []string{"bug", "feature"}},
+		},
+	}}
+	body := "## Description\n\nLogin fails on mobile browsers and needs a fix."
+
+	v := NewValidator(githubtest.NewFakeClient(), nil, TaskFormatRules{MinDescriptionLength: 10}, gd)
+	if got := violationMessages(v.checkFormat(&github.Issue{Body: body, Labels: []string{"type:bug"}})); len(got) != 0 {
+		t.Errorf("expected 'type:bug' to satisfy the 'Type' requirement, got %v", got)
+	}
+	want := []string{"Label 'TYPE:chore' is not allowed (Type labels must be one of: bug, feature)"}
+	if got := violationMessages(v.checkFormat(&github.Issue{Body: body, Labels: []string{"TYPE:chore"}})); !reflect.DeepEqual(got, want) {
+		t.Errorf("checkFormat() = %v, want %v", got, want)
+	}
+}
+
+func TestValidator_LabelRequirementNotFixedWithDefaultPriority(t *testing.T) {
+	gd := &guidelines.Guidelines{FormatRules: guidelines.FormatRules{
+		LabelRequirements: []guidelines.LabelRequirement{{Type: "type", Required: true}},
+	}}
+	rules := TaskFormatRules{MinDescriptionLength: 10, LabelPrefix: "priority:", DefaultPriorityLabel: "priority:unset"}
+	issue := &github.Issue{Number: 5, Body: "## Description\n\nLogin fails on mobile.", Labels: []string{"priority:high"}}
+
+	mockGH := githubtest.NewFakeClient()
+	result, err := NewValidator(mockGH, nil, rules, gd).Validate(context.Background(), issue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mockGH.Labels[issue.Number]) != 0 || !result.NeedsHuman {
+		t.Errorf("missing type label should be reported, got labels %v", mockGH.Labels[issue.Number])
+	}
+}
+
+func TestValidator_RewriteSendsSystemPrompt(t *testing.T) {
+	var messages []llm.ChatMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req llm.ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("invalid request body: %v", err)
+		}
+		messages = req.Messages
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"## Description\n\nLogin fails on mobile devices after the upgrade."}}]}`))
+	}))
+	defer server.Close()
+
+	mockGH := githubtest.NewFakeClient()
+	v := NewValidator(mockGH, llm.NewClient(server.URL, "test-model", "", time.Second), TaskFormatRules{
+		RequiredSections:     guidelines.Sections("Description"),
+		MinDescriptionLength: 10,
+	}, nil)
+
+	if _, err := v.Validate(context.Background(), &github.Issue{Number: 1, Title: "Fix login", Body: "broken"}); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if len(messages) != 2 {
+		t.Fatalf("expected system and user messages, got %+v", messages)
+	}
+	if messages[0].Role != "system" || messages[0].Content != validatorSystemPrompt {
+		t.Errorf("unexpected system message: %+v", messages[0])
+	}
+	if messages[1].Role != "user" || !strings.Contains(messages[1].Content, "broken") {
+		t.Errorf("unexpected user message: %+v", messages[1])
+	}
+}
+
+func TestValidator_Validate_UnfixedRewrite(t *testing.T) {
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req llm.ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("invalid request body: %v", err)
+		}
+		prompts = append(prompts, req.Messages[len(req.Messages)-1].Content)
+		// The rewrite never adds the Acceptance Criteria section
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"## Description\n\nLogin fails on mobile devices after the upgrade."}}]}`))
+	}))
+	defer server.Close()
+
+	mockGH := githubtest.NewFakeClient()
+	v := NewValidator(mockGH, llm.NewClient(server.URL, "test-model", "", time.Second), TaskFormatRules{
+		RequiredSections:     guidelines.Sections("Description", "Acceptance Criteria"),
+		MinDescriptionLength: 10,
+	}, nil)
+
+	issue := &github.Issue{Number: 1, Title: "Fix login", Body: "broken", URL: "https://github.com/o/r/issues/1"}
+	result, err := v.Validate(context.Background(), issue)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if len(prompts) != 2 || !strings.Contains(prompts[1], "STILL NOT FIXED") || !strings.Contains(prompts[1], "Login fails on mobile") {
+		t.Fatalf("expected one sharper retry on the rewritten body, got prompts %q", prompts)
+	}
+	if !result.FixFailed || !result.NeedsHuman || result.Fixed {
+		t.Errorf("expected a failed fix that needs a human, got %+v", result)
+	}
+	if mockGH.UpdatedIssues[1] != nil {
+		t.Error("expected the body to be left unchanged")
+	}
+	if len(mockGH.Comments[1]) != 1 || !strings.Contains(mockGH.Comments[1][0], "still needs manual attention") {
+		t.Errorf("expected a manual attention comment, got %v", mockGH.Comments[1])
+	}
+
+	// Later runs don't try again while the violations are the same
+	mockGH.IssueComments[1] = append(mockGH.IssueComments[1], github.Comment{Author: "agent[bot]", Body: mockGH.Comments[1][0]})
+	mockGH.Comments[1] = nil
+	result, err = v.Validate(context.Background(), issue)
+	if err != nil {
+		t.Fatalf("second Validate() error = %v", err)
+	}
+	if len(prompts) != 2 || !result.NeedsHuman || len(mockGH.Comments[1]) != 0 {
+		t.Errorf("expected the second run to skip the rewrite quietly, got %d prompts and comments %v", len(prompts), mockGH.Comments[1])
+	}
+}
+
+func TestValidator_Validate_RepeatRun(t *testing.T) {
+	llmCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		llmCalls++
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"## Description\n\nLogin fails on mobile devices after the upgrade."}}]}`))
+	}))
+	defer server.Close()
+
+	mockGH := githubtest.NewFakeClient()
+	v := NewValidator(mockGH, llm.NewClient(server.URL, "test-model", "", time.Second), TaskFormatRules{
+		RequiredSections:     guidelines.Sections("Description", "Acceptance Criteria"),
+		MinDescriptionLength: 10,
+	}, nil)
+
+	// A body rewritten by an earlier version, which wrote rewrites that
+	// still missed the section
+	issue := &github.Issue{Number: 1, Title: "Fix login", URL: "https://github.com/o/r/issues/1"}
+	issue.Body = v.preserveOriginalWithModifications("broken", "## Description\n\nLogin fails on mobile devices after the upgrade.", []string{"Missing required section: Acceptance Criteria"})
+	for run := 1; run <= 2; run++ {
+		result, err := v.Validate(context.Background(), issue)
+		if err != nil {
+			t.Fatalf("run %d: Validate() error = %v", run, err)
+		}
+		if !result.NeedsHuman || result.Fixed {
+			t.Errorf("run %d: expected the issue to need a human, got %+v", run, result)
+		}
+		for _, comment := range mockGH.Comments[1] {
+			mockGH.IssueComments[1] = append(mockGH.IssueComments[1], github.Comment{Author: "agent[bot]", Body: comment})
+		}
+		mockGH.Comments[1] = nil
+	}
+
+	if llmCalls != 0 {
+		t.Errorf("expected no rewrite on repeat runs, got %d LLM calls", llmCalls)
+	}
+	if mockGH.UpdatedIssues[1] != nil {
+		t.Error("expected the body to be left alone on repeat runs")
+	}
+	var attention int
+	for _, comment := range mockGH.IssueComments[1] {
+		if strings.Contains(comment.Body, "still needs manual attention") {
+			attention++
+		}
+	}
+	if attention != 1 {
+		t.Errorf("expected one manual attention comment, got %d in %v", attention, mockGH.IssueComments[1])
+	}
+}
+
+func TestValidator_RewriteFollowsBodyTemplate(t *testing.T) {
+	template := "## Description\n\n## Acceptance Criteria\n- [ ] \n\n## Notes"
+	var prompt string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req llm.ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("invalid request body: %v", err)
+		}
+		prompt = req.Messages[len(req.Messages)-1].Content
+		// The model drops the Notes heading and uses a deeper level for another
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"## Description\n\nLogin fails on mobile devices after the upgrade.\n\n### Acceptance Criteria\n- [ ] Login works on iOS"}}]}`))
+	}))
+	defer server.Close()
+
+	mockGH := githubtest.NewFakeClient()
+	v := NewValidator(mockGH, llm.NewClient(server.URL, "test-model", "", time.Second), TaskFormatRules{
+		MinDescriptionLength: 100,
+		BodyTemplate:         template,
+	}, nil)
+
+	if _, err := v.Validate(context.Background(), &github.Issue{Number: 1, Title: "Fix login", Body: "broken"}); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if !strings.Contains(prompt, template) {
+		t.Errorf("expected the template in the prompt, got %q", prompt)
+	}
+	updated := mockGH.UpdatedIssues[1]
+	if updated == nil {
+		t.Fatal("expected the issue to be rewritten")
+	}
+	lines := strings.Split(updated.Body, "\n")
+	for _, heading := range []string{"Description", "Acceptance Criteria", "Notes"} {
+		found := false
+		for _, line := range lines {
+			if headingText(line) == strings.ToLower(heading) && markdownHeading.MatchString(line) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected heading %q in the fixed body:\n%s", heading, updated.Body)
+		}
+	}
+	if strings.Count(updated.Body, "Acceptance Criteria") != 1 {
+		t.Errorf("expected the existing Acceptance Criteria heading to be kept as is:\n%s", updated.Body)
+	}
+}
+
+func TestApplyBodyTemplate(t *testing.T) {
+	template := "## Description\n\n## Notes\n_Anything else_"
+	if got := applyBodyTemplate("text", ""); got != "text" {
+		t.Errorf("expected no change without a template, got %q", got)
+	}
+	if got, want := applyBodyTemplate("## Description\nLogin fails", template), "## Description\nLogin fails\n\n## Notes\n_Anything else_"; got != want {
+		t.Errorf("applyBodyTemplate() = %q, want %q", got, want)
+	}
+	body := "## description\nLogin fails\n\n## Notes\nNone"
+	if got := applyBodyTemplate(body, template); got != body {
+		t.Errorf("expected a complete body to be unchanged, got %q", got)
+	}
+}
+
+func TestValidator_SkipsProtected(t *testing.T) {
+	mockGH := githubtest.NewFakeClient()
+	v := NewValidatorWithOptions(mockGH, nil, TaskFormatRules{
+		RequiredSections:     guidelines.Sections("Description"),
+		MinDescriptionLength: 10,
+		RequireLabels:        true,
+		LabelPrefix:          "priority:",
+	}, nil, ValidatorOptions{SkipLabels: []string{config.DefaultSkipLabel}})
+
+	issue := &github.Issue{Number: 3, Title: "Epic", Body: "todo", Labels: []string{"No-Agent"}, URL: "https://github.com/o/r/issues/3"}
+	result, err := v.Validate(context.Background(), issue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Skipped != SkipProtected || result.Valid || result.Fixed || result.NeedsHuman {
+		t.Errorf("expected the protected issue to be skipped, got %+v", result)
+	}
+	if len(mockGH.UpdatedIssues) != 0 || len(mockGH.Comments) != 0 || len(mockGH.Labels) != 0 {
+		t.Error("expected a protected issue not to be modified")
+	}
+
+	count, err := NewValidatorWithOptions(mockGH, nil, TaskFormatRules{MinDescriptionLength: 10}, nil, ValidatorOptions{
+		Output:     ValidateOutputReport,
+		SkipLabels: []string{config.DefaultSkipLabel},
+	}).PostReport(context.Background(), []*github.Issue{issue})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 || len(mockGH.CreatedIssues) != 0 {
+		t.Errorf("expected the protected issue to be left out of the report, got %d", count)
+	}
+}
+
+func TestValidator_ProfileAddsSections(t *testing.T) {
+	profile := guidelines.Profile{Name: "security", Label: "security", Guidelines: &guidelines.Guidelines{
+		FormatRules: guidelines.FormatRules{RequiredSections: guidelines.Sections("Threat Model", "description")},
+	}}
+	v := NewValidatorWithOptions(nil, nil, TaskFormatRules{
+		RequiredSections: guidelines.Sections("Description", "Acceptance Criteria"),
+	}, nil, ValidatorOptions{Profiles: []guidelines.Profile{profile}})
+
+	scoped, applied := v.forIssue(&github.Issue{Labels: []string{"security"}})
+	if len(applied) != 1 {
+		t.Fatalf("expected the security profile applied, got %v", applied)
+	}
+	var names []string
+	for _, section := range scoped.rules.RequiredSections {
+		names = append(names, section.Name)
+	}
+	if want := []string{"Description", "Acceptance Criteria", "Threat Model"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("required sections = %v, want %v", names, want)
+	}
+}
+
+func TestExcludeAgentGenerated(t *testing.T) {
+	issues := []*github.Issue{
+		{Number: 1, Labels: []string{"bug"}},
+		{Number: 2, Labels: []string{"Agent-Generated", validationReportLabel}},
+		{Number: 3, Labels: []string{AgentGeneratedLabel, digestLabel}},
+	}
+	got := ExcludeAgentGenerated(issues)
+	if len(got) != 1 || got[0].Number != 1 {
+		t.Errorf("expected only issue #1, got %v", got)
+	}
+}
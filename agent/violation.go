@@ -0,0 +1,71 @@
+package agent
+
+import "strings"
+
+// Severity is how serious a format violation is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// ViolationKind identifies the rule a violation comes from
+type ViolationKind string
+
+const (
+	KindSection ViolationKind = "section"
+	KindLength  ViolationKind = "length"
+	KindLabel   ViolationKind = "label"
+	KindTitle   ViolationKind = "title"
+)
+
+// Violation is a format rule an issue fails
+type Violation struct {
+	Message  string
+	Severity Severity
+	Kind     ViolationKind
+	Label    string // Label prefix the violation is about, for label violations
+}
+
+func (v Violation) String() string {
+	return v.Message
+}
+
+// AffectsBody reports whether fixing the violation requires rewriting the
+// issue body
+func (v Violation) AffectsBody() bool {
+	return v.Kind == KindSection || v.Kind == KindLength
+}
+
+// needsRewrite reports whether any violation is a body error, the only kind
+// worth an LLM rewrite
+func needsRewrite(violations []Violation) bool {
+	for _, violation := range violations {
+		if violation.Severity == SeverityError && violation.AffectsBody() {
+			return true
+		}
+	}
+	return false
+}
+
+// violationMessages returns the message of each violation
+func violationMessages(violations []Violation) []string {
+	messages := make([]string, len(violations))
+	for i, violation := range violations {
+		messages[i] = violation.Message
+	}
+	return messages
+}
+
+// formatViolations renders violations as a markdown list, marking warnings
+func formatViolations(violations []Violation) string {
+	lines := make([]string, len(violations))
+	for i, violation := range violations {
+		lines[i] = "- " + violation.Message
+		if violation.Severity == SeverityWarning {
+			lines[i] += " _(warning)_"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,107 @@
+// Package bot formats the signature agents put in front of the comments
+// they post
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DefaultName is the name comments are signed with unless configured
+const DefaultName = "Agent"
+
+// DefaultPrefixTemplate renders the default "🤖 **Agent**" signature.
+// {{.Name}} is the posting agent's name, or the bot name for the built-in
+// agents; {{.Bot}} and {{.Agent}} are available separately.
+const DefaultPrefixTemplate = "🤖 **{{.Name}}**"
+
+// Identity is who the agents sign their comments as
+type Identity struct {
+	name   string
+	prefix *template.Template
+}
+
+// Default signs comments the way the agents always have
+var Default = mustNew(DefaultName, DefaultPrefixTemplate)
+
+// New creates an identity from a bot name and a prefix template. An empty
+// name or template falls back to the default.
+func New(name, prefixTemplate string) (*Identity, error) {
+	if strings.TrimSpace(name) == "" {
+		name = DefaultName
+	}
+	if strings.TrimSpace(prefixTemplate) == "" {
+		prefixTemplate = DefaultPrefixTemplate
+	}
+
+	tmpl, err := template.New("prefix").Option("missingkey=error").Parse(prefixTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse comment prefix template: %w", err)
+	}
+	id := &Identity{name: name, prefix: tmpl}
+
+	// The prefix is how the agents recognize their own comments, so it
+	// must render to something for both the bot and a named agent
+	for _, agentName := range []string{"", "Plugin"} {
+		prefix, err := id.render(agentName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render comment prefix template: %w", err)
+		}
+		if prefix == "" {
+			return nil, fmt.Errorf("comment prefix template %q renders empty", prefixTemplate)
+		}
+	}
+	return id, nil
+}
+
+func mustNew(name, prefixTemplate string) *Identity {
+	id, err := New(name, prefixTemplate)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// Name returns the bot name
+func (id *Identity) Name() string {
+	if id == nil {
+		return Default.name
+	}
+	return id.name
+}
+
+// Prefix returns the signature for comments posted by agentName. An empty
+// agentName means the bot itself. A nil identity uses the default.
+func (id *Identity) Prefix(agentName string) string {
+	if id == nil {
+		id = Default
+	}
+	prefix, err := id.render(agentName)
+	if err != nil {
+		// Templates are checked in New, so this only happens for odd data
+		return Default.Prefix(agentName)
+	}
+	return prefix
+}
+
+// Format signs body as a comment posted by agentName, e.g.
+// "🤖 **Agent**: body"
+func (id *Identity) Format(agentName, body string) string {
+	return id.Prefix(agentName) + ": " + body
+}
+
+func (id *Identity) render(agentName string) (string, error) {
+	name := agentName
+	if name == "" {
+		name = id.name
+	}
+	data := map[string]string{"Name": name, "Bot": id.name, "Agent": agentName}
+
+	var buf bytes.Buffer
+	if err := id.prefix.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,43 @@
+package bot
+
+import "testing"
+
+func TestIdentity_Format(t *testing.T) {
+	tests := []struct {
+		name      string
+		botName   string
+		template  string
+		agentName string
+		want      string
+	}{
+		{"default bot", "", "", "", "🤖 **Agent**: hi"},
+		{"default plugin", "", "", "Task Validator", "🤖 **Task Validator**: hi"},
+		{"named bot", "Acme Bot", "", "", "🤖 **Acme Bot**: hi"},
+		{"no emoji", "Acme Bot", "**{{.Bot}}**", "Task Validator", "**Acme Bot**: hi"},
+		{"bot and agent", "Acme Bot", "{{.Bot}}{{if .Agent}} ({{.Agent}}){{end}}", "Task Validator", "Acme Bot (Task Validator): hi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := New(tt.botName, tt.template)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if got := id.Format(tt.agentName, "hi"); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	var nilIdentity *Identity
+	if got := nilIdentity.Format("", "hi"); got != "🤖 **Agent**: hi" {
+		t.Errorf("nil identity Format() = %q", got)
+	}
+}
+
+func TestNew_InvalidTemplate(t *testing.T) {
+	for _, template := range []string{"{{.Name", "{{.Missing}}", "{{if .Agent}}x{{end}}"} {
+		if _, err := New("Bot", template); err == nil {
+			t.Errorf("expected an error for template %q", template)
+		}
+	}
+}
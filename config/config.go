@@ -1,13 +1,23 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/kaskol10/github-project-agent/bot"
+	"github.com/kaskol10/github-project-agent/guidelines"
+	"github.com/kaskol10/github-project-agent/logging"
 )
 
+// DefaultSkipLabel protects an issue from automated changes when no skip
+// labels are configured
+const DefaultSkipLabel = "no-agent"
+
 type Config struct {
 	GitHub struct {
 		// Token-based authentication (legacy)
@@ -18,29 +28,80 @@ type Config struct {
 		InstallationID int64  // Installation ID
 		PrivateKeyPath string // Path to private key file (PEM format)
 		PrivateKey     []byte // Private key content (alternative to path)
+		privateKeyErr  error  // Why PrivateKeyPath could not be read
+		TokenCachePath string // Optional file that keeps installation tokens between runs
+
+		// Act as the App installation on each repository's owner, for Apps
+		// installed separately per org; InstallationID is the fallback
+		ResolveInstallations bool
 
 		Owner     string             // Optional: for single-repo mode
 		Repo      string             // Optional: for single-repo mode
 		ProjectID string             // Optional: GitHub Project number (for multi-repo mode)
 		Repos     []RepositoryConfig // Optional: list of repos for project mode
 		BaseURL   string             // Optional: for GitHub Enterprise
+		UploadURL string             // Optional: GitHub Enterprise upload URL, defaults to BaseURL
 		Mode      string             // "repo" or "project" - determines which mode to use
+
+		AddCreatedToProject   bool // Add issues created in project mode to the project board
+		ThrottleRateLimits    bool // Wait for rate limit resets instead of failing
+		RateLimitMinRemaining int  // Remaining requests that trigger a wait
+
+		ManagedLabelPrefixes []string // Only labels with these prefixes are added or removed (empty = any label)
+		WriteRetries         int      // Retries of writes failing with a server error or secondary rate limit
 	}
 
 	LLM struct {
+		Provider       string // Wire format: "litellm" (default), "openai" or "ollama"
 		LiteLLMBaseURL string // e.g., "http://localhost:4000"
 		Model          string // e.g., "gpt-4", "llama-2", etc.
 		APIKey         string // Optional: if required by litellm
 		Timeout        time.Duration
+		PricePer1K     float64       // Price per 1k tokens, used for cost estimates
+		Temperature    float64       // Default sampling temperature (0 = server default)
+		MaxTokens      int           // Default cap on generated tokens (0 = server default)
+		CacheSize      int           // Responses cached for identical prompts within a run (0 = disabled)
+		CallTimeout    time.Duration // Deadline for a single LLM call (0 = HTTP timeout only)
 	}
 
 	Agent struct {
 		StaleTaskThresholdDays int           // Days before a task is considered stale
+		StaleGraceDays         int           // Extra days after creation before stale checks apply
+		BusinessDaysOnly       bool          // Count stale and auto-close days as weekdays, skipping Holidays
+		ActivityFromEvents     bool          // Base staleness on the last human comment, assignment or reopen instead of the update time
+		Holidays               []time.Time   // Dates not counted as business days
+		SkipLabels             []string      // Issues with any of these labels are never touched by any agent
 		CheckInterval          time.Duration // How often to check for stale tasks
+		RunTimeout             time.Duration // Deadline for a whole run or daemon tick (0 = none)
+		MonitorOutput          string        // "comments" or "digest"
+		AutoCloseAfterDays     int           // Close reminded stale tasks with no activity for this many days (0 = never)
+		EscalateAfterReminders int           // Escalate stale tasks after this many unanswered reminders (0 = never)
+		EscalateTo             string        // Fallback user to escalate to, or "author" for the issue author
+		EscalateAction         string        // "mention" or "reassign"
+		Concurrency            int           // Issues processed at once per repository when validating
+		ValidateOutput         string        // "inline" or "report"
+		ReportMode             string        // Plugin report issues: "create" a new one each run or "update" the latest
+		Sample                 string        // Issue sampling for analysis agents: "recent:N", "random:N" or "priority:N"
 		TaskFormatRules        TaskFormatRules
-		GuidelinesPath         string // Path to markdown guidelines file
-		PromptsPath            string // Path to prompts directory
-		PluginsPath            string // Path to plugins directory (.github/agents)
+		GuidelinesPath         string            // Path to markdown guidelines file
+		GuidelinesProfiles     map[string]string // Label -> guidelines file applied to issues with that label
+		PromptsPath            string            // Path to prompts directory
+		PromptsStrict          bool              // Fail rendering plugin agent prompts that reference missing keys
+		PluginsPath            string            // Path to plugins directory (.github/agents)
+		StatePath              string            // Path to the JSON file used to persist state between runs
+		ChecklistStaleDays     int               // Days without newly checked items before nudging
+		ChecklistMinItems      int               // Minimum checklist size for checklist nudges
+		RoastCooldownDays      int               // Skip the roast when one from this many days ago covers the same backlog (0 = always roast)
+		StrictAgentEnv         bool              // Fail loading agents whose config references unset environment variables
+		OnLLMFailure           string            // Validator fallback when the LLM is down: "skip", "comment", "label" or "comment,label"
+		ReportAssignees        map[string]string // Report type (or "default") -> login assigned to generated report issues
+		BotName                string            // Name agent comments are signed with
+		CommentPrefixTemplate  string            // Template for the signature starting agent comments, e.g. "🤖 **{{.Name}}**"
+	}
+
+	Log struct {
+		Level  string // "debug", "info", "warn" or "error"
+		Format string // "text" or "json"
 	}
 }
 
@@ -50,42 +111,85 @@ type RepositoryConfig struct {
 }
 
 type TaskFormatRules struct {
-	RequiredSections     []string // e.g., ["Description", "Acceptance Criteria", "Priority"]
+	RequiredSections     []guidelines.Section // e.g., Description, Acceptance Criteria (aliases: AC)
 	MinDescriptionLength int
 	RequireLabels        bool
 	LabelPrefix          string // e.g., "priority:" for priority labels
+	TitlePattern         string // Regexp issue titles must match, e.g., `^\[(infra|api)\] `
+	DefaultPriorityLabel string // Label applied when the priority label is the only problem ("none" disables)
+	BodyTemplate         string // Markdown skeleton rewritten descriptions must follow
 }
 
+// Load loads the configuration from environment variables. If AGENT_CONFIG
+// names a YAML file, or agent.yaml exists in the working directory, it is
+// loaded first and environment variables override its values.
 func Load() (*Config, error) {
+	if path := os.Getenv("AGENT_CONFIG"); path != "" {
+		return LoadFromFile(path)
+	}
+	if _, err := os.Stat(DefaultConfigFile); err == nil {
+		return LoadFromFile(DefaultConfigFile)
+	}
+	return load(&File{})
+}
+
+// decodePrivateKey returns a private key given as raw PEM unchanged, and
+// base64-decodes one that isn't PEM when the result is. Anything else is
+// returned as is for Validate to report.
+func decodePrivateKey(key string) []byte {
+	if strings.Contains(key, "-----BEGIN") {
+		return []byte(key)
+	}
+	compact := strings.Join(strings.Fields(key), "")
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding} {
+		if decoded, err := encoding.DecodeString(compact); err == nil && strings.Contains(string(decoded), "-----BEGIN") {
+			return decoded
+		}
+	}
+	return []byte(key)
+}
+
+// load builds the configuration from environment variables, using values
+// from the config file as defaults
+func load(file *File) (*Config, error) {
 	cfg := &Config{}
 
 	// GitHub config
-	cfg.GitHub.Token = getEnv("GITHUB_TOKEN", "")
-	cfg.GitHub.Owner = getEnv("GITHUB_OWNER", "")
-	cfg.GitHub.Repo = getEnv("GITHUB_REPO", "")
-	cfg.GitHub.ProjectID = getEnv("GITHUB_PROJECT_ID", "")
-	cfg.GitHub.BaseURL = getEnv("GITHUB_BASE_URL", "https://api.github.com")
+	cfg.GitHub.Token = getEnv("GITHUB_TOKEN", file.GitHub.Token)
+	cfg.GitHub.Owner = getEnv("GITHUB_OWNER", file.GitHub.Owner)
+	cfg.GitHub.Repo = getEnv("GITHUB_REPO", file.GitHub.Repo)
+	cfg.GitHub.ProjectID = getEnv("GITHUB_PROJECT_ID", file.GitHub.ProjectID)
+	cfg.GitHub.BaseURL = getEnv("GITHUB_BASE_URL", stringOr(file.GitHub.BaseURL, "https://api.github.com"))
+	cfg.GitHub.UploadURL = getEnv("GITHUB_UPLOAD_URL", file.GitHub.UploadURL)
+	cfg.GitHub.AddCreatedToProject = getEnvBool("ADD_CREATED_TO_PROJECT", file.GitHub.AddCreatedToProject)
+	cfg.GitHub.ThrottleRateLimits = getEnvBool("GITHUB_RATE_LIMIT_THROTTLE", file.GitHub.ThrottleRateLimits)
+	cfg.GitHub.RateLimitMinRemaining = getEnvInt("GITHUB_RATE_LIMIT_MIN_REMAINING", intOr(file.GitHub.RateLimitMinRemaining, 10))
+	cfg.GitHub.WriteRetries = getEnvInt("GITHUB_WRITE_RETRIES", intOr(file.GitHub.WriteRetries, 3))
+	cfg.GitHub.ManagedLabelPrefixes = getEnvList("MANAGED_LABEL_PREFIXES", file.GitHub.ManagedLabelPrefixes)
 
 	// GitHub App authentication (preferred over token)
-	cfg.GitHub.AppID = getEnvInt64("GITHUB_APP_ID", 0)
-	cfg.GitHub.InstallationID = getEnvInt64("GITHUB_APP_INSTALLATION_ID", 0)
-	cfg.GitHub.PrivateKeyPath = getEnv("GITHUB_APP_PRIVATE_KEY_PATH", "")
+	cfg.GitHub.AppID = getEnvInt64("GITHUB_APP_ID", file.GitHub.AppID)
+	cfg.GitHub.InstallationID = getEnvInt64("GITHUB_APP_INSTALLATION_ID", file.GitHub.InstallationID)
+	cfg.GitHub.PrivateKeyPath = getEnv("GITHUB_APP_PRIVATE_KEY_PATH", file.GitHub.PrivateKeyPath)
+	cfg.GitHub.TokenCachePath = getEnv("GITHUB_APP_TOKEN_CACHE_PATH", file.GitHub.TokenCachePath)
+	cfg.GitHub.ResolveInstallations = getEnvBool("GITHUB_APP_RESOLVE_INSTALLATIONS", file.GitHub.ResolveInstallations)
 
 	// Try to load private key from path if provided
 	if cfg.GitHub.PrivateKeyPath != "" {
 		keyData, err := os.ReadFile(cfg.GitHub.PrivateKeyPath)
 		if err == nil {
 			cfg.GitHub.PrivateKey = keyData
+		} else {
+			cfg.GitHub.privateKeyErr = err // Reported by Validate unless the env key is set
 		}
 	}
 
 	// Alternative: load private key directly from environment variable
-	// (useful for GitHub Actions secrets)
+	// (useful for GitHub Actions secrets). The file takes precedence.
 	if len(cfg.GitHub.PrivateKey) == 0 {
 		privateKeyEnv := getEnv("GITHUB_APP_PRIVATE_KEY", "")
 		if privateKeyEnv != "" {
-			// Handle base64 encoded keys or raw PEM
-			cfg.GitHub.PrivateKey = []byte(privateKeyEnv)
+			cfg.GitHub.PrivateKey = decodePrivateKey(privateKeyEnv)
 		}
 	}
 
@@ -93,7 +197,7 @@ func Load() (*Config, error) {
 	if cfg.GitHub.ProjectID != "" {
 		cfg.GitHub.Mode = "project"
 		// Parse repositories from GITHUB_REPOS (comma-separated: owner/repo,owner/repo)
-		reposStr := getEnv("GITHUB_REPOS", "")
+		reposStr := getEnv("GITHUB_REPOS", strings.Join(file.GitHub.Repos, ","))
 		if reposStr != "" {
 			cfg.GitHub.Repos = parseRepos(reposStr)
 		}
@@ -102,26 +206,116 @@ func Load() (*Config, error) {
 	}
 
 	// LLM config
-	cfg.LLM.LiteLLMBaseURL = getEnv("LITELLM_BASE_URL", "http://localhost:4000")
-	cfg.LLM.Model = getEnv("LLM_MODEL", "gpt-4")
-	cfg.LLM.APIKey = getEnv("LLM_API_KEY", "")
+	cfg.LLM.Provider = getEnv("LLM_PROVIDER", stringOr(file.LLM.Provider, "litellm"))
+	cfg.LLM.LiteLLMBaseURL = getEnv("LITELLM_BASE_URL", stringOr(file.LLM.BaseURL, "http://localhost:4000"))
+	cfg.LLM.Model = getEnv("LLM_MODEL", stringOr(file.LLM.Model, "gpt-4"))
+	cfg.LLM.APIKey = getEnv("LLM_API_KEY", file.LLM.APIKey)
 	cfg.LLM.Timeout = 30 * time.Second
+	if file.LLM.Timeout > 0 {
+		cfg.LLM.Timeout = file.LLM.Timeout
+	}
+	cfg.LLM.PricePer1K = getEnvFloat("LLM_PRICE_PER_1K_TOKENS", floatOr(file.LLM.PricePer1K, 0.01))
+	cfg.LLM.Temperature = getEnvFloat("LLM_TEMPERATURE", file.LLM.Temperature)
+	cfg.LLM.MaxTokens = getEnvInt("LLM_MAX_TOKENS", file.LLM.MaxTokens)
+	cfg.LLM.CacheSize = getEnvInt("LLM_CACHE_SIZE", file.LLM.CacheSize)
+	cfg.LLM.CallTimeout = file.LLM.CallTimeout
+	if seconds := getEnvInt("LLM_CALL_TIMEOUT_SECONDS", -1); seconds >= 0 {
+		cfg.LLM.CallTimeout = time.Duration(seconds) * time.Second
+	}
 
 	// Agent config
-	cfg.Agent.StaleTaskThresholdDays = getEnvInt("STALE_TASK_THRESHOLD_DAYS", 7)
-	cfg.Agent.CheckInterval = time.Duration(getEnvInt("CHECK_INTERVAL_HOURS", 24)) * time.Hour
-	cfg.Agent.GuidelinesPath = getEnv("GUIDELINES_PATH", ".github/task-guidelines.md")
-	cfg.Agent.PromptsPath = getEnv("PROMPTS_PATH", "prompts")
-	cfg.Agent.PluginsPath = getEnv("PLUGINS_PATH", ".github/agents")
+	cfg.Agent.StaleTaskThresholdDays = getEnvInt("STALE_TASK_THRESHOLD_DAYS", intOr(file.Agent.StaleTaskThresholdDays, 7))
+	cfg.Agent.StaleGraceDays = getEnvInt("STALE_GRACE_DAYS", file.Agent.StaleGraceDays)
+	cfg.Agent.BusinessDaysOnly = getEnvBool("STALE_BUSINESS_DAYS_ONLY", file.Agent.BusinessDaysOnly)
+	cfg.Agent.ActivityFromEvents = getEnvBool("STALE_ACTIVITY_FROM_EVENTS", file.Agent.ActivityFromEvents)
+	holidays, err := parseDates(getEnv("HOLIDAYS", strings.Join(file.Agent.Holidays, ",")))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HOLIDAYS: %w", err)
+	}
+	cfg.Agent.Holidays = holidays
+	skipLabels := file.Agent.SkipLabels
+	if skipLabels == nil {
+		skipLabels = []string{DefaultSkipLabel}
+	}
+	cfg.Agent.SkipLabels = getEnvList("SKIP_LABELS", skipLabels)
+	if len(cfg.Agent.SkipLabels) == 1 && cfg.Agent.SkipLabels[0] == "none" {
+		cfg.Agent.SkipLabels = nil
+	}
+	cfg.Agent.CheckInterval = 24 * time.Hour
+	if file.Agent.CheckInterval > 0 {
+		cfg.Agent.CheckInterval = file.Agent.CheckInterval
+	}
+	if hours := getEnvInt("CHECK_INTERVAL_HOURS", 0); hours > 0 {
+		cfg.Agent.CheckInterval = time.Duration(hours) * time.Hour
+	}
+	cfg.Agent.RunTimeout = time.Hour
+	if file.Agent.RunTimeout > 0 {
+		cfg.Agent.RunTimeout = file.Agent.RunTimeout
+	}
+	if minutes := getEnvInt("RUN_TIMEOUT_MINUTES", -1); minutes >= 0 {
+		cfg.Agent.RunTimeout = time.Duration(minutes) * time.Minute
+	}
+	cfg.Agent.MonitorOutput = getEnv("MONITOR_OUTPUT", stringOr(file.Agent.MonitorOutput, "comments"))
+	cfg.Agent.AutoCloseAfterDays = getEnvInt("AUTO_CLOSE_AFTER_DAYS", file.Agent.AutoCloseAfterDays)
+	cfg.Agent.EscalateAfterReminders = getEnvInt("ESCALATE_AFTER_REMINDERS", file.Agent.EscalateAfterReminders)
+	cfg.Agent.EscalateTo = getEnv("ESCALATE_TO", file.Agent.EscalateTo)
+	cfg.Agent.EscalateAction = getEnv("ESCALATE_ACTION", stringOr(file.Agent.EscalateAction, "mention"))
+	cfg.Agent.Concurrency = getEnvInt("CONCURRENCY", intOr(file.Agent.Concurrency, 4))
+	cfg.Agent.ValidateOutput = getEnv("VALIDATE_OUTPUT", stringOr(file.Agent.ValidateOutput, "inline"))
+	cfg.Agent.ReportMode = getEnv("REPORT_MODE", stringOr(file.Agent.ReportMode, "create"))
+	cfg.Agent.Sample = getEnv("SAMPLE", file.Agent.Sample)
+	cfg.Agent.GuidelinesPath = getEnv("GUIDELINES_PATH", stringOr(file.Agent.GuidelinesPath, ".github/task-guidelines.md"))
+	cfg.Agent.GuidelinesProfiles = getEnvKeyValues("GUIDELINES_PROFILES", file.Agent.GuidelinesProfiles)
+	cfg.Agent.PromptsPath = getEnv("PROMPTS_PATH", stringOr(file.Agent.PromptsPath, "prompts"))
+	cfg.Agent.PromptsStrict = getEnvBool("PROMPTS_STRICT", file.Agent.PromptsStrict)
+	cfg.Agent.PluginsPath = getEnv("PLUGINS_PATH", stringOr(file.Agent.PluginsPath, ".github/agents"))
+	cfg.Agent.StatePath = getEnv("STATE_PATH", stringOr(file.Agent.StatePath, ".github-project-agent/state.json"))
+	cfg.Agent.ChecklistStaleDays = getEnvInt("CHECKLIST_STALE_DAYS", intOr(file.Agent.ChecklistStaleDays, cfg.Agent.StaleTaskThresholdDays))
+	cfg.Agent.ChecklistMinItems = getEnvInt("CHECKLIST_MIN_ITEMS", intOr(file.Agent.ChecklistMinItems, 3))
+	cfg.Agent.RoastCooldownDays = getEnvInt("ROAST_COOLDOWN_DAYS", intOr(file.Agent.RoastCooldownDays, 7))
+	cfg.Agent.OnLLMFailure = getEnv("ON_LLM_FAILURE", stringOr(file.Agent.OnLLMFailure, "skip"))
+	cfg.Agent.StrictAgentEnv = getEnvBool("AGENT_CONFIG_STRICT_ENV", file.Agent.StrictAgentEnv)
+	cfg.Agent.ReportAssignees = getEnvKeyValues("REPORT_ASSIGNEES", file.Agent.ReportAssignees)
+	cfg.Agent.BotName = getEnv("BOT_NAME", stringOr(file.Agent.BotName, bot.DefaultName))
+	cfg.Agent.CommentPrefixTemplate = getEnv("COMMENT_PREFIX_TEMPLATE", stringOr(file.Agent.CommentPrefixTemplate, bot.DefaultPrefixTemplate))
+
+	// Log config
+	cfg.Log.Level = getEnv("LOG_LEVEL", stringOr(file.Log.Level, "info"))
+	cfg.Log.Format = getEnv("LOG_FORMAT", stringOr(file.Log.Format, logging.FormatText))
 
 	// Note: PROMPTS_PATH can be comma-separated for multiple paths
 	// e.g., "prompts,.github/agents/custom/prompts"
 
 	// Task format rules (defaults, can be overridden by guidelines file)
-	cfg.Agent.TaskFormatRules.RequiredSections = []string{"Description", "Acceptance Criteria"}
-	cfg.Agent.TaskFormatRules.MinDescriptionLength = 50
-	cfg.Agent.TaskFormatRules.RequireLabels = true
-	cfg.Agent.TaskFormatRules.LabelPrefix = "priority:"
+	rules := file.TaskFormatRules
+	cfg.Agent.TaskFormatRules.RequiredSections = guidelines.Sections("Description", "Acceptance Criteria")
+	if len(rules.RequiredSections) > 0 {
+		cfg.Agent.TaskFormatRules.RequiredSections = make([]guidelines.Section, len(rules.RequiredSections))
+		for i, section := range rules.RequiredSections {
+			cfg.Agent.TaskFormatRules.RequiredSections[i] = guidelines.Section{Name: section.Name, Aliases: section.Aliases}
+		}
+	}
+	cfg.Agent.TaskFormatRules.MinDescriptionLength = intOr(rules.MinDescriptionLength, 50)
+	cfg.Agent.TaskFormatRules.RequireLabels = rules.RequireLabels == nil || *rules.RequireLabels
+	cfg.Agent.TaskFormatRules.LabelPrefix = stringOr(rules.LabelPrefix, "priority:")
+	cfg.Agent.TaskFormatRules.DefaultPriorityLabel = getEnv("DEFAULT_PRIORITY_LABEL", stringOr(rules.DefaultPriorityLabel, "priority:unset"))
+	if cfg.Agent.TaskFormatRules.DefaultPriorityLabel == "none" {
+		cfg.Agent.TaskFormatRules.DefaultPriorityLabel = ""
+	}
+	cfg.Agent.TaskFormatRules.TitlePattern = getEnv("TITLE_PATTERN", rules.TitlePattern)
+	if cfg.Agent.TaskFormatRules.TitlePattern != "" {
+		if _, err := regexp.Compile(cfg.Agent.TaskFormatRules.TitlePattern); err != nil {
+			return nil, fmt.Errorf("invalid TITLE_PATTERN %q: %w", cfg.Agent.TaskFormatRules.TitlePattern, err)
+		}
+	}
+	cfg.Agent.TaskFormatRules.BodyTemplate = rules.BodyTemplate
+	if path := getEnv("BODY_TEMPLATE_PATH", rules.BodyTemplatePath); path != "" {
+		template, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body template: %w", err)
+		}
+		cfg.Agent.TaskFormatRules.BodyTemplate = strings.TrimSpace(string(template))
+	}
 
 	return cfg, nil
 }
@@ -133,6 +327,64 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	return value == "true"
+}
+
+// getEnvKeyValues parses key=value pairs from an environment variable, or
+// returns defaultValue if it's unset
+func getEnvKeyValues(key string, defaultValue map[string]string) map[string]string {
+	value := os.Getenv(key)
+	if value == "" && defaultValue != nil {
+		return defaultValue
+	}
+	return parseKeyValues(value)
+}
+
+// getEnvList parses a comma-separated list from an environment variable, or
+// returns defaultValue if it's unset
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// stringOr returns value, or fallback if value is empty
+func stringOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// intOr returns value, or fallback if value is zero
+func intOr(value, fallback int) int {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
+
+// floatOr returns value, or fallback if value is zero
+func floatOr(value, fallback float64) float64 {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
+
 func getEnvInt(key string, defaultValue int) int {
 	value := os.Getenv(key)
 	if value == "" {
@@ -146,6 +398,19 @@ func getEnvInt(key string, defaultValue int) int {
 	return result
 }
 
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return result
+}
+
 func getEnvInt64(key string, defaultValue int64) int64 {
 	value := os.Getenv(key)
 	if value == "" {
@@ -159,6 +424,23 @@ func getEnvInt64(key string, defaultValue int64) int64 {
 	return result
 }
 
+// parseDates parses a comma-separated list of YYYY-MM-DD dates
+func parseDates(s string) ([]time.Time, error) {
+	var dates []time.Time
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", part)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a YYYY-MM-DD date", part)
+		}
+		dates = append(dates, date)
+	}
+	return dates, nil
+}
+
 func parseRepos(reposStr string) []RepositoryConfig {
 	var repos []RepositoryConfig
 	parts := strings.Split(reposStr, ",")
@@ -178,3 +460,87 @@ func parseRepos(reposStr string) []RepositoryConfig {
 	}
 	return repos
 }
+
+// Report types that can be routed to an assignee with REPORT_ASSIGNEES
+const (
+	ReportRoast            = "roast"
+	ReportStaleDigest      = "stale-digest"
+	ReportValidation       = "validation-report"
+	ReportExecutiveSummary = "executive-summary"
+	ReportProgress         = "progress-report"
+)
+
+// ReportAssignee returns the login that owns generated issues of the given
+// report type, falling back to the "default" entry
+func (c *Config) ReportAssignee(reportType string) string {
+	assignee, ok := c.Agent.ReportAssignees[reportType]
+	if !ok {
+		assignee = c.Agent.ReportAssignees["default"]
+	}
+	return strings.TrimPrefix(assignee, "@")
+}
+
+// ScopeRepos narrows project mode to a comma-separated list of owner/repo
+// names for one run, e.g. from a command-line flag. Every name must be one of
+// the project's configured repositories.
+func (c *Config) ScopeRepos(list string) error {
+	if c.GitHub.Mode != "project" {
+		return fmt.Errorf("repositories can only be scoped in project mode")
+	}
+
+	var scoped []RepositoryConfig
+	for _, part := range strings.Split(list, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		owner, name, ok := strings.Cut(part, "/")
+		if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("invalid repository %q (format: owner/repo)", part)
+		}
+		configured := false
+		for _, repo := range c.GitHub.Repos {
+			if strings.EqualFold(repo.Owner, owner) && strings.EqualFold(repo.Name, name) {
+				scoped = append(scoped, repo)
+				configured = true
+				break
+			}
+		}
+		if !configured {
+			return fmt.Errorf("repository %s is not one of the project's repositories (GITHUB_REPOS)", part)
+		}
+	}
+	if len(scoped) == 0 {
+		return fmt.Errorf("no repositories given")
+	}
+	c.GitHub.Repos = scoped
+	return nil
+}
+
+// Identity returns who agent comments are signed as. An invalid prefix
+// template, which Validate reports, falls back to the default.
+func (c *Config) Identity() *bot.Identity {
+	identity, err := bot.New(c.Agent.BotName, c.Agent.CommentPrefixTemplate)
+	if err != nil {
+		return bot.Default
+	}
+	return identity
+}
+
+// parseKeyValues parses a comma-separated list of key=value pairs
+// (e.g. "security=.github/security-guidelines.md,frontend=.github/frontend.md")
+func parseKeyValues(s string) map[string]string {
+	result := make(map[string]string)
+	for _, part := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if key != "" && value != "" {
+			result[key] = value
+		}
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kaskol10/github-project-agent/github"
+)
+
+func TestConfig_ScopeRepos(t *testing.T) {
+	project := func() *Config {
+		cfg := validConfig()
+		cfg.GitHub.Mode = "project"
+		cfg.GitHub.Repos = []RepositoryConfig{{Owner: "acme", Name: "api"}, {Owner: "acme", Name: "web"}, {Owner: "acme", Name: "infra"}}
+		return cfg
+	}
+
+	cfg := project()
+	if err := cfg.ScopeRepos("acme/web, ACME/api"); err != nil {
+		t.Fatalf("ScopeRepos() error = %v", err)
+	}
+	if want := []RepositoryConfig{{Owner: "acme", Name: "web"}, {Owner: "acme", Name: "api"}}; !reflect.DeepEqual(cfg.GitHub.Repos, want) {
+		t.Errorf("Repos = %v, want %v", cfg.GitHub.Repos, want)
+	}
+
+	tests := []struct {
+		name    string
+		cfg     *Config
+		list    string
+		wantErr string
+	}{
+		{"repo mode", validConfig(), "acme/api", "only be scoped in project mode"},
+		{"malformed", project(), "acme", "invalid repository"},
+		{"outside the project", project(), "acme/api,other/api", "other/api is not one of the project's repositories"},
+		{"empty", project(), " , ", "no repositories"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := append([]RepositoryConfig(nil), tt.cfg.GitHub.Repos...)
+			err := tt.cfg.ScopeRepos(tt.list)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ScopeRepos() error = %v, want %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tt.cfg.GitHub.Repos, before) {
+				t.Errorf("expected Repos unchanged on error, got %v", tt.cfg.GitHub.Repos)
+			}
+		})
+	}
+}
+
+func TestLoad_Base64PrivateKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	for name, env := range map[string]string{
+		"raw PEM":        string(pemKey),
+		"base64":         base64.StdEncoding.EncodeToString(pemKey),
+		"wrapped base64": base64.StdEncoding.EncodeToString(pemKey)[:64] + "\n" + base64.StdEncoding.EncodeToString(pemKey)[64:],
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("GITHUB_APP_PRIVATE_KEY_PATH", "")
+			t.Setenv("GITHUB_APP_PRIVATE_KEY", env)
+			cfg, err := load(&File{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(cfg.GitHub.PrivateKey) != string(pemKey) {
+				t.Fatalf("expected the decoded PEM key, got %q", cfg.GitHub.PrivateKey)
+			}
+			if _, err := github.NewAppAuth(123, 456, cfg.GitHub.PrivateKey, ""); err != nil {
+				t.Fatalf("NewAppAuth() error = %v", err)
+			}
+		})
+	}
+}
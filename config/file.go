@@ -0,0 +1,154 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFile is loaded by Load when it exists and AGENT_CONFIG isn't set
+const DefaultConfigFile = "agent.yaml"
+
+// File is the YAML configuration file. Every field is optional; zero values
+// fall back to the built-in defaults, and environment variables override
+// values from the file.
+type File struct {
+	GitHub struct {
+		Token                 string   `yaml:"token"`
+		AppID                 int64    `yaml:"app_id"`
+		InstallationID        int64    `yaml:"installation_id"`
+		PrivateKeyPath        string   `yaml:"private_key_path"`
+		TokenCachePath        string   `yaml:"token_cache_path"`
+		ResolveInstallations  bool     `yaml:"resolve_installations"`
+		Owner                 string   `yaml:"owner"`
+		Repo                  string   `yaml:"repo"`
+		ProjectID             string   `yaml:"project_id"`
+		Repos                 []string `yaml:"repos"` // owner/repo
+		BaseURL               string   `yaml:"base_url"`
+		UploadURL             string   `yaml:"upload_url"`
+		AddCreatedToProject   bool     `yaml:"add_created_to_project"`
+		ThrottleRateLimits    bool     `yaml:"throttle_rate_limits"`
+		RateLimitMinRemaining int      `yaml:"rate_limit_min_remaining"`
+		ManagedLabelPrefixes  []string `yaml:"managed_label_prefixes"`
+		WriteRetries          int      `yaml:"write_retries"`
+	} `yaml:"github"`
+
+	LLM struct {
+		Provider    string        `yaml:"provider"`
+		BaseURL     string        `yaml:"base_url"`
+		Model       string        `yaml:"model"`
+		APIKey      string        `yaml:"api_key"`
+		Timeout     time.Duration `yaml:"timeout"`
+		PricePer1K  float64       `yaml:"price_per_1k_tokens"`
+		Temperature float64       `yaml:"temperature"`
+		MaxTokens   int           `yaml:"max_tokens"`
+		CacheSize   int           `yaml:"cache_size"`
+		CallTimeout time.Duration `yaml:"call_timeout"`
+	} `yaml:"llm"`
+
+	Agent struct {
+		StaleTaskThresholdDays int               `yaml:"stale_task_threshold_days"`
+		StaleGraceDays         int               `yaml:"stale_grace_days"`
+		BusinessDaysOnly       bool              `yaml:"business_days_only"`
+		ActivityFromEvents     bool              `yaml:"activity_from_events"`
+		Holidays               []string          `yaml:"holidays"`
+		SkipLabels             []string          `yaml:"skip_labels"`
+		CheckInterval          time.Duration     `yaml:"check_interval"`
+		RunTimeout             time.Duration     `yaml:"run_timeout"`
+		MonitorOutput          string            `yaml:"monitor_output"`
+		AutoCloseAfterDays     int               `yaml:"auto_close_after_days"`
+		EscalateAfterReminders int               `yaml:"escalate_after_reminders"`
+		EscalateTo             string            `yaml:"escalate_to"`
+		EscalateAction         string            `yaml:"escalate_action"`
+		Concurrency            int               `yaml:"concurrency"`
+		ValidateOutput         string            `yaml:"validate_output"`
+		ReportMode             string            `yaml:"report_mode"`
+		Sample                 string            `yaml:"sample"`
+		GuidelinesPath         string            `yaml:"guidelines_path"`
+		GuidelinesProfiles     map[string]string `yaml:"guidelines_profiles"`
+		PromptsPath            string            `yaml:"prompts_path"`
+		PromptsStrict          bool              `yaml:"prompts_strict"`
+		PluginsPath            string            `yaml:"plugins_path"`
+		StatePath              string            `yaml:"state_path"`
+		ChecklistStaleDays     int               `yaml:"checklist_stale_days"`
+		ChecklistMinItems      int               `yaml:"checklist_min_items"`
+		RoastCooldownDays      int               `yaml:"roast_cooldown_days"`
+		StrictAgentEnv         bool              `yaml:"strict_agent_env"`
+		OnLLMFailure           string            `yaml:"on_llm_failure"`
+		ReportAssignees        map[string]string `yaml:"report_assignees"`
+		BotName                string            `yaml:"bot_name"`
+		CommentPrefixTemplate  string            `yaml:"comment_prefix_template"`
+	} `yaml:"agent"`
+
+	Log struct {
+		Level  string `yaml:"level"`
+		Format string `yaml:"format"`
+	} `yaml:"log"`
+
+	TaskFormatRules struct {
+		RequiredSections     []SectionConfig `yaml:"required_sections"`
+		MinDescriptionLength int             `yaml:"min_description_length"`
+		RequireLabels        *bool           `yaml:"require_labels"`
+		LabelPrefix          string          `yaml:"label_prefix"`
+		TitlePattern         string          `yaml:"title_pattern"`
+		DefaultPriorityLabel string          `yaml:"default_priority_label"`
+		BodyTemplate         string          `yaml:"body_template"`
+		BodyTemplatePath     string          `yaml:"body_template_path"`
+	} `yaml:"task_format_rules"`
+}
+
+// SectionConfig is a required section, written either as a plain name or as
+// a mapping with aliases: {name: Acceptance Criteria, aliases: [AC]}
+type SectionConfig struct {
+	Name    string   `yaml:"name"`
+	Aliases []string `yaml:"aliases"`
+}
+
+// UnmarshalYAML accepts a plain name as well as the mapping form
+func (s *SectionConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		s.Name = node.Value
+		return nil
+	}
+
+	type plain SectionConfig
+	if err := node.Decode((*plain)(s)); err != nil {
+		return err
+	}
+	if s.Name == "" {
+		return fmt.Errorf("line %d: required section needs a name", node.Line)
+	}
+	return nil
+}
+
+// LoadFromFile loads the configuration from a YAML file, with environment
+// variables overriding values from the file
+func LoadFromFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	file, err := parseFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return load(file)
+}
+
+// parseFile decodes a YAML configuration, rejecting unknown keys so typos
+// don't go unnoticed
+func parseFile(data []byte) (*File, error) {
+	file := &File{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return file, nil
+}
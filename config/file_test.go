@@ -0,0 +1,159 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaskol10/github-project-agent/guidelines"
+)
+
+const testConfigFile = `github:
+  owner: acme
+  project_id: "7"
+  repos:
+    - acme/api
+    - acme/web
+llm:
+  base_url: http://litellm:4000
+  model: claude
+  timeout: 1m
+agent:
+  stale_task_threshold_days: 14
+  holidays: [2024-12-25, 2025-01-01]
+  report_assignees:
+    roast: techlead
+task_format_rules:
+  required_sections: [Summary]
+  require_labels: false
+  title_pattern: '^\[api\] '
+  body_template: |
+    ## Summary
+
+    ## Notes
+`
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "agent.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFromFile(t *testing.T) {
+	for _, key := range []string{"GITHUB_OWNER", "GITHUB_PROJECT_ID", "GITHUB_REPOS", "LITELLM_BASE_URL", "STALE_TASK_THRESHOLD_DAYS", "REPORT_ASSIGNEES", "TITLE_PATTERN", "BODY_TEMPLATE_PATH", "HOLIDAYS"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("LLM_MODEL", "gpt-4o") // Environment variables override the file
+
+	cfg, err := LoadFromFile(writeConfigFile(t, testConfigFile))
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+
+	if cfg.GitHub.Mode != "project" || cfg.GitHub.Owner != "acme" {
+		t.Errorf("unexpected GitHub config: %+v", cfg.GitHub)
+	}
+	if want := []RepositoryConfig{{Owner: "acme", Name: "api"}, {Owner: "acme", Name: "web"}}; !reflect.DeepEqual(cfg.GitHub.Repos, want) {
+		t.Errorf("repos = %v, want %v", cfg.GitHub.Repos, want)
+	}
+	if cfg.LLM.LiteLLMBaseURL != "http://litellm:4000" || cfg.LLM.Model != "gpt-4o" || cfg.LLM.Timeout != time.Minute {
+		t.Errorf("unexpected LLM config: %+v", cfg.LLM)
+	}
+	if cfg.Agent.StaleTaskThresholdDays != 14 || cfg.Agent.ChecklistStaleDays != 14 || cfg.ReportAssignee(ReportRoast) != "techlead" {
+		t.Errorf("unexpected agent config: %+v", cfg.Agent)
+	}
+	if len(cfg.Agent.Holidays) != 2 || cfg.Agent.Holidays[1].Format("2006-01-02") != "2025-01-01" {
+		t.Errorf("unexpected holidays: %v", cfg.Agent.Holidays)
+	}
+	rules := cfg.Agent.TaskFormatRules
+	if !reflect.DeepEqual(rules.RequiredSections, guidelines.Sections("Summary")) || rules.RequireLabels || rules.TitlePattern != `^\[api\] ` || rules.BodyTemplate != "## Summary\n\n## Notes\n" {
+		t.Errorf("unexpected task format rules: %+v", rules)
+	}
+	// Unset values keep their defaults
+	if cfg.GitHub.BaseURL != "https://api.github.com" || rules.LabelPrefix != "priority:" || cfg.Agent.MonitorOutput != "comments" {
+		t.Errorf("expected defaults for unset values, got %+v", cfg)
+	}
+}
+
+func TestLoadFromFile_InvalidHoliday(t *testing.T) {
+	t.Setenv("HOLIDAYS", "2024-12-25,christmas")
+	_, err := LoadFromFile(writeConfigFile(t, "github:\n  owner: acme\n"))
+	if err == nil || !strings.Contains(err.Error(), "christmas") {
+		t.Errorf("expected an invalid holiday error, got %v", err)
+	}
+}
+
+func TestLoadFromFile_SectionAliases(t *testing.T) {
+	cfg, err := LoadFromFile(writeConfigFile(t, `github:
+  owner: acme
+task_format_rules:
+  required_sections:
+    - Description
+    - name: Acceptance Criteria
+      aliases: [AC, Definition of Done]
+`))
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	want := []guidelines.Section{
+		{Name: "Description"},
+		{Name: "Acceptance Criteria", Aliases: []string{"AC", "Definition of Done"}},
+	}
+	if got := cfg.Agent.TaskFormatRules.RequiredSections; !reflect.DeepEqual(got, want) {
+		t.Errorf("RequiredSections = %+v, want %+v", got, want)
+	}
+
+	_, err = LoadFromFile(writeConfigFile(t, "task_format_rules:\n  required_sections:\n    - aliases: [AC]\n"))
+	if err == nil || !strings.Contains(err.Error(), "needs a name") {
+		t.Errorf("expected a missing name error, got %v", err)
+	}
+}
+
+func TestLoadFromFile_UnknownKey(t *testing.T) {
+	_, err := LoadFromFile(writeConfigFile(t, "github:\n  ownr: acme\n"))
+	if err == nil || !strings.Contains(err.Error(), "ownr") {
+		t.Errorf("expected an unknown field error, got %v", err)
+	}
+}
+
+func TestLoad_DefaultFile(t *testing.T) {
+	t.Setenv("AGENT_CONFIG", "")
+	t.Setenv("GITHUB_OWNER", "")
+	t.Setenv("STALE_TASK_THRESHOLD_DAYS", "")
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	// Without a config file only the environment is used
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GitHub.Owner != "" || cfg.Agent.StaleTaskThresholdDays != 7 {
+		t.Errorf("unexpected config without a file: %+v", cfg)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, DefaultConfigFile), []byte("github:\n  owner: acme\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GitHub.Owner != "acme" {
+		t.Errorf("expected owner from %s, got %q", DefaultConfigFile, cfg.GitHub.Owner)
+	}
+}
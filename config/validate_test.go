@@ -0,0 +1,236 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func validConfig() *Config {
+	cfg := &Config{}
+	cfg.GitHub.Token = "token"
+	cfg.GitHub.Owner = "acme"
+	cfg.GitHub.Repo = "api"
+	cfg.GitHub.Mode = "repo"
+	cfg.GitHub.BaseURL = "https://api.github.com"
+	cfg.LLM.LiteLLMBaseURL = "http://localhost:4000"
+	cfg.Agent.StaleTaskThresholdDays = 7
+	cfg.Agent.MonitorOutput = "comments"
+	cfg.Agent.EscalateAction = "mention"
+	cfg.Agent.Concurrency = 4
+	cfg.Agent.ValidateOutput = "inline"
+	cfg.Agent.ReportMode = "create"
+	cfg.Agent.OnLLMFailure = "comment,label"
+	return cfg
+}
+
+func TestConfig_Validate(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		modify       func(*Config)
+		wantProblems []string
+	}{
+		{
+			name: "no credentials",
+			modify: func(c *Config) {
+				c.GitHub.Token = ""
+			},
+			wantProblems: []string{"either GITHUB_TOKEN or GitHub App credentials"},
+		},
+		{
+			name: "incomplete app credentials",
+			modify: func(c *Config) {
+				c.GitHub.AppID = 123
+				c.GitHub.PrivateKeyPath = "/missing.pem"
+				c.GitHub.privateKeyErr = fs.ErrNotExist
+			},
+			wantProblems: []string{"GITHUB_APP_INSTALLATION_ID is required", `GITHUB_APP_PRIVATE_KEY_PATH "/missing.pem" could not be read: file not found`},
+		},
+		{
+			name: "project mode without repos",
+			modify: func(c *Config) {
+				c.GitHub.Mode = "project"
+				c.GitHub.ProjectID = "7"
+			},
+			wantProblems: []string{"GITHUB_REPOS is required in project mode"},
+		},
+		{
+			name: "escalation without a fallback user",
+			modify: func(c *Config) {
+				c.Agent.EscalateAfterReminders = 3
+				c.Agent.EscalateAction = "page"
+			},
+			wantProblems: []string{
+				"ESCALATE_TO is required when ESCALATE_AFTER_REMINDERS is set",
+				`ESCALATE_ACTION must be mention or reassign, got "page"`,
+			},
+		},
+		{
+			name: "unknown log level and format",
+			modify: func(c *Config) {
+				c.Log.Level = "verbose"
+				c.Log.Format = "xml"
+			},
+			wantProblems: []string{
+				`LOG_LEVEL must be debug, info, warn or error, got "verbose"`,
+				`LOG_FORMAT must be text or json, got "xml"`,
+			},
+		},
+		{
+			name: "empty managed label prefix",
+			modify: func(c *Config) {
+				c.GitHub.ManagedLabelPrefixes = []string{"agent-", ""}
+			},
+			wantProblems: []string{
+				"MANAGED_LABEL_PREFIXES must not contain empty prefixes",
+			},
+		},
+		{
+			name: "unknown LLM provider",
+			modify: func(c *Config) {
+				c.LLM.Provider = "bedrock"
+			},
+			wantProblems: []string{
+				`LLM_PROVIDER must be one of litellm, openai, ollama, got "bedrock"`,
+			},
+		},
+		{
+			name: "comment prefix template that renders empty",
+			modify: func(c *Config) {
+				c.Agent.CommentPrefixTemplate = "{{.Agent}}"
+			},
+			wantProblems: []string{
+				`COMMENT_PREFIX_TEMPLATE is invalid: comment prefix template "{{.Agent}}" renders empty`,
+			},
+		},
+		{
+			name: "upload URL without scheme",
+			modify: func(c *Config) {
+				c.GitHub.UploadURL = "ghe.example.com/api/uploads"
+			},
+			wantProblems: []string{
+				`GITHUB_UPLOAD_URL "ghe.example.com/api/uploads" is invalid`,
+			},
+		},
+		{
+			name: "everything wrong at once",
+			modify: func(c *Config) {
+				c.GitHub.Owner = ""
+				c.GitHub.Repo = ""
+				c.LLM.LiteLLMBaseURL = "localhost:4000"
+				c.Agent.MonitorOutput = "email"
+				c.Agent.OnLLMFailure = "comment,retry"
+			},
+			wantProblems: []string{
+				"GITHUB_OWNER is required",
+				"GITHUB_REPO is required in repo mode",
+				`LITELLM_BASE_URL "localhost:4000" is invalid`,
+				`MONITOR_OUTPUT must be comments or digest, got "email"`,
+				"ON_LLM_FAILURE must be",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected a *ValidationError, got %v", err)
+			}
+			if len(validationErr.Problems) != len(tt.wantProblems) {
+				t.Fatalf("expected %d problems, got %d:\n%v", len(tt.wantProblems), len(validationErr.Problems), err)
+			}
+			for _, want := range tt.wantProblems {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error missing %q:\n%v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestConfig_ValidatePrivateKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	writeKey := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	rsaPath := writeKey("app.pem", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}))
+	junkPath := writeKey("junk.pem", []byte("not a key"))
+	ecPath := writeKey("ec.pem", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecDER}))
+	missingPath := filepath.Join(dir, "missing.pem")
+
+	tests := []struct {
+		name        string
+		path, env   string
+		wantProblem string
+	}{
+		{name: "valid RSA key", path: rsaPath},
+		{name: "missing file", path: missingPath, wantProblem: `GITHUB_APP_PRIVATE_KEY_PATH "` + missingPath + `" could not be read: file not found`},
+		{name: "junk key file", path: junkPath, wantProblem: `GITHUB_APP_PRIVATE_KEY_PATH "` + junkPath + `" is invalid: not a PEM key`},
+		{name: "non-RSA key", path: ecPath, wantProblem: "is invalid: not an RSA private key (got *ecdsa.PrivateKey)"},
+		{name: "junk key in environment", env: "junk", wantProblem: "GITHUB_APP_PRIVATE_KEY is invalid: not a PEM key"},
+		{name: "environment key backs up a missing file", path: missingPath, env: "junk", wantProblem: "GITHUB_APP_PRIVATE_KEY is invalid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_APP_ID", "123")
+			t.Setenv("GITHUB_APP_INSTALLATION_ID", "456")
+			t.Setenv("GITHUB_APP_PRIVATE_KEY_PATH", tt.path)
+			t.Setenv("GITHUB_APP_PRIVATE_KEY", tt.env)
+			loaded, err := load(&File{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg := validConfig()
+			cfg.GitHub = loaded.GitHub
+			cfg.GitHub.Owner, cfg.GitHub.Repo, cfg.GitHub.Mode = "acme", "api", "repo"
+			cfg.GitHub.BaseURL = "https://api.github.com"
+
+			err = cfg.Validate()
+			if tt.wantProblem == "" {
+				if err != nil {
+					t.Fatalf("expected a valid key, got %v", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || len(validationErr.Problems) != 1 || !strings.Contains(validationErr.Problems[0], tt.wantProblem) {
+				t.Fatalf("expected the problem %q, got %v", tt.wantProblem, err)
+			}
+		})
+	}
+}
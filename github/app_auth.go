@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -20,6 +22,16 @@ type AppAuth struct {
 	InstallationID int64
 	PrivateKey     *rsa.PrivateKey
 	BaseURL        string
+	TokenCachePath string // Optional file that keeps installation tokens between runs
+
+	// ResolveInstallations authenticates requests for a repository as the
+	// App installation on that repository's owner, for Apps installed
+	// separately on several orgs. Other requests use InstallationID.
+	ResolveInstallations bool
+
+	mu            sync.Mutex
+	installations map[string]int64             // Resolved installation by owner/repo
+	sources       map[int64]oauth2.TokenSource // Token source by installation
 }
 
 // NewAppAuth creates a new GitHub App authenticator
@@ -52,107 +64,158 @@ func (a *AppAuth) GenerateJWT() (string, error) {
 
 // GetInstallationToken gets an installation access token for the GitHub App
 func (a *AppAuth) GetInstallationToken(ctx context.Context) (string, error) {
+	token, _, err := a.requestInstallationToken(ctx, a.InstallationID)
+	return token, err
+}
+
+// requestInstallationToken asks GitHub for a new token for an installation
+// and returns it with its expiry
+func (a *AppAuth) requestInstallationToken(ctx context.Context, installationID int64) (string, time.Time, error) {
+	var tokenResponse struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	path := fmt.Sprintf("/app/installations/%d/access_tokens", installationID)
+	if err := a.appRequest(ctx, "POST", path, http.StatusCreated, &tokenResponse); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to get installation token: %w", err)
+	}
+
+	expires := tokenResponse.ExpiresAt
+	if expires.IsZero() {
+		// Installation tokens expire in 1 hour
+		expires = time.Now().Add(55 * time.Minute) // Use 55 minutes to be safe
+	}
+	return tokenResponse.Token, expires, nil
+}
+
+// appAPIError is an unexpected response to a request made as the App
+type appAPIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *appAPIError) Error() string {
+	return fmt.Sprintf("status %d, body: %s", e.StatusCode, e.Body)
+}
+
+// appRequest sends a request authenticated with the App's JWT to path below
+// the API base URL and decodes the response into out. A status other than
+// wantStatus is returned as an *appAPIError.
+func (a *AppAuth) appRequest(ctx context.Context, method, path string, wantStatus int, out interface{}) error {
 	jwtToken, err := a.GenerateJWT()
 	if err != nil {
-		return "", fmt.Errorf("failed to generate JWT: %w", err)
+		return fmt.Errorf("failed to generate JWT: %w", err)
 	}
 
 	// Determine API base URL
 	apiBaseURL := "https://api.github.com"
 	if a.BaseURL != "" && a.BaseURL != "https://api.github.com" {
-		apiBaseURL = a.BaseURL
+		apiBaseURL = strings.TrimSuffix(a.BaseURL, "/")
 	}
 
-	// Create request to get installation token
-	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", apiBaseURL, a.InstallationID)
-	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	req, err := http.NewRequestWithContext(ctx, method, apiBaseURL+path, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
-
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", jwtToken))
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to make request: %w", err)
+		return fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusCreated {
+	if resp.StatusCode != wantStatus {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("failed to get installation token: status %d, body: %s", resp.StatusCode, string(body))
-	}
-
-	var tokenResponse struct {
-		Token     string    `json:"token"`
-		ExpiresAt time.Time `json:"expires_at"`
+		return &appAPIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
 	}
-
-	return tokenResponse.Token, nil
+	return nil
 }
 
 // CreateOAuth2TokenSource creates an oauth2.TokenSource that automatically refreshes installation tokens
 func (a *AppAuth) CreateOAuth2TokenSource(ctx context.Context) oauth2.TokenSource {
-	return oauth2.ReuseTokenSource(nil, &appTokenSource{
-		appAuth: a,
-		ctx:     ctx,
+	return a.installationTokenSource(ctx, a.InstallationID)
+}
+
+// installationTokenSource returns the token source for an installation,
+// shared by every client of this App so each installation's token is
+// requested once
+func (a *AppAuth) installationTokenSource(ctx context.Context, installationID int64) oauth2.TokenSource {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if source, ok := a.sources[installationID]; ok {
+		return source
+	}
+	if a.sources == nil {
+		a.sources = make(map[int64]oauth2.TokenSource)
+	}
+	source := oauth2.ReuseTokenSource(nil, &appTokenSource{
+		appAuth:        a,
+		installationID: installationID,
+		ctx:            ctx,
 	})
+	a.sources[installationID] = source
+	return source
 }
 
 // appTokenSource implements oauth2.TokenSource for GitHub App tokens
 type appTokenSource struct {
-	appAuth *AppAuth
-	ctx     context.Context
-	token   *oauth2.Token
-	expires time.Time
+	appAuth        *AppAuth
+	installationID int64
+	ctx            context.Context
+	token          *oauth2.Token
+	expires        time.Time
 }
 
 func (ts *appTokenSource) Token() (*oauth2.Token, error) {
 	// Check if we have a valid token that hasn't expired
 	now := time.Now()
-	if ts.token != nil && ts.expires.After(now.Add(5*time.Minute)) {
+	if ts.token != nil && ts.expires.After(now.Add(tokenExpiryBuffer)) {
 		// Token is still valid (with 5 minute buffer)
 		return ts.token, nil
 	}
 
+	// Reuse a token an earlier run cached on disk
+	if tokenString, expires, ok := ts.appAuth.cachedToken(ts.installationID, now); ok {
+		ts.setToken(tokenString, expires)
+		return ts.token, nil
+	}
+
 	// Get a new installation token
-	tokenString, err := ts.appAuth.GetInstallationToken(ts.ctx)
+	tokenString, expires, err := ts.appAuth.requestInstallationToken(ts.ctx, ts.installationID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get installation token: %w", err)
 	}
+	ts.setToken(tokenString, expires)
+	ts.appAuth.cacheToken(ts.installationID, tokenString, expires)
 
-	// Installation tokens expire in 1 hour
-	ts.expires = time.Now().Add(55 * time.Minute) // Use 55 minutes to be safe
+	return ts.token, nil
+}
+
+func (ts *appTokenSource) setToken(tokenString string, expires time.Time) {
+	ts.expires = expires
 	ts.token = &oauth2.Token{
 		AccessToken: tokenString,
 		TokenType:   "token",
-		Expiry:      ts.expires,
+		Expiry:      expires,
 	}
-
-	return ts.token, nil
 }
 
-// CreateGitHubClient creates a GitHub client using App authentication
-func CreateGitHubClientWithApp(ctx context.Context, appAuth *AppAuth) (*github.Client, error) {
+// CreateGitHubClient creates a GitHub client using App authentication.
+// uploadURL defaults to the App's base URL when empty.
+func CreateGitHubClientWithApp(ctx context.Context, appAuth *AppAuth, uploadURL string) (*github.Client, error) {
+	if appAuth.ResolveInstallations {
+		return newInstallationClient(ctx, appAuth, uploadURL)
+	}
+
 	tokenSource := appAuth.CreateOAuth2TokenSource(ctx)
 	tc := oauth2.NewClient(ctx, tokenSource)
 
-	var client *github.Client
-	if appAuth.BaseURL != "" && appAuth.BaseURL != "https://api.github.com" {
-		var err error
-		client, err = github.NewClient(tc).WithEnterpriseURLs(appAuth.BaseURL, appAuth.BaseURL)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create GitHub Enterprise client: %w", err)
-		}
-	} else {
-		client = github.NewClient(tc)
-	}
-
-	return client, nil
+	return newGitHubClient(tc, appAuth.BaseURL, uploadURL)
 }
@@ -0,0 +1,86 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// addAssignees assigns users to an issue, keeping any existing assignees
+func addAssignees(ctx context.Context, client *github.Client, owner, repo string, number int, assignees ...string) error {
+	if _, _, err := client.Issues.AddAssignees(ctx, owner, repo, number, assignees); err != nil {
+		return fmt.Errorf("failed to assign issue: %w", err)
+	}
+	return nil
+}
+
+// AssignIssue assigns users to an issue (implements UnifiedClient interface)
+// In repo mode, owner and repo parameters are ignored
+func (c *Client) AssignIssue(ctx context.Context, owner, repo string, number int, assignees []string) error {
+	return c.retry.do(ctx, func(int) error {
+		return addAssignees(ctx, c.client, c.owner, c.repo, number, assignees...)
+	})
+}
+
+// AssignIssue assigns users to an issue in a specific repository
+func (pc *ProjectClient) AssignIssue(ctx context.Context, owner, repo string, number int, assignees []string) error {
+	return pc.retry.do(ctx, func(int) error {
+		return addAssignees(ctx, pc.client, owner, repo, number, assignees...)
+	})
+}
+
+// setAssignee replaces all assignees of an issue with login
+func setAssignee(ctx context.Context, client *github.Client, owner, repo string, number int, login string) error {
+	req := &github.IssueRequest{Assignees: &[]string{login}}
+	if _, _, err := client.Issues.Edit(ctx, owner, repo, number, req); err != nil {
+		return fmt.Errorf("failed to reassign issue: %w", err)
+	}
+	return nil
+}
+
+// SetAssignee makes login the only assignee of an issue (implements UnifiedClient interface)
+// In repo mode, owner and repo parameters are ignored
+func (c *Client) SetAssignee(ctx context.Context, owner, repo string, number int, login string) error {
+	return c.retry.do(ctx, func(int) error {
+		return setAssignee(ctx, c.client, c.owner, c.repo, number, login)
+	})
+}
+
+// SetAssignee makes login the only assignee of an issue in a specific repository
+func (pc *ProjectClient) SetAssignee(ctx context.Context, owner, repo string, number int, login string) error {
+	return pc.retry.do(ctx, func(int) error {
+		return setAssignee(ctx, pc.client, owner, repo, number, login)
+	})
+}
+
+// CreateAssignedIssue creates an issue and assigns it to assignee, if set.
+// A failed assignment is only a warning since the issue already exists; the
+// returned issue's Assignee is set when the assignment succeeded.
+func CreateAssignedIssue(ctx context.Context, client UnifiedClient, owner, repo, title, body string, labels []string, assignee string) (*Issue, error) {
+	issue, err := client.CreateIssue(ctx, owner, repo, title, body, labels)
+	if err != nil {
+		return nil, err
+	}
+	return assignCreatedIssue(ctx, client, owner, repo, issue, assignee), nil
+}
+
+// assignCreatedIssue assigns a just created issue to assignee, if set, only
+// warning when that fails
+func assignCreatedIssue(ctx context.Context, client UnifiedClient, owner, repo string, issue *Issue, assignee string) *Issue {
+	if assignee == "" {
+		return issue
+	}
+
+	// Created issues may land in a default repository, so take it from the URL
+	if createdOwner, createdRepo := ParseRepoFromURL(issue.URL); createdOwner != "" {
+		owner, repo = createdOwner, createdRepo
+	}
+	if err := client.AssignIssue(ctx, owner, repo, issue.Number, []string{assignee}); err != nil {
+		slog.Warn("failed to assign issue", "issue", issue.Number, "assignee", assignee, "error", err)
+		return issue
+	}
+	issue.Assignee = assignee
+	return issue
+}
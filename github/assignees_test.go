@@ -0,0 +1,91 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+)
+
+func TestCreateAssignedIssue(t *testing.T) {
+	var assigned []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/o/r/issues":
+			w.Write([]byte(`{"number": 5, "title": "Report", "state": "open", "html_url": "https://github.com/o/r/issues/5"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/o/r/issues/5/assignees":
+			var req struct {
+				Assignees []string `json:"assignees"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			assigned = req.Assignees
+			w.Write([]byte(`{"number": 5}`))
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	ghClient, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &UnifiedClientWrapper{
+		repoClient: &Client{client: ghClient, owner: "o", repo: "r"},
+		mode:       "repo",
+	}
+
+	issue, err := CreateAssignedIssue(context.Background(), client, "", "", "Report", "body", nil, "pm")
+	if err != nil {
+		t.Fatalf("CreateAssignedIssue failed: %v", err)
+	}
+	if issue.Assignee != "pm" {
+		t.Errorf("expected assignee pm on the result, got %q", issue.Assignee)
+	}
+	if len(assigned) != 1 || assigned[0] != "pm" {
+		t.Errorf("expected pm to be assigned via the API, got %v", assigned)
+	}
+
+	// Without an assignee only the issue is created
+	assigned = nil
+	issue, err = CreateAssignedIssue(context.Background(), client, "", "", "Report", "body", nil, "")
+	if err != nil {
+		t.Fatalf("CreateAssignedIssue failed: %v", err)
+	}
+	if issue.Assignee != "" || assigned != nil {
+		t.Errorf("expected no assignment, got %q / %v", issue.Assignee, assigned)
+	}
+}
+
+func TestSetAssignee(t *testing.T) {
+	var assignees []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/api/v3/repos/o/r/issues/5" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		var req struct {
+			Assignees []string `json:"assignees"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		assignees = req.Assignees
+		w.Write([]byte(`{"number": 5}`))
+	}))
+	defer server.Close()
+
+	ghClient, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &Client{client: ghClient, owner: "o", repo: "r"}
+
+	if err := client.SetAssignee(context.Background(), "", "", 5, "lead"); err != nil {
+		t.Fatalf("SetAssignee failed: %v", err)
+	}
+	if len(assignees) != 1 || assignees[0] != "lead" {
+		t.Errorf("expected assignees to be replaced with lead, got %v", assignees)
+	}
+}
@@ -3,6 +3,8 @@ package github
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/go-github/v57/github"
@@ -10,9 +12,11 @@ import (
 )
 
 type Client struct {
-	client *github.Client
-	owner  string
-	repo   string
+	client  *github.Client
+	owner   string
+	repo    string
+	appAuth *AppAuth // Set when authenticated as a GitHub App
+	retry   RetryPolicy
 }
 
 type Issue struct {
@@ -22,23 +26,45 @@ type Issue struct {
 	State     string
 	Labels    []string
 	Assignee  string
+	Author    string // Login of the user who opened the issue
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	ClosedAt  time.Time // Zero for open issues
 	URL       string
+	NodeID    string            // GraphQL node ID
+	Fields    map[string]string // Project field values by field name (e.g. "Status"), set in project mode
+
+	Milestone      string    // Milestone title, empty when the issue has none
+	MilestoneDueOn time.Time // Zero when the milestone has no due date
+}
+
+// Field returns the value of a project field such as "Status" or "Iteration".
+// Field names are matched case-insensitively. ok is false when the issue has
+// no value for the field, e.g. outside project mode.
+func (i *Issue) Field(name string) (value string, ok bool) {
+	if value, ok := i.Fields[name]; ok {
+		return value, true
+	}
+	for field, value := range i.Fields {
+		if strings.EqualFold(field, name) {
+			return value, true
+		}
+	}
+	return "", false
 }
 
 func NewClient(token, owner, repo, baseURL string) (*Client, error) {
-	return NewClientWithAuth(token, nil, owner, repo, baseURL)
+	return NewClientWithAuth(token, nil, owner, repo, baseURL, "")
 }
 
 // NewClientWithAuth creates a client with either token or GitHub App authentication
-func NewClientWithAuth(token string, appAuth *AppAuth, owner, repo, baseURL string) (*Client, error) {
+func NewClientWithAuth(token string, appAuth *AppAuth, owner, repo, baseURL, uploadURL string) (*Client, error) {
 	ctx := context.Background()
 	var client *github.Client
 
 	if appAuth != nil {
 		// Use GitHub App authentication
-		ghClient, err := CreateGitHubClientWithApp(ctx, appAuth)
+		ghClient, err := CreateGitHubClientWithApp(ctx, appAuth, uploadURL)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create GitHub client with app auth: %w", err)
 		}
@@ -50,26 +76,40 @@ func NewClientWithAuth(token string, appAuth *AppAuth, owner, repo, baseURL stri
 		)
 		tc := oauth2.NewClient(ctx, ts)
 
-		if baseURL != "" && baseURL != "https://api.github.com" {
-			var err error
-			client, err = github.NewClient(tc).WithEnterpriseURLs(baseURL, baseURL)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create GitHub Enterprise client: %w", err)
-			}
-		} else {
-			client = github.NewClient(tc)
+		var err error
+		client, err = newGitHubClient(tc, baseURL, uploadURL)
+		if err != nil {
+			return nil, err
 		}
 	} else {
 		return nil, fmt.Errorf("either token or GitHub App credentials must be provided")
 	}
 
 	return &Client{
-		client: client,
-		owner:  owner,
-		repo:   repo,
+		client:  client,
+		owner:   owner,
+		repo:    repo,
+		appAuth: appAuth,
 	}, nil
 }
 
+// newGitHubClient creates a go-github client over httpClient, pointed at a
+// GitHub Enterprise server unless baseURL is empty or github.com. uploadURL
+// defaults to baseURL when empty.
+func newGitHubClient(httpClient *http.Client, baseURL, uploadURL string) (*github.Client, error) {
+	if baseURL == "" || baseURL == "https://api.github.com" {
+		return github.NewClient(httpClient), nil
+	}
+	if uploadURL == "" {
+		uploadURL = baseURL
+	}
+	client, err := github.NewClient(httpClient).WithEnterpriseURLs(baseURL, uploadURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub Enterprise client: %w", err)
+	}
+	return client, nil
+}
+
 func (c *Client) ListIssues(ctx context.Context, state string) ([]*Issue, error) {
 	opts := &github.IssueListByRepoOptions{
 		State: state,
@@ -116,9 +156,15 @@ func (c *Client) ListIssues(ctx context.Context, state string) ([]*Issue, error)
 			State:     issue.GetState(),
 			Labels:    labels,
 			Assignee:  assignee,
+			Author:    issue.GetUser().GetLogin(),
 			CreatedAt: issue.GetCreatedAt().Time,
 			UpdatedAt: issue.GetUpdatedAt().Time,
+			ClosedAt:  issue.GetClosedAt().Time,
 			URL:       issue.GetHTMLURL(),
+			NodeID:    issue.GetNodeID(),
+
+			Milestone:      issue.GetMilestone().GetTitle(),
+			MilestoneDueOn: issue.GetMilestone().GetDueOn().Time,
 		}
 	}
 
@@ -159,9 +205,15 @@ func (c *Client) GetIssueFromRepo(ctx context.Context, owner, repo string, numbe
 		State:     issue.GetState(),
 		Labels:    labels,
 		Assignee:  assignee,
+		Author:    issue.GetUser().GetLogin(),
 		CreatedAt: issue.GetCreatedAt().Time,
 		UpdatedAt: issue.GetUpdatedAt().Time,
+		ClosedAt:  issue.GetClosedAt().Time,
 		URL:       issue.GetHTMLURL(),
+		NodeID:    issue.GetNodeID(),
+
+		Milestone:      issue.GetMilestone().GetTitle(),
+		MilestoneDueOn: issue.GetMilestone().GetDueOn().Time,
 	}, nil
 }
 
@@ -176,25 +228,18 @@ func (c *Client) UpdateIssue(ctx context.Context, owner, repo string, number int
 		issue.Body = body
 	}
 
-	_, _, err := c.client.Issues.Edit(ctx, c.owner, c.repo, number, issue)
-	if err != nil {
-		return fmt.Errorf("failed to update issue: %w", err)
-	}
-	return nil
+	return c.retry.do(ctx, func(int) error {
+		if _, _, err := c.client.Issues.Edit(ctx, c.owner, c.repo, number, issue); err != nil {
+			return fmt.Errorf("failed to update issue: %w", err)
+		}
+		return nil
+	})
 }
 
 // AddComment adds a comment to an issue (implements UnifiedClient interface)
 // In repo mode, owner and repo parameters are ignored
 func (c *Client) AddComment(ctx context.Context, owner, repo string, number int, comment string) error {
-	commentReq := &github.IssueComment{
-		Body: github.String(comment),
-	}
-
-	_, _, err := c.client.Issues.CreateComment(ctx, c.owner, c.repo, number, commentReq)
-	if err != nil {
-		return fmt.Errorf("failed to add comment: %w", err)
-	}
-	return nil
+	return addComment(ctx, c.client, c.retry, c.owner, c.repo, number, comment)
 }
 
 // GetMode returns the client mode
@@ -233,45 +278,22 @@ func (c *Client) CreateIssue(ctx context.Context, owner, repo, title, body strin
 		State:     issue.GetState(),
 		Labels:    resultLabels,
 		Assignee:  assignee,
+		Author:    issue.GetUser().GetLogin(),
 		CreatedAt: issue.GetCreatedAt().Time,
 		UpdatedAt: issue.GetUpdatedAt().Time,
+		ClosedAt:  issue.GetClosedAt().Time,
 		URL:       issue.GetHTMLURL(),
+		NodeID:    issue.GetNodeID(),
+
+		Milestone:      issue.GetMilestone().GetTitle(),
+		MilestoneDueOn: issue.GetMilestone().GetDueOn().Time,
 	}, nil
 }
 
 // AddLabel adds a label to an issue (implements UnifiedClient interface)
 // In repo mode, owner and repo parameters are ignored
 func (c *Client) AddLabel(ctx context.Context, owner, repo string, number int, label string) error {
-	// Get current issue to retrieve existing labels
-	issue, _, err := c.client.Issues.Get(ctx, c.owner, c.repo, number)
-	if err != nil {
-		return fmt.Errorf("failed to get issue: %w", err)
-	}
-
-	// Check if label already exists
-	for _, existingLabel := range issue.Labels {
-		if existingLabel.GetName() == label {
-			// Label already exists, nothing to do
-			return nil
-		}
-	}
-
-	// Add the new label to the list
-	labels := make([]string, len(issue.Labels)+1)
-	for i, l := range issue.Labels {
-		labels[i] = l.GetName()
-	}
-	labels[len(issue.Labels)] = label
-
-	// Update issue with new labels
-	issueReq := &github.IssueRequest{
-		Labels: &labels,
-	}
-
-	_, _, err = c.client.Issues.Edit(ctx, c.owner, c.repo, number, issueReq)
-	if err != nil {
-		return fmt.Errorf("failed to add label: %w", err)
-	}
-
-	return nil
+	return c.retry.do(ctx, func(int) error {
+		return addLabels(ctx, c.client, c.owner, c.repo, number, label)
+	})
 }
@@ -0,0 +1,72 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+)
+
+func TestListIssues_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var pages int32
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := atomic.AddInt32(&pages, 1)
+		// Every page links to another, so only cancellation ends the loop
+		w.Header().Set("Link", fmt.Sprintf(`<%s/api/v3/repos/o/r/issues?page=%d>; rel="next"`, serverURL, page+1))
+		w.Write([]byte(`[{"number": 1, "title": "Task", "state": "open"}]`))
+		if page == 2 {
+			cancel()
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	ghClient, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &Client{client: ghClient, owner: "o", repo: "r"}
+
+	if _, err := client.ListIssues(ctx, "open"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the listing to be cancelled, got %v", err)
+	}
+	if got := atomic.LoadInt32(&pages); got > 3 {
+		t.Errorf("expected the loop to stop right after cancellation, fetched %d pages", got)
+	}
+}
+
+func TestNewClientWithAuth_UploadURL(t *testing.T) {
+	tests := []struct {
+		name, uploadURL, want string
+	}{
+		{"defaults to base URL", "", "https://ghe.example.com/api/uploads/"},
+		{"separate upload host", "https://uploads.ghe.example.com/", "https://uploads.ghe.example.com/api/uploads/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClientWithAuth("token", nil, "o", "r", "https://ghe.example.com/", tt.uploadURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := client.client.UploadURL.String(); got != tt.want {
+				t.Errorf("UploadURL = %s, want %s", got, tt.want)
+			}
+			project, err := NewProjectClientWithAuth("token", nil, "o", "7", "https://ghe.example.com/", tt.uploadURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := project.client.UploadURL.String(); got != tt.want {
+				t.Errorf("project UploadURL = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
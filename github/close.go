@@ -0,0 +1,35 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// closeIssue closes an issue as not planned
+func closeIssue(ctx context.Context, client *github.Client, owner, repo string, number int) error {
+	req := &github.IssueRequest{
+		State:       github.String("closed"),
+		StateReason: github.String("not_planned"),
+	}
+	if _, _, err := client.Issues.Edit(ctx, owner, repo, number, req); err != nil {
+		return fmt.Errorf("failed to close issue: %w", err)
+	}
+	return nil
+}
+
+// CloseIssue closes an issue as not planned (implements UnifiedClient interface)
+// In repo mode, owner and repo parameters are ignored
+func (c *Client) CloseIssue(ctx context.Context, owner, repo string, number int) error {
+	return c.retry.do(ctx, func(int) error {
+		return closeIssue(ctx, c.client, c.owner, c.repo, number)
+	})
+}
+
+// CloseIssue closes an issue in a specific repository as not planned
+func (pc *ProjectClient) CloseIssue(ctx context.Context, owner, repo string, number int) error {
+	return pc.retry.do(ctx, func(int) error {
+		return closeIssue(ctx, pc.client, owner, repo, number)
+	})
+}
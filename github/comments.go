@@ -0,0 +1,71 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// Comment is a comment on an issue
+type Comment struct {
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// listComments returns all comments on an issue, oldest first
+func listComments(ctx context.Context, client *github.Client, owner, repo string, number int) ([]Comment, error) {
+	opts := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var comments []Comment
+	for {
+		page, resp, err := client.Issues.ListComments(ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list comments: %w", err)
+		}
+		for _, c := range page {
+			comments = append(comments, Comment{
+				Author:    c.GetUser().GetLogin(),
+				Body:      c.GetBody(),
+				CreatedAt: c.GetCreatedAt().Time,
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return comments, nil
+}
+
+// addComment comments on an issue. A failed attempt may still have posted
+// the comment, so retries first check whether it is already the last one.
+func addComment(ctx context.Context, client *github.Client, retry RetryPolicy, owner, repo string, number int, comment string) error {
+	return retry.do(ctx, func(attempt int) error {
+		if attempt > 0 {
+			comments, err := listComments(ctx, client, owner, repo, number)
+			if err == nil && len(comments) > 0 && comments[len(comments)-1].Body == comment {
+				return nil
+			}
+		}
+		if _, _, err := client.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{Body: github.String(comment)}); err != nil {
+			return fmt.Errorf("failed to add comment: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetIssueComments returns the comments on an issue (implements UnifiedClient interface)
+// In repo mode, owner and repo parameters are ignored
+func (c *Client) GetIssueComments(ctx context.Context, owner, repo string, number int) ([]Comment, error) {
+	return listComments(ctx, c.client, c.owner, c.repo, number)
+}
+
+// GetIssueComments returns the comments on an issue in a specific repository
+func (pc *ProjectClient) GetIssueComments(ctx context.Context, owner, repo string, number int) ([]Comment, error) {
+	return listComments(ctx, pc.client, owner, repo, number)
+}
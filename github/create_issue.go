@@ -0,0 +1,116 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// CreateIssueIfNotExists creates an issue unless an open one with the same
+// title (ignoring case) and all of labels already exists, e.g. from an
+// earlier attempt that failed after creating it. It returns the issue and
+// whether it was created. When owner and repo are set, only issues in that
+// repository count. If open issues can't be listed, the issue is created.
+func CreateIssueIfNotExists(ctx context.Context, client UnifiedClient, owner, repo, title, body string, labels []string) (*Issue, bool, error) {
+	openIssues, err := client.ListIssues(ctx, "open")
+	if err != nil {
+		slog.Warn("could not check for an existing issue, creating it anyway", "title", title, "error", err)
+	}
+	if existing := findOpenIssue(openIssues, owner, repo, title, labels); existing != nil {
+		slog.Info("issue already exists, not creating it again", "issue", existing.Number, "title", title)
+		return existing, false, nil
+	}
+
+	issue, err := client.CreateIssue(ctx, owner, repo, title, body, labels)
+	if err != nil {
+		return nil, false, err
+	}
+	return issue, true, nil
+}
+
+// CreateAssignedIssueIfNotExists is CreateIssueIfNotExists followed by
+// assigning a newly created issue like CreateAssignedIssue
+func CreateAssignedIssueIfNotExists(ctx context.Context, client UnifiedClient, owner, repo, title, body string, labels []string, assignee string) (*Issue, bool, error) {
+	issue, created, err := CreateIssueIfNotExists(ctx, client, owner, repo, title, body, labels)
+	if err != nil || !created {
+		return issue, created, err
+	}
+	return assignCreatedIssue(ctx, client, owner, repo, issue, assignee), true, nil
+}
+
+// UpdateOrCreateIssue keeps a report in a single issue: it rewrites the title
+// and body of the latest of openIssues carrying every label in labels or,
+// when there is none and create is set, creates the issue like
+// CreateAssignedIssue. It returns the issue, nil if nothing was published,
+// and whether it was created.
+func UpdateOrCreateIssue(ctx context.Context, client UnifiedClient, openIssues []*Issue, owner, repo, title, body string, labels []string, assignee string, create bool) (*Issue, bool, error) {
+	if existing := LatestWithLabels(openIssues, labels); existing != nil {
+		existingOwner, existingRepo := ParseRepoFromURL(existing.URL)
+		if err := client.UpdateIssue(ctx, existingOwner, existingRepo, existing.Number, &title, &body); err != nil {
+			return nil, false, fmt.Errorf("failed to update issue #%d: %w", existing.Number, err)
+		}
+		return existing, false, nil
+	}
+	if !create {
+		return nil, false, nil
+	}
+
+	issue, err := CreateAssignedIssue(ctx, client, owner, repo, title, body, labels, assignee)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create issue: %w", err)
+	}
+	return issue, true, nil
+}
+
+// LatestWithLabels returns the most recently created issue carrying every
+// label in labels, or nil
+func LatestWithLabels(issues []*Issue, labels []string) *Issue {
+	var latest *Issue
+	for _, issue := range issues {
+		if !HasLabels(issue.Labels, labels) {
+			continue
+		}
+		if latest == nil || issue.CreatedAt.After(latest.CreatedAt) ||
+			(issue.CreatedAt.Equal(latest.CreatedAt) && issue.Number > latest.Number) {
+			latest = issue
+		}
+	}
+	return latest
+}
+
+// findOpenIssue returns the issue in issues with title and every label in
+// labels, limited to owner/repo when set
+func findOpenIssue(issues []*Issue, owner, repo, title string, labels []string) *Issue {
+	title = strings.TrimSpace(title)
+	for _, issue := range issues {
+		if !strings.EqualFold(strings.TrimSpace(issue.Title), title) || !HasLabels(issue.Labels, labels) {
+			continue
+		}
+		if owner != "" && repo != "" {
+			issueOwner, issueRepo := ParseRepoFromURL(issue.URL)
+			if !strings.EqualFold(issueOwner, owner) || !strings.EqualFold(issueRepo, repo) {
+				continue
+			}
+		}
+		return issue
+	}
+	return nil
+}
+
+// HasLabels reports whether labels contains every label in want, ignoring case
+func HasLabels(labels, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, label := range labels {
+			if strings.EqualFold(label, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// issueListCreator records CreateIssue and UpdateIssue calls against a fixed
+// list of open issues
+type issueListCreator struct {
+	UnifiedClient
+	open    []*Issue
+	listErr error
+	created []string
+	updated []int
+}
+
+func (c *issueListCreator) ListIssues(ctx context.Context, state string) ([]*Issue, error) {
+	return c.open, c.listErr
+}
+
+func (c *issueListCreator) CreateIssue(ctx context.Context, owner, repo, title, body string, labels []string) (*Issue, error) {
+	c.created = append(c.created, title)
+	return &Issue{Number: 100, Title: title, Labels: labels}, nil
+}
+
+func (c *issueListCreator) UpdateIssue(ctx context.Context, owner, repo string, number int, title, body *string) error {
+	c.updated = append(c.updated, number)
+	return nil
+}
+
+func TestCreateIssueIfNotExists(t *testing.T) {
+	open := []*Issue{
+		{Number: 7, Title: "Executive Summary - 2024-05-01", Labels: []string{"Report", "executive-summary"}, URL: "https://github.com/o/r/issues/7"},
+	}
+	labels := []string{"report", "executive-summary"}
+
+	tests := []struct {
+		name        string
+		owner, repo string
+		title       string
+		labels      []string
+		listErr     error
+		wantCreated bool
+	}{
+		{name: "same title and labels returns the existing issue", owner: "o", repo: "r", title: " executive summary - 2024-05-01", labels: labels},
+		{name: "repository left unset matches any repository", title: "Executive Summary - 2024-05-01", labels: labels},
+		{name: "different title is created", owner: "o", repo: "r", title: "Executive Summary - 2024-05-08", labels: labels, wantCreated: true},
+		{name: "missing label is created", owner: "o", repo: "r", title: "Executive Summary - 2024-05-01", labels: []string{"report", "progress"}, wantCreated: true},
+		{name: "other repository is created", owner: "o", repo: "other", title: "Executive Summary - 2024-05-01", labels: labels, wantCreated: true},
+		{name: "list failure still creates", title: "Executive Summary - 2024-05-01", labels: labels, listErr: errors.New("boom"), wantCreated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &issueListCreator{open: open, listErr: tt.listErr}
+			if tt.listErr != nil {
+				client.open = nil
+			}
+
+			issue, created, err := CreateIssueIfNotExists(context.Background(), client, tt.owner, tt.repo, tt.title, "body", tt.labels)
+			if err != nil {
+				t.Fatalf("CreateIssueIfNotExists failed: %v", err)
+			}
+			if created != tt.wantCreated {
+				t.Fatalf("created = %v, want %v", created, tt.wantCreated)
+			}
+			if tt.wantCreated {
+				if issue.Number != 100 || len(client.created) != 1 {
+					t.Errorf("expected a new issue, got #%d with creates %v", issue.Number, client.created)
+				}
+				return
+			}
+			if issue.Number != 7 || len(client.created) != 0 {
+				t.Errorf("expected existing #7 without creating, got #%d with creates %v", issue.Number, client.created)
+			}
+		})
+	}
+}
+
+func TestUpdateOrCreateIssue(t *testing.T) {
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	open := []*Issue{
+		{Number: 3, Title: "Digest", Labels: []string{"agent-generated", "stale-digest"}, CreatedAt: base},
+		{Number: 5, Title: "Digest", Labels: []string{"Agent-Generated", "Stale-Digest"}, CreatedAt: base.Add(time.Hour)},
+		{Number: 8, Title: "Report", Labels: []string{"agent-generated", "validation-report"}, CreatedAt: base.Add(2 * time.Hour)},
+	}
+	ctx := context.Background()
+
+	// The latest matching issue is rewritten, ignoring label case
+	client := &issueListCreator{}
+	issue, created, err := UpdateOrCreateIssue(ctx, client, open, "", "", "Digest", "body", []string{"agent-generated", "stale-digest"}, "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || issue.Number != 5 || len(client.updated) != 1 || client.updated[0] != 5 || len(client.created) != 0 {
+		t.Errorf("expected #5 updated in place, got #%d (created %v, updates %v, creates %v)", issue.Number, created, client.updated, client.created)
+	}
+
+	// Without a match the issue is created, unless create is off
+	issue, created, err = UpdateOrCreateIssue(ctx, client, open, "", "", "Summary", "body", []string{"executive-summary"}, "", false)
+	if err != nil || issue != nil || created {
+		t.Errorf("expected nothing published, got %v, %v, %v", issue, created, err)
+	}
+	issue, created, err = UpdateOrCreateIssue(ctx, client, open, "", "", "Summary", "body", []string{"executive-summary"}, "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created || issue.Number != 100 || len(client.created) != 1 {
+		t.Errorf("expected a new issue, got #%d (created %v, creates %v)", issue.Number, created, client.created)
+	}
+}
@@ -0,0 +1,97 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// Deployment is a GitHub deployment of a ref to an environment
+type Deployment struct {
+	ID          int64
+	Environment string
+	Ref         string
+	SHA         string
+	Description string
+	Creator     string
+	CreatedAt   time.Time
+	URL         string // API URL; deployments have no HTML page
+}
+
+// DeploymentStatus is one state change of a deployment
+type DeploymentStatus struct {
+	State       string // error, failure, inactive, in_progress, queued, pending or success
+	Description string
+	Creator     string
+	CreatedAt   time.Time
+	LogURL      string
+}
+
+// listDeployments returns the most recent deployments of a repository, newest first
+func listDeployments(ctx context.Context, client *github.Client, owner, repo string) ([]*Deployment, error) {
+	deployments, _, err := client.Repositories.ListDeployments(ctx, owner, repo, &github.DeploymentsListOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	result := make([]*Deployment, len(deployments))
+	for i, d := range deployments {
+		result[i] = &Deployment{
+			ID:          d.GetID(),
+			Environment: d.GetEnvironment(),
+			Ref:         d.GetRef(),
+			SHA:         d.GetSHA(),
+			Description: d.GetDescription(),
+			Creator:     d.GetCreator().GetLogin(),
+			CreatedAt:   d.GetCreatedAt().Time,
+			URL:         d.GetURL(),
+		}
+	}
+	return result, nil
+}
+
+// deploymentStatuses returns the statuses of a deployment, newest first
+func deploymentStatuses(ctx context.Context, client *github.Client, owner, repo string, id int64) ([]*DeploymentStatus, error) {
+	statuses, _, err := client.Repositories.ListDeploymentStatuses(ctx, owner, repo, id, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statuses of deployment %d: %w", id, err)
+	}
+
+	result := make([]*DeploymentStatus, len(statuses))
+	for i, s := range statuses {
+		result[i] = &DeploymentStatus{
+			State:       s.GetState(),
+			Description: s.GetDescription(),
+			Creator:     s.GetCreator().GetLogin(),
+			CreatedAt:   s.GetCreatedAt().Time,
+			LogURL:      s.GetLogURL(),
+		}
+	}
+	return result, nil
+}
+
+// ListDeployments returns recent deployments (implements UnifiedClient interface)
+// In repo mode, owner and repo parameters are ignored
+func (c *Client) ListDeployments(ctx context.Context, owner, repo string) ([]*Deployment, error) {
+	return listDeployments(ctx, c.client, c.owner, c.repo)
+}
+
+// GetDeploymentStatuses returns a deployment's statuses (implements UnifiedClient interface)
+// In repo mode, owner and repo parameters are ignored
+func (c *Client) GetDeploymentStatuses(ctx context.Context, owner, repo string, id int64) ([]*DeploymentStatus, error) {
+	return deploymentStatuses(ctx, c.client, c.owner, c.repo, id)
+}
+
+// ListDeployments returns recent deployments of a specific repository
+func (pc *ProjectClient) ListDeployments(ctx context.Context, owner, repo string) ([]*Deployment, error) {
+	return listDeployments(ctx, pc.client, owner, repo)
+}
+
+// GetDeploymentStatuses returns a deployment's statuses in a specific repository
+func (pc *ProjectClient) GetDeploymentStatuses(ctx context.Context, owner, repo string, id int64) ([]*DeploymentStatus, error) {
+	return deploymentStatuses(ctx, pc.client, owner, repo, id)
+}
@@ -0,0 +1,46 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+)
+
+func TestDeployments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/o/r/deployments":
+			w.Write([]byte(`[{"id":7,"environment":"production","ref":"main","sha":"abc1234def","creator":{"login":"ci"},"created_at":"2024-01-01T10:00:00Z"}]`))
+		case "/api/v3/repos/o/r/deployments/7/statuses":
+			w.Write([]byte(`[{"state":"failure","description":"health check failed","log_url":"https://ci/7","created_at":"2024-01-01T10:05:00Z"},{"state":"in_progress","created_at":"2024-01-01T10:01:00Z"}]`))
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	gh, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &Client{client: gh, owner: "o", repo: "r"}
+
+	deployments, err := client.ListDeployments(context.Background(), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deployments) != 1 || deployments[0].ID != 7 || deployments[0].Environment != "production" || deployments[0].Creator != "ci" {
+		t.Fatalf("unexpected deployments: %+v", deployments)
+	}
+
+	statuses, err := client.GetDeploymentStatuses(context.Background(), "", "", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || statuses[0].State != "failure" || statuses[0].LogURL != "https://ci/7" {
+		t.Errorf("unexpected statuses: %+v", statuses)
+	}
+}
@@ -0,0 +1,94 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// dryRunClient wraps a UnifiedClient so writes are logged instead of sent.
+// Reads pass through; any write method added to UnifiedClient must be
+// overridden here.
+type dryRunClient struct {
+	UnifiedClient
+	out io.Writer
+}
+
+// NewDryRunClient returns a client that performs reads but only logs writes
+// to stdout
+func NewDryRunClient(client UnifiedClient) UnifiedClient {
+	return NewDryRunClientWithOutput(client, os.Stdout)
+}
+
+// NewDryRunClientWithOutput returns a client that performs reads but only
+// logs writes to out
+func NewDryRunClientWithOutput(client UnifiedClient, out io.Writer) UnifiedClient {
+	return &dryRunClient{UnifiedClient: client, out: out}
+}
+
+func (d *dryRunClient) UpdateIssue(ctx context.Context, owner, repo string, number int, title, body *string) error {
+	var changes []string
+	if title != nil {
+		changes = append(changes, fmt.Sprintf("title to %q", *title))
+	}
+	if body != nil {
+		changes = append(changes, fmt.Sprintf("body (%d chars)", len(*body)))
+	}
+	fmt.Fprintf(d.out, "[dry-run] Would update %s: %s\n", issueRef(owner, repo, number), strings.Join(changes, ", "))
+	return nil
+}
+
+func (d *dryRunClient) AddComment(ctx context.Context, owner, repo string, number int, comment string) error {
+	fmt.Fprintf(d.out, "[dry-run] Would comment on %s:\n%s\n", issueRef(owner, repo, number), comment)
+	return nil
+}
+
+func (d *dryRunClient) CreateIssue(ctx context.Context, owner, repo, title, body string, labels []string) (*Issue, error) {
+	fmt.Fprintf(d.out, "[dry-run] Would create issue %q with labels [%s] (%d chars)\n", title, strings.Join(labels, ", "), len(body))
+	now := time.Now()
+	return &Issue{
+		Title:     title,
+		Body:      body,
+		State:     "open",
+		Labels:    labels,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+func (d *dryRunClient) AddLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	fmt.Fprintf(d.out, "[dry-run] Would add label %q to %s\n", label, issueRef(owner, repo, number))
+	return nil
+}
+
+func (d *dryRunClient) RemoveLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	fmt.Fprintf(d.out, "[dry-run] Would remove label %q from %s\n", label, issueRef(owner, repo, number))
+	return nil
+}
+
+func (d *dryRunClient) AssignIssue(ctx context.Context, owner, repo string, number int, assignees []string) error {
+	fmt.Fprintf(d.out, "[dry-run] Would assign %s to %s\n", issueRef(owner, repo, number), strings.Join(assignees, ", "))
+	return nil
+}
+
+func (d *dryRunClient) SetAssignee(ctx context.Context, owner, repo string, number int, login string) error {
+	fmt.Fprintf(d.out, "[dry-run] Would reassign %s to %s\n", issueRef(owner, repo, number), login)
+	return nil
+}
+
+func (d *dryRunClient) CloseIssue(ctx context.Context, owner, repo string, number int) error {
+	fmt.Fprintf(d.out, "[dry-run] Would close %s\n", issueRef(owner, repo, number))
+	return nil
+}
+
+// issueRef formats an issue reference, omitting the repository when it
+// isn't known (repo mode)
+func issueRef(owner, repo string, number int) string {
+	if owner == "" || repo == "" {
+		return fmt.Sprintf("issue #%d", number)
+	}
+	return fmt.Sprintf("%s/%s#%d", owner, repo, number)
+}
@@ -0,0 +1,91 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+)
+
+func TestDryRunClient_SkipsWrites(t *testing.T) {
+	var writes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writes = append(writes, r.Method+" "+r.URL.Path)
+		}
+		w.Write([]byte(`{"number": 1, "title": "Bug", "state": "open"}`))
+	}))
+	defer server.Close()
+
+	ghClient, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	client := NewDryRunClientWithOutput(&UnifiedClientWrapper{
+		repoClient: &Client{client: ghClient, owner: "o", repo: "r"},
+		mode:       "repo",
+	}, &out)
+	ctx := context.Background()
+
+	// Reads still reach GitHub
+	issue, err := client.GetIssue(ctx, "", "", 1)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if issue.Title != "Bug" {
+		t.Errorf("expected the real issue, got %+v", issue)
+	}
+
+	body := "new body"
+	if err := client.UpdateIssue(ctx, "", "", 1, nil, &body); err != nil {
+		t.Errorf("UpdateIssue: %v", err)
+	}
+	if err := client.AddComment(ctx, "", "", 1, "hello"); err != nil {
+		t.Errorf("AddComment: %v", err)
+	}
+	if err := client.AddLabel(ctx, "", "", 1, "bug"); err != nil {
+		t.Errorf("AddLabel: %v", err)
+	}
+	if err := client.RemoveLabel(ctx, "", "", 1, "bug"); err != nil {
+		t.Errorf("RemoveLabel: %v", err)
+	}
+	if err := client.AssignIssue(ctx, "", "", 1, []string{"alice"}); err != nil {
+		t.Errorf("AssignIssue: %v", err)
+	}
+	if err := client.SetAssignee(ctx, "", "", 1, "bob"); err != nil {
+		t.Errorf("SetAssignee: %v", err)
+	}
+	if err := client.CloseIssue(ctx, "", "", 1); err != nil {
+		t.Errorf("CloseIssue: %v", err)
+	}
+	created, err := client.CreateIssue(ctx, "", "", "Report", "body", []string{"agent-generated"})
+	if err != nil {
+		t.Errorf("CreateIssue: %v", err)
+	}
+	if created == nil || created.Title != "Report" {
+		t.Errorf("expected a placeholder issue, got %+v", created)
+	}
+
+	if len(writes) != 0 {
+		t.Errorf("dry run sent writes to GitHub: %v", writes)
+	}
+	for _, want := range []string{
+		"[dry-run] Would update issue #1: body (8 chars)\n",
+		"[dry-run] Would comment on issue #1:\nhello\n",
+		"[dry-run] Would assign issue #1 to alice\n",
+		"[dry-run] Would close issue #1\n",
+		"[dry-run] Would create issue \"Report\" with labels [agent-generated] (4 chars)\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the dry-run log to contain %q, got:\n%s", want, out.String())
+		}
+	}
+	if client.GetMode() != "repo" {
+		t.Errorf("expected mode to pass through, got %q", client.GetMode())
+	}
+}
@@ -0,0 +1,57 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// IssueEvent is an entry in an issue's timeline
+type IssueEvent struct {
+	Event     string // e.g. "commented", "assigned", "labeled", "reopened"
+	Actor     string // Login of the user or app that caused the event
+	CreatedAt time.Time
+}
+
+// listIssueEvents returns an issue's timeline, oldest first
+func listIssueEvents(ctx context.Context, client *github.Client, owner, repo string, number int) ([]IssueEvent, error) {
+	opts := &github.ListOptions{PerPage: 100}
+
+	var events []IssueEvent
+	for {
+		page, resp, err := client.Issues.ListIssueTimeline(ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issue events: %w", err)
+		}
+		for _, e := range page {
+			actor := e.GetActor().GetLogin()
+			if actor == "" {
+				// Comments name their author as the user
+				actor = e.GetUser().GetLogin()
+			}
+			events = append(events, IssueEvent{
+				Event:     e.GetEvent(),
+				Actor:     actor,
+				CreatedAt: e.GetCreatedAt().Time,
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return events, nil
+}
+
+// ListIssueEvents returns the timeline of an issue (implements UnifiedClient interface)
+// In repo mode, owner and repo parameters are ignored
+func (c *Client) ListIssueEvents(ctx context.Context, owner, repo string, number int) ([]IssueEvent, error) {
+	return listIssueEvents(ctx, c.client, c.owner, c.repo, number)
+}
+
+// ListIssueEvents returns the timeline of an issue in a specific repository
+func (pc *ProjectClient) ListIssueEvents(ctx context.Context, owner, repo string, number int) ([]IssueEvent, error) {
+	return listIssueEvents(ctx, pc.client, owner, repo, number)
+}
@@ -0,0 +1,52 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+)
+
+func TestListIssueEvents(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/o/r/issues/5/timeline" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`[{"event": "commented", "user": {"login": "dev"}, "created_at": "2024-05-03T10:00:00Z"}]`))
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s/api/v3/repos/o/r/issues/5/timeline?page=2>; rel="next"`, server.URL))
+		w.Write([]byte(`[{"event": "labeled", "actor": {"login": "triage-bot[bot]"}, "created_at": "2024-05-01T10:00:00Z"},
+			{"event": "reopened", "actor": {"login": "pm"}, "created_at": "2024-05-02T10:00:00Z"}]`))
+	}))
+	defer server.Close()
+
+	ghClient, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &UnifiedClientWrapper{
+		repoClient: &Client{client: ghClient, owner: "o", repo: "r"},
+		mode:       "repo",
+	}
+
+	events, err := client.ListIssueEvents(context.Background(), "", "", 5)
+	if err != nil {
+		t.Fatalf("ListIssueEvents failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected events from both pages, got %+v", events)
+	}
+	if events[0].Event != "labeled" || events[0].Actor != "triage-bot[bot]" || events[0].CreatedAt.Day() != 1 {
+		t.Errorf("unexpected first event %+v", events[0])
+	}
+	if events[2].Event != "commented" || events[2].Actor != "dev" {
+		t.Errorf("expected the comment author as actor, got %+v", events[2])
+	}
+}
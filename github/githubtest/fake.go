@@ -0,0 +1,357 @@
+// Package githubtest provides an in-memory github.UnifiedClient for tests.
+package githubtest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/kaskol10/github-project-agent/github"
+)
+
+// Call is one method call recorded by FakeClient
+type Call struct {
+	Method string
+	Owner  string
+	Repo   string
+	Number int // Issue or pull request number, 0 when the method has none
+	Args   []interface{}
+}
+
+// FakeClient is an in-memory github.UnifiedClient. Preload Issues,
+// PullRequests and the other read fields, then assert on the recorded
+// writes. Writes are recorded only: they don't change the preloaded issues
+// or IssueComments, except that AssignIssue sets the assignee of a known
+// issue. The zero value is ready to use.
+type FakeClient struct {
+	mu sync.Mutex
+
+	// Reads
+	Issues             []*github.Issue
+	PullRequests       []*github.PullRequest
+	LinkedPRs          map[int][]*github.PullRequest
+	IssueComments      map[int][]github.Comment    // Returned by GetIssueComments
+	IssueEvents        map[int][]github.IssueEvent // Returned by ListIssueEvents
+	Diffs              map[int]string
+	Deployments        []*github.Deployment
+	DeploymentStatuses map[int64][]*github.DeploymentStatus
+	Login              string // Returned by WhoAmI, defaults to "agent-bot"
+	IsApp              bool
+	Mode               string // Returned by GetMode, defaults to "repo"
+
+	// Errors maps a method name, e.g. "AddComment", to the error it returns
+	Errors map[string]error
+
+	// Writes
+	Calls         []Call
+	UpdatedIssues map[int]*github.Issue // Title and body set by UpdateIssue
+	Comments      map[int][]string
+	CreatedIssues []*github.Issue  // Numbered from 1000
+	Labels        map[int][]string // Added and not since removed
+	Assigned      map[int][]string // Added by AssignIssue
+	Reassigned    map[int]string   // Set by SetAssignee
+	Closed        map[int]bool
+}
+
+var _ github.UnifiedClient = (*FakeClient)(nil)
+
+// NewFakeClient returns a FakeClient serving issues, with every map made so
+// tests can preload entries directly
+func NewFakeClient(issues ...*github.Issue) *FakeClient {
+	return &FakeClient{
+		Issues:             issues,
+		LinkedPRs:          make(map[int][]*github.PullRequest),
+		IssueComments:      make(map[int][]github.Comment),
+		IssueEvents:        make(map[int][]github.IssueEvent),
+		Diffs:              make(map[int]string),
+		DeploymentStatuses: make(map[int64][]*github.DeploymentStatus),
+		Errors:             make(map[string]error),
+		UpdatedIssues:      make(map[int]*github.Issue),
+		Comments:           make(map[int][]string),
+		Labels:             make(map[int][]string),
+		Assigned:           make(map[int][]string),
+		Reassigned:         make(map[int]string),
+		Closed:             make(map[int]bool),
+	}
+}
+
+// CallsTo returns the recorded calls of method
+func (f *FakeClient) CallsTo(method string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var calls []Call
+	for _, call := range f.Calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// record logs a call and returns the error configured for its method.
+// Callers hold f.mu.
+func (f *FakeClient) record(method, owner, repo string, number int, args ...interface{}) error {
+	f.Calls = append(f.Calls, Call{Method: method, Owner: owner, Repo: repo, Number: number, Args: args})
+	return f.Errors[method]
+}
+
+// issue returns the preloaded or created issue with number, or nil
+func (f *FakeClient) issue(number int) *github.Issue {
+	for _, issue := range f.Issues {
+		if issue.Number == number {
+			return issue
+		}
+	}
+	for _, issue := range f.CreatedIssues {
+		if issue.Number == number {
+			return issue
+		}
+	}
+	return nil
+}
+
+func (f *FakeClient) ListIssues(ctx context.Context, state string) ([]*github.Issue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("ListIssues", "", "", 0, state); err != nil {
+		return nil, err
+	}
+	return f.Issues, nil
+}
+
+func (f *FakeClient) ListIssuesPartial(ctx context.Context, state string) ([]*github.Issue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("ListIssuesPartial", "", "", 0, state); err != nil {
+		return nil, err
+	}
+	return f.Issues, nil
+}
+
+func (f *FakeClient) GetIssue(ctx context.Context, owner, repo string, number int) (*github.Issue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("GetIssue", owner, repo, number); err != nil {
+		return nil, err
+	}
+	if issue := f.issue(number); issue != nil {
+		return issue, nil
+	}
+	return nil, fmt.Errorf("issue #%d not found", number)
+}
+
+// GetIssues fetches each reference with GetIssue, so GetIssue errors and
+// calls apply to every reference
+func (f *FakeClient) GetIssues(ctx context.Context, refs []github.IssueRef) (map[github.IssueRef]*github.Issue, error) {
+	return github.FetchIssues(ctx, refs, 1, f.GetIssue)
+}
+
+func (f *FakeClient) UpdateIssue(ctx context.Context, owner, repo string, number int, title, body *string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("UpdateIssue", owner, repo, number, title, body); err != nil {
+		return err
+	}
+	if f.UpdatedIssues == nil {
+		f.UpdatedIssues = make(map[int]*github.Issue)
+	}
+	if f.UpdatedIssues[number] == nil {
+		f.UpdatedIssues[number] = &github.Issue{Number: number}
+	}
+	if body != nil {
+		f.UpdatedIssues[number].Body = *body
+	}
+	if title != nil {
+		f.UpdatedIssues[number].Title = *title
+	}
+	return nil
+}
+
+func (f *FakeClient) AddComment(ctx context.Context, owner, repo string, number int, comment string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("AddComment", owner, repo, number, comment); err != nil {
+		return err
+	}
+	if f.Comments == nil {
+		f.Comments = make(map[int][]string)
+	}
+	f.Comments[number] = append(f.Comments[number], comment)
+	return nil
+}
+
+func (f *FakeClient) CreateIssue(ctx context.Context, owner, repo, title, body string, labels []string) (*github.Issue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("CreateIssue", owner, repo, 0, title, body, labels); err != nil {
+		return nil, err
+	}
+	issue := &github.Issue{Number: 1000 + len(f.CreatedIssues), Title: title, Body: body, Labels: labels}
+	f.CreatedIssues = append(f.CreatedIssues, issue)
+	return issue, nil
+}
+
+func (f *FakeClient) AddLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("AddLabel", owner, repo, number, label); err != nil {
+		return err
+	}
+	if f.Labels == nil {
+		f.Labels = make(map[int][]string)
+	}
+	f.Labels[number] = append(f.Labels[number], label)
+	return nil
+}
+
+func (f *FakeClient) RemoveLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("RemoveLabel", owner, repo, number, label); err != nil {
+		return err
+	}
+	var kept []string
+	for _, l := range f.Labels[number] {
+		if l != label {
+			kept = append(kept, l)
+		}
+	}
+	if f.Labels != nil {
+		f.Labels[number] = kept
+	}
+	return nil
+}
+
+func (f *FakeClient) AssignIssue(ctx context.Context, owner, repo string, number int, assignees []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("AssignIssue", owner, repo, number, assignees); err != nil {
+		return err
+	}
+	if f.Assigned == nil {
+		f.Assigned = make(map[int][]string)
+	}
+	f.Assigned[number] = append(f.Assigned[number], assignees...)
+	if issue := f.issue(number); issue != nil && len(assignees) > 0 {
+		issue.Assignee = assignees[0]
+	}
+	return nil
+}
+
+func (f *FakeClient) SetAssignee(ctx context.Context, owner, repo string, number int, login string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("SetAssignee", owner, repo, number, login); err != nil {
+		return err
+	}
+	if f.Reassigned == nil {
+		f.Reassigned = make(map[int]string)
+	}
+	f.Reassigned[number] = login
+	return nil
+}
+
+func (f *FakeClient) CloseIssue(ctx context.Context, owner, repo string, number int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("CloseIssue", owner, repo, number); err != nil {
+		return err
+	}
+	if f.Closed == nil {
+		f.Closed = make(map[int]bool)
+	}
+	f.Closed[number] = true
+	return nil
+}
+
+func (f *FakeClient) GetIssueComments(ctx context.Context, owner, repo string, number int) ([]github.Comment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("GetIssueComments", owner, repo, number); err != nil {
+		return nil, err
+	}
+	return f.IssueComments[number], nil
+}
+
+func (f *FakeClient) ListIssueEvents(ctx context.Context, owner, repo string, number int) ([]github.IssueEvent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("ListIssueEvents", owner, repo, number); err != nil {
+		return nil, err
+	}
+	return f.IssueEvents[number], nil
+}
+
+func (f *FakeClient) GetLinkedPullRequests(ctx context.Context, owner, repo string, number int) ([]*github.PullRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("GetLinkedPullRequests", owner, repo, number); err != nil {
+		return nil, err
+	}
+	return f.LinkedPRs[number], nil
+}
+
+func (f *FakeClient) ListPullRequests(ctx context.Context, state string) ([]*github.PullRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("ListPullRequests", "", "", 0, state); err != nil {
+		return nil, err
+	}
+	return f.PullRequests, nil
+}
+
+func (f *FakeClient) GetPullRequestDiff(ctx context.Context, owner, repo string, number int) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("GetPullRequestDiff", owner, repo, number); err != nil {
+		return "", err
+	}
+	return f.Diffs[number], nil
+}
+
+func (f *FakeClient) ListDeployments(ctx context.Context, owner, repo string) ([]*github.Deployment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("ListDeployments", owner, repo, 0); err != nil {
+		return nil, err
+	}
+	return f.Deployments, nil
+}
+
+func (f *FakeClient) GetDeploymentStatuses(ctx context.Context, owner, repo string, id int64) ([]*github.DeploymentStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("GetDeploymentStatuses", owner, repo, 0, id); err != nil {
+		return nil, err
+	}
+	return f.DeploymentStatuses[id], nil
+}
+
+func (f *FakeClient) GetRepository(ctx context.Context, owner, repo string) (*github.RepoInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("GetRepository", owner, repo, 0); err != nil {
+		return nil, err
+	}
+	return &github.RepoInfo{Owner: owner, Name: repo}, nil
+}
+
+func (f *FakeClient) WhoAmI(ctx context.Context) (string, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("WhoAmI", "", "", 0); err != nil {
+		return "", false, err
+	}
+	if f.Login == "" {
+		return "agent-bot", f.IsApp, nil
+	}
+	return f.Login, f.IsApp, nil
+}
+
+func (f *FakeClient) GetMode() string {
+	if f.Mode == "" {
+		return "repo"
+	}
+	return f.Mode
+}
@@ -0,0 +1,50 @@
+package githubtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kaskol10/github-project-agent/github"
+)
+
+func TestFakeClient(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFakeClient(&github.Issue{Number: 7, Title: "Fix login"})
+	fake.Errors["CloseIssue"] = errors.New("boom")
+
+	if issue, err := fake.GetIssue(ctx, "o", "r", 7); err != nil || issue.Title != "Fix login" {
+		t.Fatalf("GetIssue() = %+v, %v", issue, err)
+	}
+	if _, err := fake.GetIssue(ctx, "o", "r", 8); err == nil {
+		t.Error("expected an error for an unknown issue")
+	}
+	if err := fake.AddComment(ctx, "o", "r", 7, "hello"); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	if err := fake.AddLabel(ctx, "o", "r", 7, "bug"); err != nil {
+		t.Fatalf("AddLabel() error = %v", err)
+	}
+	if err := fake.CloseIssue(ctx, "o", "r", 7); err == nil || fake.Closed[7] {
+		t.Errorf("expected the configured CloseIssue error, got %v", err)
+	}
+
+	if len(fake.Comments[7]) != 1 || fake.Comments[7][0] != "hello" {
+		t.Errorf("unexpected comments %v", fake.Comments)
+	}
+	if len(fake.Labels[7]) != 1 || fake.Labels[7][0] != "bug" {
+		t.Errorf("unexpected labels %v", fake.Labels)
+	}
+	calls := fake.CallsTo("AddComment")
+	if len(calls) != 1 || calls[0].Owner != "o" || calls[0].Repo != "r" || calls[0].Number != 7 {
+		t.Errorf("unexpected AddComment calls %+v", calls)
+	}
+	if len(fake.Calls) != 5 {
+		t.Errorf("expected 5 recorded calls, got %d", len(fake.Calls))
+	}
+
+	var zero FakeClient
+	if err := zero.AddLabel(ctx, "o", "r", 1, "bug"); err != nil || len(zero.Labels[1]) != 1 {
+		t.Errorf("expected the zero value to record labels, got %v, %v", zero.Labels, err)
+	}
+}
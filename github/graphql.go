@@ -0,0 +1,51 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// graphQLRequest is the body of a GraphQL API call
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// graphQLError is an error returned in a GraphQL response body
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+// graphQLResponse wraps the data and errors of a GraphQL response
+type graphQLResponse struct {
+	Data   interface{}    `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+// graphQL runs a GraphQL query or mutation and decodes its data into result.
+// The endpoint is resolved relative to the REST base URL so GitHub Enterprise
+// ("/api/v3/" -> "/api/graphql") works as well as github.com.
+func graphQL(ctx context.Context, client *github.Client, query string, variables map[string]interface{}, result interface{}) error {
+	req, err := client.NewRequest("POST", "../graphql", &graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return fmt.Errorf("failed to create GraphQL request: %w", err)
+	}
+
+	resp := &graphQLResponse{Data: result}
+	if _, err := client.Do(ctx, req, resp); err != nil {
+		return fmt.Errorf("failed to execute GraphQL request: %w", err)
+	}
+
+	if len(resp.Errors) > 0 {
+		messages := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			messages[i] = e.Message
+		}
+		return fmt.Errorf("GraphQL error: %s", strings.Join(messages, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// GetAppSlug returns the GitHub App's slug, using the app's JWT
+func (a *AppAuth) GetAppSlug(ctx context.Context) (string, error) {
+	var app struct {
+		Slug string `json:"slug"`
+	}
+	if err := a.appRequest(ctx, "GET", "/app", http.StatusOK, &app); err != nil {
+		return "", fmt.Errorf("failed to get app: %w", err)
+	}
+	return app.Slug, nil
+}
+
+// whoAmI returns the login the client acts as. GitHub Apps act as
+// "<slug>[bot]", which is the author login on their comments.
+func whoAmI(ctx context.Context, client *github.Client, appAuth *AppAuth) (string, bool, error) {
+	if appAuth != nil {
+		slug, err := appAuth.GetAppSlug(ctx)
+		if err != nil {
+			return "", true, err
+		}
+		return slug + "[bot]", true, nil
+	}
+
+	user, _, err := client.Users.Get(ctx, "")
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get authenticated user: %w", err)
+	}
+	return user.GetLogin(), false, nil
+}
+
+// WhoAmI returns the authenticated login and whether it is a GitHub App (implements UnifiedClient interface)
+func (c *Client) WhoAmI(ctx context.Context) (string, bool, error) {
+	return whoAmI(ctx, c.client, c.appAuth)
+}
+
+// WhoAmI returns the authenticated login and whether it is a GitHub App
+func (pc *ProjectClient) WhoAmI(ctx context.Context) (string, bool, error) {
+	return whoAmI(ctx, pc.client, pc.appAuth)
+}
@@ -0,0 +1,57 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+)
+
+func TestWhoAmI(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/app":
+			if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+				t.Errorf("expected the app lookup to use the app's JWT")
+			}
+			w.Write([]byte(`{"slug": "project-agent"}`))
+		case "/api/v3/user":
+			w.Write([]byte(`{"login": "octocat"}`))
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	gh, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	login, isApp, err := (&Client{client: gh}).WhoAmI(ctx)
+	if err != nil || login != "octocat" || isApp {
+		t.Errorf("token WhoAmI() = %q, %v, %v; want octocat, false", login, isApp, err)
+	}
+
+	appAuth := &AppAuth{AppID: 123, PrivateKey: key, BaseURL: server.URL + "/api/v3"}
+	login, isApp, err = (&ProjectClient{client: gh, appAuth: appAuth}).WhoAmI(ctx)
+	if err != nil || login != "project-agent[bot]" || !isApp {
+		t.Errorf("app WhoAmI() = %q, %v, %v; want project-agent[bot], true", login, isApp, err)
+	}
+
+	appAuth.BaseURL = server.URL + "/missing"
+	if _, isApp, err := (&Client{client: gh, appAuth: appAuth}).WhoAmI(ctx); err == nil || !isApp || !strings.Contains(err.Error(), "status 400") {
+		t.Errorf("expected a failed app lookup to report the status, got %v (app %v)", err, isApp)
+	}
+}
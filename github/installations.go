@@ -0,0 +1,100 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// ResolveInstallation returns the ID of the App's installation on the
+// owner of a repository, using the app's JWT
+func (a *AppAuth) ResolveInstallation(ctx context.Context, owner, repo string) (int64, error) {
+	var installation struct {
+		ID int64 `json:"id"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s/installation", url.PathEscape(owner), url.PathEscape(repo))
+	if err := a.appRequest(ctx, "GET", path, http.StatusOK, &installation); err != nil {
+		return 0, fmt.Errorf("failed to get installation for %s/%s: %w", owner, repo, err)
+	}
+	return installation.ID, nil
+}
+
+// installationFor returns the installation to act as for a repository,
+// resolving it once per repository. When the App isn't installed there (a
+// 404), the default InstallationID is used and remembered; other failures
+// fall back to it for this request only, so the next one retries.
+func (a *AppAuth) installationFor(ctx context.Context, owner, repo string) int64 {
+	key := strings.ToLower(owner + "/" + repo)
+	a.mu.Lock()
+	id, ok := a.installations[key]
+	a.mu.Unlock()
+	if ok {
+		return id
+	}
+
+	id, err := a.ResolveInstallation(ctx, owner, repo)
+	if err != nil {
+		var apiErr *appAPIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			slog.Warn("Using the default GitHub App installation for now", "repo", owner+"/"+repo, "installation", a.InstallationID, "error", err)
+			return a.InstallationID
+		}
+		slog.Warn("Using the default GitHub App installation", "repo", owner+"/"+repo, "installation", a.InstallationID, "error", err)
+		id = a.InstallationID
+	}
+
+	a.mu.Lock()
+	if a.installations == nil {
+		a.installations = make(map[string]int64)
+	}
+	a.installations[key] = id
+	a.mu.Unlock()
+	return id
+}
+
+// installationTransport authenticates each request as the App installation
+// on the repository it targets; requests not about a repository, such as
+// GraphQL queries, use the default installation
+type installationTransport struct {
+	appAuth  *AppAuth
+	ctx      context.Context
+	repoPath *regexp.Regexp // Captures owner and repo of repository API paths
+	next     http.RoundTripper
+}
+
+// newInstallationClient creates a GitHub client whose requests use the
+// installation of the repository they target
+func newInstallationClient(ctx context.Context, appAuth *AppAuth, uploadURL string) (*github.Client, error) {
+	transport := &installationTransport{appAuth: appAuth, ctx: ctx, next: http.DefaultTransport}
+	client, err := newGitHubClient(&http.Client{Transport: transport}, appAuth.BaseURL, uploadURL)
+	if err != nil {
+		return nil, err
+	}
+	// Match paths below the API root go-github settled on, e.g. /api/v3/
+	transport.repoPath = regexp.MustCompile(`^` + regexp.QuoteMeta(client.BaseURL.Path) + `repos/([^/]+)/([^/]+)`)
+	return client, nil
+}
+
+func (t *installationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	installationID := t.appAuth.InstallationID
+	if match := t.repoPath.FindStringSubmatch(req.URL.Path); match != nil {
+		installationID = t.appAuth.installationFor(req.Context(), match[1], match[2])
+	}
+
+	token, err := t.appAuth.installationTokenSource(t.ctx, installationID).Token()
+	if err != nil {
+		return nil, err
+	}
+
+	// A RoundTripper must not modify the request it was given
+	authed := req.Clone(req.Context())
+	token.SetAuthHeader(authed)
+	return t.next.RoundTrip(authed)
+}
@@ -0,0 +1,98 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestResolveInstallation(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	installations := map[string]int64{"acme/api": 11, "beta/web": 22, "flaky/svc": 33}
+
+	var mu sync.Mutex
+	lookups := map[string]int{}
+	var issueAuth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/v3")
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case strings.HasSuffix(path, "/installation"):
+			if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+				t.Errorf("expected the installation lookup to use the app's JWT")
+			}
+			repo := strings.TrimSuffix(strings.TrimPrefix(path, "/repos/"), "/installation")
+			lookups[repo]++
+			if repo == "flaky/svc" && lookups[repo] == 1 {
+				http.Error(w, `{"message": "Server Error"}`, http.StatusBadGateway)
+				return
+			}
+			id, ok := installations[repo]
+			if !ok {
+				http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+				return
+			}
+			fmt.Fprintf(w, `{"id": %d}`, id)
+		case strings.HasPrefix(path, "/app/installations/"):
+			var id int64
+			fmt.Sscanf(path, "/app/installations/%d/access_tokens", &id)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token": "token-%d"}`, id)
+		case strings.HasPrefix(path, "/repos/"):
+			issueAuth = append(issueAuth, path+" "+r.Header.Get("Authorization"))
+			w.Write([]byte(`{"number": 1, "title": "Task", "state": "open"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	appAuth := &AppAuth{AppID: 123, InstallationID: 1, PrivateKey: key, BaseURL: server.URL + "/api/v3", ResolveInstallations: true}
+	ctx := context.Background()
+
+	if id, err := appAuth.ResolveInstallation(ctx, "acme", "api"); err != nil || id != 11 {
+		t.Fatalf("ResolveInstallation(acme/api) = %d, %v; want 11", id, err)
+	}
+	if _, err := appAuth.ResolveInstallation(ctx, "gamma", "tools"); err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Fatalf("expected a 404 for an org without the app, got %v", err)
+	}
+
+	client, err := CreateGitHubClientWithApp(ctx, appAuth, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, repo := range []string{"acme/api", "beta/web", "acme/api", "gamma/tools", "gamma/tools", "flaky/svc", "flaky/svc"} {
+		owner, name, _ := strings.Cut(repo, "/")
+		if _, _, err := client.Issues.Get(ctx, owner, name, 1); err != nil {
+			t.Fatalf("get issue in %s: %v", repo, err)
+		}
+	}
+
+	want := []string{
+		"/repos/acme/api/issues/1 token token-11",
+		"/repos/beta/web/issues/1 token token-22",
+		"/repos/acme/api/issues/1 token token-11",
+		"/repos/gamma/tools/issues/1 token token-1", // Not installed there: default installation
+		"/repos/gamma/tools/issues/1 token token-1",
+		"/repos/flaky/svc/issues/1 token token-1", // Lookup failed: default installation, for now
+		"/repos/flaky/svc/issues/1 token token-33",
+	}
+	if strings.Join(issueAuth, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests authenticated as\n%s\nwant\n%s", strings.Join(issueAuth, "\n"), strings.Join(want, "\n"))
+	}
+	// ResolveInstallation always asks GitHub; the client asks once per
+	// repository, remembering a 404 but retrying other failures
+	if lookups["acme/api"] != 2 || lookups["beta/web"] != 1 || lookups["gamma/tools"] != 2 || lookups["flaky/svc"] != 2 {
+		t.Errorf("installation lookups = %v", lookups)
+	}
+}
@@ -0,0 +1,111 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kaskol10/github-project-agent/pool"
+)
+
+// IssueRef identifies an issue to fetch. Owner and Repo are empty for an
+// issue in the client's own repository (searched across the project's
+// repositories in project mode).
+type IssueRef struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// String formats the reference as GitHub writes it, e.g. "#12" or "octo/api#12"
+func (r IssueRef) String() string {
+	if r.Owner == "" && r.Repo == "" {
+		return fmt.Sprintf("#%d", r.Number)
+	}
+	return fmt.Sprintf("%s/%s#%d", r.Owner, r.Repo, r.Number)
+}
+
+// IssueFailure is an issue that could not be fetched
+type IssueFailure struct {
+	Ref IssueRef
+	Err error
+}
+
+// PartialFetchError reports the issues that could not be fetched when
+// several are fetched at once
+type PartialFetchError struct {
+	Failures []IssueFailure
+	Total    int // Issues requested
+}
+
+func (e *PartialFetchError) Error() string {
+	parts := make([]string, len(e.Failures))
+	for i, failure := range e.Failures {
+		parts[i] = fmt.Sprintf("%s: %v", failure.Ref, failure.Err)
+	}
+	return fmt.Sprintf("failed to get %d of %d issues: %s", len(e.Failures), e.Total, strings.Join(parts, "; "))
+}
+
+// Unwrap returns the per-issue errors
+func (e *PartialFetchError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure.Err
+	}
+	return errs
+}
+
+// AllFailed reports whether no issue could be fetched
+func (e *PartialFetchError) AllFailed() bool {
+	return len(e.Failures) >= e.Total
+}
+
+// FetchIssues gets each referenced issue with get, running at most limit
+// requests at once per repository. It returns the issues it could fetch,
+// keyed by the reference as given, and a *PartialFetchError listing the
+// rest. Duplicate references are fetched once.
+func FetchIssues(ctx context.Context, refs []IssueRef, limit int, get func(ctx context.Context, owner, repo string, number int) (*Issue, error)) (map[IssueRef]*Issue, error) {
+	var unique []IssueRef
+	seen := make(map[IssueRef]bool, len(refs))
+	for _, ref := range refs {
+		if !seen[ref] {
+			seen[ref] = true
+			unique = append(unique, ref)
+		}
+	}
+
+	issues := make([]*Issue, len(unique))
+	errs := make([]error, len(unique))
+	pool.ForEach(len(unique), limit, func(i int) string {
+		return unique[i].Owner + "/" + unique[i].Repo
+	}, func(i int) {
+		issues[i], errs[i] = get(ctx, unique[i].Owner, unique[i].Repo, unique[i].Number)
+	})
+
+	found := make(map[IssueRef]*Issue, len(unique))
+	var partial PartialFetchError
+	for i, ref := range unique {
+		if errs[i] != nil {
+			partial.Failures = append(partial.Failures, IssueFailure{Ref: ref, Err: errs[i]})
+			continue
+		}
+		found[ref] = issues[i]
+	}
+	if len(partial.Failures) > 0 {
+		partial.Total = len(unique)
+		return found, &partial
+	}
+	return found, nil
+}
+
+// GetIssues fetches several issues at once (implements UnifiedClient
+// interface). In repo mode, references to other repositories fail.
+func (uc *UnifiedClientWrapper) GetIssues(ctx context.Context, refs []IssueRef) (map[IssueRef]*Issue, error) {
+	return FetchIssues(ctx, refs, pool.DefaultLimit, func(ctx context.Context, owner, repo string, number int) (*Issue, error) {
+		if uc.mode != "project" && owner != "" &&
+			(!strings.EqualFold(owner, uc.repoClient.owner) || !strings.EqualFold(repo, uc.repoClient.repo)) {
+			return nil, fmt.Errorf("can't get issues from %s/%s in repo mode", owner, repo)
+		}
+		return uc.GetIssue(ctx, owner, repo, number)
+	})
+}
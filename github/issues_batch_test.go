@@ -0,0 +1,61 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+)
+
+func TestGetIssues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/o/r/issues/1":
+			w.Write([]byte(`{"number": 1, "title": "Schema", "state": "closed", "html_url": "https://github.com/o/r/issues/1"}`))
+		case "/api/v3/repos/o/r/issues/2":
+			w.Write([]byte(`{"number": 2, "title": "API", "state": "open", "html_url": "https://github.com/o/r/issues/2"}`))
+		default:
+			http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ghClient, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &UnifiedClientWrapper{
+		repoClient: &Client{client: ghClient, owner: "o", repo: "r"},
+		mode:       "repo",
+	}
+
+	refs := []IssueRef{
+		{Number: 1},
+		{Owner: "o", Repo: "r", Number: 2},
+		{Number: 3},                              // Missing
+		{Owner: "other", Repo: "lib", Number: 1}, // Another repository in repo mode
+		{Number: 1},                              // Duplicate
+	}
+	issues, err := client.GetIssues(context.Background(), refs)
+
+	if len(issues) != 2 || issues[refs[0]].Title != "Schema" || issues[refs[1]].Title != "API" {
+		t.Errorf("expected the two existing issues, got %v", issues)
+	}
+	var partial *PartialFetchError
+	if !errors.As(err, &partial) {
+		t.Fatalf("expected a partial fetch error, got %v", err)
+	}
+	if partial.Total != 4 || len(partial.Failures) != 2 || partial.AllFailed() {
+		t.Fatalf("expected 2 of 4 issues to fail, got %+v", partial)
+	}
+	if partial.Failures[0].Ref != refs[2] || partial.Failures[1].Ref != refs[3] {
+		t.Errorf("unexpected failures %+v", partial.Failures)
+	}
+
+	if issues, err := client.GetIssues(context.Background(), refs[:2]); err != nil || len(issues) != 2 {
+		t.Errorf("expected no error when every issue exists, got %v", err)
+	}
+}
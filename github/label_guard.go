@@ -0,0 +1,52 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnmanagedLabel is returned for label changes outside the managed prefixes
+var ErrUnmanagedLabel = errors.New("label is not managed by the agent")
+
+// labelGuardClient wraps a UnifiedClient so only labels with a managed prefix
+// are added or removed. Labels on issues the agent creates are not checked.
+type labelGuardClient struct {
+	UnifiedClient
+	prefixes []string
+}
+
+// NewLabelGuardClient returns a client that rejects AddLabel and RemoveLabel
+// calls for labels not starting with one of prefixes (ignoring case). With no
+// prefixes every label is allowed and client is returned as is.
+func NewLabelGuardClient(client UnifiedClient, prefixes []string) UnifiedClient {
+	if len(prefixes) == 0 {
+		return client
+	}
+	return &labelGuardClient{UnifiedClient: client, prefixes: prefixes}
+}
+
+func (g *labelGuardClient) AddLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	if err := g.check(label); err != nil {
+		return fmt.Errorf("refusing to add label to %s: %w", issueRef(owner, repo, number), err)
+	}
+	return g.UnifiedClient.AddLabel(ctx, owner, repo, number, label)
+}
+
+func (g *labelGuardClient) RemoveLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	if err := g.check(label); err != nil {
+		return fmt.Errorf("refusing to remove label from %s: %w", issueRef(owner, repo, number), err)
+	}
+	return g.UnifiedClient.RemoveLabel(ctx, owner, repo, number, label)
+}
+
+// check returns an error wrapping ErrUnmanagedLabel unless label is managed
+func (g *labelGuardClient) check(label string) error {
+	for _, prefix := range g.prefixes {
+		if strings.HasPrefix(strings.ToLower(label), strings.ToLower(prefix)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q (managed prefixes: %s): %w", label, strings.Join(g.prefixes, ", "), ErrUnmanagedLabel)
+}
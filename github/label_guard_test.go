@@ -0,0 +1,60 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// recordingLabelClient records label changes; other methods are unused
+type recordingLabelClient struct {
+	UnifiedClient
+	added, removed []string
+}
+
+func (c *recordingLabelClient) AddLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	c.added = append(c.added, label)
+	return nil
+}
+
+func (c *recordingLabelClient) RemoveLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	c.removed = append(c.removed, label)
+	return nil
+}
+
+func TestLabelGuardClient(t *testing.T) {
+	inner := &recordingLabelClient{}
+	client := NewLabelGuardClient(inner, []string{"agent-", "priority:"})
+	ctx := context.Background()
+
+	for _, label := range []string{"agent-stale", "Priority:P1"} {
+		if err := client.AddLabel(ctx, "o", "r", 1, label); err != nil {
+			t.Errorf("AddLabel(%q) error = %v", label, err)
+		}
+	}
+	if err := client.RemoveLabel(ctx, "o", "r", 1, "priority:P3"); err != nil {
+		t.Errorf("RemoveLabel error = %v", err)
+	}
+
+	if err := client.AddLabel(ctx, "o", "r", 1, "bug"); !errors.Is(err, ErrUnmanagedLabel) {
+		t.Errorf("expected ErrUnmanagedLabel adding bug, got %v", err)
+	}
+	if err := client.RemoveLabel(ctx, "o", "r", 1, "team:infra"); !errors.Is(err, ErrUnmanagedLabel) {
+		t.Errorf("expected ErrUnmanagedLabel removing team:infra, got %v", err)
+	}
+
+	if want := []string{"agent-stale", "Priority:P1"}; !reflect.DeepEqual(inner.added, want) {
+		t.Errorf("added = %v, want %v", inner.added, want)
+	}
+	if want := []string{"priority:P3"}; !reflect.DeepEqual(inner.removed, want) {
+		t.Errorf("removed = %v, want %v", inner.removed, want)
+	}
+}
+
+func TestLabelGuardClient_NoPrefixesAllowsAll(t *testing.T) {
+	inner := &recordingLabelClient{}
+	if client := NewLabelGuardClient(inner, nil); client != UnifiedClient(inner) {
+		t.Error("expected the client to be returned unwrapped")
+	}
+}
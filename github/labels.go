@@ -0,0 +1,46 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// addLabels appends labels to an issue. The API adds to the existing labels
+// rather than replacing them, so concurrent calls can't drop each other's labels.
+func addLabels(ctx context.Context, client *github.Client, owner, repo string, number int, labels ...string) error {
+	if _, _, err := client.Issues.AddLabelsToIssue(ctx, owner, repo, number, labels); err != nil {
+		return fmt.Errorf("failed to add label: %w", err)
+	}
+	return nil
+}
+
+// removeLabel removes a label from an issue. Removing a label the issue
+// doesn't have is not an error.
+func removeLabel(ctx context.Context, client *github.Client, owner, repo string, number int, label string) error {
+	resp, err := client.Issues.RemoveLabelForIssue(ctx, owner, repo, number, label)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return fmt.Errorf("failed to remove label: %w", err)
+	}
+	return nil
+}
+
+// RemoveLabel removes a label from an issue (implements UnifiedClient interface)
+// In repo mode, owner and repo parameters are ignored
+func (c *Client) RemoveLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	return c.retry.do(ctx, func(int) error {
+		return removeLabel(ctx, c.client, c.owner, c.repo, number, label)
+	})
+}
+
+// RemoveLabel removes a label from an issue in a specific repository
+func (pc *ProjectClient) RemoveLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	return pc.retry.do(ctx, func(int) error {
+		return removeLabel(ctx, pc.client, owner, repo, number, label)
+	})
+}
@@ -0,0 +1,123 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// fakeLabelServer keeps one issue's labels the way the GitHub API does
+type fakeLabelServer struct {
+	mu     sync.Mutex
+	labels []string
+}
+
+func (f *fakeLabelServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/o/r/issues/1/labels":
+		var added []string
+		if err := json.NewDecoder(r.Body).Decode(&added); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.labels = append(f.labels, added...)
+		f.writeLabels(w)
+	case r.Method == http.MethodDelete:
+		name := r.URL.Path[len("/api/v3/repos/o/r/issues/1/labels/"):]
+		for i, l := range f.labels {
+			if l == name {
+				f.labels = append(f.labels[:i], f.labels[i+1:]...)
+				f.writeLabels(w)
+				return
+			}
+		}
+		http.Error(w, `{"message":"Label does not exist"}`, http.StatusNotFound)
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func (f *fakeLabelServer) writeLabels(w http.ResponseWriter) {
+	var result []map[string]string
+	for _, l := range f.labels {
+		result = append(result, map[string]string{"name": l})
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+func newFakeLabelClient(t *testing.T, f *fakeLabelServer) *Client {
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+
+	client, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Client{client: client, owner: "o", repo: "r"}
+}
+
+func TestAddLabel_Concurrent(t *testing.T) {
+	fake := &fakeLabelServer{labels: []string{"existing"}}
+	client := newFakeLabelClient(t, fake)
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- client.AddLabel(context.Background(), "", "", 1, fmt.Sprintf("label-%d", i))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("AddLabel() error = %v", err)
+		}
+	}
+
+	if len(fake.labels) != n+1 {
+		t.Fatalf("expected %d labels, got %d: %v", n+1, len(fake.labels), fake.labels)
+	}
+	seen := make(map[string]bool)
+	for _, l := range fake.labels {
+		seen[l] = true
+	}
+	for i := 0; i < n; i++ {
+		if !seen[fmt.Sprintf("label-%d", i)] {
+			t.Errorf("label-%d was lost", i)
+		}
+	}
+	if !seen["existing"] {
+		t.Error("existing label was lost")
+	}
+}
+
+func TestRemoveLabel(t *testing.T) {
+	fake := &fakeLabelServer{labels: []string{"bug", "needs-format"}}
+	client := newFakeLabelClient(t, fake)
+
+	if err := client.RemoveLabel(context.Background(), "", "", 1, "needs-format"); err != nil {
+		t.Fatalf("RemoveLabel() error = %v", err)
+	}
+	if len(fake.labels) != 1 || fake.labels[0] != "bug" {
+		t.Errorf("unexpected labels after removal: %v", fake.labels)
+	}
+
+	// Removing a label the issue doesn't have is a no-op
+	if err := client.RemoveLabel(context.Background(), "", "", 1, "needs-format"); err != nil {
+		t.Errorf("RemoveLabel() of missing label error = %v", err)
+	}
+}
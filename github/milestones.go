@@ -0,0 +1,24 @@
+package github
+
+import (
+	"context"
+	"strings"
+)
+
+// ListIssuesByMilestone returns the issues in the given state whose milestone
+// title matches milestone case-insensitively. An empty milestone selects the
+// issues without one.
+func ListIssuesByMilestone(ctx context.Context, client UnifiedClient, state, milestone string) ([]*Issue, error) {
+	issues, err := client.ListIssues(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []*Issue
+	for _, issue := range issues {
+		if strings.EqualFold(strings.TrimSpace(issue.Milestone), strings.TrimSpace(milestone)) {
+			matching = append(matching, issue)
+		}
+	}
+	return matching, nil
+}
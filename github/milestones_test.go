@@ -0,0 +1,46 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+)
+
+func TestListIssuesByMilestone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+  {"number": 1, "title": "Login", "state": "open", "milestone": {"title": "v1.0", "due_on": "2024-02-01T00:00:00Z"}},
+  {"number": 2, "title": "Signup", "state": "open", "milestone": {"title": "v2.0"}},
+  {"number": 3, "title": "Docs", "state": "open"}
+]`))
+	}))
+	defer server.Close()
+
+	ghClient, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &UnifiedClientWrapper{repoClient: &Client{client: ghClient, owner: "o", repo: "r"}, mode: "repo"}
+
+	issues, err := ListIssuesByMilestone(context.Background(), client, "open", "V1.0")
+	if err != nil {
+		t.Fatalf("ListIssuesByMilestone() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].Number != 1 {
+		t.Fatalf("expected only issue #1, got %v", issues)
+	}
+	if issues[0].Milestone != "v1.0" || issues[0].MilestoneDueOn.Format("2006-01-02") != "2024-02-01" {
+		t.Errorf("unexpected milestone: %q due %v", issues[0].Milestone, issues[0].MilestoneDueOn)
+	}
+
+	issues, err = ListIssuesByMilestone(context.Background(), client, "open", "")
+	if err != nil {
+		t.Fatalf("ListIssuesByMilestone() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].Number != 3 || !issues[0].MilestoneDueOn.IsZero() {
+		t.Errorf("expected only issue #3 without a milestone, got %v", issues)
+	}
+}
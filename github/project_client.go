@@ -3,6 +3,9 @@ package github
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
 
 	"github.com/google/go-github/v57/github"
 	"golang.org/x/oauth2"
@@ -13,6 +16,12 @@ type ProjectClient struct {
 	client    *github.Client
 	projectID string // Project number (as string) or GraphQL node ID
 	owner     string // Organization or user that owns the project
+
+	nodeIDMu            sync.Mutex
+	projectNodeIDCache  string   // Resolved GraphQL node ID of the project, guarded by nodeIDMu
+	addCreatedToProject bool     // Add issues created by the agent to the project board
+	appAuth             *AppAuth // Set when authenticated as a GitHub App
+	retry               RetryPolicy
 }
 
 // ProjectIssue represents an issue from a GitHub Project (may be from any linked repo)
@@ -24,19 +33,54 @@ type ProjectIssue struct {
 	ProjectItemID   string // GraphQL node ID of the project item
 }
 
+// RepoFailure is a repository whose issues could not be listed
+type RepoFailure struct {
+	Repo string // owner/name
+	Err  error
+}
+
+// PartialListError reports the repositories that could not be listed when
+// issues are read repository by repository
+type PartialListError struct {
+	Failures []RepoFailure
+	Total    int // Repositories queried
+}
+
+func (e *PartialListError) Error() string {
+	parts := make([]string, len(e.Failures))
+	for i, failure := range e.Failures {
+		parts[i] = fmt.Sprintf("%s: %v", failure.Repo, failure.Err)
+	}
+	return fmt.Sprintf("failed to list issues from %d of %d repositories: %s", len(e.Failures), e.Total, strings.Join(parts, "; "))
+}
+
+// Unwrap returns the per-repository errors
+func (e *PartialListError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure.Err
+	}
+	return errs
+}
+
+// AllFailed reports whether no repository could be listed
+func (e *PartialListError) AllFailed() bool {
+	return len(e.Failures) >= e.Total
+}
+
 // NewProjectClient creates a client for GitHub Projects
 func NewProjectClient(token, owner, projectID, baseURL string) (*ProjectClient, error) {
-	return NewProjectClientWithAuth(token, nil, owner, projectID, baseURL)
+	return NewProjectClientWithAuth(token, nil, owner, projectID, baseURL, "")
 }
 
 // NewProjectClientWithAuth creates a project client with either token or GitHub App authentication
-func NewProjectClientWithAuth(token string, appAuth *AppAuth, owner, projectID, baseURL string) (*ProjectClient, error) {
+func NewProjectClientWithAuth(token string, appAuth *AppAuth, owner, projectID, baseURL, uploadURL string) (*ProjectClient, error) {
 	ctx := context.Background()
 	var client *github.Client
 
 	if appAuth != nil {
 		// Use GitHub App authentication
-		ghClient, err := CreateGitHubClientWithApp(ctx, appAuth)
+		ghClient, err := CreateGitHubClientWithApp(ctx, appAuth, uploadURL)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create GitHub client with app auth: %w", err)
 		}
@@ -48,14 +92,10 @@ func NewProjectClientWithAuth(token string, appAuth *AppAuth, owner, projectID,
 		)
 		tc := oauth2.NewClient(ctx, ts)
 
-		if baseURL != "" && baseURL != "https://api.github.com" {
-			var err error
-			client, err = github.NewClient(tc).WithEnterpriseURLs(baseURL, baseURL)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create GitHub Enterprise client: %w", err)
-			}
-		} else {
-			client = github.NewClient(tc)
+		var err error
+		client, err = newGitHubClient(tc, baseURL, uploadURL)
+		if err != nil {
+			return nil, err
 		}
 	} else {
 		return nil, fmt.Errorf("either token or GitHub App credentials must be provided")
@@ -65,76 +105,127 @@ func NewProjectClientWithAuth(token string, appAuth *AppAuth, owner, projectID,
 		client:    client,
 		projectID: projectID,
 		owner:     owner,
+		appAuth:   appAuth,
 	}, nil
 }
 
-// ListProjectIssues lists all issues in a GitHub Project across all linked repositories
-// Note: GitHub Projects v2 uses GraphQL API, but we'll use REST API workaround
-// by querying issues from all repositories that might be linked to the project
+// ListProjectIssues lists all issues on the GitHub Project board via the
+// Projects v2 GraphQL API, with their field values. If the GraphQL call fails
+// (e.g. the token lacks project access) it falls back to listing the issues
+// of the configured repositories; repositories that can't be listed are
+// reported in a *PartialListError returned with the other repositories' issues.
 func (pc *ProjectClient) ListProjectIssues(ctx context.Context, state string, repos []Repository) ([]*ProjectIssue, error) {
+	issues, err := pc.listProjectItems(ctx, state)
+	if err == nil {
+		return issues, nil
+	}
+	slog.Warn("failed to read project board, listing issues from configured repositories instead", "error", err)
+	return pc.listRepoIssues(ctx, state, repos)
+}
+
+// listRepoIssues lists the issues of each configured repository using the
+// REST API. Issues on the board from other repositories are missed.
+// Repositories that fail are retried once; if some still fail, the issues of
+// the others are returned with a *PartialListError.
+func (pc *ProjectClient) listRepoIssues(ctx context.Context, state string, repos []Repository) ([]*ProjectIssue, error) {
 	var allIssues []*ProjectIssue
+	var failed []Repository
 
 	// Query issues from each repository in the project
 	for _, repo := range repos {
-		opts := &github.IssueListByRepoOptions{
-			State: state,
-			ListOptions: github.ListOptions{
-				PerPage: 100,
-			},
+		issues, err := pc.listRepo(ctx, state, repo)
+		if err != nil {
+			slog.Warn("failed to list issues, will retry", "repo", repo.Owner+"/"+repo.Name, "error", err)
+			failed = append(failed, repo)
+			continue
+		}
+		allIssues = append(allIssues, issues...)
+	}
+
+	partial := &PartialListError{Total: len(repos)}
+	for _, repo := range failed {
+		issues, err := pc.listRepo(ctx, state, repo)
+		if err != nil {
+			partial.Failures = append(partial.Failures, RepoFailure{Repo: repo.Owner + "/" + repo.Name, Err: err})
+			continue
+		}
+		allIssues = append(allIssues, issues...)
+	}
+
+	if len(partial.Failures) > 0 {
+		if partial.AllFailed() {
+			return nil, partial
+		}
+		return allIssues, partial
+	}
+	return allIssues, nil
+}
+
+// listRepo lists the issues of one repository, all pages or nothing
+func (pc *ProjectClient) listRepo(ctx context.Context, state string, repo Repository) ([]*ProjectIssue, error) {
+	opts := &github.IssueListByRepoOptions{
+		State: state,
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	var result []*ProjectIssue
+	for {
+		issues, resp, err := pc.client.Issues.ListByRepo(ctx, repo.Owner, repo.Name, opts)
+		if err != nil {
+			return nil, err
 		}
 
-		for {
-			issues, resp, err := pc.client.Issues.ListByRepo(ctx, repo.Owner, repo.Name, opts)
-			if err != nil {
-				// Log error but continue with other repos
-				fmt.Printf("Warning: failed to list issues from %s/%s: %v\n", repo.Owner, repo.Name, err)
-				break
+		for _, issue := range issues {
+			// Filter out pull requests - only include actual issues
+			// If PullRequestLinks is not nil, it's a PR, not an issue
+			if issue.PullRequestLinks != nil {
+				continue
+			}
+			labels := make([]string, len(issue.Labels))
+			for j, label := range issue.Labels {
+				labels[j] = label.GetName()
 			}
 
-			for _, issue := range issues {
-				// Filter out pull requests - only include actual issues
-				// If PullRequestLinks is not nil, it's a PR, not an issue
-				if issue.PullRequestLinks != nil {
-					continue
-				}
-				labels := make([]string, len(issue.Labels))
-				for j, label := range issue.Labels {
-					labels[j] = label.GetName()
-				}
-
-				assignee := ""
-				if issue.Assignee != nil {
-					assignee = issue.Assignee.GetLogin()
-				}
-
-				projectIssue := &ProjectIssue{
-					Issue: Issue{
-						Number:    issue.GetNumber(),
-						Title:     issue.GetTitle(),
-						Body:      issue.GetBody(),
-						State:     issue.GetState(),
-						Labels:    labels,
-						Assignee:  assignee,
-						CreatedAt: issue.GetCreatedAt().Time,
-						UpdatedAt: issue.GetUpdatedAt().Time,
-						URL:       issue.GetHTMLURL(),
-					},
-					RepositoryOwner: repo.Owner,
-					RepositoryName:  repo.Name,
-					RepositoryURL:   fmt.Sprintf("https://github.com/%s/%s", repo.Owner, repo.Name),
-				}
-
-				allIssues = append(allIssues, projectIssue)
+			assignee := ""
+			if issue.Assignee != nil {
+				assignee = issue.Assignee.GetLogin()
 			}
 
-			if resp.NextPage == 0 {
-				break
+			projectIssue := &ProjectIssue{
+				Issue: Issue{
+					Number:    issue.GetNumber(),
+					Title:     issue.GetTitle(),
+					Body:      issue.GetBody(),
+					State:     issue.GetState(),
+					Labels:    labels,
+					Assignee:  assignee,
+					Author:    issue.GetUser().GetLogin(),
+					CreatedAt: issue.GetCreatedAt().Time,
+					UpdatedAt: issue.GetUpdatedAt().Time,
+					ClosedAt:  issue.GetClosedAt().Time,
+					URL:       issue.GetHTMLURL(),
+					NodeID:    issue.GetNodeID(),
+
+					Milestone:      issue.GetMilestone().GetTitle(),
+					MilestoneDueOn: issue.GetMilestone().GetDueOn().Time,
+				},
+				RepositoryOwner: repo.Owner,
+				RepositoryName:  repo.Name,
+				RepositoryURL:   fmt.Sprintf("https://github.com/%s/%s", repo.Owner, repo.Name),
 			}
-			opts.Page = resp.NextPage
+
+			result = append(result, projectIssue)
+		}
+
+		if resp.NextPage == 0 {
+			break
 		}
+		opts.Page = resp.NextPage
 	}
 
-	return allIssues, nil
+	return result, nil
 }
 
 // GetProjectIssue gets a specific issue from a repository
@@ -167,9 +258,15 @@ func (pc *ProjectClient) GetProjectIssue(ctx context.Context, owner, repo string
 			State:     issue.GetState(),
 			Labels:    labels,
 			Assignee:  assignee,
+			Author:    issue.GetUser().GetLogin(),
 			CreatedAt: issue.GetCreatedAt().Time,
 			UpdatedAt: issue.GetUpdatedAt().Time,
+			ClosedAt:  issue.GetClosedAt().Time,
 			URL:       issue.GetHTMLURL(),
+			NodeID:    issue.GetNodeID(),
+
+			Milestone:      issue.GetMilestone().GetTitle(),
+			MilestoneDueOn: issue.GetMilestone().GetDueOn().Time,
 		},
 		RepositoryOwner: owner,
 		RepositoryName:  repo,
@@ -187,24 +284,17 @@ func (pc *ProjectClient) UpdateProjectIssue(ctx context.Context, owner, repo str
 		issue.Body = body
 	}
 
-	_, _, err := pc.client.Issues.Edit(ctx, owner, repo, number, issue)
-	if err != nil {
-		return fmt.Errorf("failed to update issue: %w", err)
-	}
-	return nil
+	return pc.retry.do(ctx, func(int) error {
+		if _, _, err := pc.client.Issues.Edit(ctx, owner, repo, number, issue); err != nil {
+			return fmt.Errorf("failed to update issue: %w", err)
+		}
+		return nil
+	})
 }
 
 // AddProjectComment adds a comment to an issue in a specific repository
 func (pc *ProjectClient) AddProjectComment(ctx context.Context, owner, repo string, number int, comment string) error {
-	commentReq := &github.IssueComment{
-		Body: github.String(comment),
-	}
-
-	_, _, err := pc.client.Issues.CreateComment(ctx, owner, repo, number, commentReq)
-	if err != nil {
-		return fmt.Errorf("failed to add comment: %w", err)
-	}
-	return nil
+	return addComment(ctx, pc.client, pc.retry, owner, repo, number, comment)
 }
 
 // CreateProjectIssue creates an issue in a specific repository
@@ -230,6 +320,13 @@ func (pc *ProjectClient) CreateProjectIssue(ctx context.Context, owner, repo, ti
 		resultAssignee = issue.Assignee.GetLogin()
 	}
 
+	if pc.addCreatedToProject {
+		if err := pc.AddIssueToProject(ctx, issue.GetNodeID()); err != nil {
+			// The issue exists either way; don't fail creation
+			slog.Warn("failed to add issue to project", "issue", issue.GetNumber(), "error", err)
+		}
+	}
+
 	return &ProjectIssue{
 		Issue: Issue{
 			Number:    issue.GetNumber(),
@@ -238,9 +335,15 @@ func (pc *ProjectClient) CreateProjectIssue(ctx context.Context, owner, repo, ti
 			State:     issue.GetState(),
 			Labels:    resultLabels,
 			Assignee:  resultAssignee,
+			Author:    issue.GetUser().GetLogin(),
 			CreatedAt: issue.GetCreatedAt().Time,
 			UpdatedAt: issue.GetUpdatedAt().Time,
+			ClosedAt:  issue.GetClosedAt().Time,
 			URL:       issue.GetHTMLURL(),
+			NodeID:    issue.GetNodeID(),
+
+			Milestone:      issue.GetMilestone().GetTitle(),
+			MilestoneDueOn: issue.GetMilestone().GetDueOn().Time,
 		},
 		RepositoryOwner: owner,
 		RepositoryName:  repo,
@@ -250,38 +353,9 @@ func (pc *ProjectClient) CreateProjectIssue(ctx context.Context, owner, repo, ti
 
 // AddLabel adds a label to an issue in a specific repository
 func (pc *ProjectClient) AddLabel(ctx context.Context, owner, repo string, number int, label string) error {
-	// Get current issue to retrieve existing labels
-	issue, _, err := pc.client.Issues.Get(ctx, owner, repo, number)
-	if err != nil {
-		return fmt.Errorf("failed to get issue: %w", err)
-	}
-
-	// Check if label already exists
-	for _, existingLabel := range issue.Labels {
-		if existingLabel.GetName() == label {
-			// Label already exists, nothing to do
-			return nil
-		}
-	}
-
-	// Add the new label to the list
-	labels := make([]string, len(issue.Labels)+1)
-	for i, l := range issue.Labels {
-		labels[i] = l.GetName()
-	}
-	labels[len(issue.Labels)] = label
-
-	// Update issue with new labels
-	issueReq := &github.IssueRequest{
-		Labels: &labels,
-	}
-
-	_, _, err = pc.client.Issues.Edit(ctx, owner, repo, number, issueReq)
-	if err != nil {
-		return fmt.Errorf("failed to add label: %w", err)
-	}
-
-	return nil
+	return pc.retry.do(ctx, func(int) error {
+		return addLabels(ctx, pc.client, owner, repo, number, label)
+	})
 }
 
 // Repository represents a repository linked to a GitHub Project
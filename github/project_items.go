@@ -0,0 +1,87 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// projectNodeID returns the GraphQL node ID of the project.
+// The configured project ID may already be a node ID, or a project number
+// owned by an organization or user.
+func (pc *ProjectClient) projectNodeID(ctx context.Context) (string, error) {
+	pc.nodeIDMu.Lock()
+	defer pc.nodeIDMu.Unlock()
+
+	if pc.projectNodeIDCache != "" {
+		return pc.projectNodeIDCache, nil
+	}
+
+	number, err := strconv.Atoi(pc.projectID)
+	if err != nil {
+		// Not a number - assume it's already a node ID
+		pc.projectNodeIDCache = pc.projectID
+		return pc.projectID, nil
+	}
+
+	var orgErr error
+	for _, ownerType := range []string{"organization", "user"} {
+		query := fmt.Sprintf(`query($login: String!, $number: Int!) {
+  owner: %s(login: $login) { projectV2(number: $number) { id } }
+}`, ownerType)
+
+		var result struct {
+			Owner *struct {
+				ProjectV2 *struct {
+					ID string `json:"id"`
+				} `json:"projectV2"`
+			} `json:"owner"`
+		}
+		err := graphQL(ctx, pc.client, query, map[string]interface{}{"login": pc.owner, "number": number}, &result)
+		if err == nil && result.Owner != nil && result.Owner.ProjectV2 != nil {
+			pc.projectNodeIDCache = result.Owner.ProjectV2.ID
+			return pc.projectNodeIDCache, nil
+		}
+		if orgErr == nil {
+			orgErr = err
+		}
+	}
+
+	if orgErr != nil {
+		return "", fmt.Errorf("failed to resolve project %d for %s: %w", number, pc.owner, orgErr)
+	}
+	return "", fmt.Errorf("project %d not found for %s", number, pc.owner)
+}
+
+// AddIssueToProject adds an issue or pull request to the project board.
+// itemContentID is the GraphQL node ID of the issue or pull request.
+func (pc *ProjectClient) AddIssueToProject(ctx context.Context, itemContentID string) error {
+	if itemContentID == "" {
+		return fmt.Errorf("item content ID is required")
+	}
+
+	projectID, err := pc.projectNodeID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const mutation = `mutation($projectId: ID!, $contentId: ID!) {
+  addProjectV2ItemById(input: {projectId: $projectId, contentId: $contentId}) { item { id } }
+}`
+
+	var result struct {
+		AddProjectV2ItemByID struct {
+			Item struct {
+				ID string `json:"id"`
+			} `json:"item"`
+		} `json:"addProjectV2ItemById"`
+	}
+	variables := map[string]interface{}{"projectId": projectID, "contentId": itemContentID}
+	// Adding an item that is already on the board returns it, so retrying is safe
+	return pc.retry.do(ctx, func(int) error {
+		if err := graphQL(ctx, pc.client, mutation, variables, &result); err != nil {
+			return fmt.Errorf("failed to add item to project: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,77 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+)
+
+func TestAddIssueToProject(t *testing.T) {
+	var requests []graphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/graphql" {
+			t.Errorf("unexpected GraphQL path %q", r.URL.Path)
+		}
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		requests = append(requests, req)
+
+		if len(requests) == 1 {
+			w.Write([]byte(`{"data":{"owner":{"projectV2":{"id":"PVT_123"}}}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"addProjectV2ItemById":{"item":{"id":"PVTI_1"}}}}`))
+	}))
+	defer server.Close()
+
+	client, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pc := &ProjectClient{client: client, projectID: "7", owner: "acme"}
+
+	if err := pc.AddIssueToProject(context.Background(), "I_abc"); err != nil {
+		t.Fatalf("AddIssueToProject() error = %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 GraphQL requests, got %d", len(requests))
+	}
+	if requests[0].Variables["login"] != "acme" || requests[0].Variables["number"] != float64(7) {
+		t.Errorf("unexpected project lookup variables: %v", requests[0].Variables)
+	}
+	if requests[1].Variables["projectId"] != "PVT_123" || requests[1].Variables["contentId"] != "I_abc" {
+		t.Errorf("unexpected mutation variables: %v", requests[1].Variables)
+	}
+
+	// The resolved project node ID is cached
+	if err := pc.AddIssueToProject(context.Background(), "I_def"); err != nil {
+		t.Fatalf("AddIssueToProject() error = %v", err)
+	}
+	if len(requests) != 3 {
+		t.Errorf("expected project lookup to be cached, got %d requests", len(requests))
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":null,"errors":[{"message":"Could not resolve to a node"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pc := &ProjectClient{client: client, projectID: "PVT_123"}
+
+	if err := pc.AddIssueToProject(context.Background(), "I_abc"); err == nil {
+		t.Error("expected GraphQL errors to be returned")
+	}
+}
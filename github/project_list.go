@@ -0,0 +1,232 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// projectItemsQuery pages through a Projects v2 board with each item's issue
+// and field values
+const projectItemsQuery = `query($id: ID!, $cursor: String) {
+  node(id: $id) {
+    ... on ProjectV2 {
+      items(first: 100, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          id
+          fieldValues(first: 30) {
+            nodes {
+              ... on ProjectV2ItemFieldSingleSelectValue { name field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldIterationValue { title field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldTextValue { text field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldNumberValue { number field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldDateValue { date field { ... on ProjectV2FieldCommon { name } } }
+            }
+          }
+          content {
+            __typename
+            ... on Issue {
+              id number title body state url createdAt updatedAt closedAt
+              repository { name url owner { login } }
+              labels(first: 50) { nodes { name } }
+              assignees(first: 1) { nodes { login } }
+              author { login }
+              milestone { title dueOn }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// projectItemFieldValue is one field value of a project item. Only the
+// attribute matching the field's type is set.
+type projectItemFieldValue struct {
+	Name   string   `json:"name"`
+	Title  string   `json:"title"`
+	Text   string   `json:"text"`
+	Number *float64 `json:"number"`
+	Date   string   `json:"date"`
+	Field  struct {
+		Name string `json:"name"`
+	} `json:"field"`
+}
+
+// value returns the field value as a string
+func (v projectItemFieldValue) value() string {
+	switch {
+	case v.Name != "":
+		return v.Name
+	case v.Title != "":
+		return v.Title
+	case v.Text != "":
+		return v.Text
+	case v.Number != nil:
+		return strconv.FormatFloat(*v.Number, 'f', -1, 64)
+	default:
+		return v.Date
+	}
+}
+
+type projectItemNode struct {
+	ID          string `json:"id"`
+	FieldValues struct {
+		Nodes []projectItemFieldValue `json:"nodes"`
+	} `json:"fieldValues"`
+	Content struct {
+		Typename   string     `json:"__typename"`
+		ID         string     `json:"id"`
+		Number     int        `json:"number"`
+		Title      string     `json:"title"`
+		Body       string     `json:"body"`
+		State      string     `json:"state"`
+		URL        string     `json:"url"`
+		CreatedAt  time.Time  `json:"createdAt"`
+		UpdatedAt  time.Time  `json:"updatedAt"`
+		ClosedAt   *time.Time `json:"closedAt"`
+		Repository struct {
+			Name  string `json:"name"`
+			URL   string `json:"url"`
+			Owner struct {
+				Login string `json:"login"`
+			} `json:"owner"`
+		} `json:"repository"`
+		Labels struct {
+			Nodes []struct {
+				Name string `json:"name"`
+			} `json:"nodes"`
+		} `json:"labels"`
+		Assignees struct {
+			Nodes []struct {
+				Login string `json:"login"`
+			} `json:"nodes"`
+		} `json:"assignees"`
+		Author struct {
+			Login string `json:"login"`
+		} `json:"author"`
+		Milestone *struct {
+			Title string     `json:"title"`
+			DueOn *time.Time `json:"dueOn"`
+		} `json:"milestone"`
+	} `json:"content"`
+}
+
+// listProjectItems lists the issues on the project board with their field
+// values via the Projects v2 GraphQL API. Pull requests and draft items are
+// skipped. state is "open", "closed" or "all".
+func (pc *ProjectClient) listProjectItems(ctx context.Context, state string) ([]*ProjectIssue, error) {
+	projectID, err := pc.projectNodeID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []*ProjectIssue
+	var cursor interface{}
+	for {
+		var result struct {
+			Node *struct {
+				Items struct {
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+					Nodes []projectItemNode `json:"nodes"`
+				} `json:"items"`
+			} `json:"node"`
+		}
+		variables := map[string]interface{}{"id": projectID, "cursor": cursor}
+		if err := graphQL(ctx, pc.client, projectItemsQuery, variables, &result); err != nil {
+			return nil, fmt.Errorf("failed to list project items: %w", err)
+		}
+		if result.Node == nil {
+			return nil, fmt.Errorf("project %s not found", projectID)
+		}
+
+		for _, item := range result.Node.Items.Nodes {
+			issue := item.toProjectIssue()
+			if issue == nil {
+				continue
+			}
+			if state != "all" && issue.State != state {
+				continue
+			}
+			issues = append(issues, issue)
+		}
+
+		if !result.Node.Items.PageInfo.HasNextPage {
+			break
+		}
+		cursor = result.Node.Items.PageInfo.EndCursor
+	}
+
+	return issues, nil
+}
+
+// toProjectIssue converts a project item to a ProjectIssue, or returns nil
+// if the item isn't an issue
+func (item projectItemNode) toProjectIssue() *ProjectIssue {
+	content := item.Content
+	if content.Typename != "Issue" {
+		return nil
+	}
+
+	labels := make([]string, len(content.Labels.Nodes))
+	for i, label := range content.Labels.Nodes {
+		labels[i] = label.Name
+	}
+
+	assignee := ""
+	if len(content.Assignees.Nodes) > 0 {
+		assignee = content.Assignees.Nodes[0].Login
+	}
+
+	var closedAt time.Time
+	if content.ClosedAt != nil {
+		closedAt = *content.ClosedAt
+	}
+
+	var milestone string
+	var milestoneDueOn time.Time
+	if content.Milestone != nil {
+		milestone = content.Milestone.Title
+		if content.Milestone.DueOn != nil {
+			milestoneDueOn = *content.Milestone.DueOn
+		}
+	}
+
+	fields := make(map[string]string)
+	for _, v := range item.FieldValues.Nodes {
+		if v.Field.Name != "" {
+			fields[v.Field.Name] = v.value()
+		}
+	}
+
+	return &ProjectIssue{
+		Issue: Issue{
+			Number:    content.Number,
+			Title:     content.Title,
+			Body:      content.Body,
+			State:     strings.ToLower(content.State),
+			Labels:    labels,
+			Assignee:  assignee,
+			Author:    content.Author.Login,
+			CreatedAt: content.CreatedAt,
+			UpdatedAt: content.UpdatedAt,
+			ClosedAt:  closedAt,
+			URL:       content.URL,
+			NodeID:    content.ID,
+			Fields:    fields,
+
+			Milestone:      milestone,
+			MilestoneDueOn: milestoneDueOn,
+		},
+		RepositoryOwner: content.Repository.Owner.Login,
+		RepositoryName:  content.Repository.Name,
+		RepositoryURL:   content.Repository.URL,
+		ProjectItemID:   item.ID,
+	}
+}
@@ -0,0 +1,223 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+)
+
+func TestListProjectIssues_GraphQL(t *testing.T) {
+	var requests []graphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/graphql" {
+			t.Errorf("unexpected request to %q", r.URL.Path)
+			return
+		}
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		requests = append(requests, req)
+
+		if req.Variables["cursor"] == nil {
+			w.Write([]byte(`{"data":{"node":{"items":{"pageInfo":{"hasNextPage":true,"endCursor":"c1"},"nodes":[
+  {"id":"PVTI_1","fieldValues":{"nodes":[
+    {"name":"In Progress","field":{"name":"Status"}},
+    {"number":3,"field":{"name":"Estimate"}},
+    {}
+  ]},"content":{"__typename":"Issue","id":"I_1","number":1,"title":"First","state":"OPEN","url":"https://github.com/acme/api/issues/1",
+    "createdAt":"2024-01-01T00:00:00Z","updatedAt":"2024-01-02T00:00:00Z","closedAt":null,
+    "repository":{"name":"api","url":"https://github.com/acme/api","owner":{"login":"acme"}},
+    "labels":{"nodes":[{"name":"bug"}]},"assignees":{"nodes":[{"login":"alice"}]},
+    "milestone":{"title":"v1.0","dueOn":"2024-02-01T00:00:00Z"}}},
+  {"id":"PVTI_2","fieldValues":{"nodes":[]},"content":{"__typename":"PullRequest"}}
+]}}}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"node":{"items":{"pageInfo":{"hasNextPage":false,"endCursor":""},"nodes":[
+  {"id":"PVTI_3","fieldValues":{"nodes":[]},"content":{"__typename":"Issue","id":"I_3","number":3,"title":"Done","state":"CLOSED",
+    "createdAt":"2024-01-01T00:00:00Z","updatedAt":"2024-01-02T00:00:00Z","closedAt":"2024-01-03T00:00:00Z",
+    "repository":{"name":"web","url":"https://github.com/acme/web","owner":{"login":"acme"}},
+    "labels":{"nodes":[]},"assignees":{"nodes":[]}}}
+]}}}}`))
+	}))
+	defer server.Close()
+
+	client, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pc := &ProjectClient{client: client, projectID: "PVT_123", owner: "acme"}
+
+	issues, err := pc.ListProjectIssues(context.Background(), "open", nil)
+	if err != nil {
+		t.Fatalf("ListProjectIssues() error = %v", err)
+	}
+	if len(requests) != 2 || requests[1].Variables["cursor"] != "c1" {
+		t.Fatalf("expected two paginated requests, got %v", requests)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 open issue, got %d", len(issues))
+	}
+
+	issue := issues[0]
+	if issue.Number != 1 || issue.State != "open" || issue.Assignee != "alice" || issue.NodeID != "I_1" {
+		t.Errorf("unexpected issue: %+v", issue.Issue)
+	}
+	if issue.RepositoryOwner != "acme" || issue.RepositoryName != "api" || issue.ProjectItemID != "PVTI_1" {
+		t.Errorf("unexpected project metadata: %+v", issue)
+	}
+	if issue.Milestone != "v1.0" || issue.MilestoneDueOn.Format("2006-01-02") != "2024-02-01" {
+		t.Errorf("unexpected milestone: %q due %v", issue.Milestone, issue.MilestoneDueOn)
+	}
+	if issue.Fields["Status"] != "In Progress" || issue.Fields["Estimate"] != "3" {
+		t.Errorf("unexpected fields: %v", issue.Fields)
+	}
+	if status, ok := issue.Field("status"); !ok || status != "In Progress" {
+		t.Errorf("Field(status) = %q, %v", status, ok)
+	}
+	if _, ok := issue.Field("Iteration"); ok {
+		t.Error("expected no Iteration value")
+	}
+}
+
+func TestListProjectIssues_FallsBackToREST(t *testing.T) {
+	var restCalls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/graphql" {
+			w.Write([]byte(`{"data":null,"errors":[{"message":"Resource not accessible by integration"}]}`))
+			return
+		}
+		if r.URL.Path != "/api/v3/repos/acme/api/issues" {
+			t.Errorf("unexpected request to %q", r.URL.Path)
+			return
+		}
+		restCalls++
+		w.Write([]byte(`[{"number":5,"title":"From REST","state":"open"}]`))
+	}))
+	defer server.Close()
+
+	client, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pc := &ProjectClient{client: client, projectID: "PVT_123", owner: "acme"}
+
+	issues, err := pc.ListProjectIssues(context.Background(), "open", []Repository{{Owner: "acme", Name: "api"}})
+	if err != nil {
+		t.Fatalf("ListProjectIssues() error = %v", err)
+	}
+	if restCalls == 0 {
+		t.Fatal("expected REST fallback to be used")
+	}
+	if len(issues) != 1 || issues[0].Number != 5 || issues[0].RepositoryName != "api" {
+		t.Errorf("unexpected issues: %+v", issues)
+	}
+}
+
+func TestListProjectIssues_PartialFailure(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/graphql":
+			w.Write([]byte(`{"data":null,"errors":[{"message":"Resource not accessible by integration"}]}`))
+		case "/api/v3/repos/acme/api/issues":
+			w.Write([]byte(`[{"number":1,"title":"API task","state":"open"}]`))
+		case "/api/v3/repos/acme/web/issues":
+			requests["web"]++
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+		case "/api/v3/repos/acme/cli/issues":
+			w.Write([]byte(`[{"number":2,"title":"CLI task","state":"open"}]`))
+		default:
+			t.Errorf("unexpected request to %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repos := []Repository{{Owner: "acme", Name: "api"}, {Owner: "acme", Name: "web"}, {Owner: "acme", Name: "cli"}}
+	uc := &UnifiedClientWrapper{
+		projectClient: &ProjectClient{client: client, projectID: "PVT_123", owner: "acme"},
+		mode:          "project",
+		repos:         repos,
+	}
+
+	issues, err := uc.ListIssuesPartial(context.Background(), "open")
+	var partial *PartialListError
+	if !errors.As(err, &partial) {
+		t.Fatalf("expected a *PartialListError, got %v", err)
+	}
+	if partial.AllFailed() || len(partial.Failures) != 1 || partial.Failures[0].Repo != "acme/web" {
+		t.Errorf("expected only acme/web to fail, got %+v", partial.Failures)
+	}
+	if len(issues) != 2 || issues[0].Number != 1 || issues[1].Number != 2 {
+		t.Errorf("expected the issues of the other repositories, got %+v", issues)
+	}
+	if requests["web"] != 2 {
+		t.Errorf("expected the failing repository to be retried once, got %d requests", requests["web"])
+	}
+
+	// ListIssues skips the failed repository with a warning
+	issues, err = uc.ListIssues(context.Background(), "open")
+	if err != nil || len(issues) != 2 {
+		t.Errorf("ListIssues() = %d issues, %v; want 2 issues and no error", len(issues), err)
+	}
+
+	// With every repository failing, listing fails
+	uc.repos = []Repository{{Owner: "acme", Name: "web"}}
+	if _, err := uc.ListIssues(context.Background(), "open"); !errors.As(err, &partial) || !partial.AllFailed() {
+		t.Errorf("expected an error when all repositories fail, got %v", err)
+	}
+}
+
+func TestListIssues_OnlyRepos(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/graphql" {
+			t.Errorf("unexpected request to %q", r.URL.Path)
+			return
+		}
+		w.Write([]byte(`{"data":{"node":{"items":{"pageInfo":{"hasNextPage":false,"endCursor":""},"nodes":[
+  {"id":"PVTI_1","fieldValues":{"nodes":[]},"content":{"__typename":"Issue","id":"I_1","number":1,"title":"API task","state":"OPEN",
+    "createdAt":"2024-01-01T00:00:00Z","updatedAt":"2024-01-02T00:00:00Z","closedAt":null,
+    "repository":{"name":"api","url":"https://github.com/acme/api","owner":{"login":"acme"}},
+    "labels":{"nodes":[]},"assignees":{"nodes":[]}}},
+  {"id":"PVTI_2","fieldValues":{"nodes":[]},"content":{"__typename":"Issue","id":"I_2","number":2,"title":"Web task","state":"OPEN",
+    "createdAt":"2024-01-01T00:00:00Z","updatedAt":"2024-01-02T00:00:00Z","closedAt":null,
+    "repository":{"name":"web","url":"https://github.com/acme/web","owner":{"login":"acme"}},
+    "labels":{"nodes":[]},"assignees":{"nodes":[]}}}
+]}}}}`))
+	}))
+	defer server.Close()
+
+	repos := []Repository{{Owner: "ACME", Name: "api"}}
+	for _, tt := range []struct {
+		onlyRepos bool
+		want      int
+	}{
+		{onlyRepos: false, want: 2},
+		{onlyRepos: true, want: 1},
+	} {
+		client, err := NewUnifiedClientWithOptions("token", nil, "acme", "", "PVT_123", repos, server.URL, ClientOptions{OnlyRepos: tt.onlyRepos})
+		if err != nil {
+			t.Fatal(err)
+		}
+		issues, err := client.ListIssues(context.Background(), "open")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(issues) != tt.want {
+			t.Fatalf("OnlyRepos=%v: expected %d issues, got %d", tt.onlyRepos, tt.want, len(issues))
+		}
+		if tt.onlyRepos && issues[0].Number != 1 {
+			t.Errorf("expected only the acme/api issue, got #%d", issues[0].Number)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/kaskol10/github-project-agent/markdown"
+)
+
+// PullRequest is a pull request linked to an issue
+type PullRequest struct {
+	Number    int
+	Title     string
+	Body      string
+	State     string
+	Author    string
+	Labels    []string
+	HeadSHA   string // Commit at the tip of the PR branch
+	UpdatedAt time.Time
+	URL       string
+}
+
+// linkedPullRequests returns the pull requests that reference an issue.
+// It prefers the timeline cross-reference events and falls back to parsing
+// closing keywords in open PR bodies when the timeline API is unavailable
+// (e.g. older GitHub Enterprise Server versions).
+func linkedPullRequests(ctx context.Context, client *github.Client, owner, repo string, number int) ([]*PullRequest, error) {
+	prs, err := timelineLinkedPullRequests(ctx, client, owner, repo, number)
+	if err == nil {
+		return prs, nil
+	}
+
+	prs, fallbackErr := bodyLinkedPullRequests(ctx, client, owner, repo, number)
+	if fallbackErr != nil {
+		return nil, fmt.Errorf("failed to resolve linked pull requests (timeline: %v): %w", err, fallbackErr)
+	}
+	return prs, nil
+}
+
+// timelineLinkedPullRequests finds PRs via "cross-referenced" timeline events
+func timelineLinkedPullRequests(ctx context.Context, client *github.Client, owner, repo string, number int) ([]*PullRequest, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	var prs []*PullRequest
+	seen := make(map[string]bool)
+
+	for {
+		events, resp, err := client.Issues.ListIssueTimeline(ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issue timeline: %w", err)
+		}
+
+		for _, event := range events {
+			if event.GetEvent() != "cross-referenced" || event.Source == nil || event.Source.Issue == nil {
+				continue
+			}
+			source := event.Source.Issue
+			if source.PullRequestLinks == nil || seen[source.GetHTMLURL()] {
+				continue
+			}
+			seen[source.GetHTMLURL()] = true
+
+			prs = append(prs, &PullRequest{
+				Number:    source.GetNumber(),
+				Title:     source.GetTitle(),
+				Body:      source.GetBody(),
+				State:     source.GetState(),
+				Author:    source.GetUser().GetLogin(),
+				UpdatedAt: source.GetUpdatedAt().Time,
+				URL:       source.GetHTMLURL(),
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return prs, nil
+}
+
+// bodyLinkedPullRequests finds open PRs in the issue's repository whose body
+// closes the issue ("Closes #123", "Fixes owner/repo#123")
+func bodyLinkedPullRequests(ctx context.Context, client *github.Client, owner, repo string, number int) ([]*PullRequest, error) {
+	openPRs, err := listPullRequests(ctx, client, owner, repo, "open")
+	if err != nil {
+		return nil, err
+	}
+
+	var linked []*PullRequest
+	for _, pr := range openPRs {
+		for _, ref := range markdown.ClosingReferences(pr.Body) {
+			if ref.Matches(owner, repo, number) {
+				linked = append(linked, pr)
+				break
+			}
+		}
+	}
+	return linked, nil
+}
+
+// listPullRequests lists pull requests in a repository
+func listPullRequests(ctx context.Context, client *github.Client, owner, repo, state string) ([]*PullRequest, error) {
+	opts := &github.PullRequestListOptions{
+		State: state,
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	var result []*PullRequest
+	for {
+		prs, resp, err := client.PullRequests.List(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests: %w", err)
+		}
+		for _, pr := range prs {
+			result = append(result, convertPullRequest(pr))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return result, nil
+}
+
+func convertPullRequest(pr *github.PullRequest) *PullRequest {
+	labels := make([]string, len(pr.Labels))
+	for i, label := range pr.Labels {
+		labels[i] = label.GetName()
+	}
+
+	return &PullRequest{
+		Number:    pr.GetNumber(),
+		Title:     pr.GetTitle(),
+		Body:      pr.GetBody(),
+		State:     pr.GetState(),
+		Author:    pr.GetUser().GetLogin(),
+		Labels:    labels,
+		HeadSHA:   pr.GetHead().GetSHA(),
+		UpdatedAt: pr.GetUpdatedAt().Time,
+		URL:       pr.GetHTMLURL(),
+	}
+}
+
+// pullRequestDiff returns the unified diff of a pull request
+func pullRequestDiff(ctx context.Context, client *github.Client, owner, repo string, number int) (string, error) {
+	diff, _, err := client.PullRequests.GetRaw(ctx, owner, repo, number, github.RawOptions{Type: github.Diff})
+	if err != nil {
+		return "", fmt.Errorf("failed to get pull request diff: %w", err)
+	}
+	return diff, nil
+}
+
+// ListPullRequests lists pull requests in the configured repository (implements UnifiedClient interface)
+func (c *Client) ListPullRequests(ctx context.Context, state string) ([]*PullRequest, error) {
+	return listPullRequests(ctx, c.client, c.owner, c.repo, state)
+}
+
+// ListPullRequests lists pull requests across the given repositories
+func (pc *ProjectClient) ListPullRequests(ctx context.Context, state string, repos []Repository) ([]*PullRequest, error) {
+	var result []*PullRequest
+	for _, repo := range repos {
+		prs, err := listPullRequests(ctx, pc.client, repo.Owner, repo.Name, state)
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %w", repo.Owner, repo.Name, err)
+		}
+		result = append(result, prs...)
+	}
+	return result, nil
+}
+
+// GetPullRequestDiff returns the diff of a pull request (implements UnifiedClient interface)
+// In repo mode, owner and repo parameters are ignored
+func (c *Client) GetPullRequestDiff(ctx context.Context, owner, repo string, number int) (string, error) {
+	return pullRequestDiff(ctx, c.client, c.owner, c.repo, number)
+}
+
+// GetPullRequestDiff returns the diff of a pull request in a specific repository
+func (pc *ProjectClient) GetPullRequestDiff(ctx context.Context, owner, repo string, number int) (string, error) {
+	return pullRequestDiff(ctx, pc.client, owner, repo, number)
+}
+
+// GetLinkedPullRequests returns pull requests linked to an issue (implements UnifiedClient interface)
+// In repo mode, owner and repo parameters are ignored
+func (c *Client) GetLinkedPullRequests(ctx context.Context, owner, repo string, number int) ([]*PullRequest, error) {
+	return linkedPullRequests(ctx, c.client, c.owner, c.repo, number)
+}
+
+// GetLinkedPullRequests returns pull requests linked to an issue in a specific repository
+func (pc *ProjectClient) GetLinkedPullRequests(ctx context.Context, owner, repo string, number int) ([]*PullRequest, error) {
+	return linkedPullRequests(ctx, pc.client, owner, repo, number)
+}
@@ -0,0 +1,91 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+)
+
+func TestPullRequests_ListAndDiff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/o/r/pulls":
+			if r.URL.Query().Get("state") != "open" {
+				t.Errorf("unexpected state %q", r.URL.Query().Get("state"))
+			}
+			w.Write([]byte(`[{"number":5,"title":"Add cache","state":"open","user":{"login":"dev"},"labels":[{"name":"needs-review"}],"head":{"sha":"abc123"},"html_url":"https://github.com/o/r/pull/5"}]`))
+		case "/api/v3/repos/o/r/pulls/5":
+			if accept := r.Header.Get("Accept"); accept != "application/vnd.github.v3.diff" {
+				t.Errorf("unexpected Accept header %q", accept)
+			}
+			w.Write([]byte("diff --git a/x.go b/x.go\n"))
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	gh, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &Client{client: gh, owner: "o", repo: "r"}
+
+	prs, err := client.ListPullRequests(context.Background(), "open")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) != 1 || prs[0].Number != 5 || prs[0].Author != "dev" || prs[0].HeadSHA != "abc123" || !reflect.DeepEqual(prs[0].Labels, []string{"needs-review"}) {
+		t.Errorf("unexpected pull requests: %+v", prs)
+	}
+
+	diff, err := client.GetPullRequestDiff(context.Background(), "", "", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "diff --git a/x.go b/x.go\n" {
+		t.Errorf("unexpected diff %q", diff)
+	}
+}
+
+func TestGetLinkedPullRequests_FallsBackToClosingKeywords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/o/r/issues/7/timeline":
+			// Older GitHub Enterprise Server versions lack the timeline API
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+		case "/api/v3/repos/o/r/pulls":
+			w.Write([]byte(`[
+				{"number":5,"state":"open","body":"Closes #7","html_url":"https://github.com/o/r/pull/5"},
+				{"number":6,"state":"open","body":"Fixes o/r#8","html_url":"https://github.com/o/r/pull/6"},
+				{"number":9,"state":"open","body":"Resolves o/r#7","html_url":"https://github.com/o/r/pull/9"},
+				{"number":10,"state":"open","body":"Related to #7","html_url":"https://github.com/o/r/pull/10"}
+			]`))
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	gh, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &Client{client: gh, owner: "o", repo: "r"}
+
+	prs, err := client.GetLinkedPullRequests(context.Background(), "", "", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var numbers []int
+	for _, pr := range prs {
+		numbers = append(numbers, pr.Number)
+	}
+	if !reflect.DeepEqual(numbers, []int{5, 9}) {
+		t.Errorf("expected PRs closing #7 to be linked, got %v", numbers)
+	}
+}
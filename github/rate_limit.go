@@ -0,0 +1,160 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+const (
+	// defaultRateLimitMinRemaining is how many requests may remain before the
+	// client waits for the rate limit window to reset
+	defaultRateLimitMinRemaining = 10
+
+	// maxRateLimitWait caps a single wait in case of a bogus reset header
+	maxRateLimitWait = time.Hour
+
+	// maxSecondaryRateLimitRetries bounds retries after a Retry-After response
+	maxSecondaryRateLimitRetries = 3
+)
+
+// rateLimitTransport throttles requests based on GitHub's rate limit headers.
+// When few requests remain it waits until the window resets, and when GitHub
+// answers with Retry-After (secondary rate limits) it waits and retries.
+// Waits end early when the request context is canceled.
+type rateLimitTransport struct {
+	base         http.RoundTripper
+	minRemaining int
+	now          func() time.Time
+	sleep        func(ctx context.Context, d time.Duration) error
+}
+
+func newRateLimitTransport(base http.RoundTripper, minRemaining int) *rateLimitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if minRemaining <= 0 {
+		minRemaining = defaultRateLimitMinRemaining
+	}
+	return &rateLimitTransport{
+		base:         base,
+		minRemaining: minRemaining,
+		now:          time.Now,
+		sleep:        sleepContext,
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if wait, ok := retryAfter(resp); ok && attempt < maxSecondaryRateLimitRetries && canRetry(req) {
+			slog.Warn("GitHub secondary rate limit hit, retrying", "wait", wait)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if err := t.sleep(req.Context(), wait); err != nil {
+				return nil, err
+			}
+			if req, err = rewind(req); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if wait, ok := t.untilReset(resp); ok {
+			slog.Warn("GitHub rate limit low, waiting for reset",
+				"remaining", resp.Header.Get("X-RateLimit-Remaining"), "wait", wait)
+			// The response is still valid; a canceled wait surfaces on the next request
+			_ = t.sleep(req.Context(), wait)
+		}
+		return resp, nil
+	}
+}
+
+// retryAfter returns the wait requested by a rate limited response
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return capWait(time.Duration(seconds) * time.Second), true
+}
+
+// untilReset returns how long to wait when the remaining requests are at or
+// below the threshold
+func (t *rateLimitTransport) untilReset(resp *http.Response) (time.Duration, bool) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil || remaining > t.minRemaining {
+		return 0, false
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	wait := time.Unix(reset, 0).Sub(t.now())
+	if wait <= 0 {
+		return 0, false
+	}
+	return capWait(wait), true
+}
+
+func capWait(d time.Duration) time.Duration {
+	if d > maxRateLimitWait {
+		return maxRateLimitWait
+	}
+	return d
+}
+
+// canRetry reports whether the request body can be sent again
+func canRetry(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns a copy of req with a fresh body for a retry
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewind request body: %w", err)
+	}
+	retry := req.Clone(req.Context())
+	retry.Body = body
+	return retry, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// withRateLimitThrottle returns a copy of client whose requests are throttled
+// by rateLimitTransport
+func withRateLimitThrottle(client *github.Client, minRemaining int) *github.Client {
+	httpClient := client.Client()
+	httpClient.Transport = newRateLimitTransport(httpClient.Transport, minRemaining)
+
+	throttled := github.NewClient(httpClient)
+	throttled.BaseURL = client.BaseURL
+	throttled.UploadURL = client.UploadURL
+	return throttled
+}
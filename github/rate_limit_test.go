@@ -0,0 +1,117 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// recordSleeps replaces the transport's sleep so tests don't wait
+func recordSleeps(t *rateLimitTransport) *[]time.Duration {
+	var sleeps []time.Duration
+	t.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return ctx.Err()
+	}
+	return &sleeps
+}
+
+func TestRateLimitTransport_WaitsWhenRemainingLow(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "1")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(30*time.Second).Unix(), 10))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	transport := newRateLimitTransport(http.DefaultTransport, 5)
+	transport.now = func() time.Time { return now }
+	sleeps := recordSleeps(transport)
+
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(*sleeps) != 1 || (*sleeps)[0] != 30*time.Second {
+		t.Errorf("expected one 30s wait for the reset, got %v", *sleeps)
+	}
+}
+
+func TestRateLimitTransport_NoWaitWithHeadroom(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "4000")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+	}))
+	defer server.Close()
+
+	transport := newRateLimitTransport(http.DefaultTransport, 5)
+	sleeps := recordSleeps(transport)
+
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(*sleeps) != 0 {
+		t.Errorf("expected no wait, got %v", *sleeps)
+	}
+}
+
+func TestRateLimitTransport_RetriesAfterSecondaryLimit(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	transport := newRateLimitTransport(http.DefaultTransport, 5)
+	sleeps := recordSleeps(transport)
+
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls != 2 {
+		t.Errorf("expected a successful retry, got status %d after %d calls", resp.StatusCode, calls)
+	}
+	if len(*sleeps) != 1 || (*sleeps)[0] != 2*time.Second {
+		t.Errorf("expected one 2s wait, got %v", *sleeps)
+	}
+}
+
+func TestRateLimitTransport_WaitBoundedByContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	transport := newRateLimitTransport(http.DefaultTransport, 5)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	start := time.Now()
+	_, err := (&http.Client{Transport: transport}).Do(req)
+	if err == nil {
+		t.Fatal("expected the canceled context to end the wait")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("wait was not bounded by the context (took %v)", elapsed)
+	}
+}
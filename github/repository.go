@@ -0,0 +1,48 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// RepoInfo is repository-level context useful for prompts
+type RepoInfo struct {
+	Owner         string
+	Name          string
+	FullName      string
+	Description   string
+	DefaultBranch string
+	Topics        []string
+	URL           string
+}
+
+// getRepository fetches repository metadata
+func getRepository(ctx context.Context, client *github.Client, owner, repo string) (*RepoInfo, error) {
+	r, _, err := client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository %s/%s: %w", owner, repo, err)
+	}
+
+	return &RepoInfo{
+		Owner:         r.GetOwner().GetLogin(),
+		Name:          r.GetName(),
+		FullName:      r.GetFullName(),
+		Description:   r.GetDescription(),
+		DefaultBranch: r.GetDefaultBranch(),
+		Topics:        r.Topics,
+		URL:           r.GetHTMLURL(),
+	}, nil
+}
+
+// GetRepository returns repository metadata (implements UnifiedClient interface)
+// In repo mode, owner and repo parameters are ignored
+func (c *Client) GetRepository(ctx context.Context, owner, repo string) (*RepoInfo, error) {
+	return getRepository(ctx, c.client, c.owner, c.repo)
+}
+
+// GetRepository returns metadata for a specific repository
+func (pc *ProjectClient) GetRepository(ctx context.Context, owner, repo string) (*RepoInfo, error) {
+	return getRepository(ctx, pc.client, owner, repo)
+}
@@ -0,0 +1,70 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// defaultRetryDelay is the wait before the first retry of a failed write
+const defaultRetryDelay = time.Second
+
+// RetryPolicy retries GitHub writes that fail with a server error or a
+// secondary rate limit. Retries wait BaseDelay, doubling each time, unless
+// GitHub asks for a specific wait with Retry-After. Creating issues is never
+// retried since a failed request may still have created one.
+type RetryPolicy struct {
+	MaxRetries int           // Retries after the first attempt (0 = none)
+	BaseDelay  time.Duration // Wait before the first retry (defaults to one second)
+}
+
+// do runs write, retrying transient failures as configured. The last error is
+// returned when retries run out or ctx is canceled while waiting.
+func (p RetryPolicy) do(ctx context.Context, write func(attempt int) error) error {
+	delay := p.BaseDelay
+	if delay <= 0 {
+		delay = defaultRetryDelay
+	}
+	for attempt := 0; ; attempt++ {
+		err := write(attempt)
+		if err == nil || attempt >= p.MaxRetries {
+			return err
+		}
+		wait, ok := transientWait(err)
+		if !ok {
+			return err
+		}
+		if wait <= 0 {
+			wait = delay
+		}
+		slog.Warn("GitHub write failed, retrying", "attempt", attempt+1, "wait", wait, "error", err)
+		if sleepContext(ctx, capWait(wait)) != nil {
+			return err
+		}
+		delay *= 2
+	}
+}
+
+// transientWait reports whether err is worth retrying, with the wait GitHub
+// asked for, if any
+func transientWait(err error) (time.Duration, bool) {
+	var abuse *github.AbuseRateLimitError
+	if errors.As(err, &abuse) {
+		if abuse.RetryAfter != nil {
+			return *abuse.RetryAfter, true
+		}
+		return 0, true
+	}
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		if wait, ok := retryAfter(errResp.Response); ok {
+			return wait, true
+		}
+		return 0, errResp.Response.StatusCode >= http.StatusInternalServerError
+	}
+	return 0, false
+}
@@ -0,0 +1,88 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// newRetryTestClient returns a repo client for o/r talking to server
+func newRetryTestClient(t *testing.T, server *httptest.Server, retries int) *Client {
+	t.Helper()
+	ghClient, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Client{client: ghClient, owner: "o", repo: "r", retry: RetryPolicy{MaxRetries: retries, BaseDelay: time.Millisecond}}
+}
+
+func TestRetryPolicy_RetriesServerErrors(t *testing.T) {
+	var mu sync.Mutex
+	edits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		edits++
+		if edits == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`{"message": "Server Error"}`))
+			return
+		}
+		w.Write([]byte(`{"number": 1}`))
+	}))
+	defer server.Close()
+
+	body := "fixed"
+	if err := newRetryTestClient(t, server, 2).UpdateIssue(context.Background(), "", "", 1, nil, &body); err != nil {
+		t.Fatalf("UpdateIssue() error = %v", err)
+	}
+	if edits != 2 {
+		t.Errorf("expected one failed attempt and one retry, got %d requests", edits)
+	}
+}
+
+func TestRetryPolicy_DoesNotRetryClientErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message": "Validation Failed"}`))
+	}))
+	defer server.Close()
+
+	if err := newRetryTestClient(t, server, 3).AddLabel(context.Background(), "", "", 1, "bug"); err == nil {
+		t.Fatal("expected the validation error")
+	}
+	if requests != 1 {
+		t.Errorf("expected no retries for a 422, got %d requests", requests)
+	}
+}
+
+func TestAddComment_RetryDoesNotDuplicate(t *testing.T) {
+	var posts, lists int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			// The comment is stored, but the response is lost
+			posts++
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`{"message": "Server Error"}`))
+		case http.MethodGet:
+			lists++
+			w.Write([]byte(`[{"body": "older"}, {"body": "hello"}]`))
+		}
+	}))
+	defer server.Close()
+
+	if err := newRetryTestClient(t, server, 2).AddComment(context.Background(), "", "", 1, "hello"); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	if posts != 1 || lists != 1 {
+		t.Errorf("expected the retry to find the posted comment, got %d posts and %d lists", posts, lists)
+	}
+}
@@ -0,0 +1,119 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// tokenExpiryBuffer is how long before expiry an installation token is
+// replaced, so a request never goes out with a token about to expire
+const tokenExpiryBuffer = 5 * time.Minute
+
+// cachedInstallationToken is an installation token stored in the token cache
+type cachedInstallationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// tokenCacheMu serializes updates of token cache files, which several
+// installations' token sources may refresh at once
+var tokenCacheMu sync.Mutex
+
+// tokenCacheKey identifies the App installation a cached token belongs to
+func (a *AppAuth) tokenCacheKey(installationID int64) string {
+	return fmt.Sprintf("%d/%d", a.AppID, installationID)
+}
+
+// cachedToken returns an installation's token from the token cache when it
+// is still valid past the expiry buffer
+func (a *AppAuth) cachedToken(installationID int64, now time.Time) (string, time.Time, bool) {
+	if a.TokenCachePath == "" {
+		return "", time.Time{}, false
+	}
+	tokens, err := readTokenCache(a.TokenCachePath)
+	if err != nil {
+		slog.Warn("Ignoring unreadable GitHub App token cache", "path", a.TokenCachePath, "error", err)
+		return "", time.Time{}, false
+	}
+	cached, ok := tokens[a.tokenCacheKey(installationID)]
+	if !ok || cached.Token == "" || !cached.ExpiresAt.After(now.Add(tokenExpiryBuffer)) {
+		return "", time.Time{}, false
+	}
+	return cached.Token, cached.ExpiresAt, true
+}
+
+// cacheToken stores an installation's token in the token cache. Failures
+// are logged; the token still works for this run.
+func (a *AppAuth) cacheToken(installationID int64, token string, expires time.Time) {
+	if a.TokenCachePath == "" {
+		return
+	}
+	tokenCacheMu.Lock()
+	defer tokenCacheMu.Unlock()
+	tokens, err := readTokenCache(a.TokenCachePath)
+	if err != nil {
+		tokens = map[string]cachedInstallationToken{}
+	}
+	tokens[a.tokenCacheKey(installationID)] = cachedInstallationToken{Token: token, ExpiresAt: expires}
+	if err := writeTokenCache(a.TokenCachePath, tokens); err != nil {
+		slog.Warn("Failed to cache GitHub App token", "path", a.TokenCachePath, "error", err)
+	}
+}
+
+// readTokenCache reads the cached tokens by installation; a missing file is
+// an empty cache
+func readTokenCache(path string) (map[string]cachedInstallationToken, error) {
+	tokens := map[string]cachedInstallationToken{}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to decode token cache: %w", err)
+	}
+	return tokens, nil
+}
+
+// writeTokenCache replaces the token cache atomically. The file is only
+// readable by its owner since it holds credentials.
+func writeTokenCache(path string, tokens map[string]cachedInstallationToken) error {
+	content, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode token cache: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create token cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to restrict token cache permissions: %w", err)
+	}
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace token cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAppTokenSource_DiskCache(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests int32
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/456/access_tokens" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		n := atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "fresh-%d", "expires_at": %q}`, n, expiresAt.Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		cached       map[string]cachedInstallationToken
+		wantToken    string
+		wantRequests int32
+	}{
+		{
+			name:         "no cache file",
+			wantToken:    "fresh-1",
+			wantRequests: 1,
+		},
+		{
+			name:         "valid cached token",
+			cached:       map[string]cachedInstallationToken{"123/456": {Token: "cached", ExpiresAt: time.Now().Add(30 * time.Minute)}},
+			wantToken:    "cached",
+			wantRequests: 0,
+		},
+		{
+			name:         "cached token about to expire",
+			cached:       map[string]cachedInstallationToken{"123/456": {Token: "cached", ExpiresAt: time.Now().Add(2 * time.Minute)}},
+			wantToken:    "fresh-1",
+			wantRequests: 1,
+		},
+		{
+			name:         "token of another installation",
+			cached:       map[string]cachedInstallationToken{"123/789": {Token: "other", ExpiresAt: time.Now().Add(30 * time.Minute)}},
+			wantToken:    "fresh-1",
+			wantRequests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&requests, 0)
+			path := filepath.Join(t.TempDir(), "cache", "tokens.json")
+			if tt.cached != nil {
+				if err := writeTokenCache(path, tt.cached); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			appAuth := &AppAuth{AppID: 123, InstallationID: 456, PrivateKey: key, BaseURL: server.URL, TokenCachePath: path}
+			token, err := appAuth.CreateOAuth2TokenSource(context.Background()).Token()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token.AccessToken != tt.wantToken {
+				t.Errorf("token = %q, want %q", token.AccessToken, tt.wantToken)
+			}
+			if got := atomic.LoadInt32(&requests); got != tt.wantRequests {
+				t.Errorf("token requests = %d, want %d", got, tt.wantRequests)
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if perm := info.Mode().Perm(); perm != 0o600 {
+				t.Errorf("cache file permissions = %o, want 600", perm)
+			}
+			tokens, err := readTokenCache(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cached := tokens["123/456"]; cached.Token != tt.wantToken {
+				t.Errorf("cached token = %q, want %q", cached.Token, tt.wantToken)
+			}
+			if tt.cached["123/789"].Token != "" && tokens["123/789"].Token != "other" {
+				t.Error("expected the other installation's token to stay cached")
+			}
+		})
+	}
+}
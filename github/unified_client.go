@@ -2,18 +2,36 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"strings"
 )
 
 // UnifiedClient provides a unified interface that works with both repo and project modes
 type UnifiedClient interface {
 	ListIssues(ctx context.Context, state string) ([]*Issue, error)
+	ListIssuesPartial(ctx context.Context, state string) ([]*Issue, error)
 	GetIssue(ctx context.Context, owner, repo string, number int) (*Issue, error)
+	GetIssues(ctx context.Context, refs []IssueRef) (map[IssueRef]*Issue, error)
 	UpdateIssue(ctx context.Context, owner, repo string, number int, title, body *string) error
 	AddComment(ctx context.Context, owner, repo string, number int, comment string) error
 	CreateIssue(ctx context.Context, owner, repo, title, body string, labels []string) (*Issue, error)
 	AddLabel(ctx context.Context, owner, repo string, number int, label string) error
+	RemoveLabel(ctx context.Context, owner, repo string, number int, label string) error
+	AssignIssue(ctx context.Context, owner, repo string, number int, assignees []string) error
+	SetAssignee(ctx context.Context, owner, repo string, number int, login string) error
+	CloseIssue(ctx context.Context, owner, repo string, number int) error
+	GetIssueComments(ctx context.Context, owner, repo string, number int) ([]Comment, error)
+	ListIssueEvents(ctx context.Context, owner, repo string, number int) ([]IssueEvent, error)
+	GetLinkedPullRequests(ctx context.Context, owner, repo string, number int) ([]*PullRequest, error)
+	ListPullRequests(ctx context.Context, state string) ([]*PullRequest, error)
+	GetPullRequestDiff(ctx context.Context, owner, repo string, number int) (string, error)
+	ListDeployments(ctx context.Context, owner, repo string) ([]*Deployment, error)
+	GetDeploymentStatuses(ctx context.Context, owner, repo string, id int64) ([]*DeploymentStatus, error)
+	GetRepository(ctx context.Context, owner, repo string) (*RepoInfo, error)
+	WhoAmI(ctx context.Context) (login string, isApp bool, err error)
 	GetMode() string // Returns "repo" or "project"
 }
 
@@ -23,6 +41,7 @@ type UnifiedClientWrapper struct {
 	projectClient *ProjectClient
 	mode          string
 	repos         []Repository
+	onlyRepos     bool // Drop project board issues from other repositories
 }
 
 // NewUnifiedClient creates a unified client based on configuration
@@ -30,39 +49,117 @@ func NewUnifiedClient(token, owner, repo, projectID string, repos []Repository,
 	return NewUnifiedClientWithAuth(token, nil, owner, repo, projectID, repos, baseURL)
 }
 
+// ClientOptions configures optional client behavior
+type ClientOptions struct {
+	// AddCreatedToProject adds issues created in project mode to the project board
+	AddCreatedToProject bool
+	// DryRun logs writes (updates, comments, created issues, labels) instead
+	// of sending them to GitHub
+	DryRun bool
+	// DryRunOutput receives the dry-run log; defaults to stdout
+	DryRunOutput io.Writer
+	// ThrottleRateLimits waits for the rate limit window to reset when few
+	// requests remain, and retries requests rejected with Retry-After
+	ThrottleRateLimits bool
+	// RateLimitMinRemaining is the remaining request count that triggers a
+	// wait (defaults to 10)
+	RateLimitMinRemaining int
+	// ManagedLabelPrefixes limits label additions and removals to labels
+	// with one of these prefixes; empty allows every label
+	ManagedLabelPrefixes []string
+	// WriteRetries is how often writes failing with a server error or a
+	// secondary rate limit are retried, with exponential backoff
+	WriteRetries int
+	// UploadURL is the upload API URL of a GitHub Enterprise server;
+	// defaults to the base URL
+	UploadURL string
+	// OnlyRepos limits the issues listed in project mode to the given
+	// repositories; by default every issue on the project board is listed
+	OnlyRepos bool
+}
+
 // NewUnifiedClientWithAuth creates a unified client with either token or GitHub App authentication
 func NewUnifiedClientWithAuth(token string, appAuth *AppAuth, owner, repo, projectID string, repos []Repository, baseURL string) (UnifiedClient, error) {
+	return NewUnifiedClientWithOptions(token, appAuth, owner, repo, projectID, repos, baseURL, ClientOptions{})
+}
+
+// NewUnifiedClientWithOptions creates a unified client with optional behavior configured
+func NewUnifiedClientWithOptions(token string, appAuth *AppAuth, owner, repo, projectID string, repos []Repository, baseURL string, options ClientOptions) (UnifiedClient, error) {
+	var client UnifiedClient
 	if projectID != "" {
 		// Project mode
-		projectClient, err := NewProjectClientWithAuth(token, appAuth, owner, projectID, baseURL)
+		projectClient, err := NewProjectClientWithAuth(token, appAuth, owner, projectID, baseURL, options.UploadURL)
 		if err != nil {
 			return nil, err
 		}
+		projectClient.addCreatedToProject = options.AddCreatedToProject
+		projectClient.retry = RetryPolicy{MaxRetries: options.WriteRetries}
+		if options.ThrottleRateLimits {
+			projectClient.client = withRateLimitThrottle(projectClient.client, options.RateLimitMinRemaining)
+		}
 
-		return &UnifiedClientWrapper{
+		client = &UnifiedClientWrapper{
 			projectClient: projectClient,
 			mode:          "project",
 			repos:         repos,
-		}, nil
-	}
+			onlyRepos:     options.OnlyRepos,
+		}
+	} else {
+		// Repo mode
+		repoClient, err := NewClientWithAuth(token, appAuth, owner, repo, baseURL, options.UploadURL)
+		if err != nil {
+			return nil, err
+		}
+		repoClient.retry = RetryPolicy{MaxRetries: options.WriteRetries}
+		if options.ThrottleRateLimits {
+			repoClient.client = withRateLimitThrottle(repoClient.client, options.RateLimitMinRemaining)
+		}
 
-	// Repo mode
-	repoClient, err := NewClientWithAuth(token, appAuth, owner, repo, baseURL)
-	if err != nil {
-		return nil, err
+		client = &UnifiedClientWrapper{
+			repoClient: repoClient,
+			mode:       "repo",
+		}
 	}
 
-	return &UnifiedClientWrapper{
-		repoClient: repoClient,
-		mode:       "repo",
-	}, nil
+	if options.DryRun {
+		if options.DryRunOutput != nil {
+			client = NewDryRunClientWithOutput(client, options.DryRunOutput)
+		} else {
+			client = NewDryRunClient(client)
+		}
+	}
+	// Outermost, so dry runs report rejected labels too
+	client = NewLabelGuardClient(client, options.ManagedLabelPrefixes)
+	return client, nil
 }
 
 func (uc *UnifiedClientWrapper) GetMode() string {
 	return uc.mode
 }
 
+func (uc *UnifiedClientWrapper) WhoAmI(ctx context.Context) (string, bool, error) {
+	if uc.mode == "project" {
+		return uc.projectClient.WhoAmI(ctx)
+	}
+	return uc.repoClient.WhoAmI(ctx)
+}
+
+// ListIssues lists issues. In project mode, repositories that can't be listed
+// are skipped with a warning; it only fails when none could be listed.
 func (uc *UnifiedClientWrapper) ListIssues(ctx context.Context, state string) ([]*Issue, error) {
+	issues, err := uc.ListIssuesPartial(ctx, state)
+	var partial *PartialListError
+	if errors.As(err, &partial) && !partial.AllFailed() {
+		slog.Warn("skipped repositories that could not be listed", "error", err)
+		return issues, nil
+	}
+	return issues, err
+}
+
+// ListIssuesPartial is ListIssues, except that in project mode the issues of
+// the repositories that could be listed are returned together with a
+// *PartialListError naming the ones that couldn't
+func (uc *UnifiedClientWrapper) ListIssuesPartial(ctx context.Context, state string) ([]*Issue, error) {
 	if uc.mode == "project" {
 		// Convert RepositoryConfig to Repository
 		repos := make([]Repository, len(uc.repos))
@@ -71,17 +168,20 @@ func (uc *UnifiedClientWrapper) ListIssues(ctx context.Context, state string) ([
 		}
 
 		projectIssues, err := uc.projectClient.ListProjectIssues(ctx, state, repos)
-		if err != nil {
+		if err != nil && len(projectIssues) == 0 {
 			return nil, err
 		}
 
 		// Convert ProjectIssue to Issue
-		issues := make([]*Issue, len(projectIssues))
-		for i, pi := range projectIssues {
-			issues[i] = &pi.Issue
+		issues := make([]*Issue, 0, len(projectIssues))
+		for _, pi := range projectIssues {
+			if uc.onlyRepos && !containsRepo(repos, pi.RepositoryOwner, pi.RepositoryName) {
+				continue
+			}
+			issues = append(issues, &pi.Issue)
 		}
 
-		return issues, nil
+		return issues, err
 	}
 
 	return uc.repoClient.ListIssues(ctx, state)
@@ -170,7 +270,7 @@ func (uc *UnifiedClientWrapper) AddLabel(ctx context.Context, owner, repo string
 				return fmt.Errorf("failed to find issue: %w", err)
 			}
 			// Extract owner/repo from issue URL
-			owner, repo = extractRepoFromURL(issue.URL)
+			owner, repo = ParseRepoFromURL(issue.URL)
 			if owner == "" || repo == "" {
 				return fmt.Errorf("could not determine repository for issue #%d", number)
 			}
@@ -182,12 +282,196 @@ func (uc *UnifiedClientWrapper) AddLabel(ctx context.Context, owner, repo string
 	return uc.repoClient.AddLabel(ctx, "", "", number, label)
 }
 
-// extractRepoFromURL extracts owner and repo from a GitHub issue URL
-func extractRepoFromURL(url string) (string, string) {
-	// URL format: https://github.com/owner/repo/issues/123
-	parts := strings.Split(url, "/")
-	if len(parts) >= 5 {
-		return parts[3], parts[4] // owner, repo
+func (uc *UnifiedClientWrapper) RemoveLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	if uc.mode == "project" {
+		if owner == "" || repo == "" {
+			issue, err := uc.GetIssue(ctx, "", "", number)
+			if err != nil {
+				return fmt.Errorf("failed to find issue: %w", err)
+			}
+			owner, repo = ParseRepoFromURL(issue.URL)
+			if owner == "" || repo == "" {
+				return fmt.Errorf("could not determine repository for issue #%d", number)
+			}
+		}
+		return uc.projectClient.RemoveLabel(ctx, owner, repo, number, label)
+	}
+
+	// In repo mode, owner and repo are ignored
+	return uc.repoClient.RemoveLabel(ctx, "", "", number, label)
+}
+
+func (uc *UnifiedClientWrapper) AssignIssue(ctx context.Context, owner, repo string, number int, assignees []string) error {
+	if uc.mode == "project" {
+		owner, repo, err := uc.resolveRepo(ctx, owner, repo, number)
+		if err != nil {
+			return err
+		}
+		return uc.projectClient.AssignIssue(ctx, owner, repo, number, assignees)
+	}
+
+	// In repo mode, owner and repo are ignored
+	return uc.repoClient.AssignIssue(ctx, "", "", number, assignees)
+}
+
+func (uc *UnifiedClientWrapper) SetAssignee(ctx context.Context, owner, repo string, number int, login string) error {
+	if uc.mode == "project" {
+		owner, repo, err := uc.resolveRepo(ctx, owner, repo, number)
+		if err != nil {
+			return err
+		}
+		return uc.projectClient.SetAssignee(ctx, owner, repo, number, login)
+	}
+
+	// In repo mode, owner and repo are ignored
+	return uc.repoClient.SetAssignee(ctx, "", "", number, login)
+}
+
+func (uc *UnifiedClientWrapper) CloseIssue(ctx context.Context, owner, repo string, number int) error {
+	if uc.mode == "project" {
+		owner, repo, err := uc.resolveRepo(ctx, owner, repo, number)
+		if err != nil {
+			return err
+		}
+		return uc.projectClient.CloseIssue(ctx, owner, repo, number)
+	}
+
+	// In repo mode, owner and repo are ignored
+	return uc.repoClient.CloseIssue(ctx, "", "", number)
+}
+
+func (uc *UnifiedClientWrapper) GetIssueComments(ctx context.Context, owner, repo string, number int) ([]Comment, error) {
+	if uc.mode == "project" {
+		owner, repo, err := uc.resolveRepo(ctx, owner, repo, number)
+		if err != nil {
+			return nil, err
+		}
+		return uc.projectClient.GetIssueComments(ctx, owner, repo, number)
+	}
+
+	// In repo mode, owner and repo are ignored
+	return uc.repoClient.GetIssueComments(ctx, "", "", number)
+}
+
+func (uc *UnifiedClientWrapper) ListIssueEvents(ctx context.Context, owner, repo string, number int) ([]IssueEvent, error) {
+	if uc.mode == "project" {
+		owner, repo, err := uc.resolveRepo(ctx, owner, repo, number)
+		if err != nil {
+			return nil, err
+		}
+		return uc.projectClient.ListIssueEvents(ctx, owner, repo, number)
+	}
+
+	// In repo mode, owner and repo are ignored
+	return uc.repoClient.ListIssueEvents(ctx, "", "", number)
+}
+
+// resolveRepo finds the repository of a project issue when owner/repo aren't given
+func (uc *UnifiedClientWrapper) resolveRepo(ctx context.Context, owner, repo string, number int) (string, string, error) {
+	if owner != "" && repo != "" {
+		return owner, repo, nil
+	}
+	issue, err := uc.GetIssue(ctx, "", "", number)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to find issue: %w", err)
+	}
+	owner, repo = ParseRepoFromURL(issue.URL)
+	if owner == "" || repo == "" {
+		return "", "", fmt.Errorf("could not determine repository for issue #%d", number)
+	}
+	return owner, repo, nil
+}
+
+func (uc *UnifiedClientWrapper) GetLinkedPullRequests(ctx context.Context, owner, repo string, number int) ([]*PullRequest, error) {
+	if uc.mode == "project" {
+		if owner == "" || repo == "" {
+			issue, err := uc.GetIssue(ctx, "", "", number)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find issue: %w", err)
+			}
+			owner, repo = ParseRepoFromURL(issue.URL)
+			if owner == "" || repo == "" {
+				return nil, fmt.Errorf("could not determine repository for issue #%d", number)
+			}
+		}
+		return uc.projectClient.GetLinkedPullRequests(ctx, owner, repo, number)
+	}
+
+	// In repo mode, owner and repo are ignored
+	return uc.repoClient.GetLinkedPullRequests(ctx, "", "", number)
+}
+
+func (uc *UnifiedClientWrapper) ListPullRequests(ctx context.Context, state string) ([]*PullRequest, error) {
+	if uc.mode == "project" {
+		return uc.projectClient.ListPullRequests(ctx, state, uc.repos)
+	}
+	return uc.repoClient.ListPullRequests(ctx, state)
+}
+
+func (uc *UnifiedClientWrapper) GetPullRequestDiff(ctx context.Context, owner, repo string, number int) (string, error) {
+	if uc.mode == "project" {
+		owner, repo, err := uc.defaultRepo(owner, repo)
+		if err != nil {
+			return "", err
+		}
+		return uc.projectClient.GetPullRequestDiff(ctx, owner, repo, number)
+	}
+
+	// In repo mode, owner and repo are ignored
+	return uc.repoClient.GetPullRequestDiff(ctx, "", "", number)
+}
+
+func (uc *UnifiedClientWrapper) ListDeployments(ctx context.Context, owner, repo string) ([]*Deployment, error) {
+	if uc.mode == "project" {
+		owner, repo, err := uc.defaultRepo(owner, repo)
+		if err != nil {
+			return nil, err
+		}
+		return uc.projectClient.ListDeployments(ctx, owner, repo)
+	}
+	return uc.repoClient.ListDeployments(ctx, owner, repo)
+}
+
+func (uc *UnifiedClientWrapper) GetDeploymentStatuses(ctx context.Context, owner, repo string, id int64) ([]*DeploymentStatus, error) {
+	if uc.mode == "project" {
+		owner, repo, err := uc.defaultRepo(owner, repo)
+		if err != nil {
+			return nil, err
+		}
+		return uc.projectClient.GetDeploymentStatuses(ctx, owner, repo, id)
+	}
+	return uc.repoClient.GetDeploymentStatuses(ctx, owner, repo, id)
+}
+
+func (uc *UnifiedClientWrapper) GetRepository(ctx context.Context, owner, repo string) (*RepoInfo, error) {
+	if uc.mode == "project" {
+		owner, repo, err := uc.defaultRepo(owner, repo)
+		if err != nil {
+			return nil, err
+		}
+		return uc.projectClient.GetRepository(ctx, owner, repo)
+	}
+	return uc.repoClient.GetRepository(ctx, owner, repo)
+}
+
+// defaultRepo falls back to the first configured repository in project mode
+// when owner/repo aren't given
+func (uc *UnifiedClientWrapper) defaultRepo(owner, repo string) (string, string, error) {
+	if owner != "" && repo != "" {
+		return owner, repo, nil
+	}
+	if len(uc.repos) == 0 {
+		return "", "", fmt.Errorf("owner and repo are required in project mode")
+	}
+	return uc.repos[0].Owner, uc.repos[0].Name, nil
+}
+
+// containsRepo reports whether owner/name is one of repos, ignoring case
+func containsRepo(repos []Repository, owner, name string) bool {
+	for _, repo := range repos {
+		if strings.EqualFold(repo.Owner, owner) && strings.EqualFold(repo.Name, name) {
+			return true
+		}
 	}
-	return "", ""
+	return false
 }
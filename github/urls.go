@@ -0,0 +1,53 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ParseRepository parses an "owner/name" repository name
+func ParseRepository(name string) (Repository, error) {
+	owner, repo, ok := strings.Cut(strings.TrimSpace(name), "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return Repository{}, fmt.Errorf("%q is not an owner/name repository", name)
+	}
+	return Repository{Owner: owner, Name: repo}, nil
+}
+
+// ParseRepoFromURL returns the owner and repository of a GitHub or GitHub
+// Enterprise URL, such as an issue, pull request or repository page
+// (https://ghe.example.com/owner/repo/issues/5) or an API URL
+// (https://api.github.com/repos/owner/repo/issues/5). Both are empty when
+// the URL doesn't name a repository.
+func ParseRepoFromURL(rawURL string) (owner, repo string) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return "", ""
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	// API URLs: api.github.com/repos/... or <ghe>/api/v3/repos/...
+	api := strings.HasPrefix(u.Host, "api.")
+	if len(segments) >= 2 && segments[0] == "api" && segments[1] == "v3" {
+		segments = segments[2:]
+		api = true
+	}
+	if api {
+		if len(segments) == 0 || segments[0] != "repos" {
+			return "", ""
+		}
+		segments = segments[1:]
+	}
+
+	if len(segments) < 2 {
+		return "", ""
+	}
+	return segments[0], strings.TrimSuffix(segments[1], ".git")
+}
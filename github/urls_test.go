@@ -0,0 +1,48 @@
+package github
+
+import "testing"
+
+func TestParseRepoFromURL(t *testing.T) {
+	tests := []struct {
+		url       string
+		wantOwner string
+		wantRepo  string
+	}{
+		{"https://github.com/acme/api/issues/5", "acme", "api"},
+		{"https://github.com/acme/api/pull/7/files", "acme", "api"},
+		{"https://github.com/acme/api", "acme", "api"},
+		{"https://github.com/acme/api/", "acme", "api"},
+		{"https://github.com/acme/api.git", "acme", "api"},
+		{"https://api.github.com/repos/acme/api/issues/5", "acme", "api"},
+		{"https://ghe.example.com/acme/api/issues/5", "acme", "api"},
+		{"https://ghe.example.com/acme/api/issues/5/", "acme", "api"},
+		{"https://ghe.example.com/api/v3/repos/acme/api/issues/5", "acme", "api"},
+		{"http://localhost:8080/acme/api/issues/5", "acme", "api"},
+		{"https://github.com//acme//api/issues/5", "acme", "api"},
+		{"", "", ""},
+		{"not a url", "", ""},
+		{"/acme/api/issues/5", "", ""},
+		{"https://github.com", "", ""},
+		{"https://github.com/acme", "", ""},
+		{"https://api.github.com/users/acme", "", ""},
+		{"://github.com/acme/api", "", ""},
+	}
+	for _, tt := range tests {
+		owner, repo := ParseRepoFromURL(tt.url)
+		if owner != tt.wantOwner || repo != tt.wantRepo {
+			t.Errorf("ParseRepoFromURL(%q) = %q, %q; want %q, %q", tt.url, owner, repo, tt.wantOwner, tt.wantRepo)
+		}
+	}
+}
+
+func TestParseRepository(t *testing.T) {
+	repo, err := ParseRepository(" acme/api ")
+	if err != nil || repo != (Repository{Owner: "acme", Name: "api"}) {
+		t.Errorf("ParseRepository() = %+v, %v", repo, err)
+	}
+	for _, name := range []string{"", "acme", "acme/", "/api", "acme/api/issues"} {
+		if _, err := ParseRepository(name); err == nil {
+			t.Errorf("ParseRepository(%q) expected an error", name)
+		}
+	}
+}
@@ -16,11 +16,14 @@ type Guidelines struct {
 }
 
 type FormatRules struct {
-	RequiredSections     []string
+	RequiredSections     []Section // A heading named after each, or one of its aliases, is required
 	MinDescriptionLength int
 	RequireLabels        bool
 	LabelPrefix          string
 	LabelRequirements    []LabelRequirement
+	TitlePattern         string // Regexp issue titles must match
+	DefaultPriorityLabel string // Label applied to issues missing a priority label
+	BodyTemplate         string // Markdown skeleton rewritten descriptions must follow
 }
 
 type LabelRequirement struct {
@@ -58,7 +61,7 @@ func Parse(content string) (*Guidelines, error) {
 	g := &Guidelines{
 		RawContent: content,
 		FormatRules: FormatRules{
-			RequiredSections:     []string{},
+			RequiredSections:     []Section{},
 			MinDescriptionLength: 50,
 			RequireLabels:        false,
 			LabelPrefix:          "priority:",
@@ -69,7 +72,15 @@ func Parse(content string) (*Guidelines, error) {
 	
 	// Extract format rules
 	g.extractFormatRules(content)
+	if g.FormatRules.TitlePattern != "" {
+		if _, err := regexp.Compile(g.FormatRules.TitlePattern); err != nil {
+			return nil, fmt.Errorf("invalid title pattern %q in guidelines: %w", g.FormatRules.TitlePattern, err)
+		}
+	}
 	
+	// Extract body template
+	g.FormatRules.BodyTemplate = extractBodyTemplate(content)
+
 	// Extract instructions
 	g.extractInstructions(content)
 	
@@ -89,9 +100,18 @@ func (g *Guidelines) extractFormatRules(content string) {
 	// Extract required sections
 	requiredSections := extractListItems(formatSection, "Required Sections", "Required sections", "Sections")
 	if len(requiredSections) > 0 {
-		g.FormatRules.RequiredSections = requiredSections
+		g.FormatRules.RequiredSections = make([]Section, len(requiredSections))
+		for i, item := range requiredSections {
+			g.FormatRules.RequiredSections[i] = parseSection(item)
+		}
 	}
 	
+	// Extract title pattern
+	g.FormatRules.TitlePattern = extractTitlePattern(formatSection)
+
+	// Extract default priority label
+	g.FormatRules.DefaultPriorityLabel = extractRuleValue(formatSection, defaultPriorityLabelLine)
+	
 	// Extract minimum description length
 	minLength := extractIntValue(formatSection, "Minimum.*length", "Min.*length", "Description.*length")
 	if minLength > 0 {
@@ -158,6 +178,44 @@ func (g *Guidelines) extractExamples(content string) {
 
 // Helper functions
 
+var bodyTemplateHeader = regexp.MustCompile(`(?i)^##+\s*(?:body|issue) template\s*$`)
+
+// extractBodyTemplate returns the first fenced code block under a
+// "## Body Template" heading. The block is read verbatim since the skeleton
+// itself is made of headings that would end a regular section.
+func extractBodyTemplate(content string) string {
+	lines := strings.Split(content, "\n")
+	start := -1
+	for i, line := range lines {
+		if bodyTemplateHeader.MatchString(strings.TrimSpace(line)) {
+			start = i + 1
+			break
+		}
+	}
+	if start == -1 {
+		return ""
+	}
+
+	var block []string
+	inBlock := false
+	for _, line := range lines[start:] {
+		trimmed := strings.TrimSpace(line)
+		if !inBlock {
+			if strings.HasPrefix(trimmed, "```") {
+				inBlock = true
+			} else if strings.HasPrefix(trimmed, "#") {
+				return "" // Next section started without a template block
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") {
+			return strings.TrimSpace(strings.Join(block, "\n"))
+		}
+		block = append(block, line)
+	}
+	return ""
+}
+
 func extractSection(content string, titles ...string) string {
 	lines := strings.Split(content, "\n")
 	
@@ -235,6 +293,34 @@ func extractIntValue(section string, patterns ...string) int {
 	return 0
 }
 
+// titlePatternLine matches a "Title pattern: `...`" rule line
+var titlePatternLine = regexp.MustCompile(`(?im)^[\s*-]*\**title\s+pattern\**\s*:\**\s*(.+)$`)
+
+// defaultPriorityLabelLine matches a "Default priority label: `...`" rule line
+var defaultPriorityLabelLine = regexp.MustCompile(`(?im)^[\s*-]*\**default\s+priority\s+label\**\s*:\**\s*(.+)$`)
+
+// extractTitlePattern returns the title regexp from a format section. The
+// pattern may be wrapped in backticks or quotes.
+func extractTitlePattern(section string) string {
+	return extractRuleValue(section, titlePatternLine)
+}
+
+// extractRuleValue returns the value of the first rule line matching line,
+// without surrounding backticks or quotes
+func extractRuleValue(section string, line *regexp.Regexp) string {
+	matches := line.FindStringSubmatch(section)
+	if len(matches) < 2 {
+		return ""
+	}
+	value := strings.TrimSpace(matches[1])
+	for _, quote := range []string{"`", `"`, "'"} {
+		if len(value) >= 2 && strings.HasPrefix(value, quote) && strings.HasSuffix(value, quote) {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
+
 func extractStringValue(section string, patterns ...string) string {
 	for _, pattern := range patterns {
 		re := regexp.MustCompile(fmt.Sprintf(`(?i)%s[:\s]*["']?([^"'\n]+)["']?`, pattern))
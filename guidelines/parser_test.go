@@ -0,0 +1,88 @@
+package guidelines
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse_TitlePattern(t *testing.T) {
+	g, err := Parse("# Guidelines\n\n## Format Rules\n\n- Title pattern: `^\\[(infra|api)\\] `\n- Minimum description length: 80\n")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if g.FormatRules.TitlePattern != `^\[(infra|api)\] ` {
+		t.Errorf("unexpected title pattern %q", g.FormatRules.TitlePattern)
+	}
+
+	g, err = Parse("# Guidelines\n\n## Format Rules\n\n- Minimum description length: 80\n")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if g.FormatRules.TitlePattern != "" {
+		t.Errorf("expected no title pattern, got %q", g.FormatRules.TitlePattern)
+	}
+
+	_, err = Parse("# Guidelines\n\n## Format Rules\n\n- Title pattern: `^[api`\n")
+	if err == nil || !strings.Contains(err.Error(), "invalid title pattern") {
+		t.Errorf("expected an invalid title pattern error, got %v", err)
+	}
+}
+
+func TestParse_SectionAliases(t *testing.T) {
+	g, err := Parse("# Guidelines\n\n## Format Rules\n\nRequired Sections:\n- Description\n- Acceptance Criteria (aliases: AC, Definition of Done)\n")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []Section{
+		{Name: "Description"},
+		{Name: "Acceptance Criteria", Aliases: []string{"AC", "Definition of Done"}},
+	}
+	if !reflect.DeepEqual(g.FormatRules.RequiredSections, want) {
+		t.Errorf("RequiredSections = %+v, want %+v", g.FormatRules.RequiredSections, want)
+	}
+
+	overlay := &Guidelines{FormatRules: FormatRules{RequiredSections: []Section{{Name: "acceptance criteria", Aliases: []string{"DoD"}}}}}
+	merged := Merge(g, overlay).FormatRules.RequiredSections
+	if len(merged) != 2 || !reflect.DeepEqual(merged[1].Aliases, []string{"AC", "Definition of Done", "DoD"}) {
+		t.Errorf("expected Merge to combine aliases, got %+v", merged)
+	}
+}
+
+func TestParse_DefaultPriorityLabel(t *testing.T) {
+	g, err := Parse("# Guidelines\n\n## Format Rules\n\n- **Default priority label**: `priority:triage`\n")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if g.FormatRules.DefaultPriorityLabel != "priority:triage" {
+		t.Errorf("unexpected default priority label %q", g.FormatRules.DefaultPriorityLabel)
+	}
+
+	merged := Merge(g, &Guidelines{})
+	if merged.FormatRules.DefaultPriorityLabel != "priority:triage" {
+		t.Errorf("Merge should keep the base default priority label, got %q", merged.FormatRules.DefaultPriorityLabel)
+	}
+}
+
+func TestParse_BodyTemplate(t *testing.T) {
+	content := "# Guidelines\n\n## Body Template\n\n```markdown\n## Description\n\n## Acceptance Criteria\n- [ ] \n\n## Notes\n```\n\n## Format Rules\n\n- Minimum description length: 80\n"
+	g, err := Parse(content)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := "## Description\n\n## Acceptance Criteria\n- [ ] \n\n## Notes"
+	if g.FormatRules.BodyTemplate != want {
+		t.Errorf("BodyTemplate = %q, want %q", g.FormatRules.BodyTemplate, want)
+	}
+	if g.FormatRules.MinDescriptionLength != 80 {
+		t.Errorf("template headings should not hide the format rules, got min length %d", g.FormatRules.MinDescriptionLength)
+	}
+
+	g, err = Parse("# Guidelines\n\n## Body Template\n\n## Format Rules\n\n- Minimum description length: 80\n")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if g.FormatRules.BodyTemplate != "" {
+		t.Errorf("expected no body template without a code block, got %q", g.FormatRules.BodyTemplate)
+	}
+}
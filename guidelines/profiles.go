@@ -0,0 +1,145 @@
+package guidelines
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Profile is a named guidelines file applied to issues carrying a label
+type Profile struct {
+	Name       string // Profile name (defaults to the label)
+	Label      string // Issue label that selects this profile
+	Guidelines *Guidelines
+}
+
+// Resolver picks the effective guidelines for an issue based on its labels
+type Resolver struct {
+	Base     *Guidelines
+	Profiles []Profile
+}
+
+// LoadProfiles loads one profile per label from a label -> file path mapping
+func LoadProfiles(paths map[string]string) ([]Profile, error) {
+	var profiles []Profile
+	var errs []string
+
+	labels := make([]string, 0, len(paths))
+	for label := range paths {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	for _, label := range labels {
+		path := paths[label]
+		g, err := LoadFromFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s (%s): %v", label, path, err))
+			continue
+		}
+		profiles = append(profiles, Profile{Name: label, Label: label, Guidelines: g})
+	}
+
+	if len(errs) > 0 {
+		return profiles, fmt.Errorf("failed to load guidelines profiles: %s", strings.Join(errs, "; "))
+	}
+	return profiles, nil
+}
+
+// Resolve returns the base guidelines merged with every profile whose label
+// is present on the issue, along with the names of the profiles applied.
+// It returns the base guidelines unchanged when no profile matches.
+func (r *Resolver) Resolve(labels []string) (*Guidelines, []string) {
+	labelSet := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		labelSet[strings.ToLower(label)] = true
+	}
+
+	effective := r.Base
+	var applied []string
+	for _, profile := range r.Profiles {
+		if !labelSet[strings.ToLower(profile.Label)] || profile.Guidelines == nil {
+			continue
+		}
+		effective = Merge(effective, profile.Guidelines)
+		applied = append(applied, profile.Name)
+	}
+
+	return effective, applied
+}
+
+// Merge combines base guidelines with a profile overlay.
+// Required sections and label requirements are unioned, the stricter minimum
+// length wins, and instructions/examples from both are kept.
+func Merge(base, overlay *Guidelines) *Guidelines {
+	if base == nil {
+		return overlay
+	}
+	if overlay == nil {
+		return base
+	}
+
+	merged := &Guidelines{
+		RawContent:   joinNonEmpty(base.RawContent, overlay.RawContent),
+		Instructions: joinNonEmpty(base.Instructions, overlay.Instructions),
+		FormatRules: FormatRules{
+			RequiredSections:     UnionSections(base.FormatRules.RequiredSections, overlay.FormatRules.RequiredSections),
+			MinDescriptionLength: base.FormatRules.MinDescriptionLength,
+			RequireLabels:        base.FormatRules.RequireLabels || overlay.FormatRules.RequireLabels,
+			LabelPrefix:          base.FormatRules.LabelPrefix,
+		},
+	}
+
+	if overlay.FormatRules.MinDescriptionLength > merged.FormatRules.MinDescriptionLength {
+		merged.FormatRules.MinDescriptionLength = overlay.FormatRules.MinDescriptionLength
+	}
+	if overlay.FormatRules.LabelPrefix != "" {
+		merged.FormatRules.LabelPrefix = overlay.FormatRules.LabelPrefix
+	}
+	merged.FormatRules.TitlePattern = base.FormatRules.TitlePattern
+	if overlay.FormatRules.TitlePattern != "" {
+		merged.FormatRules.TitlePattern = overlay.FormatRules.TitlePattern
+	}
+	merged.FormatRules.DefaultPriorityLabel = base.FormatRules.DefaultPriorityLabel
+	if overlay.FormatRules.DefaultPriorityLabel != "" {
+		merged.FormatRules.DefaultPriorityLabel = overlay.FormatRules.DefaultPriorityLabel
+	}
+	merged.FormatRules.BodyTemplate = base.FormatRules.BodyTemplate
+	if overlay.FormatRules.BodyTemplate != "" {
+		merged.FormatRules.BodyTemplate = overlay.FormatRules.BodyTemplate
+	}
+
+	merged.FormatRules.LabelRequirements = append(merged.FormatRules.LabelRequirements, base.FormatRules.LabelRequirements...)
+	merged.FormatRules.LabelRequirements = append(merged.FormatRules.LabelRequirements, overlay.FormatRules.LabelRequirements...)
+	merged.Examples = append(merged.Examples, base.Examples...)
+	merged.Examples = append(merged.Examples, overlay.Examples...)
+
+	return merged
+}
+
+func unionStrings(a, b []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, list := range [][]string{a, b} {
+		for _, s := range list {
+			key := strings.ToLower(s)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func joinNonEmpty(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	default:
+		return a + "\n\n" + b
+	}
+}
@@ -0,0 +1,56 @@
+package guidelines
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	base := &Guidelines{
+		Instructions: "Base instructions",
+		FormatRules: FormatRules{
+			RequiredSections:     Sections("Description", "Acceptance Criteria"),
+			MinDescriptionLength: 50,
+			LabelPrefix:          "priority:",
+		},
+	}
+	security := &Guidelines{
+		Instructions: "Security instructions",
+		FormatRules: FormatRules{
+			RequiredSections:     Sections("Description", "Threat Model"),
+			MinDescriptionLength: 200,
+			RequireLabels:        true,
+		},
+	}
+	resolver := &Resolver{
+		Base:     base,
+		Profiles: []Profile{{Name: "security", Label: "security", Guidelines: security}},
+	}
+
+	t.Run("matching label merges profile", func(t *testing.T) {
+		got, applied := resolver.Resolve([]string{"bug", "Security"})
+		if !reflect.DeepEqual(applied, []string{"security"}) {
+			t.Fatalf("applied = %v, want [security]", applied)
+		}
+		wantSections := Sections("Description", "Acceptance Criteria", "Threat Model")
+		if !reflect.DeepEqual(got.FormatRules.RequiredSections, wantSections) {
+			t.Errorf("RequiredSections = %v, want %v", got.FormatRules.RequiredSections, wantSections)
+		}
+		if got.FormatRules.MinDescriptionLength != 200 {
+			t.Errorf("MinDescriptionLength = %d, want 200", got.FormatRules.MinDescriptionLength)
+		}
+		if !got.FormatRules.RequireLabels || got.FormatRules.LabelPrefix != "priority:" {
+			t.Errorf("unexpected label rules: %+v", got.FormatRules)
+		}
+		if got.Instructions != "Base instructions\n\nSecurity instructions" {
+			t.Errorf("Instructions = %q", got.Instructions)
+		}
+	})
+
+	t.Run("no matching label returns base", func(t *testing.T) {
+		got, applied := resolver.Resolve([]string{"bug"})
+		if got != base || len(applied) != 0 {
+			t.Errorf("expected base guidelines and no profiles, got %v", applied)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package guidelines
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Section is a required issue section. A heading with the name or any alias
+// satisfies it, e.g. "AC" for "Acceptance Criteria".
+type Section struct {
+	Name    string
+	Aliases []string
+}
+
+// Sections returns sections without aliases, one per name
+func Sections(names ...string) []Section {
+	sections := make([]Section, len(names))
+	for i, name := range names {
+		sections[i] = Section{Name: name}
+	}
+	return sections
+}
+
+// SectionNames returns the name of each section
+func SectionNames(sections []Section) []string {
+	names := make([]string, len(sections))
+	for i, section := range sections {
+		names[i] = section.Name
+	}
+	return names
+}
+
+// String returns the name followed by any aliases, e.g.
+// "Acceptance Criteria (aliases: AC, Definition of Done)"
+func (s Section) String() string {
+	if len(s.Aliases) == 0 {
+		return s.Name
+	}
+	return s.Name + " (aliases: " + strings.Join(s.Aliases, ", ") + ")"
+}
+
+// sectionAliasesPattern matches a trailing "(aliases: AC, DoD)" on a
+// required sections list item
+var sectionAliasesPattern = regexp.MustCompile(`(?i)^(.*?)\s*\(\s*(?:aliases?|also)\s*:\s*(.*?)\s*\)$`)
+
+// parseSection parses a required sections list item, which is either a
+// plain name or a name followed by "(aliases: A, B)"
+func parseSection(item string) Section {
+	matches := sectionAliasesPattern.FindStringSubmatch(strings.TrimSpace(item))
+	if matches == nil {
+		return Section{Name: strings.TrimSpace(item)}
+	}
+
+	section := Section{Name: matches[1]}
+	for _, alias := range strings.Split(matches[2], ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			section.Aliases = append(section.Aliases, alias)
+		}
+	}
+	return section
+}
+
+// UnionSections combines two section lists. Sections with the same name
+// (ignoring case) are merged, keeping the aliases of both.
+func UnionSections(a, b []Section) []Section {
+	var result []Section
+	index := make(map[string]int)
+	for _, list := range [][]Section{a, b} {
+		for _, section := range list {
+			key := strings.ToLower(section.Name)
+			if i, ok := index[key]; ok {
+				result[i].Aliases = unionStrings(result[i].Aliases, section.Aliases)
+				continue
+			}
+			index[key] = len(result)
+			result = append(result, Section{Name: section.Name, Aliases: append([]string(nil), section.Aliases...)})
+		}
+	}
+	return result
+}
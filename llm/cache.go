@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+)
+
+// WithCache keeps up to size responses in memory so an identical request
+// (same model, messages and options) is answered without calling the server.
+// The least recently used response is evicted first. Sizes below 1 disable
+// the cache.
+func WithCache(size int) Option {
+	return func(c *Client) {
+		if size > 0 {
+			c.cache = newResponseCache(size)
+		}
+	}
+}
+
+// ClearCache drops all cached responses, e.g. between daemon runs so edited
+// prompts are not answered from stale state
+func (c *Client) ClearCache() {
+	if c.cache != nil {
+		c.cache.clear()
+	}
+}
+
+// responseCache is a bounded LRU cache of response text keyed by request hash
+type responseCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // Front is most recently used
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key      string
+	response string
+}
+
+func newResponseCache(size int) *responseCache {
+	return &responseCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// cacheKey hashes everything in a request that affects the response
+func cacheKey(req ChatRequest) string {
+	data, _ := json.Marshal(req)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (rc *responseCache) get(key string) (string, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	element, ok := rc.entries[key]
+	if !ok {
+		return "", false
+	}
+	rc.order.MoveToFront(element)
+	return element.Value.(*cacheEntry).response, true
+}
+
+func (rc *responseCache) put(key, response string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if element, ok := rc.entries[key]; ok {
+		element.Value.(*cacheEntry).response = response
+		rc.order.MoveToFront(element)
+		return
+	}
+
+	rc.entries[key] = rc.order.PushFront(&cacheEntry{key: key, response: response})
+	for rc.order.Len() > rc.size {
+		oldest := rc.order.Back()
+		rc.order.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (rc *responseCache) clear() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.order.Init()
+	rc.entries = make(map[string]*list.Element)
+}
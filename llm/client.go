@@ -1,21 +1,65 @@
 package llm
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"net/http"
-	"strings"
+	"sync"
 	"time"
+
+	"github.com/kaskol10/github-project-agent/metrics"
 )
 
 type Client struct {
-	baseURL string
-	model   string
-	apiKey  string
-	timeout time.Duration
-	client  *http.Client
+	model    string
+	timeout  time.Duration
+	client   *http.Client
+	defaults ChatOptions // Applied to requests that don't set their own
+
+	callTimeout time.Duration // Deadline for a whole call, see WithCallTimeout
+
+	mu    sync.Mutex
+	usage Usage // Tokens used by all requests so far
+
+	cache *responseCache // Optional, see WithCache
+
+	providerName string   // See WithProvider
+	provider     Provider // Wire format of the server
+}
+
+// ChatOptions tunes a chat request. Zero values are omitted so the server
+// defaults apply.
+type ChatOptions struct {
+	Temperature float64
+	MaxTokens   int
+	System      string // Sent as a leading system message
+	JSON        bool   // Ask the server for a JSON object, see ChatJSON
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithTemperature sets the default sampling temperature
+func WithTemperature(temperature float64) Option {
+	return func(c *Client) { c.defaults.Temperature = temperature }
+}
+
+// WithSystemPrompt sets the default system message sent before the
+// conversation
+func WithSystemPrompt(system string) Option {
+	return func(c *Client) { c.defaults.System = system }
+}
+
+// WithCallTimeout bounds each call, on top of any deadline of the caller's
+// context. Unlike the HTTP client timeout it is applied per call rather than
+// per request. Zero leaves calls bounded by the HTTP timeout only.
+func WithCallTimeout(timeout time.Duration) Option {
+	return func(c *Client) { c.callTimeout = timeout }
+}
+
+// WithMaxTokens caps the tokens generated per request by default
+func WithMaxTokens(maxTokens int) Option {
+	return func(c *Client) { c.defaults.MaxTokens = maxTokens }
 }
 
 type ChatMessage struct {
@@ -24,102 +68,190 @@ type ChatMessage struct {
 }
 
 type ChatRequest struct {
-	Model    string        `json:"model"`
-	Messages []ChatMessage `json:"messages"`
-	Stream   bool          `json:"stream,omitempty"`
+	Model          string          `json:"model"`
+	Messages       []ChatMessage   `json:"messages"`
+	Stream         bool            `json:"stream,omitempty"`
+	Temperature    float64         `json:"temperature,omitempty"`
+	MaxTokens      int             `json:"max_tokens,omitempty"`
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
 }
 
 type ChatResponse struct {
 	Choices []struct {
 		Message ChatMessage `json:"message"`
 	} `json:"choices"`
+	Usage Usage `json:"usage"`
 	Error *struct {
 		Message string `json:"message"`
 	} `json:"error,omitempty"`
 }
 
-func NewClient(baseURL, model, apiKey string, timeout time.Duration) *Client {
-	return &Client{
-		baseURL: baseURL,
+// Usage counts the tokens billed for chat requests
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+	Requests         int `json:"-"` // Requests that reported usage
+}
+
+// Add returns the sum of u and other
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+		TotalTokens:      u.TotalTokens + other.TotalTokens,
+		Requests:         u.Requests + other.Requests,
+	}
+}
+
+// String summarizes the usage for logs
+func (u Usage) String() string {
+	return fmt.Sprintf("%d tokens (%d prompt, %d completion) across %d requests", u.TotalTokens, u.PromptTokens, u.CompletionTokens, u.Requests)
+}
+
+func NewClient(baseURL, model, apiKey string, timeout time.Duration, opts ...Option) *Client {
+	c := &Client{
 		model:   model,
-		apiKey:  apiKey,
 		timeout: timeout,
 		client: &http.Client{
 			Timeout: timeout,
 		},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.provider = newProvider(c.providerName, baseURL, apiKey, c.client)
+	return c
 }
 
 func (c *Client) Chat(messages []ChatMessage) (string, error) {
-	// Check if baseURL already includes the path
-	var url string
-	if strings.Contains(c.baseURL, "/v1/chat/completions") {
-		url = c.baseURL
-	} else {
-		// Remove trailing slash if present, then append path
-		baseURL := strings.TrimSuffix(c.baseURL, "/")
-		url = fmt.Sprintf("%s/v1/chat/completions", baseURL)
-	}
+	return c.ChatWithOptions(messages, ChatOptions{})
+}
+
+// ChatWithOptions sends a chat request with per-request tuning; unset options
+// fall back to the client defaults
+func (c *Client) ChatWithOptions(messages []ChatMessage, opts ChatOptions) (string, error) {
+	return c.ChatWithOptionsContext(context.Background(), messages, opts)
+}
+
+// ChatWithOptionsContext is ChatWithOptions with a context; cancelling it
+// aborts the request
+func (c *Client) ChatWithOptionsContext(ctx context.Context, messages []ChatMessage, opts ChatOptions) (response string, err error) {
+	reqBody := c.newChatRequest(messages, opts)
 	
-	reqBody := ChatRequest{
-		Model:    c.model,
-		Messages: messages,
-		Stream:   false,
+	var key string
+	if c.cache != nil {
+		key = cacheKey(reqBody)
+		if response, ok := c.cache.get(key); ok {
+			return response, nil
+		}
 	}
 	
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
-	}
+	start := time.Now()
+	defer func() {
+		metrics.ObserveLLMCall(time.Since(start), err)
+	}()
+	
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
 	
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	response, usage, err := c.provider.Chat(ctx, reqBody)
+	c.recordUsage(usage)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", err
 	}
 	
-	req.Header.Set("Content-Type", "application/json")
-	if c.apiKey != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	if c.cache != nil {
+		c.cache.put(key, response)
 	}
-	
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+	return response, nil
+}
+
+// callContext applies the call timeout, if any, to ctx
+func (c *Client) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.callTimeout > 0 {
+		return context.WithTimeout(ctx, c.callTimeout)
 	}
-	defer resp.Body.Close()
-	
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+	return context.WithCancel(ctx)
+}
+
+// Usage returns the tokens used by the client's requests so far
+func (c *Client) Usage() Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.usage
+}
+
+// recordUsage adds the usage reported for one response. Servers that don't
+// report usage send zeros, which are not counted as a request.
+func (c *Client) recordUsage(usage Usage) {
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
 	}
-	
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	if usage.TotalTokens == 0 {
+		return
 	}
+	usage.Requests = 1
 	
-	var chatResp ChatResponse
-	if err := json.Unmarshal(body, &chatResp); err != nil {
-		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.usage = c.usage.Add(usage)
+}
+
+// newChatRequest builds a request, filling unset options from the client defaults
+func (c *Client) newChatRequest(messages []ChatMessage, opts ChatOptions) ChatRequest {
+	if opts.Temperature == 0 {
+		opts.Temperature = c.defaults.Temperature
 	}
-	
-	if chatResp.Error != nil {
-		return "", fmt.Errorf("API error: %s", chatResp.Error.Message)
+	if opts.MaxTokens == 0 {
+		opts.MaxTokens = c.defaults.MaxTokens
 	}
-	
-	if len(chatResp.Choices) == 0 {
-		return "", fmt.Errorf("no choices in response")
+	if opts.System == "" {
+		opts.System = c.defaults.System
 	}
-	
-	return chatResp.Choices[0].Message.Content, nil
+	// Messages that bring their own system message take precedence
+	if opts.System != "" && (len(messages) == 0 || messages[0].Role != "system") {
+		messages = append([]ChatMessage{{Role: "system", Content: opts.System}}, messages...)
+	}
+	req := ChatRequest{
+		Model:       c.model,
+		Messages:    messages,
+		Temperature: opts.Temperature,
+		MaxTokens:   opts.MaxTokens,
+	}
+	if opts.JSON {
+		req.ResponseFormat = &ResponseFormat{Type: "json_object"}
+	}
+	return req
 }
 
 func (c *Client) Prompt(prompt string) (string, error) {
+	return c.PromptContext(context.Background(), prompt)
+}
+
+// PromptContext is Prompt with a context; cancelling it aborts the request
+func (c *Client) PromptContext(ctx context.Context, prompt string) (string, error) {
+	return c.PromptWithOptionsContext(ctx, prompt, ChatOptions{})
+}
+
+// ChatWithSystem sends a user message preceded by a system message
+func (c *Client) ChatWithSystem(system, user string) (string, error) {
+	return c.PromptWithOptions(user, ChatOptions{System: system})
+}
+
+// PromptWithOptions sends a single user prompt with per-request tuning
+func (c *Client) PromptWithOptions(prompt string, opts ChatOptions) (string, error) {
+	return c.PromptWithOptionsContext(context.Background(), prompt, opts)
+}
+
+// PromptWithOptionsContext is PromptWithOptions with a context
+func (c *Client) PromptWithOptionsContext(ctx context.Context, prompt string, opts ChatOptions) (string, error) {
 	messages := []ChatMessage{
 		{
 			Role:    "user",
 			Content: prompt,
 		},
 	}
-	return c.Chat(messages)
+	return c.ChatWithOptionsContext(ctx, messages, opts)
 }
 
@@ -0,0 +1,196 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestChatWithOptions(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Fatalf("invalid request body: %v", err)
+		}
+		bodies = append(bodies, body)
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	plain := NewClient(server.URL, "m", "", time.Second)
+	if _, err := plain.Prompt("hi"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := bodies[0]["temperature"]; ok {
+		t.Error("zero temperature should be omitted")
+	}
+	if _, ok := bodies[0]["max_tokens"]; ok {
+		t.Error("zero max_tokens should be omitted")
+	}
+
+	tuned := NewClient(server.URL, "m", "", time.Second, WithTemperature(0.5), WithMaxTokens(200))
+	if _, err := tuned.Prompt("hi"); err != nil {
+		t.Fatal(err)
+	}
+	if bodies[1]["temperature"] != 0.5 || bodies[1]["max_tokens"] != float64(200) {
+		t.Errorf("client defaults not sent: %v", bodies[1])
+	}
+
+	if _, err := tuned.PromptWithOptions("hi", ChatOptions{Temperature: 0.1}); err != nil {
+		t.Fatal(err)
+	}
+	if bodies[2]["temperature"] != 0.1 || bodies[2]["max_tokens"] != float64(200) {
+		t.Errorf("per-request options should override defaults: %v", bodies[2])
+	}
+}
+
+func TestClient_Usage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "m", "", time.Second)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Prompt("hi"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	want := Usage{PromptTokens: 40, CompletionTokens: 20, TotalTokens: 60, Requests: 4}
+	if got := client.Usage(); got != want {
+		t.Errorf("Usage() = %+v, want %+v", got, want)
+	}
+}
+
+func TestClient_UsageNotReported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "m", "", time.Second)
+	if _, err := client.Prompt("hi"); err != nil {
+		t.Fatal(err)
+	}
+	if got := client.Usage(); got != (Usage{}) {
+		t.Errorf("expected no usage, got %+v", got)
+	}
+}
+
+func TestClient_Cache(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "m", "", time.Second, WithCache(1))
+	for i := 0; i < 2; i++ {
+		if got, err := client.Prompt("hi"); err != nil || got != "ok" {
+			t.Fatalf("Prompt() = %q, %v", got, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 HTTP call for identical prompts, got %d", calls)
+	}
+
+	// Different options are a different request
+	if _, err := client.PromptWithOptions("hi", ChatOptions{Temperature: 0.7}); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("expected a call for different options, got %d calls", calls)
+	}
+
+	// The size bound evicted the first prompt
+	if _, err := client.Prompt("hi"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("expected evicted prompt to be refetched, got %d calls", calls)
+	}
+
+	client.ClearCache()
+	if _, err := client.Prompt("hi"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 4 {
+		t.Errorf("expected a call after ClearCache, got %d calls", calls)
+	}
+}
+
+func TestNewChatRequest_SystemPrompt(t *testing.T) {
+	user := []ChatMessage{{Role: "user", Content: "hi"}}
+
+	plain := NewClient("http://llm", "m", "", time.Second)
+	if got := plain.newChatRequest(user, ChatOptions{}).Messages; len(got) != 1 {
+		t.Errorf("expected no system message by default, got %+v", got)
+	}
+
+	client := NewClient("http://llm", "m", "", time.Second, WithSystemPrompt("default system"))
+	got := client.newChatRequest(user, ChatOptions{}).Messages
+	want := []ChatMessage{{Role: "system", Content: "default system"}, {Role: "user", Content: "hi"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("messages = %+v, want %+v", got, want)
+	}
+
+	got = client.newChatRequest(user, ChatOptions{System: "per request"}).Messages
+	if got[0].Content != "per request" || len(got) != 2 {
+		t.Errorf("per-request system prompt should override the default: %+v", got)
+	}
+
+	own := []ChatMessage{{Role: "system", Content: "own"}, {Role: "user", Content: "hi"}}
+	if got := client.newChatRequest(own, ChatOptions{}).Messages; !reflect.DeepEqual(got, own) {
+		t.Errorf("existing system message should be kept as-is: %+v", got)
+	}
+	if len(user) != 1 {
+		t.Error("caller's messages should not be modified")
+	}
+}
+
+func TestClient_CallTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	// The call deadline applies even though the HTTP timeout is far away
+	client := NewClient(server.URL, "m", "", time.Minute, WithCallTimeout(50*time.Millisecond))
+	start := time.Now()
+	if _, err := client.Prompt("hello"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("call took %v, expected it to stop at the call timeout", elapsed)
+	}
+
+	// Cancelling the caller's context aborts the call too
+	client = NewClient(server.URL, "m", "", time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if _, err := client.PromptContext(ctx, "hello"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+package llm
+
+import "context"
+
+// LLMClient is what the agents need from an LLM. *Client implements it;
+// tests substitute fakes.
+type LLMClient interface {
+	Prompt(prompt string) (string, error)
+	PromptContext(ctx context.Context, prompt string) (string, error)
+	PromptWithOptionsContext(ctx context.Context, prompt string, opts ChatOptions) (string, error)
+	Chat(messages []ChatMessage) (string, error)
+	ChatWithOptionsContext(ctx context.Context, messages []ChatMessage, opts ChatOptions) (string, error)
+	ChatJSONContext(ctx context.Context, prompt string, v interface{}) error
+}
+
+var _ LLMClient = (*Client)(nil)
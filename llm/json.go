@@ -0,0 +1,58 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ResponseFormat constrains the shape of a reply, e.g. {"type":"json_object"}
+type ResponseFormat struct {
+	Type string `json:"type"`
+}
+
+// JSONError reports a reply that could not be decoded as the requested JSON.
+// Response holds the raw reply so callers can fall back to reading it as text.
+type JSONError struct {
+	Response string
+	Err      error
+}
+
+func (e *JSONError) Error() string {
+	return fmt.Sprintf("failed to parse JSON response: %v", e.Err)
+}
+
+func (e *JSONError) Unwrap() error {
+	return e.Err
+}
+
+// ChatJSON sends a single user prompt asking for a JSON object and
+// unmarshals the reply into v. The prompt should describe the expected
+// fields. A reply that is not valid JSON yields a *JSONError.
+func (c *Client) ChatJSON(prompt string, v interface{}) error {
+	return c.ChatJSONContext(context.Background(), prompt, v)
+}
+
+// ChatJSONContext is ChatJSON with a context
+func (c *Client) ChatJSONContext(ctx context.Context, prompt string, v interface{}) error {
+	response, err := c.PromptWithOptionsContext(ctx, prompt, ChatOptions{JSON: true})
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(extractJSON(response)), v); err != nil {
+		return &JSONError{Response: response, Err: err}
+	}
+	return nil
+}
+
+// extractJSON returns the outermost JSON object in a reply, dropping code
+// fences and any prose models add around it despite being asked not to
+func extractJSON(response string) string {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return strings.TrimSpace(response)
+	}
+	return response[start : end+1]
+}
@@ -0,0 +1,51 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestChatJSON(t *testing.T) {
+	reply := ""
+	var formats []*ResponseFormat
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("invalid request body: %v", err)
+		}
+		formats = append(formats, req.ResponseFormat)
+		data, _ := json.Marshal(reply)
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":` + string(data) + `}}]}`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "m", "", time.Second)
+
+	var out struct {
+		Priority  string `json:"priority"`
+		Rationale string `json:"rationale"`
+	}
+	reply = "```json\n{\"priority\":\"P1\",\"rationale\":\"Blocks the release\"}\n```"
+	if err := client.ChatJSON("prioritize", &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Priority != "P1" || out.Rationale != "Blocks the release" {
+		t.Errorf("unexpected result %+v", out)
+	}
+	if formats[0] == nil || formats[0].Type != "json_object" {
+		t.Errorf("expected a json_object response format, got %+v", formats[0])
+	}
+
+	reply = "Priority: P2, it can wait"
+	err := client.ChatJSON("prioritize", &out)
+	var jsonErr *JSONError
+	if !errors.As(err, &jsonErr) {
+		t.Fatalf("expected a *JSONError, got %v", err)
+	}
+	if jsonErr.Response != reply {
+		t.Errorf("expected the raw reply to be kept, got %q", jsonErr.Response)
+	}
+}
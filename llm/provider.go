@@ -0,0 +1,184 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Provider sends chat requests in one server's wire format
+type Provider interface {
+	// Chat sends req and returns the reply text and the token usage the
+	// server reported
+	Chat(ctx context.Context, req ChatRequest) (string, Usage, error)
+}
+
+// Provider names accepted by WithProvider
+const (
+	ProviderLiteLLM = "litellm"
+	ProviderOpenAI  = "openai" // Same wire format as LiteLLM
+	ProviderOllama  = "ollama"
+)
+
+// Providers returns the provider names accepted by WithProvider
+func Providers() []string {
+	return []string{ProviderLiteLLM, ProviderOpenAI, ProviderOllama}
+}
+
+// WithProvider selects the wire format by name (see Providers). The default,
+// and the fallback for unknown names, is LiteLLM.
+func WithProvider(name string) Option {
+	return func(c *Client) { c.providerName = name }
+}
+
+// newProvider returns the provider named name talking to baseURL
+func newProvider(name, baseURL, apiKey string, httpClient *http.Client) Provider {
+	switch strings.ToLower(name) {
+	case ProviderOllama:
+		return &OllamaProvider{BaseURL: baseURL, APIKey: apiKey, HTTPClient: httpClient}
+	default:
+		return &LiteLLMProvider{BaseURL: baseURL, APIKey: apiKey, HTTPClient: httpClient}
+	}
+}
+
+// LiteLLMProvider speaks the OpenAI chat completions API at
+// /v1/chat/completions, as served by LiteLLM, OpenAI and compatible servers
+type LiteLLMProvider struct {
+	BaseURL    string
+	APIKey     string // Sent as a bearer token when set
+	HTTPClient *http.Client
+}
+
+// Chat sends req to the chat completions endpoint
+func (p *LiteLLMProvider) Chat(ctx context.Context, req ChatRequest) (string, Usage, error) {
+	req.Stream = false
+	body, err := postJSON(ctx, p.HTTPClient, p.chatURL(), p.APIKey, req)
+	if err != nil {
+		return "", Usage{}, err
+	}
+
+	var chatResp ChatResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return "", Usage{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if chatResp.Error != nil {
+		return "", Usage{}, fmt.Errorf("API error: %s", chatResp.Error.Message)
+	}
+	if len(chatResp.Choices) == 0 {
+		return "", chatResp.Usage, fmt.Errorf("no choices in response")
+	}
+	return chatResp.Choices[0].Message.Content, chatResp.Usage, nil
+}
+
+// chatURL returns the chat completions endpoint
+func (p *LiteLLMProvider) chatURL() string {
+	// Check if baseURL already includes the path
+	if strings.Contains(p.BaseURL, "/v1/chat/completions") {
+		return p.BaseURL
+	}
+	// Remove trailing slash if present, then append path
+	return fmt.Sprintf("%s/v1/chat/completions", strings.TrimSuffix(p.BaseURL, "/"))
+}
+
+// OllamaProvider speaks Ollama's native chat API at /api/chat
+type OllamaProvider struct {
+	BaseURL    string
+	APIKey     string // Sent as a bearer token when set, e.g. behind a proxy
+	HTTPClient *http.Client
+}
+
+// ollamaRequest is the body of an Ollama chat request. Sampling settings go
+// in options, with the token cap named num_predict.
+type ollamaRequest struct {
+	Model    string         `json:"model"`
+	Messages []ChatMessage  `json:"messages"`
+	Stream   bool           `json:"stream"`
+	Format   string         `json:"format,omitempty"` // "json" constrains the reply to JSON
+	Options  *ollamaOptions `json:"options,omitempty"`
+}
+
+type ollamaOptions struct {
+	Temperature float64 `json:"temperature,omitempty"`
+	NumPredict  int     `json:"num_predict,omitempty"`
+}
+
+type ollamaResponse struct {
+	Message         ChatMessage `json:"message"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+	EvalCount       int         `json:"eval_count"`
+	Error           string      `json:"error,omitempty"`
+}
+
+// Chat sends req to the Ollama chat endpoint
+func (p *OllamaProvider) Chat(ctx context.Context, req ChatRequest) (string, Usage, error) {
+	ollamaReq := ollamaRequest{Model: req.Model, Messages: req.Messages}
+	if req.ResponseFormat != nil {
+		ollamaReq.Format = "json"
+	}
+	if req.Temperature != 0 || req.MaxTokens != 0 {
+		ollamaReq.Options = &ollamaOptions{Temperature: req.Temperature, NumPredict: req.MaxTokens}
+	}
+
+	body, err := postJSON(ctx, p.HTTPClient, p.chatURL(), p.APIKey, ollamaReq)
+	if err != nil {
+		return "", Usage{}, err
+	}
+
+	var chatResp ollamaResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return "", Usage{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if chatResp.Error != "" {
+		return "", Usage{}, fmt.Errorf("API error: %s", chatResp.Error)
+	}
+	usage := Usage{
+		PromptTokens:     chatResp.PromptEvalCount,
+		CompletionTokens: chatResp.EvalCount,
+		TotalTokens:      chatResp.PromptEvalCount + chatResp.EvalCount,
+	}
+	return chatResp.Message.Content, usage, nil
+}
+
+// chatURL returns the chat endpoint
+func (p *OllamaProvider) chatURL() string {
+	if strings.HasSuffix(p.BaseURL, "/api/chat") {
+		return p.BaseURL
+	}
+	return strings.TrimSuffix(p.BaseURL, "/") + "/api/chat"
+}
+
+// postJSON posts payload to url and returns the body of a 200 response
+func postJSON(ctx context.Context, client *http.Client, url, apiKey string, payload interface{}) ([]byte, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
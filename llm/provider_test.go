@@ -0,0 +1,123 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLiteLLMProvider_Chat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var req ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("invalid request body: %v", err)
+		}
+		if req.Model != "m" || req.MaxTokens != 50 {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"hello"}}],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`))
+	}))
+	defer server.Close()
+
+	provider := &LiteLLMProvider{BaseURL: server.URL + "/", APIKey: "secret", HTTPClient: server.Client()}
+	reply, usage, err := provider.Chat(context.Background(), ChatRequest{
+		Model:     "m",
+		Messages:  []ChatMessage{{Role: "user", Content: "hi"}},
+		MaxTokens: 50,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply != "hello" {
+		t.Errorf("reply = %q", reply)
+	}
+	if usage.TotalTokens != 5 {
+		t.Errorf("usage = %+v", usage)
+	}
+}
+
+func TestOllamaProvider_Chat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("invalid request body: %v", err)
+		}
+		if body["stream"] != false {
+			t.Errorf("stream should be sent as false: %v", body)
+		}
+		options, _ := body["options"].(map[string]interface{})
+		if options["temperature"] != 0.2 || options["num_predict"] != float64(64) {
+			t.Errorf("options not sent: %v", body)
+		}
+		if _, ok := body["max_tokens"]; ok {
+			t.Errorf("max_tokens is not part of the Ollama API: %v", body)
+		}
+		w.Write([]byte(`{"model":"llama3","message":{"role":"assistant","content":"hello"},"done":true,"prompt_eval_count":7,"eval_count":4}`))
+	}))
+	defer server.Close()
+
+	provider := &OllamaProvider{BaseURL: server.URL, HTTPClient: server.Client()}
+	reply, usage, err := provider.Chat(context.Background(), ChatRequest{
+		Model:       "llama3",
+		Messages:    []ChatMessage{{Role: "user", Content: "hi"}},
+		Temperature: 0.2,
+		MaxTokens:   64,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply != "hello" {
+		t.Errorf("reply = %q", reply)
+	}
+	if usage.PromptTokens != 7 || usage.CompletionTokens != 4 || usage.TotalTokens != 11 {
+		t.Errorf("usage = %+v", usage)
+	}
+}
+
+func TestOllamaProvider_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":"model \"nope\" not found"}`))
+	}))
+	defer server.Close()
+
+	provider := &OllamaProvider{BaseURL: server.URL, HTTPClient: server.Client()}
+	_, _, err := provider.Chat(context.Background(), ChatRequest{Model: "nope"})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected the server error, got %v", err)
+	}
+}
+
+func TestClient_WithProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("expected the Ollama endpoint, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"message":{"role":"assistant","content":"ok"},"prompt_eval_count":1,"eval_count":1}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "llama3", "", time.Second, WithProvider(ProviderOllama))
+	reply, err := client.Prompt("hi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply != "ok" {
+		t.Errorf("reply = %q", reply)
+	}
+	if usage := client.Usage(); usage.TotalTokens != 2 {
+		t.Errorf("usage = %+v", usage)
+	}
+}
@@ -0,0 +1,161 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kaskol10/github-project-agent/metrics"
+)
+
+// streamChunk is one server-sent event of a streamed chat completion
+type streamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *Usage `json:"usage,omitempty"` // Sent on the final chunk by some servers
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// ChatStream sends a chat request with streaming enabled, calling fn with each
+// content delta as it arrives, and returns the full response text
+func (c *Client) ChatStream(messages []ChatMessage, fn func(chunk string) error) (string, error) {
+	return c.ChatStreamContext(context.Background(), messages, fn)
+}
+
+// ChatStreamContext is ChatStream with a context; cancelling it stops reading
+// mid-stream. The client timeout applies to the wait for each chunk rather
+// than to the whole response, since long generations can exceed it. Only the
+// LiteLLM provider streams; others call fn once with the whole response.
+func (c *Client) ChatStreamContext(ctx context.Context, messages []ChatMessage, fn func(chunk string) error) (response string, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.ObserveLLMCall(time.Since(start), err)
+	}()
+
+	reqBody := c.newChatRequest(messages, ChatOptions{})
+
+	lite, ok := c.provider.(*LiteLLMProvider)
+	if !ok {
+		ctx, cancel := c.callContext(ctx)
+		defer cancel()
+
+		response, usage, err := c.provider.Chat(ctx, reqBody)
+		c.recordUsage(usage)
+		if err != nil {
+			return "", err
+		}
+		if fn != nil && response != "" {
+			if err := fn(response); err != nil {
+				return response, err
+			}
+		}
+		return response, nil
+	}
+	reqBody.Stream = true
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+
+	var idle *time.Timer
+	if c.timeout > 0 {
+		idle = time.AfterFunc(c.timeout, cancel)
+		defer idle.Stop()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", lite.chatURL(), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	if lite.APIKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", lite.APIKey))
+	}
+
+	// No overall timeout: the idle timer above bounds the wait between chunks
+	streamClient := &http.Client{Transport: c.client.Transport}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var full strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		if idle != nil {
+			idle.Reset(c.timeout)
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ":") {
+			// Event separator or keep-alive comment
+			continue
+		}
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			return full.String(), nil
+		}
+
+		var chunk streamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return full.String(), fmt.Errorf("failed to unmarshal stream chunk: %w", err)
+		}
+		if chunk.Error != nil {
+			return full.String(), fmt.Errorf("API error: %s", chunk.Error.Message)
+		}
+		if chunk.Usage != nil {
+			c.recordUsage(*chunk.Usage)
+		}
+
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			full.WriteString(choice.Delta.Content)
+			if fn != nil {
+				if err := fn(choice.Delta.Content); err != nil {
+					return full.String(), err
+				}
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return full.String(), fmt.Errorf("stream interrupted: %w", ctx.Err())
+		}
+		return full.String(), fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	// Stream ended without [DONE]; return what was received
+	return full.String(), nil
+}
@@ -0,0 +1,96 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChatStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !req.Stream {
+			t.Errorf("expected a streaming request, got %+v (err %v)", req, err)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"role\":\"assistant\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Hello\"}}]}\n\n")
+		fmt.Fprint(w, "\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\", world\"}}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"ignored\"}}]}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-model", "", time.Second)
+
+	var chunks []string
+	full, err := client.ChatStream([]ChatMessage{{Role: "user", Content: "hi"}}, func(chunk string) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ChatStream() error = %v", err)
+	}
+	if full != "Hello, world" {
+		t.Errorf("ChatStream() = %q, want %q", full, "Hello, world")
+	}
+	if strings.Join(chunks, "|") != "Hello|, world" {
+		t.Errorf("unexpected chunks: %v", chunks)
+	}
+}
+
+func TestChatStream_CallbackErrorStops(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"a\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"b\"}}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-model", "", time.Second)
+	stop := errors.New("stop")
+	full, err := client.ChatStream(nil, func(chunk string) error { return stop })
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected callback error, got %v", err)
+	}
+	if full != "a" {
+		t.Errorf("expected partial text %q, got %q", "a", full)
+	}
+}
+
+func TestChatStreamContext_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"partial\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := NewClient(server.URL, "test-model", "", 10*time.Second)
+
+	full, err := client.ChatStreamContext(ctx, nil, func(chunk string) error {
+		cancel()
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected an error after cancellation")
+	}
+	if full != "partial" {
+		t.Errorf("expected partial text %q, got %q", "partial", full)
+	}
+}
@@ -3,11 +3,15 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -16,28 +20,86 @@ import (
 	"github.com/kaskol10/github-project-agent/github"
 	"github.com/kaskol10/github-project-agent/guidelines"
 	"github.com/kaskol10/github-project-agent/llm"
+	"github.com/kaskol10/github-project-agent/logging"
 	"github.com/kaskol10/github-project-agent/mcp"
+	"github.com/kaskol10/github-project-agent/metrics"
+	"github.com/kaskol10/github-project-agent/output"
 	"github.com/kaskol10/github-project-agent/plugins"
+	"github.com/kaskol10/github-project-agent/pool"
+	"github.com/kaskol10/github-project-agent/sampling"
+	"github.com/kaskol10/github-project-agent/store"
 )
 
 func main() {
 	var (
-		mode         = flag.String("mode", "validate", "Mode: validate, monitor, roast, all, or mcp")
-		issueNumber  = flag.Int("issue", 0, "Issue number to validate (for validate mode)")
+		mode         = flag.String("mode", "validate", "Mode: validate, explain, monitor, checklist, roast, all, mcp, mcp-server, healthcheck, or lint-agents")
+		issueNumber  = flag.Int("issue", 0, "Issue number to validate (for validate and explain modes)")
+		issueRepo    = flag.String("repo", "", "Repository of -issue as owner/name, fetched directly in project mode instead of searching the project (ignored in repo mode)")
+		scopeRepos   = flag.String("repos", "", "Comma-separated owner/repo list limiting this run to some of the project's repositories (project mode)")
 		runOnce      = flag.Bool("once", false, "Run once and exit (for monitor mode)")
 		daemon       = flag.Bool("daemon", false, "Run as daemon (for monitor mode)")
 		agentName    = flag.String("agent", "", "Agent name to execute (for mcp mode)")
 		workflowName = flag.String("workflow", "", "Workflow name to execute (for mcp mode)")
+		listJSON     = flag.Bool("list-json", false, "Print the loaded plugin agents' metadata as JSON and exit (for mcp mode)")
+		verbose      = flag.Bool("verbose", false, "Include each agent's raw markdown in -list-json output")
+		estimate     = flag.Bool("estimate", false, "Print the projected LLM calls and cost, then exit (for validate mode)")
+		explainFix   = flag.Bool("explain", false, "Print a diff of the fix the agent would make to -issue without changing it (for validate mode)")
+		dryRun       = flag.Bool("dry-run", false, "Log GitHub writes (issue updates, comments, new issues, labels) instead of performing them")
+		configPath   = flag.String("config", "", "Path to a YAML config file (default: agent.yaml if present); environment variables override its values")
+		metricsAddr  = flag.String("metrics-addr", "", "Serve Prometheus metrics on this address at /metrics, e.g. :9090 (useful with -daemon)")
+		outputFormat = flag.String("output", output.FormatText, "Output format: "+strings.Join(output.Formats(), ", ")+" (json prints a single JSON result for validate, monitor -once, roast and mcp)")
 	)
+	agentParams := mcp.Params{}
+	flag.Var(agentParams, "param", "Parameter key=value passed to the -agent or -workflow, repeatable; integers and true/false are typed, quote a value to keep it a string (for mcp mode)")
 	flag.Parse()
 
-	cfg, err := config.Load()
+	if _, err := output.Lookup(*outputFormat); err != nil {
+		log.Fatalf("Invalid -output: %v", err)
+	}
+	var targetRepo github.Repository
+	if *issueRepo != "" {
+		repo, err := github.ParseRepository(*issueRepo)
+		if err != nil {
+			log.Fatalf("Invalid -repo: %v", err)
+		}
+		targetRepo = repo
+	}
+	if *outputFormat == output.FormatJSON || *mode == "mcp-server" {
+		// Keep stdout for the JSON result or protocol messages; progress
+		// goes to stderr
+		progressOutput = os.Stderr
+	}
+
+	var cfg *config.Config
+	var err error
+	if *configPath != "" {
+		cfg, err = config.LoadFromFile(*configPath)
+	} else {
+		cfg, err = config.Load()
+	}
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	// Linting only reads the agent files, so it needs no credentials
+	if *mode == "lint-agents" {
+		if err := runLintAgents(cfg, *outputFormat); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	if *scopeRepos != "" {
+		if err := cfg.ScopeRepos(*scopeRepos); err != nil {
+			log.Fatalf("Invalid -repos: %v", err)
+		}
+	}
+	if err := logging.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		log.Fatal(err)
+	}
 
-	// Validate required config
-	// Either token or GitHub App credentials must be provided
+	// Prefer GitHub App credentials over a token
 	var appAuth *github.AppAuth
 	if cfg.GitHub.AppID > 0 && cfg.GitHub.InstallationID > 0 && len(cfg.GitHub.PrivateKey) > 0 {
 		// Use GitHub App authentication
@@ -51,31 +113,17 @@ func main() {
 		if err != nil {
 			log.Fatalf("Failed to create GitHub App authenticator: %v", err)
 		}
+		appAuth.TokenCachePath = cfg.GitHub.TokenCachePath
+		appAuth.ResolveInstallations = cfg.GitHub.ResolveInstallations
 		log.Println("Using GitHub App authentication")
-	} else if cfg.GitHub.Token == "" {
-		log.Fatal("Either GITHUB_TOKEN or GitHub App credentials (GITHUB_APP_ID, GITHUB_APP_INSTALLATION_ID, GITHUB_APP_PRIVATE_KEY) must be provided")
 	} else {
 		log.Println("Using token-based authentication")
 	}
 
-	if cfg.GitHub.Owner == "" {
-		log.Fatal("GITHUB_OWNER environment variable is required")
-	}
-
-	// Validate mode-specific requirements
 	if cfg.GitHub.Mode == "project" {
-		if cfg.GitHub.ProjectID == "" {
-			log.Fatal("GITHUB_PROJECT_ID is required when using project mode")
-		}
-		if len(cfg.GitHub.Repos) == 0 {
-			log.Fatal("GITHUB_REPOS is required when using project mode (format: owner/repo,owner/repo)")
-		}
 		log.Printf("Using project mode with project ID: %s", cfg.GitHub.ProjectID)
 		log.Printf("Monitoring %d repositories", len(cfg.GitHub.Repos))
 	} else {
-		if cfg.GitHub.Repo == "" {
-			log.Fatal("GITHUB_REPO environment variable is required for repo mode")
-		}
 		log.Printf("Using repo mode: %s/%s", cfg.GitHub.Owner, cfg.GitHub.Repo)
 	}
 
@@ -86,7 +134,7 @@ func main() {
 	}
 
 	// Initialize unified client (works with both repo and project modes)
-	ghClient, err := github.NewUnifiedClientWithAuth(
+	ghClient, err := github.NewUnifiedClientWithOptions(
 		cfg.GitHub.Token,
 		appAuth,
 		cfg.GitHub.Owner,
@@ -94,16 +142,36 @@ func main() {
 		cfg.GitHub.ProjectID,
 		repos,
 		cfg.GitHub.BaseURL,
+		github.ClientOptions{
+			AddCreatedToProject:   cfg.GitHub.AddCreatedToProject,
+			DryRun:                *dryRun,
+			DryRunOutput:          progressOutput,
+			ThrottleRateLimits:    cfg.GitHub.ThrottleRateLimits,
+			RateLimitMinRemaining: cfg.GitHub.RateLimitMinRemaining,
+			ManagedLabelPrefixes:  cfg.GitHub.ManagedLabelPrefixes,
+			WriteRetries:          cfg.GitHub.WriteRetries,
+			UploadURL:             cfg.GitHub.UploadURL,
+			OnlyRepos:             *scopeRepos != "",
+		},
 	)
 	if err != nil {
 		log.Fatalf("Failed to create GitHub client: %v", err)
 	}
 
+	if *dryRun {
+		log.Println("Dry run: GitHub writes will be logged with a [dry-run] prefix and not performed")
+	}
+
 	llmClient := llm.NewClient(
 		cfg.LLM.LiteLLMBaseURL,
 		cfg.LLM.Model,
 		cfg.LLM.APIKey,
 		cfg.LLM.Timeout,
+		llm.WithProvider(cfg.LLM.Provider),
+		llm.WithTemperature(cfg.LLM.Temperature),
+		llm.WithMaxTokens(cfg.LLM.MaxTokens),
+		llm.WithCache(cfg.LLM.CacheSize),
+		llm.WithCallTimeout(cfg.LLM.CallTimeout),
 	)
 
 	// Load guidelines if path is specified
@@ -113,256 +181,729 @@ func main() {
 			gd = g
 			log.Printf("Loaded guidelines from: %s", cfg.Agent.GuidelinesPath)
 		} else {
-			log.Printf("Warning: Could not load guidelines from %s: %v (using defaults)", cfg.Agent.GuidelinesPath, err)
+			slog.Warn("could not load guidelines, using defaults", "path", cfg.Agent.GuidelinesPath, "error", err)
 		}
 	}
 
 	// Load plugin agents from .github/agents/ directory
 	var pluginAgents []*plugins.PluginAgent
 	if cfg.Agent.PluginsPath != "" {
-		if pa, err := plugins.LoadPlugins(cfg.Agent.PluginsPath); err == nil {
+		if pa, err := plugins.LoadPluginsWithOptions(cfg.Agent.PluginsPath, plugins.LoadOptions{
+			StrictEnv: cfg.Agent.StrictAgentEnv,
+		}); err == nil {
 			pluginAgents = pa
 			log.Printf("Loaded %d plugin agents from: %s", len(pluginAgents), cfg.Agent.PluginsPath)
 			for _, agent := range pluginAgents {
 				log.Printf("  - %s (%s)", agent.Name, agent.Type)
 			}
 		} else {
-			log.Printf("Info: Could not load plugins from %s: %v (continuing without plugins)", cfg.Agent.PluginsPath, err)
+			slog.Info("could not load plugins, continuing without them", "path", cfg.Agent.PluginsPath, "error", err)
 		}
 	}
 
-	ctx := context.Background()
+	// Interrupts cancel in-flight GitHub and LLM calls; a second one exits
+	// immediately
+	interrupted, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-interrupted.Done()
+		stop()
+	}()
+	ctx := interrupted
+
+	if *metricsAddr != "" {
+		go func() {
+			if err := metrics.Serve(ctx, *metricsAddr); err != nil {
+				slog.Error("metrics server stopped", "error", err)
+			}
+		}()
+		log.Printf("Serving metrics on %s/metrics", *metricsAddr)
+	}
+
+	// One-shot runs get an overall deadline. The daemon applies it to each
+	// check instead, and the MCP server runs until its input closes.
+	if !(*mode == "monitor" && *daemon) && *mode != "mcp-server" {
+		var cancel context.CancelFunc
+		ctx, cancel = withRunTimeout(ctx, cfg.Agent.RunTimeout)
+		defer cancel()
+	}
 
 	switch *mode {
 	case "validate":
-		if err := runValidate(ctx, ghClient, llmClient, cfg, *issueNumber, gd); err != nil {
+		if *estimate {
+			if err := runEstimate(ctx, ghClient, llmClient, cfg, targetRepo, *issueNumber, gd); err != nil {
+				log.Fatalf("Estimate failed: %v", err)
+			}
+			return
+		}
+		if *explainFix {
+			if err := runPreview(ctx, ghClient, llmClient, cfg, targetRepo, *issueNumber, gd); err != nil {
+				log.Fatalf("Preview failed: %v", err)
+			}
+			return
+		}
+		if err := runValidate(ctx, ghClient, llmClient, cfg, targetRepo, *issueNumber, gd, *outputFormat); err != nil {
 			log.Fatalf("Validation failed: %v", err)
 		}
+	case "explain":
+		if err := runExplain(ctx, ghClient, llmClient, cfg, targetRepo, *issueNumber, gd); err != nil {
+			log.Fatalf("Explain failed: %v", err)
+		}
 	case "monitor":
 		if *daemon {
-			runMonitorDaemon(ctx, ghClient, llmClient, cfg)
+			runMonitorDaemon(ctx, ghClient, llmClient, cfg, pluginAgents, gd)
 		} else if *runOnce {
-			if err := runMonitorOnce(ctx, ghClient, llmClient, cfg); err != nil {
+			if err := runMonitorOnce(ctx, ghClient, llmClient, cfg, *outputFormat); err != nil {
 				log.Fatalf("Monitoring failed: %v", err)
 			}
 		} else {
 			log.Fatal("Monitor mode requires either -once or -daemon flag")
 		}
+	case "checklist":
+		if err := runChecklistMonitor(ctx, ghClient, cfg); err != nil {
+			log.Fatalf("Checklist monitoring failed: %v", err)
+		}
 	case "roast":
-		if err := runRoast(ctx, ghClient, llmClient); err != nil {
+		if err := runRoast(ctx, ghClient, llmClient, cfg, *outputFormat); err != nil {
 			log.Fatalf("Roast failed: %v", err)
 		}
 	case "all":
-		if err := runAll(ctx, ghClient, llmClient, cfg, *issueNumber, gd); err != nil {
+		if err := runAll(ctx, ghClient, llmClient, cfg, targetRepo, *issueNumber, gd); err != nil {
 			log.Fatalf("Failed: %v", err)
 		}
+	case "healthcheck":
+		if err := runHealthcheck(ctx, ghClient, cfg); err != nil {
+			log.Fatalf("Healthcheck failed: %v", err)
+		}
 	case "mcp":
+		if *listJSON {
+			data, err := mcp.NewMCPInterface(ghClient, pluginAgents, llmClient, gd, cfg).DescribeAgentsJSON(*verbose)
+			if err != nil {
+				log.Fatalf("Failed to describe agents: %v", err)
+			}
+			fmt.Fprintln(resultOutput, string(data))
+			break
+		}
 		if len(pluginAgents) == 0 {
 			log.Fatal("No plugin agents found. Create agents in .github/agents/core/ or .github/agents/custom/")
 		}
-		if err := runMCP(ctx, ghClient, pluginAgents, *agentName, *workflowName, *issueNumber, llmClient, gd, cfg); err != nil {
+		if err := runMCP(ctx, ghClient, pluginAgents, *agentName, *workflowName, *issueNumber, agentParams, llmClient, gd, cfg, *outputFormat); err != nil {
 			log.Fatalf("MCP execution failed: %v", err)
 		}
+	case "mcp-server":
+		if len(pluginAgents) == 0 {
+			log.Fatal("No plugin agents found. Create agents in .github/agents/core/ or .github/agents/custom/")
+		}
+		mcpInterface := mcp.NewMCPInterface(ghClient, pluginAgents, llmClient, gd, cfg)
+		log.Printf("Serving %d plugin agents as MCP tools on stdio", len(pluginAgents))
+		if err := mcp.NewServer(mcpInterface, "github-project-agent", "dev").Serve(ctx, os.Stdin, resultOutput); err != nil {
+			log.Fatalf("MCP server failed: %v", err)
+		}
 	default:
-		log.Fatalf("Unknown mode: %s. Use: validate, monitor, roast, all, or mcp", *mode)
+		log.Fatalf("Unknown mode: %s. Use: validate, explain, monitor, checklist, roast, all, mcp, mcp-server, healthcheck, or lint-agents", *mode)
+	}
+
+	if usage := llmClient.Usage(); usage.Requests > 0 {
+		log.Printf("LLM usage: %s", usage)
 	}
 }
 
-func runValidate(ctx context.Context, ghClient github.UnifiedClient, llmClient *llm.Client, cfg *config.Config, issueNumber int, guidelines *guidelines.Guidelines) error {
-	validator := agent.NewValidator(ghClient, llmClient, agent.TaskFormatRules{
+// withRunTimeout bounds a run by timeout; zero means no deadline
+func withRunTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(parent, timeout)
+	}
+	return context.WithCancel(parent)
+}
+
+func newValidator(ghClient github.UnifiedClient, llmClient *llm.Client, cfg *config.Config, gd *guidelines.Guidelines) *agent.Validator {
+	var profiles []guidelines.Profile
+	if len(cfg.Agent.GuidelinesProfiles) > 0 {
+		loaded, err := guidelines.LoadProfiles(cfg.Agent.GuidelinesProfiles)
+		if err != nil {
+			slog.Warn("failed to load guidelines profiles", "error", err)
+		}
+		profiles = loaded
+		for _, p := range profiles {
+			log.Printf("Loaded guidelines profile %q for label %q", p.Name, p.Label)
+		}
+	}
+
+	rules := agent.TaskFormatRules{
 		RequiredSections:     cfg.Agent.TaskFormatRules.RequiredSections,
 		MinDescriptionLength: cfg.Agent.TaskFormatRules.MinDescriptionLength,
 		RequireLabels:        cfg.Agent.TaskFormatRules.RequireLabels,
 		LabelPrefix:          cfg.Agent.TaskFormatRules.LabelPrefix,
-	}, guidelines)
+		TitlePattern:         cfg.Agent.TaskFormatRules.TitlePattern,
+		DefaultPriorityLabel: cfg.Agent.TaskFormatRules.DefaultPriorityLabel,
+		BodyTemplate:         cfg.Agent.TaskFormatRules.BodyTemplate,
+	}
+	if err := rules.Validate(); err != nil {
+		log.Fatalf("Invalid task format rules: %v", err)
+	}
 
+	return agent.NewValidatorWithOptions(ghClient, llmClient, rules, gd, agent.ValidatorOptions{
+		Profiles:       profiles,
+		OnLLMFailure:   cfg.Agent.OnLLMFailure,
+		Output:         cfg.Agent.ValidateOutput,
+		ReportAssignee: cfg.ReportAssignee(config.ReportValidation),
+		Identity:       cfg.Identity(),
+		SkipLabels:     cfg.Agent.SkipLabels,
+		StrictPrompts:  cfg.Agent.PromptsStrict,
+	})
+}
+
+func runValidate(ctx context.Context, ghClient github.UnifiedClient, llmClient *llm.Client, cfg *config.Config, issueRepo github.Repository, issueNumber int, guidelines *guidelines.Guidelines, format string) error {
+	validator := newValidator(ghClient, llmClient, cfg, guidelines)
+
+	summary := output.NewRunSummary("validate")
+	summary.NeedsHumanLabel = validator.NeedsHumanLabel()
+	summary.LLMBaseURL = cfg.LLM.LiteLLMBaseURL
+
+	var issues []*github.Issue
 	if issueNumber > 0 {
-		// Validate specific issue
-		// In project mode, we need owner/repo - for now, search all repos
-		// In repo mode, owner/repo can be empty
-		var issue *github.Issue
+		issue, err := findIssue(ctx, ghClient, issueRepo, issueNumber)
+		if err != nil {
+			return err
+		}
+
+		if validator.ReportOnly() {
+			// Report mode never edits issues; show what the report would list
+			explanation := validator.Explain(issue)
+			if explanation.Failed() == 0 {
+				fmt.Fprintf(progressOutput, "✅ Issue #%d is valid\n", issueNumber)
+			} else {
+				fmt.Fprintf(progressOutput, "⚠️  Issue #%d fails %d rules (run -mode=explain -issue=%d for details)\n", issueNumber, explanation.Failed(), issueNumber)
+			}
+			return nil
+		}
+		issues = []*github.Issue{issue}
+	} else {
+		// Validate all open issues, continuing with the repositories that
+		// could be listed if others failed
 		var err error
+		issues, err = ghClient.ListIssuesPartial(ctx, "open")
+		var partial *github.PartialListError
+		if errors.As(err, &partial) && !partial.AllFailed() {
+			for _, failure := range partial.Failures {
+				fmt.Fprintf(progressOutput, "⚠️  Skipping %s: %v\n", failure.Repo, failure.Err)
+				summary.FailedRepos = append(summary.FailedRepos, output.RepoError{Repo: failure.Repo, Error: failure.Err.Error()})
+			}
+		} else if err != nil {
+			return fmt.Errorf("failed to list issues: %w", err)
+		}
 
-		if ghClient.GetMode() == "project" {
-			// In project mode, we'd need to know which repo - for now, list all and find it
-			// This is a limitation - in production, you'd want to pass repo info
-			allIssues, listErr := ghClient.ListIssues(ctx, "all")
-			if listErr != nil {
-				return fmt.Errorf("failed to list issues: %w", listErr)
+		if validator.ReportOnly() {
+			count, err := validator.PostReport(ctx, issues)
+			if err != nil {
+				return err
 			}
-			found := false
-			for _, i := range allIssues {
-				if i.Number == issueNumber {
-					issue = i
-					found = true
-					break
-				}
+			fmt.Fprintf(progressOutput, "✅ Validation report complete. %d non-compliant issues.\n", count)
+			return nil
+		}
+
+		// Reports and digests the agents opened aren't tasks to validate
+		issues = agent.ExcludeAgentGenerated(issues)
+
+		fmt.Fprintf(progressOutput, "Validating %d open issues...\n", len(issues))
+	}
+
+	// Validate concurrently, then report in issue order
+	results := make([]*agent.ValidationResult, len(issues))
+	errs := make([]error, len(issues))
+	pool.ForEach(len(issues), cfg.Agent.Concurrency, func(i int) string {
+		return repositoryKey(issues[i])
+	}, func(i int) {
+		results[i], errs[i] = validator.Validate(ctx, issues[i])
+	})
+
+	for i, issue := range issues {
+		summary.Checked++
+		result, err := results[i], errs[i]
+		if err != nil {
+			var llmErr *agent.LLMError
+			if errors.As(err, &llmErr) {
+				summary.LLMErrors = append(summary.LLMErrors, issue.Number)
+			} else {
+				summary.Errors = append(summary.Errors, output.IssueError{Issue: issue.Number, Error: err.Error()})
 			}
-			if !found {
-				return fmt.Errorf("issue #%d not found in project", issueNumber)
+			fmt.Fprintf(progressOutput, "Error validating issue #%d: %v\n", issue.Number, err)
+			continue
+		}
+
+		switch {
+		case result.Skipped != "":
+			summary.Protected = append(summary.Protected, issue.Number)
+			if issueNumber > 0 {
+				fmt.Fprintf(progressOutput, "Issue #%d %s\n", issue.Number, result.Skipped)
 			}
-		} else {
-			// Repo mode - owner/repo not needed
-			issue, err = ghClient.GetIssue(ctx, "", "", issueNumber)
-			if err != nil {
-				return fmt.Errorf("failed to get issue: %w", err)
+		case result.Valid:
+			summary.Valid++
+			if issueNumber > 0 {
+				fmt.Fprintf(progressOutput, "✅ Issue #%d is valid\n", issue.Number)
 			}
+		case result.Fixed:
+			summary.Fixed = append(summary.Fixed, issue.Number)
+			fmt.Fprintf(progressOutput, "Fixed issue #%d: %s\n", issue.Number, issue.Title)
+			if issueNumber > 0 {
+				fmt.Fprintf(progressOutput, "Comment: %s\n", result.Comment)
+			}
+		case result.NeedsHuman:
+			summary.NeedsHuman = append(summary.NeedsHuman, issue.Number)
+			fmt.Fprintf(progressOutput, "Reported violations on issue #%d: %s\n", issue.Number, issue.Title)
+		}
+	}
+
+	if issueNumber == 0 {
+		fmt.Fprintf(progressOutput, "✅ Validation complete. Fixed %d issues.\n", len(summary.Fixed))
+		if n := len(summary.FailedRepos); n > 0 {
+			fmt.Fprintf(progressOutput, "⚠️  %d repositories could not be listed:\n", n)
+			for _, failure := range summary.FailedRepos {
+				fmt.Fprintf(progressOutput, "  - %s: %s\n", failure.Repo, failure.Error)
+			}
+		}
+	}
+
+	if err := output.Write(resultOutput, format, summary); err != nil {
+		return err
+	}
+
+	// A single-issue run should still fail loudly when that issue errored
+	if issueNumber > 0 && (len(summary.Errors) > 0 || len(summary.LLMErrors) > 0) {
+		return fmt.Errorf("failed to validate issue #%d", issueNumber)
+	}
+	return nil
+}
+
+// repositoryKey returns the owner/repo an issue belongs to, used to bound
+// concurrent work per repository
+func repositoryKey(issue *github.Issue) string {
+	owner, repo := github.ParseRepoFromURL(issue.URL)
+	return owner + "/" + repo
+}
+
+// findIssue looks up an issue by number. In project mode the issue is fetched
+// from repo when it's given; otherwise all project issues are searched.
+func findIssue(ctx context.Context, ghClient github.UnifiedClient, repo github.Repository, issueNumber int) (*github.Issue, error) {
+	if ghClient.GetMode() != "project" {
+		// Repo mode - owner/repo not needed
+		issue, err := ghClient.GetIssue(ctx, "", "", issueNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get issue: %w", err)
 		}
+		return issue, nil
+	}
 
-		valid, comment, err := validator.ValidateAndFix(ctx, issue)
+	if repo.Owner != "" && repo.Name != "" {
+		issue, err := ghClient.GetIssue(ctx, repo.Owner, repo.Name, issueNumber)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("failed to get issue %s/%s#%d: %w", repo.Owner, repo.Name, issueNumber, err)
 		}
+		return issue, nil
+	}
 
-		if valid {
-			fmt.Printf("✅ Issue #%d is valid\n", issueNumber)
-		} else {
-			fmt.Printf("⚠️  Issue #%d was fixed\n", issueNumber)
-			fmt.Printf("Comment: %s\n", comment)
+	allIssues, err := ghClient.ListIssues(ctx, "all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+	for _, issue := range allIssues {
+		if issue.Number == issueNumber {
+			return issue, nil
 		}
+	}
+	return nil, fmt.Errorf("issue #%d not found in project", issueNumber)
+}
+
+// runEstimate lists the issues that would trigger LLM calls and the projected
+// token usage and cost, without calling the LLM or modifying anything
+func runEstimate(ctx context.Context, ghClient github.UnifiedClient, llmClient *llm.Client, cfg *config.Config, issueRepo github.Repository, issueNumber int, gd *guidelines.Guidelines) error {
+	var issues []*github.Issue
+	if issueNumber > 0 {
+		issue, err := findIssue(ctx, ghClient, issueRepo, issueNumber)
+		if err != nil {
+			return err
+		}
+		issues = []*github.Issue{issue}
 	} else {
-		// Validate all open issues
-		issues, err := ghClient.ListIssues(ctx, "open")
+		var err error
+		issues, err = ghClient.ListIssues(ctx, "open")
 		if err != nil {
 			return fmt.Errorf("failed to list issues: %w", err)
 		}
+	}
 
-		fmt.Printf("Validating %d open issues...\n", len(issues))
-		fixed := 0
-		for _, issue := range issues {
-			valid, _, err := validator.ValidateAndFix(ctx, issue)
-			if err != nil {
-				fmt.Printf("Error validating issue #%d: %v\n", issue.Number, err)
-				continue
-			}
-			if !valid {
-				fixed++
-				fmt.Printf("Fixed issue #%d: %s\n", issue.Number, issue.Title)
-			}
+	estimate := newValidator(ghClient, llmClient, cfg, gd).Estimate(issues)
+
+	fmt.Fprintf(progressOutput, "Checked %d issues, %d would be fixed with the LLM:\n\n", estimate.Checked, estimate.Calls())
+	for _, item := range estimate.Issues {
+		fmt.Fprintf(progressOutput, "  #%d %s — %d violation(s), ~%d tokens\n",
+			item.Issue.Number, item.Issue.Title, len(item.Violations), item.PromptTokens+item.CompletionTokens)
+	}
+	fmt.Fprintf(progressOutput, "\nProjected: %d LLM calls, ~%d tokens, ~$%.2f (at $%.4f per 1k tokens, model %s)\n",
+		estimate.Calls(), estimate.Tokens(), estimate.Cost(cfg.LLM.PricePer1K), cfg.LLM.PricePer1K, cfg.LLM.Model)
+	return nil
+}
+
+// runExplain prints each validation rule with pass/fail and evidence for one
+// issue without modifying it
+func runExplain(ctx context.Context, ghClient github.UnifiedClient, llmClient *llm.Client, cfg *config.Config, issueRepo github.Repository, issueNumber int, gd *guidelines.Guidelines) error {
+	if issueNumber <= 0 {
+		return fmt.Errorf("explain mode requires -issue")
+	}
+
+	issue, err := findIssue(ctx, ghClient, issueRepo, issueNumber)
+	if err != nil {
+		return err
+	}
+
+	explanation := newValidator(ghClient, llmClient, cfg, gd).Explain(issue)
+
+	fmt.Fprintf(progressOutput, "Validation rules for issue #%d: %s\n", issue.Number, issue.Title)
+	if len(explanation.Profiles) > 0 {
+		fmt.Fprintf(progressOutput, "Guidelines profile(s): %s\n", strings.Join(explanation.Profiles, ", "))
+	}
+	fmt.Fprintln(progressOutput)
+	for _, result := range explanation.Results {
+		status := "✅ PASS"
+		if !result.Passed {
+			status = "❌ FAIL"
+		}
+		fmt.Fprintf(progressOutput, "%s  %s — %s (from %s)\n", status, result.Rule, result.Evidence, result.Source)
+	}
+	fmt.Fprintln(progressOutput)
+
+	if failed := explanation.Failed(); failed > 0 {
+		fmt.Fprintf(progressOutput, "Issue #%d fails %d of %d rules\n", issue.Number, failed, len(explanation.Results))
+	} else {
+		fmt.Fprintf(progressOutput, "Issue #%d passes all %d rules\n", issue.Number, len(explanation.Results))
+	}
+	return nil
+}
+
+// runPreview prints the violations of one issue and a unified diff of the
+// body the validator would write, without updating the issue or commenting
+func runPreview(ctx context.Context, ghClient github.UnifiedClient, llmClient *llm.Client, cfg *config.Config, issueRepo github.Repository, issueNumber int, gd *guidelines.Guidelines) error {
+	if issueNumber <= 0 {
+		return fmt.Errorf("-explain requires -issue")
+	}
+
+	issue, err := findIssue(ctx, ghClient, issueRepo, issueNumber)
+	if err != nil {
+		return err
+	}
+
+	preview, err := newValidator(ghClient, llmClient, cfg, gd).Preview(ctx, issue)
+	if err != nil {
+		return err
+	}
+	if len(preview.Violations) == 0 {
+		fmt.Fprintf(progressOutput, "✅ Issue #%d is valid; nothing to change\n", issue.Number)
+		return nil
+	}
+
+	fmt.Fprintf(progressOutput, "Issue #%d: %s\n", issue.Number, issue.Title)
+	for _, violation := range preview.Violations {
+		fmt.Fprintf(progressOutput, "  - %s\n", violation.Message)
+	}
+	if !preview.Changed() {
+		fmt.Fprintln(progressOutput, "No body changes; these violations aren't fixed by rewriting the body")
+		return nil
+	}
+	if len(preview.Remaining) > 0 {
+		fmt.Fprintf(progressOutput, "⚠️  The rewrite still has violations, so validate would leave the body unchanged: %s\n", strings.Join(preview.Remaining, "; "))
+	}
+	fmt.Fprint(progressOutput, output.UnifiedDiff(fmt.Sprintf("issue #%d (current)", issue.Number), fmt.Sprintf("issue #%d (proposed)", issue.Number), preview.Original, preview.Proposed))
+	return nil
+}
+
+// runHealthcheck verifies GitHub authentication and prints the identity the
+// agent acts as
+func runHealthcheck(ctx context.Context, ghClient github.UnifiedClient, cfg *config.Config) error {
+	login, isApp, err := ghClient.WhoAmI(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to authenticate with GitHub: %w", err)
+	}
+
+	kind := "user"
+	if isApp {
+		kind = "GitHub App"
+	}
+	fmt.Fprintf(progressOutput, "✅ Authenticated as %s (%s)\n", login, kind)
+	if ghClient.GetMode() == "project" {
+		fmt.Fprintf(progressOutput, "Mode: project %s (%d repositories)\n", cfg.GitHub.ProjectID, len(cfg.GitHub.Repos))
+	} else {
+		fmt.Fprintf(progressOutput, "Mode: repo %s/%s\n", cfg.GitHub.Owner, cfg.GitHub.Repo)
+	}
+	fmt.Fprintf(progressOutput, "LLM: %s at %s\n", cfg.LLM.Model, cfg.LLM.LiteLLMBaseURL)
+	return nil
+}
+
+// runLintAgents reports the problems in every plugin agent file and fails if
+// any file has errors
+func runLintAgents(cfg *config.Config, format string) error {
+	results, err := plugins.LintPlugins(cfg.Agent.PluginsPath, plugins.LoadOptions{
+		StrictEnv: cfg.Agent.StrictAgentEnv,
+	}, mcp.NewPromptLoader(cfg))
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, result := range results {
+		if !result.OK() {
+			failed++
+		}
+		if format == output.FormatJSON {
+			continue
+		}
+		status := "✅"
+		if !result.OK() {
+			status = "❌"
+		}
+		name := ""
+		if result.Name != "" {
+			name = fmt.Sprintf(" (%s)", result.Name)
+		}
+		fmt.Fprintf(resultOutput, "%s %s%s\n", status, result.Path, name)
+		for _, problem := range result.Errors {
+			fmt.Fprintf(resultOutput, "   error: %s\n", problem)
+		}
+		for _, problem := range result.Warnings {
+			fmt.Fprintf(resultOutput, "   warning: %s\n", problem)
 		}
-		fmt.Printf("✅ Validation complete. Fixed %d issues.\n", fixed)
 	}
 
+	if results == nil {
+		results = []plugins.LintResult{}
+	}
+	if err := printResult(format, results, fmt.Sprintf("Linted %d agent files in %s: %d with errors", len(results), cfg.Agent.PluginsPath, failed)); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d agent files have errors", failed)
+	}
 	return nil
 }
 
-func runMonitorOnce(ctx context.Context, ghClient github.UnifiedClient, llmClient *llm.Client, cfg *config.Config) error {
-	monitor := agent.NewMonitor(ghClient, llmClient, cfg.Agent.StaleTaskThresholdDays)
-	fmt.Println("Checking for stale tasks...")
-	return monitor.CheckStaleTasks(ctx)
+func newMonitor(ghClient github.UnifiedClient, llmClient *llm.Client, cfg *config.Config) *agent.Monitor {
+	return agent.NewMonitorWithOptions(ghClient, llmClient, cfg.Agent.StaleTaskThresholdDays, agent.MonitorOptions{
+		Output:                 cfg.Agent.MonitorOutput,
+		DigestAssignee:         cfg.ReportAssignee(config.ReportStaleDigest),
+		AutoCloseAfterDays:     cfg.Agent.AutoCloseAfterDays,
+		GracePeriodDays:        cfg.Agent.StaleGraceDays,
+		BusinessDaysOnly:       cfg.Agent.BusinessDaysOnly,
+		ActivityFromEvents:     cfg.Agent.ActivityFromEvents,
+		Holidays:               cfg.Agent.Holidays,
+		SkipLabels:             cfg.Agent.SkipLabels,
+		EscalateAfterReminders: cfg.Agent.EscalateAfterReminders,
+		EscalateTo:             strings.TrimPrefix(cfg.Agent.EscalateTo, "@"),
+		EscalateAction:         cfg.Agent.EscalateAction,
+		Identity:               cfg.Identity(),
+		StrictPrompts:          cfg.Agent.PromptsStrict,
+	})
 }
 
-func runMonitorDaemon(ctx context.Context, ghClient github.UnifiedClient, llmClient *llm.Client, cfg *config.Config) {
-	monitor := agent.NewMonitor(ghClient, llmClient, cfg.Agent.StaleTaskThresholdDays)
+func runMonitorOnce(ctx context.Context, ghClient github.UnifiedClient, llmClient *llm.Client, cfg *config.Config, format string) error {
+	monitor := newMonitor(ghClient, llmClient, cfg)
+	fmt.Fprintln(progressOutput, "Checking for stale tasks...")
+	result, err := monitor.Check(ctx)
+	if err != nil {
+		return err
+	}
+	return printResult(format, result, fmt.Sprintf("✅ Monitoring complete. %d stale, %d reminded, %d escalated, %d skipped, %d closed, %d protected.",
+		len(result.Stale), len(result.Reminded), len(result.Escalated), len(result.Skipped), len(result.Closed), len(result.Protected)))
+}
 
-	// Handle graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+func runMonitorDaemon(ctx context.Context, ghClient github.UnifiedClient, llmClient *llm.Client, cfg *config.Config, pluginAgents []*plugins.PluginAgent, gd *guidelines.Guidelines) {
+	monitor := newMonitor(ghClient, llmClient, cfg)
+
+	// Run plugin agents on their trigger schedules alongside the monitor
+	mcpInterface := mcp.NewMCPInterface(ghClient, pluginAgents, llmClient, gd, cfg)
+	if executor := mcpInterface.Executor(); executor != nil {
+		scheduler := plugins.NewSchedulerWithOptions(ctx, executor, pluginAgents, plugins.SchedulerOptions{
+			RunTimeout: cfg.Agent.RunTimeout,
+		})
+		if scheduler.Jobs() > 0 {
+			scheduler.Start()
+			defer func() { <-scheduler.Stop().Done() }()
+			fmt.Fprintf(progressOutput, "Scheduled %d plugin agent jobs\n", scheduler.Jobs())
+		}
+	}
 
 	ticker := time.NewTicker(cfg.Agent.CheckInterval)
 	defer ticker.Stop()
 
-	fmt.Printf("Starting monitor daemon (checking every %v)...\n", cfg.Agent.CheckInterval)
+	fmt.Fprintf(progressOutput, "Starting monitor daemon (checking every %v)...\n", cfg.Agent.CheckInterval)
 
-	// Run immediately
-	if err := monitor.CheckStaleTasks(ctx); err != nil {
-		log.Printf("Error checking stale tasks: %v", err)
+	// Each check gets a fresh deadline so one hung check doesn't stall the next
+	check := func() {
+		checkCtx, cancel := withRunTimeout(ctx, cfg.Agent.RunTimeout)
+		defer cancel()
+		if err := monitor.CheckStaleTasks(checkCtx); err != nil {
+			slog.Error("failed to check stale tasks", "error", err)
+		}
 	}
 
+	// Run immediately
+	check()
+
 	for {
 		select {
 		case <-ticker.C:
-			fmt.Println("Checking for stale tasks...")
-			if err := monitor.CheckStaleTasks(ctx); err != nil {
-				log.Printf("Error checking stale tasks: %v", err)
+			// Start each check fresh so edited issues aren't answered from cache
+			// and edited prompt templates apply without a restart
+			llmClient.ClearCache()
+			for _, reload := range []func() error{monitor.ReloadPrompts, mcpInterface.ReloadPrompts} {
+				if err := reload(); err != nil {
+					slog.Warn("keeping previous prompt templates", "error", err)
+				}
 			}
-		case <-sigChan:
-			fmt.Println("\nShutting down monitor daemon...")
+			fmt.Fprintln(progressOutput, "Checking for stale tasks...")
+			check()
+		case <-ctx.Done():
+			fmt.Fprintln(progressOutput, "\nShutting down monitor daemon...")
 			return
 		}
 	}
 }
 
-func runRoast(ctx context.Context, ghClient github.UnifiedClient, llmClient *llm.Client) error {
-	roaster := agent.NewRoaster(ghClient, llmClient)
-	fmt.Println("Roasting your product and generating suggestions...")
-	return roaster.RoastAndSuggest(ctx)
+func runChecklistMonitor(ctx context.Context, ghClient github.UnifiedClient, cfg *config.Config) error {
+	stateStore, err := store.NewFileStore(cfg.Agent.StatePath)
+	if err != nil {
+		return fmt.Errorf("failed to open state store: %w", err)
+	}
+
+	monitor := agent.NewChecklistMonitorWithOptions(ghClient, stateStore, cfg.Agent.ChecklistStaleDays, cfg.Agent.ChecklistMinItems,
+		agent.ChecklistMonitorOptions{Identity: cfg.Identity(), SkipLabels: cfg.Agent.SkipLabels})
+	fmt.Fprintln(progressOutput, "Checking for stalled checklists...")
+	return monitor.CheckStaleChecklists(ctx)
+}
+
+func runRoast(ctx context.Context, ghClient github.UnifiedClient, llmClient *llm.Client, cfg *config.Config, format string) error {
+	sample, err := sampling.Parse(cfg.Agent.Sample)
+	if err != nil {
+		return fmt.Errorf("invalid SAMPLE: %w", err)
+	}
+	roaster := agent.NewRoasterWithOptions(ghClient, llmClient, agent.RoasterOptions{
+		Sample:        sample,
+		Assignee:      cfg.ReportAssignee(config.ReportRoast),
+		Cooldown:      time.Duration(cfg.Agent.RoastCooldownDays) * 24 * time.Hour,
+		StrictPrompts: cfg.Agent.PromptsStrict,
+	})
+	fmt.Fprintln(progressOutput, "Roasting your product and generating suggestions...")
+	result, err := roaster.Roast(ctx)
+	if err != nil {
+		return err
+	}
+	summary := ""
+	if result.Status == agent.RoastSkipped {
+		summary = fmt.Sprintf("⏭️  Roast %s: #%d covers the same backlog", result.Status, result.Issue)
+	}
+	return printResult(format, result, summary)
+}
+
+// resultOutput receives command results, and progressOutput everything else
+// printed along the way. Progress moves to stderr when stdout carries a JSON
+// result or MCP protocol messages.
+var (
+	resultOutput   io.Writer = os.Stdout
+	progressOutput io.Writer = os.Stdout
+)
+
+// printResult writes a command's result: the result itself as indented JSON
+// with -output=json, otherwise the human-readable summary, if any
+func printResult(format string, result interface{}, summary string) error {
+	if format == output.FormatJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode result: %w", err)
+		}
+		_, err = fmt.Fprintln(resultOutput, string(data))
+		return err
+	}
+	if summary != "" {
+		fmt.Fprintln(resultOutput, summary)
+	}
+	return nil
 }
 
-func runAll(ctx context.Context, ghClient github.UnifiedClient, llmClient *llm.Client, cfg *config.Config, issueNumber int, guidelines *guidelines.Guidelines) error {
-	fmt.Println("Running all agent tasks...\n")
+func runAll(ctx context.Context, ghClient github.UnifiedClient, llmClient *llm.Client, cfg *config.Config, issueRepo github.Repository, issueNumber int, guidelines *guidelines.Guidelines) error {
+	fmt.Fprintln(progressOutput, "Running all agent tasks...")
+	fmt.Fprintln(progressOutput)
 
 	// 1. Validate
-	fmt.Println("1. Validating tasks...")
-	if err := runValidate(ctx, ghClient, llmClient, cfg, issueNumber, guidelines); err != nil {
+	fmt.Fprintln(progressOutput, "1. Validating tasks...")
+	if err := runValidate(ctx, ghClient, llmClient, cfg, issueRepo, issueNumber, guidelines, output.FormatText); err != nil {
 		log.Printf("Validation error: %v", err)
 	}
 
 	// 2. Monitor
-	fmt.Println("\n2. Checking for stale tasks...")
-	if err := runMonitorOnce(ctx, ghClient, llmClient, cfg); err != nil {
+	fmt.Fprintln(progressOutput, "\n2. Checking for stale tasks...")
+	if err := runMonitorOnce(ctx, ghClient, llmClient, cfg, output.FormatText); err != nil {
 		log.Printf("Monitoring error: %v", err)
 	}
 
 	// 3. Roast
-	fmt.Println("\n3. Generating product roast and suggestions...")
-	if err := runRoast(ctx, ghClient, llmClient); err != nil {
+	fmt.Fprintln(progressOutput, "\n3. Generating product roast and suggestions...")
+	if err := runRoast(ctx, ghClient, llmClient, cfg, output.FormatText); err != nil {
 		log.Printf("Roast error: %v", err)
 	}
 
-	fmt.Println("\n✅ All tasks completed!")
+	fmt.Fprintln(progressOutput, "\n✅ All tasks completed!")
 	return nil
 }
 
-func runMCP(ctx context.Context, ghClient github.UnifiedClient, pluginAgents []*plugins.PluginAgent, agentName, workflowName string, issueNumber int, llmClient *llm.Client, guidelines *guidelines.Guidelines, cfg *config.Config) error {
+func runMCP(ctx context.Context, ghClient github.UnifiedClient, pluginAgents []*plugins.PluginAgent, agentName, workflowName string, issueNumber int, extraParams mcp.Params, llmClient *llm.Client, guidelines *guidelines.Guidelines, cfg *config.Config, format string) error {
 	mcpInterface := mcp.NewMCPInterface(ghClient, pluginAgents, llmClient, guidelines, cfg)
 
+	// -param values go in the same map as issue_number; -issue wins over a
+	// -param issue_number when both are given
+	params := map[string]interface{}{}
+	for key, value := range extraParams {
+		params[key] = value
+	}
+	if _, ok := params["issue_number"]; !ok || issueNumber != 0 {
+		params["issue_number"] = issueNumber
+	}
+
 	if workflowName != "" {
 		// Execute workflow
-		params := map[string]interface{}{
-			"issue_number": issueNumber,
-		}
-
 		result, err := mcpInterface.ExecuteWorkflow(ctx, workflowName, params)
 		if err != nil {
 			return fmt.Errorf("failed to execute workflow: %w", err)
 		}
 
 		resultJSON, _ := json.MarshalIndent(result, "", "  ")
-		fmt.Printf("Workflow '%s' executed successfully:\n%s\n", workflowName, string(resultJSON))
-		return nil
+		return printResult(format, result, fmt.Sprintf("Workflow '%s' executed successfully:\n%s", workflowName, string(resultJSON)))
 	}
 
 	if agentName != "" {
 		// Execute agent
-		params := map[string]interface{}{
-			"issue_number": issueNumber,
-		}
-
 		result, err := mcpInterface.ExecuteAgent(ctx, agentName, params)
 		if err != nil {
 			return fmt.Errorf("failed to execute agent: %w", err)
 		}
 
 		resultJSON, _ := json.MarshalIndent(result, "", "  ")
-		fmt.Printf("Agent '%s' executed successfully:\n%s\n", agentName, string(resultJSON))
-		return nil
+		return printResult(format, result, fmt.Sprintf("Agent '%s' executed successfully:\n%s", agentName, string(resultJSON)))
 	}
 
 	// List available agents and workflows
-	fmt.Println("Available Agents:")
+	fmt.Fprintln(progressOutput, "Available Agents:")
 	for _, name := range mcpInterface.ListAgents() {
-		fmt.Printf("  - %s\n", name)
+		fmt.Fprintf(progressOutput, "  - %s\n", name)
 	}
 
-	fmt.Println("\nAvailable Workflows:")
+	fmt.Fprintln(progressOutput, "\nAvailable Workflows:")
 	for _, name := range mcpInterface.ListWorkflows() {
-		fmt.Printf("  - %s\n", name)
+		fmt.Fprintf(progressOutput, "  - %s\n", name)
 	}
 
-	fmt.Println("\nUsage:")
-	fmt.Println("  Execute agent: -mode=mcp -agent='Agent Name' -issue=123")
-	fmt.Println("  Execute workflow: -mode=mcp -workflow='Workflow Name' -issue=123")
+	fmt.Fprintln(progressOutput, "\nUsage:")
+	fmt.Fprintln(progressOutput, "  Execute agent: -mode=mcp -agent='Agent Name' -issue=123")
+	fmt.Fprintln(progressOutput, "  Execute workflow: -mode=mcp -workflow='Workflow Name' -issue=123")
+	fmt.Fprintln(progressOutput, "  Pass parameters: -mode=mcp -agent='Agent Name' -param label=needs-review -param days=14")
+	fmt.Fprintln(progressOutput, "  Describe agents as JSON: -mode=mcp -list-json [-verbose]")
 
 	return nil
 }
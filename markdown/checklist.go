@@ -0,0 +1,88 @@
+// Package markdown provides helpers for inspecting GitHub issue bodies
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+// checklistItemPattern matches GitHub task list items such as "- [ ] todo" or "* [x] done"
+var checklistItemPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\]\s+(.*)$`)
+
+// ChecklistItem is a single task list entry in an issue body
+type ChecklistItem struct {
+	Text    string
+	Checked bool
+	Line    int // 1-based line number in the body
+}
+
+// Checklist holds all task list items found in an issue body
+type Checklist struct {
+	Items []ChecklistItem
+}
+
+// ParseChecklist extracts task list items from a markdown body.
+// Items inside fenced code blocks are ignored.
+func ParseChecklist(body string) Checklist {
+	var checklist Checklist
+	inFence := false
+
+	for i, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		matches := checklistItemPattern.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+
+		checklist.Items = append(checklist.Items, ChecklistItem{
+			Text:    strings.TrimSpace(matches[2]),
+			Checked: matches[1] != " ",
+			Line:    i + 1,
+		})
+	}
+
+	return checklist
+}
+
+// Total returns the number of checklist items
+func (c Checklist) Total() int {
+	return len(c.Items)
+}
+
+// Done returns the number of checked items
+func (c Checklist) Done() int {
+	done := 0
+	for _, item := range c.Items {
+		if item.Checked {
+			done++
+		}
+	}
+	return done
+}
+
+// Remaining returns the unchecked items in body order
+func (c Checklist) Remaining() []ChecklistItem {
+	var remaining []ChecklistItem
+	for _, item := range c.Items {
+		if !item.Checked {
+			remaining = append(remaining, item)
+		}
+	}
+	return remaining
+}
+
+// Percent returns the completion percentage (0 when there are no items)
+func (c Checklist) Percent() float64 {
+	if len(c.Items) == 0 {
+		return 0
+	}
+	return float64(c.Done()) / float64(len(c.Items)) * 100
+}
@@ -0,0 +1,34 @@
+package markdown
+
+import "testing"
+
+func TestParseChecklist(t *testing.T) {
+	body := "## Tasks\n\n- [x] Design API\n- [ ] Implement handler\n* [X] Write docs\n1. [ ] Release\n\n```\n- [ ] not a real item\n```\n\nSome prose with [ ] brackets"
+
+	checklist := ParseChecklist(body)
+
+	if checklist.Total() != 4 {
+		t.Fatalf("Total() = %d, want 4", checklist.Total())
+	}
+	if checklist.Done() != 2 {
+		t.Errorf("Done() = %d, want 2", checklist.Done())
+	}
+
+	remaining := checklist.Remaining()
+	if len(remaining) != 2 || remaining[0].Text != "Implement handler" || remaining[1].Text != "Release" {
+		t.Errorf("Remaining() = %+v, want [Implement handler, Release]", remaining)
+	}
+	if remaining[0].Line != 4 {
+		t.Errorf("Remaining()[0].Line = %d, want 4", remaining[0].Line)
+	}
+	if checklist.Percent() != 50 {
+		t.Errorf("Percent() = %v, want 50", checklist.Percent())
+	}
+}
+
+func TestParseChecklist_Empty(t *testing.T) {
+	checklist := ParseChecklist("No tasks here")
+	if checklist.Total() != 0 || checklist.Percent() != 0 {
+		t.Errorf("expected empty checklist, got %+v", checklist)
+	}
+}
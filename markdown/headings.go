@@ -0,0 +1,89 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+// atxHeadingPattern matches "## Title" headings, with optional closing hashes
+var atxHeadingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+
+// setextUnderlinePattern matches the "===" or "---" line under a setext heading
+var setextUnderlinePattern = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+
+// Heading is a markdown heading in an issue body
+type Heading struct {
+	Text  string
+	Level int // 1 for "#", 2 for "##" and so on
+	Line  int // 1-based line number in the body
+}
+
+// ParseHeadings extracts ATX ("## Title") and setext (underlined) headings
+// from a markdown body. Headings inside fenced code blocks are ignored.
+func ParseHeadings(body string) []Heading {
+	var headings []Heading
+	inFence := false
+
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || trimmed == "" {
+			continue
+		}
+
+		if matches := atxHeadingPattern.FindStringSubmatch(line); matches != nil {
+			headings = append(headings, Heading{Text: strings.TrimSpace(matches[2]), Level: len(matches[1]), Line: i + 1})
+			continue
+		}
+		if i+1 < len(lines) && !isBlockStart(trimmed) {
+			if underline := setextUnderlinePattern.FindStringSubmatch(lines[i+1]); underline != nil {
+				level := 2
+				if underline[1][0] == '=' {
+					level = 1
+				}
+				headings = append(headings, Heading{Text: trimmed, Level: level, Line: i + 1})
+			}
+		}
+	}
+
+	return headings
+}
+
+// isBlockStart reports whether a line starts a list item or quote, which
+// can't be a setext heading
+func isBlockStart(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") ||
+		strings.HasPrefix(trimmed, "+ ") || strings.HasPrefix(trimmed, ">") ||
+		setextUnderlinePattern.MatchString(trimmed)
+}
+
+// FindSection returns the first heading named name or one of aliases,
+// ignoring case, emphasis and a trailing colon, so "## Description" and
+// "### **description:**" both match "Description". Mentions of the name in
+// ordinary text don't count.
+func FindSection(body, name string, aliases ...string) (Heading, bool) {
+	want := map[string]bool{normalizeHeading(name): true}
+	for _, alias := range aliases {
+		want[normalizeHeading(alias)] = true
+	}
+	for _, heading := range ParseHeadings(body) {
+		if want[normalizeHeading(heading.Text)] {
+			return heading, true
+		}
+	}
+	return Heading{}, false
+}
+
+// normalizeHeading lowercases heading text and strips emphasis and a
+// trailing colon
+func normalizeHeading(text string) string {
+	text = strings.TrimSpace(text)
+	text = strings.Trim(text, "*_")
+	text = strings.TrimSuffix(strings.TrimSpace(text), ":")
+	text = strings.Trim(text, "*_")
+	return strings.ToLower(strings.TrimSpace(text))
+}
@@ -0,0 +1,43 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHeadings(t *testing.T) {
+	body := "# Title\nSome description of the problem.\n\n## Acceptance Criteria ##\n- done\n\n```\n## Not a heading\n```\nNotes\n-----\n- list item\n---\n#hashtag"
+	want := []Heading{
+		{Text: "Title", Level: 1, Line: 1},
+		{Text: "Acceptance Criteria", Level: 2, Line: 4},
+		{Text: "Notes", Level: 2, Line: 10},
+	}
+	if got := ParseHeadings(body); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseHeadings() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindSection(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantLine int
+	}{
+		{"heading", "## Description\nLogin fails", 1},
+		{"nested heading with emphasis and colon", "Intro\n### **description:**\nLogin fails", 2},
+		{"setext heading", "Description\n===========\nLogin fails", 1},
+		{"inline mention", "This description explains that login fails", 0},
+		{"bold line", "**Description**\nLogin fails", 0},
+		{"longer heading", "## Description of the fix\nLogin fails", 0},
+		{"inside code block", "```\n## Description\n```", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			heading, ok := FindSection(tt.body, "Description")
+			if ok != (tt.wantLine > 0) || heading.Line != tt.wantLine {
+				t.Errorf("FindSection() = %+v, %v, want line %d", heading, ok, tt.wantLine)
+			}
+		})
+	}
+}
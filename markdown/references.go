@@ -0,0 +1,101 @@
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// closingKeywordPattern matches GitHub closing keywords followed by an issue
+// reference, e.g. "Closes #12", "fixes owner/repo#34", "Resolved: #5"
+var closingKeywordPattern = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?)\b:?\s+(?:([\w.-]+)/([\w.-]+))?#(\d+)\b`)
+
+// issueReferencePattern matches issue references in the forms GitHub links:
+// issue and pull request URLs, owner/repo#12, GH-12 and a bare #12. Bare and
+// cross-repo references must follow whitespace or punctuation so "&#12;" and
+// URL fragments aren't taken for issues.
+var issueReferencePattern = regexp.MustCompile(`https?://[^\s/]+/([\w-]+)/([\w.-]+)/(?:issues|pull)/(\d+)\b|(?:^|[\s(\[{,;:])([\w-]+)/([\w.-]+)#(\d+)\b|(?i:\bGH-(\d+))\b|(?:^|[\s(\[{,;:])#(\d+)\b`)
+
+// Reference is an issue reference found in markdown text.
+// Owner and Repo are empty for same-repository references.
+type Reference struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// Matches reports whether the reference points to the given issue.
+// References without an explicit repository match any owner/repo.
+func (r Reference) Matches(owner, repo string, number int) bool {
+	if r.Number != number {
+		return false
+	}
+	if r.Owner == "" && r.Repo == "" {
+		return true
+	}
+	return r.Owner == owner && r.Repo == repo
+}
+
+// String formats the reference as GitHub writes it, e.g. "#12" or "octo/api#12"
+func (r Reference) String() string {
+	if r.Owner == "" && r.Repo == "" {
+		return fmt.Sprintf("#%d", r.Number)
+	}
+	return fmt.Sprintf("%s/%s#%d", r.Owner, r.Repo, r.Number)
+}
+
+// IssueReferences returns every issue referenced in text, in order of first
+// appearance and without duplicates
+func IssueReferences(text string) []Reference {
+	var refs []Reference
+	seen := make(map[Reference]bool)
+
+	for _, match := range issueReferencePattern.FindAllStringSubmatch(text, -1) {
+		var ref Reference
+		switch {
+		case match[3] != "":
+			ref.Owner, ref.Repo, ref.Number = match[1], match[2], atoi(match[3])
+		case match[6] != "":
+			ref.Owner, ref.Repo, ref.Number = match[4], match[5], atoi(match[6])
+		case match[7] != "":
+			ref.Number = atoi(match[7])
+		default:
+			ref.Number = atoi(match[8])
+		}
+		if ref.Number == 0 || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		refs = append(refs, ref)
+	}
+
+	return refs
+}
+
+// atoi parses a matched run of digits, returning 0 if it overflows
+func atoi(digits string) int {
+	n, _ := strconv.Atoi(digits)
+	return n
+}
+
+// ClosingReferences returns the issues a PR body says it closes via GitHub's
+// closing keywords (close, closes, closed, fix, fixes, fixed, resolve, resolves, resolved)
+func ClosingReferences(text string) []Reference {
+	var refs []Reference
+	seen := make(map[Reference]bool)
+
+	for _, match := range closingKeywordPattern.FindAllStringSubmatch(text, -1) {
+		number, err := strconv.Atoi(match[3])
+		if err != nil {
+			continue
+		}
+		ref := Reference{Owner: match[1], Repo: match[2], Number: number}
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		refs = append(refs, ref)
+	}
+
+	return refs
+}
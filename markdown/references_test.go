@@ -0,0 +1,110 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClosingReferences(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Reference
+	}{
+		{
+			name: "closes keyword",
+			text: "This PR closes #12.",
+			want: []Reference{{Number: 12}},
+		},
+		{
+			name: "keyword variants and colon",
+			text: "Fixes: #3\nResolved #4\nfix #3",
+			want: []Reference{{Number: 3}, {Number: 4}},
+		},
+		{
+			name: "cross-repo reference",
+			text: "Closes octo/api#99",
+			want: []Reference{{Owner: "octo", Repo: "api", Number: 99}},
+		},
+		{
+			name: "mention without keyword",
+			text: "Related to #7, see also #8",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClosingReferences(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ClosingReferences() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReference_Matches(t *testing.T) {
+	if !(Reference{Number: 5}).Matches("octo", "api", 5) {
+		t.Error("same-repo reference should match any repository")
+	}
+	if (Reference{Owner: "octo", Repo: "web", Number: 5}).Matches("octo", "api", 5) {
+		t.Error("cross-repo reference should not match a different repository")
+	}
+}
+
+func TestIssueReferences(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Reference
+	}{
+		{
+			name: "bare reference",
+			text: "Depends on #12",
+			want: []Reference{{Number: 12}},
+		},
+		{
+			name: "GH- reference",
+			text: "Waiting for GH-34 and gh-35",
+			want: []Reference{{Number: 34}, {Number: 35}},
+		},
+		{
+			name: "cross-repo reference",
+			text: "Requires octo/api#99",
+			want: []Reference{{Owner: "octo", Repo: "api", Number: 99}},
+		},
+		{
+			name: "issue and pull request URLs",
+			text: "Needs https://github.com/octo/web.app/issues/5 and https://github.com/octo/api/pull/6#issuecomment-1",
+			want: []Reference{{Owner: "octo", Repo: "web.app", Number: 5}, {Owner: "octo", Repo: "api", Number: 6}},
+		},
+		{
+			name: "duplicates are dropped",
+			text: "#3, #4 (#3) and octo/api#3",
+			want: []Reference{{Number: 3}, {Number: 4}, {Owner: "octo", Repo: "api", Number: 3}},
+		},
+		{
+			name: "entities and fragments are not references",
+			text: "&#123; see https://example.com/docs#42 and page#7",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IssueReferences(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IssueReferences() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReference_String(t *testing.T) {
+	if got := (Reference{Number: 12}).String(); got != "#12" {
+		t.Errorf("String() = %q, want #12", got)
+	}
+	if got := (Reference{Owner: "octo", Repo: "api", Number: 12}).String(); got != "octo/api#12" {
+		t.Errorf("String() = %q, want octo/api#12", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"path/filepath"
 	"strings"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/kaskol10/github-project-agent/llm"
 	"github.com/kaskol10/github-project-agent/plugins"
 	"github.com/kaskol10/github-project-agent/prompts"
+	"github.com/kaskol10/github-project-agent/sampling"
 )
 
 // MCPInterface provides a Model Context Protocol compatible interface
@@ -19,80 +21,197 @@ import (
 type MCPInterface struct {
 	githubClient   github.UnifiedClient
 	pluginAgents   []*plugins.PluginAgent
-	pluginExecutor *plugins.PluginExecutor
-	llmClient      interface{} // *llm.Client - using interface{} to avoid circular import
+	workflows      []*plugins.Workflow
+	pluginExecutor plugins.Runner
+	promptLoader   *prompts.Loader
+	llmClient      interface{} // llm.LLMClient - using interface{} to avoid circular import
 	guidelines     interface{} // *guidelines.Guidelines - using interface{} to avoid circular import
 	config         interface{} // *config.Config - for accessing task format rules
 }
 
-// NewMCPInterface creates a new MCP-compatible interface
-func NewMCPInterface(ghClient github.UnifiedClient, pluginAgents []*plugins.PluginAgent, llmClient, guidelines, cfg interface{}) *MCPInterface {
-	var executor *plugins.PluginExecutor
-	var promptLoader *prompts.Loader
-
-	// Try to create prompt loader if config is available
+// NewPromptLoader loads the plugin agents' prompt templates from the
+// configured prompts paths and the agents' own prompts directories. Without
+// a config only the embedded defaults are available.
+func NewPromptLoader(cfg *config.Config) *prompts.Loader {
+	var paths []string
+	var loaderOptions prompts.LoaderOptions
 	if cfg != nil {
-		if config, ok := cfg.(*config.Config); ok && config.Agent.PromptsPath != "" {
+		if cfg.Agent.PromptsPath != "" {
 			// Support comma-separated paths for multiple prompt locations
-			paths := strings.Split(config.Agent.PromptsPath, ",")
+			paths = strings.Split(cfg.Agent.PromptsPath, ",")
 			// Trim whitespace from each path
 			for i, path := range paths {
 				paths[i] = strings.TrimSpace(path)
 			}
 
 			// Also add agent-specific prompt directories
-			if config.Agent.PluginsPath != "" {
+			if cfg.Agent.PluginsPath != "" {
 				// Add custom agents prompts directory
-				customPromptsPath := filepath.Join(config.Agent.PluginsPath, "custom", "prompts")
+				customPromptsPath := filepath.Join(cfg.Agent.PluginsPath, "custom", "prompts")
 				paths = append(paths, customPromptsPath)
 
 				// Add core agents prompts directory
-				corePromptsPath := filepath.Join(config.Agent.PluginsPath, "core", "prompts")
+				corePromptsPath := filepath.Join(cfg.Agent.PluginsPath, "core", "prompts")
 				paths = append(paths, corePromptsPath)
 			}
+		}
+		loaderOptions.Strict = cfg.Agent.PromptsStrict
+	}
+	loader, _ := prompts.NewMultiPathLoaderWithOptions(paths, loaderOptions)
+	return loader
+}
 
-			loader, err := prompts.NewMultiPathLoader(paths)
-			if err == nil {
-				promptLoader = loader
-			}
+// NewMCPInterface creates a new MCP-compatible interface
+func NewMCPInterface(ghClient github.UnifiedClient, pluginAgents []*plugins.PluginAgent, llmClient, guidelines, cfg interface{}) *MCPInterface {
+	appConfig, _ := cfg.(*config.Config)
+	promptLoader := NewPromptLoader(appConfig)
+
+	var executorOptions plugins.ExecutorOptions
+	if appConfig, ok := cfg.(*config.Config); ok {
+		sample, err := sampling.Parse(appConfig.Agent.Sample)
+		if err != nil {
+			slog.Warn("ignoring SAMPLE", "error", err)
+		}
+		executorOptions.Sample = sample
+		executorOptions.Concurrency = appConfig.Agent.Concurrency
+		executorOptions.Identity = appConfig.Identity()
+		executorOptions.ReportMode = appConfig.Agent.ReportMode
+		executorOptions.SkipLabels = appConfig.Agent.SkipLabels
+		executorOptions.StrictPrompts = appConfig.Agent.PromptsStrict
+		executorOptions.ReportAssignees = map[string]string{
+			config.ReportExecutiveSummary: appConfig.ReportAssignee(config.ReportExecutiveSummary),
+			config.ReportProgress:         appConfig.ReportAssignee(config.ReportProgress),
+		}
+	}
+
+	var workflows []*plugins.Workflow
+	if appConfig, ok := cfg.(*config.Config); ok && appConfig.Agent.PluginsPath != "" {
+		loaded, err := plugins.LoadWorkflows(filepath.Join(appConfig.Agent.PluginsPath, "workflows"))
+		if err != nil {
+			slog.Warn("could not load workflows", "error", err)
 		}
+		workflows = loaded
+	}
+
+	m := &MCPInterface{
+		githubClient: ghClient,
+		pluginAgents: pluginAgents,
+		workflows:    workflows,
+		promptLoader: promptLoader,
+		llmClient:    llmClient,
+		guidelines:   guidelines,
+		config:       cfg,
 	}
 
 	if llmClient != nil {
-		if llm, ok := llmClient.(*llm.Client); ok {
-			executor = plugins.NewPluginExecutor(llm, ghClient, promptLoader)
+		if llm, ok := llmClient.(llm.LLMClient); ok {
+			m.pluginExecutor = plugins.NewPluginExecutorWithOptions(llm, ghClient, promptLoader, executorOptions)
 		}
 	}
 
-	return &MCPInterface{
-		githubClient:   ghClient,
-		pluginAgents:   pluginAgents,
-		pluginExecutor: executor,
-		llmClient:      llmClient,
-		guidelines:     guidelines,
-		config:         cfg,
+	return m
+}
+
+// ReloadPrompts re-reads the plugin agents' prompt templates
+func (m *MCPInterface) ReloadPrompts() error {
+	if m.promptLoader == nil {
+		return nil
 	}
+	return m.promptLoader.Reload()
+}
+
+// Executor returns the plugin executor, or nil when no LLM client is configured
+func (m *MCPInterface) Executor() plugins.Runner {
+	return m.pluginExecutor
 }
 
 // ExecuteAgent executes an agent by name with given parameters
-func (m *MCPInterface) ExecuteAgent(ctx context.Context, agentName string, params map[string]interface{}) (interface{}, error) {
-	// Find and execute plugin agent
+func (m *MCPInterface) ExecuteAgent(ctx context.Context, agentName string, params map[string]interface{}) (*plugins.Result, error) {
+	pluginAgent := m.findAgent(agentName)
+	if pluginAgent == nil {
+		return nil, fmt.Errorf("agent not found: %s", agentName)
+	}
+	if m.pluginExecutor == nil {
+		return nil, fmt.Errorf("plugin executor not available")
+	}
+	return m.pluginExecutor.Execute(ctx, pluginAgent, params)
+}
+
+// findAgent returns the plugin agent with the given name, or nil
+func (m *MCPInterface) findAgent(agentName string) *plugins.PluginAgent {
 	for _, pluginAgent := range m.pluginAgents {
 		if pluginAgent.Name == agentName {
-			if m.pluginExecutor != nil {
-				return m.pluginExecutor.Execute(ctx, pluginAgent, params)
-			}
-			return nil, fmt.Errorf("plugin executor not available")
+			return pluginAgent
 		}
 	}
-
-	return nil, fmt.Errorf("agent not found: %s", agentName)
+	return nil
 }
 
-// ExecuteWorkflow executes a workflow by name
-// Note: Workflows are not yet supported in plugin-only mode
+// ExecuteWorkflow executes a workflow by name, running its steps in order.
+// Each step receives the workflow parameters plus "previous_result" (the
+// output of the step before it) and "step_results" (all outputs so far). The
+// workflow stops at the first failing step; the result then holds the
+// outputs of the steps that completed.
 func (m *MCPInterface) ExecuteWorkflow(ctx context.Context, workflowName string, params map[string]interface{}) (interface{}, error) {
-	return nil, fmt.Errorf("workflows not yet supported in plugin-only mode")
+	var workflow *plugins.Workflow
+	for _, w := range m.workflows {
+		if w.Name == workflowName {
+			workflow = w
+			break
+		}
+	}
+	if workflow == nil {
+		return nil, fmt.Errorf("workflow not found: %s", workflowName)
+	}
+	if m.pluginExecutor == nil {
+		return nil, fmt.Errorf("plugin executor not available")
+	}
+
+	// Resolve every step before running any so a typo doesn't leave the
+	// workflow half applied
+	stepAgents := make([]*plugins.PluginAgent, len(workflow.Steps))
+	for i, step := range workflow.Steps {
+		stepAgents[i] = m.findAgent(step.Agent)
+		if stepAgents[i] == nil {
+			return nil, fmt.Errorf("workflow %s step %d: agent not found: %s", workflow.Name, i+1, step.Agent)
+		}
+	}
+
+	stepParams := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		stepParams[key] = value
+	}
+
+	var steps []map[string]interface{}
+	result := map[string]interface{}{
+		"workflow": workflow.Name,
+		"steps":    steps,
+	}
+	for i, step := range workflow.Steps {
+		runParams := make(map[string]interface{}, len(stepParams)+len(step.Params))
+		for key, value := range stepParams {
+			runParams[key] = value
+		}
+		for key, value := range step.Params {
+			runParams[key] = value
+		}
+
+		output, err := m.pluginExecutor.Execute(ctx, stepAgents[i], runParams)
+		if err != nil {
+			return result, fmt.Errorf("workflow %s step %d (%s) failed: %w", workflow.Name, i+1, step.Agent, err)
+		}
+
+		steps = append(steps, map[string]interface{}{
+			"agent":  step.Agent,
+			"output": output,
+		})
+		result["steps"] = steps
+
+		stepParams["previous_result"] = output
+		stepParams["step_results"] = steps
+	}
+
+	return result, nil
 }
 
 // GetAgentCapabilities returns the capabilities of a specific agent
@@ -115,9 +234,27 @@ func (m *MCPInterface) ListAgents() []string {
 }
 
 // ListWorkflows returns all available workflows
-// Note: Workflows are not yet supported in plugin-only mode
 func (m *MCPInterface) ListWorkflows() []string {
-	return []string{}
+	workflows := []string{}
+	for _, workflow := range m.workflows {
+		workflows = append(workflows, workflow.Name)
+	}
+	return workflows
+}
+
+// DescribeAgentsJSON describes every plugin agent, with its triggers, actions
+// and prompt path, as JSON for external tooling. The agents' raw markdown and
+// file path are only included when verbose is set.
+func (m *MCPInterface) DescribeAgentsJSON(verbose bool) ([]byte, error) {
+	agents := make([]map[string]interface{}, len(m.pluginAgents))
+	for i, pluginAgent := range m.pluginAgents {
+		agents[i] = pluginAgent.Summary()
+		if verbose {
+			agents[i]["raw_content"] = pluginAgent.RawContent
+			agents[i]["file_path"] = pluginAgent.FilePath
+		}
+	}
+	return json.MarshalIndent(map[string]interface{}{"agents": agents}, "", "  ")
 }
 
 // ToJSON converts the MCP interface state to JSON for external consumption
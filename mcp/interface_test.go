@@ -0,0 +1,160 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kaskol10/github-project-agent/config"
+	"github.com/kaskol10/github-project-agent/plugins"
+)
+
+// recordingRunner records the parameters each agent ran with
+type recordingRunner struct {
+	params map[string]map[string]interface{}
+	failOn string
+}
+
+func (r *recordingRunner) Execute(ctx context.Context, pluginAgent *plugins.PluginAgent, params map[string]interface{}) (*plugins.Result, error) {
+	r.params[pluginAgent.Name] = params
+	if pluginAgent.Name == r.failOn {
+		return nil, errors.New("boom")
+	}
+	return &plugins.Result{Agent: pluginAgent.Name, Status: plugins.StatusCompleted}, nil
+}
+
+func newWorkflowInterface(t *testing.T, runner *recordingRunner) *MCPInterface {
+	t.Helper()
+	dir := t.TempDir()
+	workflowsDir := filepath.Join(dir, "workflows")
+	if err := os.Mkdir(workflowsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(workflowsDir, "triage.md"), "# Workflow: Triage\n\n## Steps\n\n1. Validator\n2. Priority\n")
+	writeFile(t, filepath.Join(workflowsDir, "report.yaml"), "name: Report\nsteps:\n  - agent: Priority\n    params:\n      mode: weekly\n  - agent: Missing\n")
+
+	cfg := &config.Config{}
+	cfg.Agent.PluginsPath = dir
+	agents := []*plugins.PluginAgent{{Name: "Validator"}, {Name: "Priority"}}
+	m := NewMCPInterface(nil, agents, nil, nil, cfg)
+	m.pluginExecutor = runner
+	return m
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListWorkflows(t *testing.T) {
+	m := newWorkflowInterface(t, &recordingRunner{})
+	if got, want := m.ListWorkflows(), []string{"Report", "Triage"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListWorkflows() = %v, want %v", got, want)
+	}
+}
+
+func TestExecuteWorkflow_PassesOutputToNextStep(t *testing.T) {
+	runner := &recordingRunner{params: map[string]map[string]interface{}{}}
+	m := newWorkflowInterface(t, runner)
+
+	result, err := m.ExecuteWorkflow(context.Background(), "Triage", map[string]interface{}{"issue_number": 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := result.(map[string]interface{})["steps"].([]map[string]interface{})
+	if len(steps) != 2 || steps[0]["agent"] != "Validator" || steps[1]["agent"] != "Priority" {
+		t.Fatalf("unexpected steps: %v", steps)
+	}
+
+	if _, ok := runner.params["Validator"]["previous_result"]; ok {
+		t.Error("first step should not receive a previous result")
+	}
+	priority := runner.params["Priority"]
+	if priority["issue_number"] != 7 {
+		t.Errorf("expected workflow params to reach every step, got %v", priority)
+	}
+	previous, _ := priority["previous_result"].(*plugins.Result)
+	if previous == nil || previous.Agent != "Validator" {
+		t.Errorf("expected validator output as previous result, got %v", priority["previous_result"])
+	}
+}
+
+func TestExecuteWorkflow_StopsOnError(t *testing.T) {
+	runner := &recordingRunner{params: map[string]map[string]interface{}{}, failOn: "Validator"}
+	m := newWorkflowInterface(t, runner)
+
+	if _, err := m.ExecuteWorkflow(context.Background(), "Triage", nil); err == nil {
+		t.Fatal("expected error from failing step")
+	}
+	if _, ran := runner.params["Priority"]; ran {
+		t.Error("steps after a failure should not run")
+	}
+}
+
+func TestExecuteWorkflow_UnknownAgentRunsNothing(t *testing.T) {
+	runner := &recordingRunner{params: map[string]map[string]interface{}{}}
+	m := newWorkflowInterface(t, runner)
+
+	if _, err := m.ExecuteWorkflow(context.Background(), "Report", nil); err == nil {
+		t.Fatal("expected error for unknown step agent")
+	}
+	if len(runner.params) != 0 {
+		t.Errorf("expected no steps to run, got %v", runner.params)
+	}
+}
+
+func TestDescribeAgentsJSON(t *testing.T) {
+	agents := []*plugins.PluginAgent{{
+		Name:       "Priority",
+		Type:       "core",
+		Purpose:    "Rank open issues",
+		Triggers:   []plugins.Trigger{{Schedule: "0 9 * * 1"}, {Manual: true}},
+		Actions:    []string{"add_label"},
+		PromptPath: "prompts/priority-calculator.md",
+		RawContent: "# Agent: Priority",
+		FilePath:   ".github/agents/core/priority.md",
+	}}
+	m := NewMCPInterface(nil, agents, nil, nil, &config.Config{})
+
+	var described struct {
+		Agents []map[string]interface{} `json:"agents"`
+	}
+	data, err := m.DescribeAgentsJSON(false)
+	if err != nil {
+		t.Fatalf("DescribeAgentsJSON() error = %v", err)
+	}
+	if err := json.Unmarshal(data, &described); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(described.Agents) != 1 {
+		t.Fatalf("expected one agent, got %s", data)
+	}
+	agent := described.Agents[0]
+	for key, want := range map[string]interface{}{"name": "Priority", "type": "core", "purpose": "Rank open issues", "prompt_path": "prompts/priority-calculator.md"} {
+		if agent[key] != want {
+			t.Errorf("%s = %v, want %v", key, agent[key], want)
+		}
+	}
+	if triggers, ok := agent["triggers"].([]interface{}); !ok || len(triggers) != 2 || triggers[0].(map[string]interface{})["schedule"] != "0 9 * * 1" {
+		t.Errorf("unexpected triggers: %v", agent["triggers"])
+	}
+	if _, ok := agent["raw_content"]; ok {
+		t.Error("expected raw content to be left out without verbose")
+	}
+
+	data, err = m.DescribeAgentsJSON(true)
+	if err != nil {
+		t.Fatalf("DescribeAgentsJSON(verbose) error = %v", err)
+	}
+	if !strings.Contains(string(data), `"raw_content": "# Agent: Priority"`) {
+		t.Errorf("expected raw content with verbose, got %s", data)
+	}
+}
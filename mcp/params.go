@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Params collects repeated -param key=value flags into the params map passed
+// to ExecuteAgent and ExecuteWorkflow. It implements flag.Value.
+type Params map[string]interface{}
+
+// String renders the params as sorted key=value pairs
+func (p Params) String() string {
+	keys := make([]string, 0, len(p))
+	for key := range p {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", key, p[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Set parses one key=value pair; a later value for the same key wins
+func (p Params) Set(pair string) error {
+	key, value, ok := strings.Cut(pair, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", pair)
+	}
+	p[key] = ParseParamValue(value)
+	return nil
+}
+
+// ParseParamValue infers a param's type: integers become int and true or
+// false a bool, like the values agents read. Anything else, or a value in
+// quotes, is a string.
+func ParseParamValue(value string) interface{} {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		return n
+	}
+	switch strings.ToLower(value) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return value
+}
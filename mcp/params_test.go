@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestParams_Flag(t *testing.T) {
+	params := Params{}
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Var(params, "param", "")
+
+	err := flags.Parse([]string{
+		"-param", "label=needs-review",
+		"-param", "days=14",
+		"-param", "dry_run=true",
+		"-param", "since=2024-05-01",
+		"-param", "code='007'",
+		"-param", "query=a=b",
+		"-param", "days=30",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Params{
+		"label":   "needs-review",
+		"days":    30,
+		"dry_run": true,
+		"since":   "2024-05-01",
+		"code":    "007",
+		"query":   "a=b",
+	}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("params = %#v, want %#v", params, want)
+	}
+	if got := (Params{"b": 2, "a": "x"}).String(); got != "a=x,b=2" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestParams_Invalid(t *testing.T) {
+	for _, pair := range []string{"novalue", "=value", " =x"} {
+		if err := (Params{}).Set(pair); err == nil {
+			t.Errorf("Set(%q) succeeded, want an error", pair)
+		}
+	}
+}
+
+func TestParseParamValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  interface{}
+	}{
+		{"42", 42},
+		{"-3", -3},
+		{"FALSE", false},
+		{"True", true},
+		{"1.5", "1.5"},
+		{"", ""},
+		{`"true"`, "true"},
+		{"'", "'"},
+		{"yes", "yes"},
+	}
+	for _, tt := range tests {
+		if got := ParseParamValue(tt.value); got != tt.want {
+			t.Errorf("ParseParamValue(%q) = %#v, want %#v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ProtocolVersion is the Model Context Protocol revision the server speaks
+const ProtocolVersion = "2024-11-05"
+
+// JSON-RPC 2.0 error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Server answers Model Context Protocol requests over newline-delimited
+// JSON-RPC, exposing each plugin agent as a tool
+type Server struct {
+	mcp     *MCPInterface
+	name    string
+	version string
+	out     io.Writer
+}
+
+// Tool describes an agent as an MCP tool
+type Tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type toolCallParams struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+type toolContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type toolCallResult struct {
+	Content []toolContent `json:"content"`
+	IsError bool          `json:"isError"`
+}
+
+// NewServer creates an MCP server for the agents of m
+func NewServer(m *MCPInterface, name, version string) *Server {
+	return &Server{mcp: m, name: name, version: version}
+}
+
+// Serve reads requests from in and writes responses to out until in is
+// exhausted or ctx is cancelled
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.out = out
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := s.handleLine(ctx, []byte(line)); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read request: %w", err)
+	}
+	return nil
+}
+
+// handleLine answers a single request. Notifications get no response.
+func (s *Server) handleLine(ctx context.Context, line []byte) error {
+	var req rpcRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		return s.write(rpcResponse{ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: err.Error()}})
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return s.write(rpcResponse{ID: idOrNull(req.ID), Error: &rpcError{Code: codeInvalidRequest, Message: "invalid JSON-RPC 2.0 request"}})
+	}
+
+	result, rpcErr := s.dispatch(ctx, req)
+	if len(req.ID) == 0 {
+		return nil
+	}
+	return s.write(rpcResponse{ID: req.ID, Result: result, Error: rpcErr})
+}
+
+// dispatch runs the method named by req
+func (s *Server) dispatch(ctx context.Context, req rpcRequest) (interface{}, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities": map[string]interface{}{
+				"tools": map[string]interface{}{},
+			},
+			"serverInfo": map[string]interface{}{
+				"name":    s.name,
+				"version": s.version,
+			},
+		}, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": s.Tools()}, nil
+	case "tools/call":
+		var params toolCallParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "tools/call requires a tool name"}
+		}
+		return s.callTool(ctx, params), nil
+	default:
+		if strings.HasPrefix(req.Method, "notifications/") {
+			return nil, nil
+		}
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+	}
+}
+
+// Tools returns an MCP tool for every plugin agent
+func (s *Server) Tools() []Tool {
+	tools := []Tool{}
+	for _, pluginAgent := range s.mcp.pluginAgents {
+		description := pluginAgent.Purpose
+		if description == "" {
+			description = pluginAgent.Name
+		}
+		tools = append(tools, Tool{
+			Name:        ToolName(pluginAgent.Name),
+			Description: description,
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"issue_number": map[string]interface{}{
+						"type":        "integer",
+						"description": "Issue number to run the agent on (omit for project-wide agents)",
+					},
+				},
+			},
+		})
+	}
+	return tools
+}
+
+// callTool executes the agent behind a tool. Agent failures are reported
+// in the result so the client can show them, per the MCP spec.
+func (s *Server) callTool(ctx context.Context, params toolCallParams) toolCallResult {
+	agentName := ""
+	for _, pluginAgent := range s.mcp.pluginAgents {
+		if ToolName(pluginAgent.Name) == params.Name {
+			agentName = pluginAgent.Name
+			break
+		}
+	}
+	if agentName == "" {
+		return errorResult(fmt.Errorf("unknown tool: %s", params.Name))
+	}
+
+	agentParams := map[string]interface{}{}
+	for key, value := range params.Arguments {
+		agentParams[key] = value
+	}
+	// JSON numbers decode as float64; agents expect an int issue number
+	if number, ok := agentParams["issue_number"].(float64); ok {
+		agentParams["issue_number"] = int(number)
+	}
+
+	result, err := s.mcp.ExecuteAgent(ctx, agentName, agentParams)
+	if err != nil {
+		return errorResult(err)
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return errorResult(fmt.Errorf("failed to encode result: %w", err))
+	}
+	return toolCallResult{Content: []toolContent{{Type: "text", Text: string(data)}}}
+}
+
+func errorResult(err error) toolCallResult {
+	return toolCallResult{Content: []toolContent{{Type: "text", Text: err.Error()}}, IsError: true}
+}
+
+// write sends a single response line
+func (s *Server) write(resp rpcResponse) error {
+	resp.JSONRPC = "2.0"
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+	if _, err := s.out.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+	return nil
+}
+
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
+
+var toolNameInvalid = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ToolName returns the MCP tool name for an agent, e.g. "Task Validator"
+// becomes "task_validator"
+func ToolName(agentName string) string {
+	return strings.Trim(toolNameInvalid.ReplaceAllString(strings.ToLower(agentName), "_"), "_")
+}
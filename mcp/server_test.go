@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/kaskol10/github-project-agent/plugins"
+)
+
+func serve(t *testing.T, m *MCPInterface, requests ...string) []map[string]interface{} {
+	t.Helper()
+	var out bytes.Buffer
+	in := strings.NewReader(strings.Join(requests, "\n") + "\n")
+	if err := NewServer(m, "test", "0.0.0").Serve(context.Background(), in, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var responses []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var resp map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("invalid response %q: %v", line, err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func TestServer_ToolsList(t *testing.T) {
+	m := NewMCPInterface(nil, []*plugins.PluginAgent{{Name: "Task Validator", Purpose: "Validate tasks"}}, nil, nil, nil)
+
+	responses := serve(t, m,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+	)
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses (notifications get none), got %d", len(responses))
+	}
+
+	initResult := responses[0]["result"].(map[string]interface{})
+	if initResult["protocolVersion"] != ProtocolVersion {
+		t.Errorf("unexpected initialize result: %v", initResult)
+	}
+
+	tools := responses[1]["result"].(map[string]interface{})["tools"].([]interface{})
+	if len(tools) != 1 {
+		t.Fatalf("expected 1 tool, got %v", tools)
+	}
+	tool := tools[0].(map[string]interface{})
+	if tool["name"] != "task_validator" || tool["description"] != "Validate tasks" {
+		t.Errorf("unexpected tool: %v", tool)
+	}
+	properties := tool["inputSchema"].(map[string]interface{})["properties"].(map[string]interface{})
+	if _, ok := properties["issue_number"]; !ok {
+		t.Errorf("expected issue_number parameter, got %v", properties)
+	}
+}
+
+func TestServer_ToolsCall(t *testing.T) {
+	runner := &recordingRunner{params: map[string]map[string]interface{}{}}
+	m := NewMCPInterface(nil, []*plugins.PluginAgent{{Name: "Task Validator"}}, nil, nil, nil)
+	m.pluginExecutor = runner
+
+	responses := serve(t, m,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"task_validator","arguments":{"issue_number":42}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"missing"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/list"}`,
+	)
+	if len(responses) != 3 {
+		t.Fatalf("expected 3 responses, got %d", len(responses))
+	}
+
+	if got := runner.params["Task Validator"]["issue_number"]; got != 42 {
+		t.Errorf("expected int issue number 42, got %#v", got)
+	}
+	call := responses[0]["result"].(map[string]interface{})
+	if call["isError"] != false || !strings.Contains(call["content"].([]interface{})[0].(map[string]interface{})["text"].(string), "Task Validator") {
+		t.Errorf("unexpected call result: %v", call)
+	}
+
+	if unknown := responses[1]["result"].(map[string]interface{}); unknown["isError"] != true {
+		t.Errorf("expected error result for unknown tool, got %v", unknown)
+	}
+
+	rpcErr := responses[2]["error"].(map[string]interface{})
+	if rpcErr["code"] != float64(codeMethodNotFound) {
+		t.Errorf("expected method not found, got %v", rpcErr)
+	}
+}
+
+func TestToolName(t *testing.T) {
+	if got := ToolName("Executive Summary Generator"); got != "executive_summary_generator" {
+		t.Errorf("ToolName() = %q", got)
+	}
+	if got := ToolName(" Code-Review (beta) "); got != "code_review_beta" {
+		t.Errorf("ToolName() = %q", got)
+	}
+}
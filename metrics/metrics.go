@@ -0,0 +1,229 @@
+// Package metrics counts what the agents do and exposes the counts in the
+// Prometheus text format, so a long-running daemon can be monitored
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Default holds the agent metrics below
+var Default = NewRegistry()
+
+// Agent metrics
+var (
+	IssuesProcessed = Default.NewCounter("agent_issues_processed_total", "Issues checked by the monitor or validated by agents.")
+	CommentsPosted  = Default.NewCounter("agent_comments_posted_total", "Comments posted on issues and pull requests.")
+	LLMCalls        = Default.NewCounter("agent_llm_calls_total", "Requests sent to the LLM, excluding cache hits.")
+	LLMErrors       = Default.NewCounter("agent_llm_errors_total", "LLM requests that failed.")
+	LLMCallDuration = Default.NewHistogram("agent_llm_call_duration_seconds", "Duration of LLM requests.",
+		[]float64{0.5, 1, 2.5, 5, 10, 30, 60, 120})
+)
+
+// ObserveLLMCall records one LLM request that took elapsed and failed if
+// err is non-nil
+func ObserveLLMCall(elapsed time.Duration, err error) {
+	LLMCalls.Inc()
+	LLMCallDuration.Observe(elapsed.Seconds())
+	if err != nil {
+		LLMErrors.Inc()
+	}
+}
+
+// metric is anything a registry can write out
+type metric interface {
+	name() string
+	write(w io.Writer) error
+}
+
+// Registry is a set of metrics written out together
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = append(r.metrics, m)
+}
+
+// NewCounter registers a counter
+func (r *Registry) NewCounter(name, help string) *Counter {
+	c := &Counter{metricName: name, help: help}
+	r.register(c)
+	return c
+}
+
+// NewHistogram registers a histogram with the given upper bucket bounds
+func (r *Registry) NewHistogram(name, help string, buckets []float64) *Histogram {
+	bounds := append([]float64(nil), buckets...)
+	sort.Float64s(bounds)
+	h := &Histogram{metricName: name, help: help, bounds: bounds, counts: make([]uint64, len(bounds))}
+	r.register(h)
+	return h
+}
+
+// WriteText writes every metric in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	metrics := append([]metric(nil), r.metrics...)
+	r.mu.Unlock()
+
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].name() < metrics[j].name() })
+	for _, m := range metrics {
+		if err := m.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler serves the registry's metrics
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := r.WriteText(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// Counter is a value that only goes up
+type Counter struct {
+	metricName string
+	help       string
+
+	mu    sync.Mutex
+	value float64
+}
+
+// Inc adds one to the counter
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add adds delta, which must not be negative, to the counter
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.value += delta
+}
+
+// Value returns the current count
+func (c *Counter) Value() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value
+}
+
+func (c *Counter) name() string {
+	return c.metricName
+}
+
+func (c *Counter) write(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %s\n",
+		c.metricName, c.help, c.metricName, c.metricName, formatFloat(c.Value()))
+	return err
+}
+
+// Histogram counts observations into cumulative buckets
+type Histogram struct {
+	metricName string
+	help       string
+	bounds     []float64
+
+	mu     sync.Mutex
+	counts []uint64 // Observations per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// Observe records one value
+func (h *Histogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, bound := range h.bounds {
+		if value <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+// Count returns how many values were observed
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+func (h *Histogram) name() string {
+	return h.metricName
+}
+
+func (h *Histogram) write(w io.Writer) error {
+	h.mu.Lock()
+	counts := append([]uint64(nil), h.counts...)
+	count, sum := h.count, h.sum
+	h.mu.Unlock()
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.metricName, h.help, h.metricName); err != nil {
+		return err
+	}
+	var cumulative uint64
+	for i, bound := range h.bounds {
+		cumulative += counts[i]
+		if _, err := fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", h.metricName, formatFloat(bound), cumulative); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n%s_sum %s\n%s_count %d\n",
+		h.metricName, count, h.metricName, formatFloat(sum), h.metricName, count)
+	return err
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// Serve exposes the default registry on addr at /metrics until ctx is
+// cancelled
+func Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Default.Handler())
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve metrics: %w", err)
+	}
+	return nil
+}
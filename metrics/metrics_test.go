@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	r := NewRegistry()
+	calls := r.NewCounter("test_calls_total", "Calls made.")
+	duration := r.NewHistogram("test_duration_seconds", "Call duration.", []float64{5, 1})
+
+	calls.Inc()
+	calls.Add(2)
+	calls.Add(-1) // Counters never go down
+	duration.Observe(0.5)
+	duration.Observe(3)
+	duration.Observe(10)
+
+	var sb strings.Builder
+	if err := r.WriteText(&sb); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP test_calls_total Calls made.
+# TYPE test_calls_total counter
+test_calls_total 3
+# HELP test_duration_seconds Call duration.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{le="1"} 1
+test_duration_seconds_bucket{le="5"} 2
+test_duration_seconds_bucket{le="+Inf"} 3
+test_duration_seconds_sum 13.5
+test_duration_seconds_count 3
+`
+	if sb.String() != want {
+		t.Errorf("WriteText() =\n%s\nwant\n%s", sb.String(), want)
+	}
+}
+
+func TestObserveLLMCall(t *testing.T) {
+	calls, failures, observed := LLMCalls.Value(), LLMErrors.Value(), LLMCallDuration.Count()
+
+	ObserveLLMCall(time.Second, nil)
+	ObserveLLMCall(2*time.Second, errors.New("timeout"))
+
+	if LLMCalls.Value()-calls != 2 || LLMErrors.Value()-failures != 1 || LLMCallDuration.Count()-observed != 2 {
+		t.Errorf("unexpected metrics: calls %v, errors %v, durations %d",
+			LLMCalls.Value()-calls, LLMErrors.Value()-failures, LLMCallDuration.Count()-observed)
+	}
+
+	recorder := httptest.NewRecorder()
+	Default.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	if !strings.Contains(recorder.Body.String(), "# TYPE agent_llm_call_duration_seconds histogram") {
+		t.Errorf("expected the LLM duration histogram in the output, got:\n%s", recorder.Body.String())
+	}
+}
@@ -0,0 +1,38 @@
+package output
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	b := "one\ntwo\nTHREE\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+
+	want := `--- a
++++ b
+@@ -1,6 +1,6 @@
+ one
+ two
+-three
++THREE
+ four
+ five
+ six
+@@ -8,3 +8,4 @@
+ eight
+ nine
+ ten
++eleven
+`
+	if got := UnifiedDiff("a", "b", a, b); got != want {
+		t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUnifiedDiff_Edges(t *testing.T) {
+	if got := UnifiedDiff("a", "b", "same", "same"); got != "" {
+		t.Errorf("expected no diff for equal text, got %q", got)
+	}
+	want := "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+## Description\n+Login fails\n"
+	if got := UnifiedDiff("a", "b", "", "## Description\nLogin fails"); got != want {
+		t.Errorf("UnifiedDiff() = %q, want %q", got, want)
+	}
+}
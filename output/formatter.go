@@ -0,0 +1,101 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Built-in values for the -output flag
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Formatter renders a run summary in one output format
+type Formatter interface {
+	Format(summary RunSummary) ([]byte, error)
+}
+
+// FormatterFunc adapts a function to the Formatter interface
+type FormatterFunc func(summary RunSummary) ([]byte, error)
+
+// Format calls f(summary)
+func (f FormatterFunc) Format(summary RunSummary) ([]byte, error) {
+	return f(summary)
+}
+
+var (
+	formattersMu sync.RWMutex
+	formatters   = map[string]Formatter{
+		FormatText: FormatterFunc(formatText),
+		FormatJSON: FormatterFunc(formatJSON),
+	}
+)
+
+// Register makes a formatter available under name for the -output flag,
+// replacing any formatter already registered with that name
+func Register(name string, formatter Formatter) {
+	formattersMu.Lock()
+	defer formattersMu.Unlock()
+	formatters[name] = formatter
+}
+
+// Lookup returns the formatter registered for name. An empty name selects
+// the text formatter.
+func Lookup(name string) (Formatter, error) {
+	if name == "" {
+		name = FormatText
+	}
+
+	formattersMu.RLock()
+	defer formattersMu.RUnlock()
+	formatter, ok := formatters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q (use %s)", name, strings.Join(formatNames(), ", "))
+	}
+	return formatter, nil
+}
+
+// Formats lists the registered format names
+func Formats() []string {
+	formattersMu.RLock()
+	defer formattersMu.RUnlock()
+	return formatNames()
+}
+
+func formatNames() []string {
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatText renders only the next steps, since each mode already prints its
+// own progress
+func formatText(summary RunSummary) ([]byte, error) {
+	if len(summary.NextSteps) == 0 {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("\nNext steps:\n")
+	for _, step := range summary.NextSteps {
+		fmt.Fprintf(&buf, "  • %s\n", step)
+	}
+	return buf.Bytes(), nil
+}
+
+// formatJSON renders the whole summary as indented JSON
+func formatJSON(summary RunSummary) ([]byte, error) {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
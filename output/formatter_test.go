@@ -0,0 +1,42 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	for _, name := range []string{"", FormatText, FormatJSON} {
+		if _, err := Lookup(name); err != nil {
+			t.Errorf("Lookup(%q) failed: %v", name, err)
+		}
+	}
+
+	_, err := Lookup("sarif-nope")
+	if err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+	if !strings.Contains(err.Error(), "json, text") {
+		t.Errorf("expected the error to list available formats, got %v", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("csv-test", FormatterFunc(func(s RunSummary) ([]byte, error) {
+		return []byte(fmt.Sprintf("%s,%d,%d\n", s.Mode, s.Checked, len(s.NextSteps))), nil
+	}))
+
+	summary := NewRunSummary("validate")
+	summary.Checked = 4
+	summary.LLMErrors = []int{1}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, "csv-test", summary); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "validate,4,1\n" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,137 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// IssueError records an issue that couldn't be processed
+type IssueError struct {
+	Issue int    `json:"issue"`
+	Error string `json:"error"`
+}
+
+// RepoError records a repository whose issues couldn't be listed
+type RepoError struct {
+	Repo  string `json:"repo"`
+	Error string `json:"error"`
+}
+
+// RunSummary is the structured result of a run, used to derive next steps
+type RunSummary struct {
+	Mode            string       `json:"mode"`
+	Checked         int          `json:"checked"`
+	Valid           int          `json:"valid"`
+	Fixed           []int        `json:"fixed"`
+	NeedsHuman      []int        `json:"needs_human"`
+	NeedsHumanLabel string       `json:"needs_human_label,omitempty"` // Label added to issues needing human input, if any
+	LLMErrors       []int        `json:"llm_errors"`
+	Protected       []int        `json:"protected"` // Issues skipped: protected by a skip label
+	LLMBaseURL      string       `json:"-"`
+	Errors          []IssueError `json:"errors"`
+	FailedRepos     []RepoError  `json:"failed_repos"`
+	NextSteps       []string     `json:"next_steps"`
+}
+
+// NewRunSummary creates an empty summary for a mode. Lists start empty rather
+// than nil so JSON output always has arrays.
+func NewRunSummary(mode string) *RunSummary {
+	return &RunSummary{
+		Mode:        mode,
+		Fixed:       []int{},
+		NeedsHuman:  []int{},
+		LLMErrors:   []int{},
+		Protected:   []int{},
+		Errors:      []IssueError{},
+		FailedRepos: []RepoError{},
+		NextSteps:   []string{},
+	}
+}
+
+// NextSteps turns the counts in a summary into actionable guidance
+func NextSteps(s *RunSummary) []string {
+	steps := []string{}
+
+	if n := len(s.NeedsHuman); n > 0 {
+		labeled := ""
+		if s.NeedsHumanLabel != "" {
+			labeled = fmt.Sprintf(" (labeled %s)", s.NeedsHumanLabel)
+		}
+		verb := "need"
+		if n == 1 {
+			verb = "needs"
+		}
+		steps = append(steps, fmt.Sprintf("%s %s human input%s: %s", pluralIssues(n), verb, labeled, issueList(s.NeedsHuman)))
+	}
+
+	if n := len(s.LLMErrors); n > 0 {
+		target := "LiteLLM connectivity"
+		if s.LLMBaseURL != "" {
+			target = fmt.Sprintf("LiteLLM connectivity at %s", s.LLMBaseURL)
+		}
+		steps = append(steps, fmt.Sprintf("LLM errors on %s — check %s, or set ON_LLM_FAILURE=comment,label to report violations instead",
+			pluralIssues(n), target))
+	}
+
+	if n := len(s.Errors); n > 0 {
+		numbers := make([]int, len(s.Errors))
+		for i, e := range s.Errors {
+			numbers[i] = e.Issue
+		}
+		steps = append(steps, fmt.Sprintf("%s failed: %s — rerun with -issue=%d to see the error", pluralIssues(n), issueList(numbers), numbers[0]))
+	}
+
+	if n := len(s.FailedRepos); n > 0 {
+		repos := make([]string, n)
+		for i, e := range s.FailedRepos {
+			repos[i] = e.Repo
+		}
+		noun, verb := "repositories", "were"
+		if n == 1 {
+			noun, verb = "repository", "was"
+		}
+		steps = append(steps, fmt.Sprintf("%d %s could not be listed and %s skipped: %s — check the token's access and rerun",
+			n, noun, verb, strings.Join(repos, ", ")))
+	}
+
+	if n := len(s.Fixed); n > 0 {
+		steps = append(steps, fmt.Sprintf("%s fixed by the agent: %s — review the changes", pluralIssues(n), issueList(s.Fixed)))
+	}
+
+	return steps
+}
+
+// Write formats the summary with the formatter registered for format and
+// writes it to w
+func Write(w io.Writer, format string, s *RunSummary) error {
+	formatter, err := Lookup(format)
+	if err != nil {
+		return err
+	}
+
+	s.NextSteps = NextSteps(s)
+	data, err := formatter.Format(*s)
+	if err != nil {
+		return fmt.Errorf("failed to format summary as %s: %w", format, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	return nil
+}
+
+func pluralIssues(n int) string {
+	if n == 1 {
+		return "1 issue"
+	}
+	return fmt.Sprintf("%d issues", n)
+}
+
+func issueList(numbers []int) string {
+	refs := make([]string, len(numbers))
+	for i, n := range numbers {
+		refs[i] = fmt.Sprintf("#%d", n)
+	}
+	return strings.Join(refs, ", ")
+}
@@ -0,0 +1,74 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNextSteps(t *testing.T) {
+	summary := NewRunSummary("validate")
+	summary.NeedsHuman = []int{12, 34, 56}
+	summary.NeedsHumanLabel = "needs-format"
+	summary.LLMErrors = []int{7, 8}
+	summary.LLMBaseURL = "http://localhost:4000"
+	summary.Errors = []IssueError{{Issue: 9, Error: "failed to update issue: 403"}}
+	summary.FailedRepos = []RepoError{{Repo: "acme/web", Error: "404 Not Found"}}
+
+	steps := NextSteps(summary)
+	want := []string{
+		"3 issues need human input (labeled needs-format): #12, #34, #56",
+		"LLM errors on 2 issues — check LiteLLM connectivity at http://localhost:4000",
+		"1 issue failed: #9 — rerun with -issue=9",
+		"1 repository could not be listed and was skipped: acme/web",
+	}
+	if len(steps) != len(want) {
+		t.Fatalf("got %d steps, want %d: %v", len(steps), len(want), steps)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(steps[i], prefix) {
+			t.Errorf("step %d = %q, want prefix %q", i, steps[i], prefix)
+		}
+	}
+}
+
+func TestNextSteps_CleanRun(t *testing.T) {
+	summary := NewRunSummary("validate")
+	summary.Checked, summary.Valid = 5, 5
+
+	if steps := NextSteps(summary); len(steps) != 0 {
+		t.Errorf("expected no next steps for a clean run, got %v", steps)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatText, summary); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no text output for a clean run, got %q", buf.String())
+	}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	summary := NewRunSummary("validate")
+	summary.Checked = 2
+	summary.NeedsHuman = []int{3}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, summary); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	steps, ok := decoded["next_steps"].([]interface{})
+	if !ok || len(steps) != 1 {
+		t.Fatalf("expected a next_steps array with 1 entry, got %v", decoded["next_steps"])
+	}
+	if errs, ok := decoded["errors"].([]interface{}); !ok || len(errs) != 0 {
+		t.Errorf("expected an empty errors array, got %v", decoded["errors"])
+	}
+}
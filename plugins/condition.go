@@ -0,0 +1,266 @@
+package plugins
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/kaskol10/github-project-agent/github"
+)
+
+// Event describes what happened to an issue or pull request, for matching
+// an agent's triggers
+type Event struct {
+	Name   string   // e.g. "issues.opened" or "manual"
+	Labels []string // Labels on the issue or pull request
+	State  string   // "open" or "closed"
+}
+
+// EventForIssue describes the named event on issue, with the labels and
+// state its trigger conditions are evaluated against
+func EventForIssue(name string, issue *github.Issue) Event {
+	return Event{Name: name, Labels: issue.Labels, State: issue.State}
+}
+
+// Condition is a parsed trigger condition such as
+// labels.contains('needs-review') && state == 'open'
+type Condition struct {
+	expr conditionNode
+}
+
+// conditionNode is a node of a parsed condition
+type conditionNode interface {
+	eval(event Event) bool
+}
+
+type conditionAnd struct{ left, right conditionNode }
+type conditionOr struct{ left, right conditionNode }
+type conditionNot struct{ operand conditionNode }
+
+// conditionContains is labels.contains('x'), ignoring case like GitHub
+type conditionContains struct{ label string }
+
+// conditionCompare is state or event compared with a string
+type conditionCompare struct {
+	field, value string
+	equal        bool
+}
+
+func (n conditionAnd) eval(event Event) bool { return n.left.eval(event) && n.right.eval(event) }
+func (n conditionOr) eval(event Event) bool  { return n.left.eval(event) || n.right.eval(event) }
+func (n conditionNot) eval(event Event) bool { return !n.operand.eval(event) }
+
+func (n conditionContains) eval(event Event) bool {
+	return github.HasLabels(event.Labels, []string{n.label})
+}
+
+func (n conditionCompare) eval(event Event) bool {
+	actual := event.State
+	if n.field == "event" {
+		actual = event.Name
+	}
+	return strings.EqualFold(actual, n.value) == n.equal
+}
+
+// ParseCondition parses a trigger condition. It supports
+// labels.contains('x'), state == 'open', event != 'issues.edited', !, &&
+// (or "and"), || (or "or") and parentheses. Strings take single or double
+// quotes.
+func ParseCondition(expr string) (*Condition, error) {
+	tokens, err := tokenizeCondition(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &conditionParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in condition %q", p.tokens[p.pos].text, expr)
+	}
+	return &Condition{expr: node}, nil
+}
+
+// Matches reports whether event satisfies the condition
+func (c *Condition) Matches(event Event) bool {
+	return c.expr.eval(event)
+}
+
+// conditionToken is a token of a condition; text is unquoted for strings
+type conditionToken struct {
+	text   string
+	string bool
+}
+
+// tokenizeCondition splits a condition into identifiers, strings and operators
+func tokenizeCondition(expr string) ([]conditionToken, error) {
+	var tokens []conditionToken
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated string in condition %q", expr)
+			}
+			tokens = append(tokens, conditionToken{text: string(runes[i+1 : end]), string: true})
+			i = end + 1
+		case strings.ContainsRune("()", r):
+			tokens = append(tokens, conditionToken{text: string(r)})
+			i++
+		case strings.ContainsRune("&|=!", r):
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r != '=' && r != '!' && runes[i+1] == r)) {
+				tokens = append(tokens, conditionToken{text: string(runes[i : i+2])})
+				i += 2
+				continue
+			}
+			if r != '!' {
+				return nil, fmt.Errorf("unexpected %q in condition %q", string(r), expr)
+			}
+			tokens = append(tokens, conditionToken{text: "!"})
+			i++
+		case unicode.IsLetter(r) || r == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_' || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, conditionToken{text: string(runes[i:end])})
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected %q in condition %q", string(r), expr)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty condition")
+	}
+	return tokens, nil
+}
+
+// conditionParser is a recursive descent parser over condition tokens
+type conditionParser struct {
+	tokens []conditionToken
+	pos    int
+}
+
+// peek returns the next operator or identifier, or "" for a string or the end
+func (p *conditionParser) peek() string {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].string {
+		return ""
+	}
+	return p.tokens[p.pos].text
+}
+
+// expect consumes the next token if it is the operator or identifier want
+func (p *conditionParser) expect(want string) error {
+	if p.peek() != want {
+		return p.unexpected("expected " + want)
+	}
+	p.pos++
+	return nil
+}
+
+// str consumes a string token
+func (p *conditionParser) str() (string, error) {
+	if p.pos >= len(p.tokens) || !p.tokens[p.pos].string {
+		return "", p.unexpected("expected a quoted string")
+	}
+	p.pos++
+	return p.tokens[p.pos-1].text, nil
+}
+
+func (p *conditionParser) unexpected(what string) error {
+	if p.pos >= len(p.tokens) {
+		return fmt.Errorf("%s at end of condition", what)
+	}
+	return fmt.Errorf("%s, got %q", what, p.tokens[p.pos].text)
+}
+
+func (p *conditionParser) parseOr() (conditionNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for op := strings.ToLower(p.peek()); op == "||" || op == "or"; op = strings.ToLower(p.peek()) {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = conditionOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseAnd() (conditionNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for op := strings.ToLower(p.peek()); op == "&&" || op == "and"; op = strings.ToLower(p.peek()) {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = conditionAnd{left, right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseUnary() (conditionNode, error) {
+	if op := strings.ToLower(p.peek()); op == "!" || op == "not" {
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return conditionNot{operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *conditionParser) parsePrimary() (conditionNode, error) {
+	switch ident := p.peek(); strings.ToLower(ident) {
+	case "(":
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(")")
+	case "labels.contains":
+		p.pos++
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		label, err := p.str()
+		if err != nil {
+			return nil, err
+		}
+		return conditionContains{label: label}, p.expect(")")
+	case "state", "event":
+		p.pos++
+		var equal bool
+		switch p.peek() {
+		case "==":
+			equal = true
+		case "!=":
+		default:
+			return nil, p.unexpected("expected == or !=")
+		}
+		p.pos++
+		value, err := p.str()
+		if err != nil {
+			return nil, err
+		}
+		return conditionCompare{field: strings.ToLower(ident), value: value, equal: equal}, nil
+	}
+	return nil, p.unexpected("expected labels.contains(...), state or event")
+}
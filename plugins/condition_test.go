@@ -0,0 +1,120 @@
+package plugins
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kaskol10/github-project-agent/github"
+)
+
+func TestParseCondition(t *testing.T) {
+	open := Event{Name: "issues.opened", Labels: []string{"Needs-Review", "bug"}, State: "open"}
+	closed := Event{Name: "issues.closed", Labels: []string{"bug"}, State: "closed"}
+
+	tests := []struct {
+		condition  string
+		wantOpen   bool
+		wantClosed bool
+	}{
+		{condition: `labels.contains('needs-review')`, wantOpen: true},
+		{condition: `labels.contains("bug")`, wantOpen: true, wantClosed: true},
+		{condition: `state == 'open'`, wantOpen: true},
+		{condition: `state != "open"`, wantClosed: true},
+		{condition: `event == 'issues.closed'`, wantClosed: true},
+		{condition: `labels.contains('bug') && state == 'closed'`, wantClosed: true},
+		{condition: `labels.contains('needs-review') || state == 'closed'`, wantOpen: true, wantClosed: true},
+		{condition: `labels.contains('bug') and not labels.contains('needs-review')`, wantClosed: true},
+		{condition: `state == 'closed' or labels.contains('x') and state == 'open'`, wantClosed: true},
+		{condition: `(state == 'closed' || labels.contains('bug')) && !labels.contains('wontfix')`, wantOpen: true, wantClosed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.condition, func(t *testing.T) {
+			condition, err := ParseCondition(tt.condition)
+			if err != nil {
+				t.Fatalf("ParseCondition() error = %v", err)
+			}
+			if got := condition.Matches(open); got != tt.wantOpen {
+				t.Errorf("Matches(open) = %v, want %v", got, tt.wantOpen)
+			}
+			if got := condition.Matches(closed); got != tt.wantClosed {
+				t.Errorf("Matches(closed) = %v, want %v", got, tt.wantClosed)
+			}
+		})
+	}
+}
+
+func TestParseCondition_Errors(t *testing.T) {
+	for _, condition := range []string{
+		"",
+		"labels.contains(bug)",
+		"labels.contains('bug'",
+		"state = 'open'",
+		"state == 'open",
+		"title == 'x'",
+		"state == 'open' &&",
+		"state == 'open' state == 'closed'",
+	} {
+		if _, err := ParseCondition(condition); err == nil {
+			t.Errorf("ParseCondition(%q) succeeded, want an error", condition)
+		}
+	}
+}
+
+func TestMatchEvent_Conditions(t *testing.T) {
+	lines := strings.Split(`## Trigger
+
+- event: issues.opened
+- event: issues.edited
+- condition: labels.contains("needs-priority") && state == 'open'
+- event: issues.labeled
+- labels: [urgent]
+- manual: true
+`, "\n")
+	agent := &PluginAgent{Triggers: parseTriggers(lines, 1)}
+	agent.parseConditions()
+	if len(agent.Triggers) != 4 {
+		t.Fatalf("expected 4 triggers, got %+v", agent.Triggers)
+	}
+
+	tests := []struct {
+		name  string
+		event Event
+		want  bool
+	}{
+		{name: "first event with condition", event: Event{Name: "issues.opened", Labels: []string{"needs-priority"}, State: "open"}, want: true},
+		{name: "second event shares the condition", event: Event{Name: "issues.edited", Labels: []string{"needs-priority"}, State: "open"}, want: true},
+		{name: "condition label missing", event: Event{Name: "issues.opened", State: "open"}},
+		{name: "condition state differs", event: Event{Name: "issues.edited", Labels: []string{"needs-priority"}, State: "closed"}},
+		{name: "labels apply only to the event above", event: Event{Name: "issues.labeled", Labels: []string{"urgent"}}, want: true},
+		{name: "required label missing", event: Event{Name: "issues.labeled", Labels: []string{"needs-priority"}}},
+		{name: "manual ignores conditions", event: Event{Name: "manual"}, want: true},
+		{name: "unknown event", event: Event{Name: "pull_request.opened", Labels: []string{"needs-priority"}, State: "open"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := agent.MatchEvent(tt.event); got != tt.want {
+				t.Errorf("MatchEvent(%+v) = %v, want %v", tt.event, got, tt.want)
+			}
+		})
+	}
+
+	broken := &PluginAgent{Name: "Broken", Triggers: []Trigger{{Event: "issues.opened", Condition: "labels.has('x')"}}}
+	broken.parseConditions()
+	if len(broken.ConditionErrors) != 1 || broken.MatchEvent(Event{Name: "issues.opened", Labels: []string{"x"}}) {
+		t.Errorf("expected an invalid condition to be reported and never match, got %v", broken.ConditionErrors)
+	}
+}
+
+func TestMatchEvent_IssueState(t *testing.T) {
+	agent := &PluginAgent{Triggers: []Trigger{{Event: "issues.labeled", Condition: "labels.contains('needs-priority') && state == 'open'"}}}
+	agent.parseConditions()
+
+	issue := &github.Issue{Number: 1, Labels: []string{"needs-priority"}, State: "open"}
+	if !agent.MatchEvent(EventForIssue("issues.labeled", issue)) {
+		t.Error("expected the open issue to match")
+	}
+	issue.State = "closed"
+	if agent.MatchEvent(EventForIssue("issues.labeled", issue)) {
+		t.Error("expected the closed issue not to match the state condition")
+	}
+}
@@ -2,38 +2,119 @@ package plugins
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/kaskol10/github-project-agent/agent"
+	"github.com/kaskol10/github-project-agent/bot"
 	"github.com/kaskol10/github-project-agent/github"
+	"github.com/kaskol10/github-project-agent/guidelines"
 	"github.com/kaskol10/github-project-agent/llm"
+	"github.com/kaskol10/github-project-agent/markdown"
+	"github.com/kaskol10/github-project-agent/metrics"
+	"github.com/kaskol10/github-project-agent/pool"
 	"github.com/kaskol10/github-project-agent/prompts"
+	"github.com/kaskol10/github-project-agent/sampling"
 )
 
 // PluginExecutor executes plugin-based agents
 type PluginExecutor struct {
-	llmClient    *llm.Client
+	llmClient    llm.LLMClient
 	githubClient github.UnifiedClient
 	promptLoader *prompts.Loader
+	repoInfoMu   sync.Mutex                  // Guards repoInfo; agents run concurrently
+	repoInfo     map[string]*github.RepoInfo // Repository context cached per run
+	options      ExecutorOptions
+}
+
+// ExecutorOptions configures optional executor behavior
+type ExecutorOptions struct {
+	// Sample bounds the issues analyzed by project-wide agents. Agents can
+	// override it with "sample" in their configuration.
+	Sample sampling.Strategy
+
+	// ReportAssignees maps a report type ("executive-summary",
+	// "progress-report") to the login assigned to created report issues.
+	// Agents can override it with "assignee" in their configuration.
+	ReportAssignees map[string]string
+
+	// Concurrency bounds how many issues the validator processes at once per
+	// repository (default pool.DefaultLimit). Agents can override it with
+	// "concurrency" in their configuration.
+	Concurrency int
+
+	// Identity signs the comments agents post (default bot.Default)
+	Identity *bot.Identity
+
+	// ReportMode is ReportModeCreate (the default) to open a new report
+	// issue on every run, or ReportModeUpdate to rewrite the latest open
+	// one. Agents can override it with "report_mode" in their configuration.
+	ReportMode string
+
+	// SkipLabels protect issues and pull requests from every agent
+	SkipLabels []string
+
+	// StrictPrompts is passed on to the validator agent; the executor's own
+	// templates follow the strictness of its prompt loader
+	StrictPrompts bool
 }
 
 // NewPluginExecutor creates a new plugin executor
-func NewPluginExecutor(llmClient *llm.Client, githubClient github.UnifiedClient, promptLoader *prompts.Loader) *PluginExecutor {
+func NewPluginExecutor(llmClient llm.LLMClient, githubClient github.UnifiedClient, promptLoader *prompts.Loader) *PluginExecutor {
+	return NewPluginExecutorWithOptions(llmClient, githubClient, promptLoader, ExecutorOptions{})
+}
+
+// NewPluginExecutorWithOptions creates a plugin executor with optional behavior configured
+func NewPluginExecutorWithOptions(llmClient llm.LLMClient, githubClient github.UnifiedClient, promptLoader *prompts.Loader, options ExecutorOptions) *PluginExecutor {
 	return &PluginExecutor{
 		llmClient:    llmClient,
 		githubClient: githubClient,
 		promptLoader: promptLoader,
+		repoInfo:     make(map[string]*github.RepoInfo),
+		options:      options,
 	}
 }
 
-// Execute runs a plugin agent
-func (e *PluginExecutor) Execute(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (map[string]interface{}, error) {
-	result := make(map[string]interface{})
-	result["agent"] = pluginAgent.Name
-	result["type"] = pluginAgent.Type
+// concurrency returns how many issues the agent may process at once per
+// repository, falling back to the executor default
+func (e *PluginExecutor) concurrency(pluginAgent *PluginAgent) int {
+	fallback := e.options.Concurrency
+	if fallback < 1 {
+		fallback = pool.DefaultLimit
+	}
+	return configInt(pluginAgent, "concurrency", fallback)
+}
+
+// sampleStrategy returns the agent's sampling strategy, falling back to the
+// executor default
+func (e *PluginExecutor) sampleStrategy(pluginAgent *PluginAgent) sampling.Strategy {
+	if value, ok := pluginAgent.Config["sample"].(string); ok {
+		strategy, err := sampling.Parse(value)
+		if err == nil {
+			return strategy
+		}
+		slog.Warn("ignoring sample setting", "agent", pluginAgent.Name, "error", err)
+	}
+	return e.options.Sample
+}
 
+// reportAssignee returns who should own a report issue created by the agent
+func (e *PluginExecutor) reportAssignee(pluginAgent *PluginAgent, reportType string) string {
+	if value, ok := pluginAgent.Config["assignee"].(string); ok && value != "" {
+		return strings.TrimPrefix(value, "@")
+	}
+	return e.options.ReportAssignees[reportType]
+}
+
+// Execute runs a plugin agent
+func (e *PluginExecutor) Execute(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (*Result, error) {
 	// Execute actions based on agent type
 	switch {
 	case pluginAgent.Name == "Task Validator" || strings.Contains(strings.ToLower(pluginAgent.Name), "validator"):
@@ -46,6 +127,8 @@ func (e *PluginExecutor) Execute(ctx context.Context, pluginAgent *PluginAgent,
 		return e.executeCodeReview(ctx, pluginAgent, params)
 	case strings.Contains(strings.ToLower(pluginAgent.Name), "deployment"):
 		return e.executeDeployment(ctx, pluginAgent, params)
+	case strings.Contains(strings.ToLower(pluginAgent.Name), "dedup") || strings.Contains(strings.ToLower(pluginAgent.Name), "duplicate"):
+		return e.executeDeduplicator(ctx, pluginAgent, params)
 	case pluginAgent.Name == "Executive Summary Generator" || strings.Contains(strings.ToLower(pluginAgent.Name), "executive summary"):
 		return e.executeExecutiveSummary(ctx, pluginAgent, params)
 	case pluginAgent.Name == "Progress Reporter" || strings.Contains(strings.ToLower(pluginAgent.Name), "progress reporter"):
@@ -59,7 +142,7 @@ func (e *PluginExecutor) Execute(ctx context.Context, pluginAgent *PluginAgent,
 }
 
 // executeValidator executes a task validator plugin
-func (e *PluginExecutor) executeValidator(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (map[string]interface{}, error) {
+func (e *PluginExecutor) executeValidator(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (*Result, error) {
 	// Try both "issue_number" and "issue" for compatibility
 	var issueNum int
 	var ok bool
@@ -111,14 +194,18 @@ func (e *PluginExecutor) executeValidator(ctx context.Context, pluginAgent *Plug
 	// Use the actual validator agent to perform validation
 	// Create validator rules with defaults
 	rules := agent.TaskFormatRules{
-		RequiredSections:     []string{"Description", "Acceptance Criteria"},
+		RequiredSections:     guidelines.Sections("Description", "Acceptance Criteria"),
 		MinDescriptionLength: 50,
 		RequireLabels:        true,
 		LabelPrefix:          "priority:",
 	}
 
 	// Create validator instance
-	validatorInstance := agent.NewValidator(e.githubClient, e.llmClient, rules, nil)
+	validatorInstance := agent.NewValidatorWithOptions(e.githubClient, e.llmClient, rules, nil, agent.ValidatorOptions{
+		Identity:      e.options.Identity,
+		SkipLabels:    e.options.SkipLabels,
+		StrictPrompts: e.options.StrictPrompts,
+	})
 
 	// Get all open issues in the project
 	allIssues, err := e.githubClient.ListIssues(ctx, "open")
@@ -126,9 +213,16 @@ func (e *PluginExecutor) executeValidator(ctx context.Context, pluginAgent *Plug
 		return nil, fmt.Errorf("failed to list issues: %w", err)
 	}
 
-	// Filter issues that don't have the "agent-validator" label
+	// Filter issues that don't have the "agent-validator" label, leaving
+	// protected issues and the agents' own reports alone
+	allIssues = agent.ExcludeAgentGenerated(allIssues)
 	issuesToValidate := make([]*github.Issue, 0)
+	protected := []int{}
 	for _, issue := range allIssues {
+		if _, ok := agent.ProtectedBy(issue, e.options.SkipLabels); ok {
+			protected = append(protected, issue.Number)
+			continue
+		}
 		hasValidatorLabel := false
 		for _, label := range issue.Labels {
 			if label == "agent-validator" {
@@ -143,17 +237,17 @@ func (e *PluginExecutor) executeValidator(ctx context.Context, pluginAgent *Plug
 
 	if len(issuesToValidate) == 0 {
 		// All issues already validated
-		result := map[string]interface{}{
-			"agent":           pluginAgent.Name,
-			"status":          "completed",
-			"total_issues":    len(allIssues),
-			"validated_count": 0,
-			"skipped_count":   len(allIssues),
-			"message":         "All issues already validated (have 'agent-validator' label)",
-		}
+		result := newResult(pluginAgent)
+		result.Status = StatusSkipped
+		result.Message = "All issues already validated (have 'agent-validator' label)"
+		result.Metrics["total_issues"] = float64(len(allIssues))
+		result.Metrics["validated_count"] = 0
+		result.Metrics["skipped_count"] = float64(len(allIssues))
+		result.Extra["protected"] = protected
+		result.legacyStatus = StatusCompleted
 		if specificIssue != nil {
-			result["issue"] = specificIssue.Number
-			result["title"] = specificIssue.Title
+			result.setLegacy("issue", specificIssue.Number)
+			result.setLegacy("title", specificIssue.Title)
 		}
 		return result, nil
 	}
@@ -163,19 +257,39 @@ func (e *PluginExecutor) executeValidator(ctx context.Context, pluginAgent *Plug
 	var errors []string
 	validatedIssues := make([]map[string]interface{}, 0)
 
-	for _, issue := range issuesToValidate {
-		// Actually run the validation
+	// Validate concurrently per repository; each worker only touches its own
+	// issue and its own slot, and outcomes are aggregated below in issue order
+	type outcome struct {
+		valid   bool
+		comment string
+		err     error
+	}
+	outcomes := make([]outcome, len(issuesToValidate))
+	pool.ForEach(len(issuesToValidate), e.concurrency(pluginAgent), func(i int) string {
+		owner, repo := github.ParseRepoFromURL(issuesToValidate[i].URL)
+		return owner + "/" + repo
+	}, func(i int) {
+		issue := issuesToValidate[i]
 		valid, comment, err := validatorInstance.ValidateAndFix(ctx, issue)
 		if err != nil {
-			errors = append(errors, fmt.Sprintf("issue #%d: %v", issue.Number, err))
-			continue
+			outcomes[i] = outcome{err: err}
+			return
 		}
 
 		// Add "agent-validator" label to mark this issue as validated
-		owner, repo := extractRepoFromURL(issue.URL)
+		owner, repo := github.ParseRepoFromURL(issue.URL)
 		if err := e.githubClient.AddLabel(ctx, owner, repo, issue.Number, "agent-validator"); err != nil {
 			// Log error but don't fail - label addition is not critical
-			fmt.Printf("Warning: failed to add 'agent-validator' label to issue #%d: %v\n", issue.Number, err)
+			slog.Warn("failed to add agent-validator label", "issue", issue.Number, "error", err)
+		}
+		outcomes[i] = outcome{valid: valid, comment: comment}
+	})
+
+	for i, issue := range issuesToValidate {
+		valid, comment, err := outcomes[i].valid, outcomes[i].comment, outcomes[i].err
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("issue #%d: %v", issue.Number, err))
+			continue
 		}
 
 		validatedCount++
@@ -193,32 +307,31 @@ func (e *PluginExecutor) executeValidator(ctx context.Context, pluginAgent *Plug
 	}
 
 	// Build result
-	result := map[string]interface{}{
-		"agent":            pluginAgent.Name,
-		"status":           "completed",
-		"total_issues":     len(allIssues),
-		"validated_count":  validatedCount,
-		"fixed_count":      fixedCount,
-		"skipped_count":    len(allIssues) - validatedCount,
-		"validated_issues": validatedIssues,
-		"message":          fmt.Sprintf("Validated %d issues (%d fixed, %d already valid), %d skipped (already validated)", validatedCount, fixedCount, validatedCount-fixedCount, len(allIssues)-validatedCount),
-	}
-
+	result := newResult(pluginAgent)
+	result.Message = fmt.Sprintf("Validated %d issues (%d fixed, %d already valid), %d skipped (already validated)", validatedCount, fixedCount, validatedCount-fixedCount, len(allIssues)-validatedCount)
+	for _, validated := range validatedIssues {
+		result.affect(validated["number"].(int))
+	}
+	result.Metrics["total_issues"] = float64(len(allIssues))
+	result.Metrics["validated_count"] = float64(validatedCount)
+	result.Metrics["fixed_count"] = float64(fixedCount)
+	result.Metrics["skipped_count"] = float64(len(allIssues) - validatedCount)
+	result.Extra["validated_issues"] = validatedIssues
+	result.Extra["protected"] = protected
+	result.Errors = errors
 	if specificIssue != nil {
-		result["requested_issue"] = specificIssue.Number
-		result["requested_title"] = specificIssue.Title
+		result.setLegacy("requested_issue", specificIssue.Number)
+		result.setLegacy("requested_title", specificIssue.Title)
 	}
-
 	if len(errors) > 0 {
-		result["errors"] = errors
-		result["error_count"] = len(errors)
+		result.setLegacy("error_count", len(errors))
 	}
 
 	return result, nil
 }
 
 // executeMonitor executes a stale task monitor plugin
-func (e *PluginExecutor) executeMonitor(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (map[string]interface{}, error) {
+func (e *PluginExecutor) executeMonitor(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (*Result, error) {
 	// Get stale threshold from configuration (default: 7 days)
 	staleThresholdDays := 7
 	if val, ok := pluginAgent.Config["stale_threshold_days"]; ok {
@@ -229,7 +342,9 @@ func (e *PluginExecutor) executeMonitor(ctx context.Context, pluginAgent *Plugin
 		}
 	}
 
-	threshold := time.Now().AddDate(0, 0, -staleThresholdDays)
+	gracePeriodDays := configInt(pluginAgent, "grace_period_days", 0)
+
+	now := time.Now()
 	var issuesToCheck []*github.Issue
 	var checkedIssue *github.Issue
 
@@ -256,23 +371,27 @@ func (e *PluginExecutor) executeMonitor(ctx context.Context, pluginAgent *Plugin
 	var staleIssues []int
 	var commentedIssues []int
 	var errors []string
+	protected := []int{}
 
 	// Check each issue
+	metrics.IssuesProcessed.Add(float64(len(issuesToCheck)))
 	for _, issue := range issuesToCheck {
+		if _, ok := agent.ProtectedBy(issue, e.options.SkipLabels); ok {
+			protected = append(protected, issue.Number)
+			continue
+		}
+
 		// Only check issues that are assigned
 		if issue.Assignee == "" {
 			continue
 		}
 
 		// Check if stale
-		if issue.UpdatedAt.Before(threshold) {
+		if agent.IsStale(issue, now, staleThresholdDays, gracePeriodDays) {
 			staleIssues = append(staleIssues, issue.Number)
 			daysStale := int(time.Since(issue.UpdatedAt).Hours() / 24)
 
 			// Generate message using LLM
-			var prompt string
-			templateName := e.extractTemplateName(pluginAgent)
-
 			// Prepare data for prompt template
 			data := map[string]interface{}{
 				"Title":       issue.Title,
@@ -284,12 +403,7 @@ func (e *PluginExecutor) executeMonitor(ctx context.Context, pluginAgent *Plugin
 			}
 
 			// Try to load and render prompt template
-			if e.promptLoader != nil && templateName != "" && e.promptLoader.HasTemplate(templateName) {
-				rendered, err := e.promptLoader.Render(templateName, data)
-				if err == nil {
-					prompt = rendered
-				}
-			}
+			prompt := e.renderPrompt(pluginAgent, data)
 
 			// Fallback prompt
 			if prompt == "" {
@@ -314,7 +428,7 @@ The task has been in progress for %d days without updates. Ask for a status upda
 			}
 
 			// Generate message using LLM
-			message, err := e.llmClient.Prompt(prompt)
+			message, err := e.llmClient.PromptContext(ctx, prompt)
 			if err != nil {
 				// Fallback to a simple message
 				message = fmt.Sprintf("👋 Hey @%s! This task has been in progress for %d days. Could you share a quick status update? Thanks! 🙏",
@@ -331,11 +445,11 @@ The task has been in progress for %d days without updates. Ask for a status upda
 			}
 
 			// Format message with agent prefix
-			message = fmt.Sprintf("🤖 **%s**: %s", pluginAgent.Name, message)
+			message = e.options.Identity.Format(pluginAgent.Name, message)
 
 			// Add comment to issue
-			owner, repo := extractRepoFromURL(issue.URL)
-			if err := e.githubClient.AddComment(ctx, owner, repo, issue.Number, message); err != nil {
+			owner, repo := github.ParseRepoFromURL(issue.URL)
+			if err := e.addComment(ctx, owner, repo, issue.Number, message); err != nil {
 				errors = append(errors, fmt.Sprintf("issue #%d: %v", issue.Number, err))
 			} else {
 				commentedIssues = append(commentedIssues, issue.Number)
@@ -344,41 +458,47 @@ The task has been in progress for %d days without updates. Ask for a status upda
 	}
 
 	// Build result
-	result := map[string]interface{}{
-		"agent":            pluginAgent.Name,
-		"status":           "monitored",
-		"total_checked":    len(issuesToCheck),
-		"stale_issues":     staleIssues,
-		"commented_issues": commentedIssues,
-		"stale_threshold":  fmt.Sprintf("%d days", staleThresholdDays),
+	result := newResult(pluginAgent)
+	if len(staleIssues) > 0 {
+		result.Status = StatusAttention
+	}
+	result.affect(commentedIssues...)
+	result.Metrics["total_checked"] = float64(len(issuesToCheck))
+	result.Metrics["stale_count"] = float64(len(staleIssues))
+	result.Metrics["stale_threshold_days"] = float64(staleThresholdDays)
+	result.Extra["stale_issues"] = staleIssues
+	result.Extra["protected"] = protected
+	result.Errors = errors
+	result.legacyStatus = "monitored"
+	result.setLegacy("commented_issues", commentedIssues)
+	result.setLegacy("stale_threshold", fmt.Sprintf("%d days", staleThresholdDays))
+	if len(errors) > 0 {
+		result.setLegacy("warning", fmt.Sprintf("Some comments failed: %d errors", len(errors)))
 	}
 
 	if checkedIssue != nil {
-		result["issue"] = checkedIssue.Number
-		result["title"] = checkedIssue.Title
-		if len(staleIssues) > 0 {
-			result["is_stale"] = true
-			result["days_stale"] = int(time.Since(checkedIssue.UpdatedAt).Hours() / 24)
-			result["message"] = fmt.Sprintf("Issue #%d is stale and has been commented", checkedIssue.Number)
+		result.setLegacy("issue", checkedIssue.Number)
+		result.setLegacy("title", checkedIssue.Title)
+		result.Extra["is_stale"] = len(staleIssues) > 0
+		if len(protected) > 0 {
+			result.Status = StatusSkipped
+			result.Message = fmt.Sprintf("Issue #%d %s", checkedIssue.Number, agent.SkipProtected)
+		} else if len(staleIssues) > 0 {
+			result.Metrics["days_stale"] = float64(int(time.Since(checkedIssue.UpdatedAt).Hours() / 24))
+			result.Message = fmt.Sprintf("Issue #%d is stale and has been commented", checkedIssue.Number)
 		} else {
-			result["is_stale"] = false
-			result["message"] = fmt.Sprintf("Issue #%d is not stale", checkedIssue.Number)
+			result.Message = fmt.Sprintf("Issue #%d is not stale", checkedIssue.Number)
 		}
 	} else {
-		result["total_issues"] = len(issuesToCheck)
-		result["message"] = fmt.Sprintf("Checked %d issues, found %d stale, commented on %d", len(issuesToCheck), len(staleIssues), len(commentedIssues))
-	}
-
-	if len(errors) > 0 {
-		result["errors"] = errors
-		result["warning"] = fmt.Sprintf("Some comments failed: %d errors", len(errors))
+		result.Message = fmt.Sprintf("Checked %d issues, found %d stale, commented on %d", len(issuesToCheck), len(staleIssues), len(commentedIssues))
+		result.setLegacy("total_issues", len(issuesToCheck))
 	}
 
 	return result, nil
 }
 
 // executeRoaster executes a product roaster plugin
-func (e *PluginExecutor) executeRoaster(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (map[string]interface{}, error) {
+func (e *PluginExecutor) executeRoaster(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (*Result, error) {
 	// Load prompt (for future use)
 	_, _ = e.loadPrompt(pluginAgent)
 
@@ -388,56 +508,593 @@ func (e *PluginExecutor) executeRoaster(ctx context.Context, pluginAgent *Plugin
 		return nil, fmt.Errorf("failed to list issues: %w", err)
 	}
 
-	result := map[string]interface{}{
-		"agent":        pluginAgent.Name,
-		"status":       "analyzed",
-		"total_issues": len(issues),
-		"message":      "Product analysis completed",
-	}
+	result := newResult(pluginAgent)
+	result.Message = "Product analysis completed"
+	result.Metrics["total_issues"] = float64(len(issues))
+	result.legacyStatus = "analyzed"
 
 	return result, nil
 }
 
 // executeCodeReview executes a code review plugin
-func (e *PluginExecutor) executeCodeReview(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (map[string]interface{}, error) {
-	// This would implement code review logic
-	// For now, return a placeholder
-	result := map[string]interface{}{
-		"agent":   pluginAgent.Name,
-		"status":  "reviewed",
-		"message": "Code review plugin executed (custom implementation needed)",
+func (e *PluginExecutor) executeCodeReview(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (*Result, error) {
+	openPRs, err := e.githubClient.ListPullRequests(ctx, "open")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+
+	// Review the requested PR, or every open PR carrying the review label
+	var toReview []*github.PullRequest
+	if number, ok := e.extractIssueNumber(params); ok {
+		for _, pr := range openPRs {
+			if pr.Number == number {
+				toReview = append(toReview, pr)
+				break
+			}
+		}
+		if len(toReview) == 0 {
+			return nil, fmt.Errorf("pull request #%d not found among open pull requests", number)
+		}
+	} else {
+		reviewLabel := configString(pluginAgent, "review_label", "needs-review")
+		for _, pr := range openPRs {
+			if reviewLabel == "" || github.HasLabels(pr.Labels, []string{reviewLabel}) {
+				toReview = append(toReview, pr)
+			}
+		}
+	}
+
+	// Leave protected pull requests alone
+	protected := []int{}
+	unprotected := toReview[:0]
+	for _, pr := range toReview {
+		if label, ok := agent.ProtectedByLabels(pr.Labels, e.options.SkipLabels); ok {
+			slog.Info("skipping protected pull request", "pr", pr.Number, "label", label)
+			protected = append(protected, pr.Number)
+			continue
+		}
+		unprotected = append(unprotected, pr)
+	}
+	toReview = unprotected
+
+	maxDiffBytes := configInt(pluginAgent, "max_diff_bytes", defaultMaxDiffBytes)
+	var reviewed []int
+	var errors []string
+	alreadyReviewed := 0
+	for _, pr := range toReview {
+		posted, err := e.reviewPullRequest(ctx, pluginAgent, pr, maxDiffBytes)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("PR #%d: %v", pr.Number, err))
+			continue
+		}
+		if !posted {
+			alreadyReviewed++
+			continue
+		}
+		reviewed = append(reviewed, pr.Number)
 	}
 
+	result := newResult(pluginAgent)
+	result.Message = fmt.Sprintf("Reviewed %d of %d pull requests", len(reviewed), len(toReview))
+	result.affect(reviewed...)
+	result.Metrics["reviewed_count"] = float64(len(reviewed))
+	result.Metrics["already_reviewed"] = float64(alreadyReviewed)
+	result.Extra["protected"] = protected
+	result.legacyStatus = "reviewed"
+	result.setLegacy("reviewed", reviewed)
+	if len(errors) > 0 {
+		result.Errors = errors
+		if len(reviewed) == 0 {
+			return result, fmt.Errorf("failed to review pull requests: %s", strings.Join(errors, "; "))
+		}
+	}
 	return result, nil
 }
 
-// executeDeployment executes a deployment checker plugin
-func (e *PluginExecutor) executeDeployment(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (map[string]interface{}, error) {
-	// This would implement deployment checking logic
-	// For now, return a placeholder
-	result := map[string]interface{}{
-		"agent":   pluginAgent.Name,
-		"status":  "checked",
-		"message": "Deployment checker plugin executed (custom implementation needed)",
+// codeReviewHeader starts every code review comment
+const codeReviewHeader = "🔍 **Code Review**"
+
+// defaultMaxDiffBytes bounds the diff sent to the LLM when the agent doesn't
+// set max_diff_bytes
+const defaultMaxDiffBytes = 60000
+
+// reviewPullRequest sends a PR's diff to the LLM and posts the review as a
+// comment on the PR. It returns false without reviewing when the agent
+// already reviewed the PR's head commit.
+func (e *PluginExecutor) reviewPullRequest(ctx context.Context, pluginAgent *PluginAgent, pr *github.PullRequest, maxDiffBytes int) (bool, error) {
+	owner, repo := github.ParseRepoFromURL(pr.URL)
+	if pr.HeadSHA != "" {
+		comments, err := e.githubClient.GetIssueComments(ctx, owner, repo, pr.Number)
+		if err != nil {
+			return false, fmt.Errorf("failed to read comments: %w", err)
+		}
+		for _, comment := range comments {
+			if e.isAgentComment(pluginAgent, codeReviewHeader, comment.Body) && strings.Contains(comment.Body, reviewedSHAMarker(pr.HeadSHA)) {
+				slog.Info("skipping code review: head commit already reviewed", "pr", pr.Number, "sha", pr.HeadSHA)
+				return false, nil
+			}
+		}
+	}
+
+	diff, err := e.githubClient.GetPullRequestDiff(ctx, owner, repo, pr.Number)
+	if err != nil {
+		return false, err
+	}
+	diff, truncated := truncateDiff(diff, maxDiffBytes)
+
+	data := map[string]interface{}{
+		"Title":     pr.Title,
+		"Body":      pr.Body,
+		"Author":    pr.Author,
+		"Diff":      diff,
+		"Truncated": truncated,
+	}
+	e.addRepoContext(ctx, pluginAgent, data, nil)
+
+	prompt := e.renderPrompt(pluginAgent, data)
+
+	// Fallback prompt
+	if prompt == "" {
+		note := ""
+		if truncated {
+			note = "\n\nThe diff was truncated; only review what is shown."
+		}
+		prompt = fmt.Sprintf(`You are a senior engineer reviewing a pull request.
+
+Title: %s
+Description:
+%s
+
+Diff:
+%s%s
+
+Point out bugs, risky changes, missing tests and unclear code, citing file names. Keep it concise and actionable. If the change looks good, say so briefly.`,
+			pr.Title, pr.Body, diff, note)
+	}
+
+	review, err := e.llmClient.PromptContext(ctx, prompt)
+	if err != nil {
+		return false, fmt.Errorf("failed to generate review: %w", err)
+	}
+
+	comment := e.signedComment(pluginAgent, codeReviewHeader, cleanMarkdownResponse(review))
+	if truncated {
+		comment += fmt.Sprintf("\n\n_Only the first %d bytes of the diff were reviewed._", maxDiffBytes)
+	}
+	if pr.HeadSHA != "" {
+		comment += "\n\n" + reviewedSHAMarker(pr.HeadSHA)
+	}
+	if err := e.addComment(ctx, owner, repo, pr.Number, comment); err != nil {
+		return false, fmt.Errorf("failed to post review: %w", err)
+	}
+	return true, nil
+}
+
+// reviewedSHAMarker records in a review comment which head commit was
+// reviewed, so the PR is only reviewed again after new commits
+func reviewedSHAMarker(sha string) string {
+	return "<!-- reviewed-sha: " + sha + " -->"
+}
+
+// truncateDiff cuts a diff to at most maxBytes, at a line boundary when
+// possible. A limit below 1 disables truncation.
+func truncateDiff(diff string, maxBytes int) (string, bool) {
+	if maxBytes <= 0 || len(diff) <= maxBytes {
+		return diff, false
+	}
+	cut := diff[:maxBytes]
+	if idx := strings.LastIndex(cut, "\n"); idx > 0 {
+		cut = cut[:idx+1]
+	}
+	return cut, true
+}
+
+// configString returns a string setting from the agent configuration
+func configString(pluginAgent *PluginAgent, key, fallback string) string {
+	if value, ok := pluginAgent.Config[key].(string); ok {
+		return value
+	}
+	return fallback
+}
+
+// configFloat returns a numeric setting from the agent configuration
+func configFloat(pluginAgent *PluginAgent, key string, fallback float64) float64 {
+	switch value := pluginAgent.Config[key].(type) {
+	case int:
+		return float64(value)
+	case float64:
+		return value
+	}
+	return fallback
+}
+
+// configInt returns an integer setting from the agent configuration
+func configInt(pluginAgent *PluginAgent, key string, fallback int) int {
+	switch value := pluginAgent.Config[key].(type) {
+	case int:
+		return value
+	case float64:
+		return int(value)
+	}
+	return fallback
+}
+
+// executeDeployment checks recent GitHub deployments, summarizes failed and
+// stuck ones with the LLM, and optionally opens an issue for each deployment
+// pending longer than pending_timeout
+func (e *PluginExecutor) executeDeployment(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (*Result, error) {
+	var owner, repo string
+	if value := configString(pluginAgent, "repo", ""); value != "" {
+		parts := strings.SplitN(value, "/", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid repo %q (expected owner/name)", value)
+		}
+		owner, repo = parts[0], parts[1]
+	}
+
+	pendingTimeout := defaultPendingTimeout
+	if value := configString(pluginAgent, "pending_timeout", ""); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pending_timeout %q: %w", value, err)
+		}
+		pendingTimeout = timeout
+	}
+	lookback := time.Duration(configInt(pluginAgent, "lookback_hours", 24)) * time.Hour
+
+	deployments, err := e.githubClient.ListDeployments(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	now := time.Now()
+	var failed, stuck []deploymentCheck
+	checked := 0
+	for _, deployment := range deployments {
+		statuses, err := e.githubClient.GetDeploymentStatuses(ctx, owner, repo, deployment.ID)
+		if err != nil {
+			return nil, err
+		}
+		check := newDeploymentCheck(deployment, statuses)
+
+		age := now.Sub(deployment.CreatedAt)
+		switch {
+		case check.pending():
+			// A deployment becomes stuck pending_timeout after its last
+			// status, and is reported for lookback_hours from then on;
+			// older ones were abandoned
+			if age > lookback+pendingTimeout {
+				continue
+			}
+			if now.Sub(check.Since) > pendingTimeout {
+				stuck = append(stuck, check)
+			}
+		case age > lookback:
+			continue
+		case check.State == "failure" || check.State == "error":
+			failed = append(failed, check)
+		}
+		checked++
+	}
+
+	result := newResult(pluginAgent)
+	result.Message = fmt.Sprintf("Checked %d deployments: no failed or stuck deployments", checked)
+	result.Metrics["checked"] = float64(checked)
+	result.Metrics["failed_count"] = float64(len(failed))
+	result.Metrics["stuck_count"] = float64(len(stuck))
+	result.Extra["failed"] = deploymentRefs(failed)
+	result.Extra["stuck"] = deploymentRefs(stuck)
+	if len(failed) == 0 && len(stuck) == 0 {
+		result.legacyStatus = "healthy"
+		return result, nil
+	}
+	result.Status = StatusAttention
+	result.Message = fmt.Sprintf("Checked %d deployments: %d failed, %d stuck", checked, len(failed), len(stuck))
+
+	data := map[string]interface{}{
+		"Failed":         formatDeploymentChecks(failed, now),
+		"Stuck":          formatDeploymentChecks(stuck, now),
+		"PendingTimeout": pendingTimeout.String(),
+	}
+	e.addRepoContext(ctx, pluginAgent, data, nil)
+
+	prompt := e.renderPrompt(pluginAgent, data)
+
+	// Fallback prompt
+	if prompt == "" {
+		prompt = fmt.Sprintf(`Summarize these deployment problems for the team.
+
+Failed deployments:
+%s
+
+Deployments pending longer than %s:
+%s
+
+For each, say what likely went wrong based on the status descriptions and what to check next. Be concise.`,
+			data["Failed"], pendingTimeout, data["Stuck"])
+	}
+
+	summary, err := e.llmClient.PromptContext(ctx, prompt)
+	if err != nil {
+		// The checks are still useful without a summary
+		slog.Warn("failed to summarize deployments", "error", err)
+	} else {
+		summary = cleanMarkdownResponse(summary)
+		result.Extra["summary"] = summary
+	}
+
+	if createIssues, _ := pluginAgent.Config["create_issue_on_stuck"].(bool); createIssues && len(stuck) > 0 {
+		created, err := e.openStuckDeploymentIssues(ctx, pluginAgent, owner, repo, stuck, summary, now)
+		if err != nil {
+			slog.Warn("failed to open stuck deployment issues", "error", err)
+		}
+		result.affect(created...)
+		result.Extra["created_issues"] = created
 	}
 
 	return result, nil
 }
 
+// defaultPendingTimeout is how long a deployment may stay pending before it
+// counts as stuck
+const defaultPendingTimeout = time.Hour
+
+// deploymentCheck is a deployment with its latest state
+type deploymentCheck struct {
+	Deployment  *github.Deployment
+	State       string    // Latest status; "pending" when none was reported
+	Description string    // Latest status description
+	Since       time.Time // When the deployment entered State
+	LogURL      string
+}
+
+func newDeploymentCheck(deployment *github.Deployment, statuses []*github.DeploymentStatus) deploymentCheck {
+	check := deploymentCheck{Deployment: deployment, State: "pending", Since: deployment.CreatedAt}
+	if len(statuses) > 0 {
+		latest := statuses[0]
+		check.State = latest.State
+		check.Description = latest.Description
+		check.Since = latest.CreatedAt
+		check.LogURL = latest.LogURL
+	}
+	return check
+}
+
+// pending reports whether the deployment hasn't finished yet
+func (c deploymentCheck) pending() bool {
+	return c.State == "pending" || c.State == "queued" || c.State == "in_progress"
+}
+
+// title names the deployment in comments and issues
+func (c deploymentCheck) title() string {
+	sha := c.Deployment.SHA
+	if len(sha) > 7 {
+		sha = sha[:7]
+	}
+	return fmt.Sprintf("%s deployment of %s (%s)", c.Deployment.Environment, c.Deployment.Ref, sha)
+}
+
+func deploymentRefs(checks []deploymentCheck) []int64 {
+	ids := []int64{}
+	for _, check := range checks {
+		ids = append(ids, check.Deployment.ID)
+	}
+	return ids
+}
+
+func formatDeploymentChecks(checks []deploymentCheck, now time.Time) string {
+	if len(checks) == 0 {
+		return "None"
+	}
+	var lines []string
+	for _, check := range checks {
+		line := fmt.Sprintf("- %s: %s for %s", check.title(), check.State, now.Sub(check.Since).Round(time.Minute))
+		if check.Description != "" {
+			line += fmt.Sprintf(" (%s)", check.Description)
+		}
+		if check.LogURL != "" {
+			line += fmt.Sprintf(" [logs](%s)", check.LogURL)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// openStuckDeploymentIssues opens one issue per stuck deployment, skipping
+// deployments that already have an open issue
+func (e *PluginExecutor) openStuckDeploymentIssues(ctx context.Context, pluginAgent *PluginAgent, owner, repo string, stuck []deploymentCheck, summary string, now time.Time) ([]int, error) {
+	label := configString(pluginAgent, "stuck_label", "deployment-stuck")
+	var created []int
+	for _, check := range stuck {
+		title := "Deployment stuck: " + check.title()
+		body := e.signedComment(pluginAgent, "🚦 **Deployment Stuck**", formatDeploymentChecks([]deploymentCheck{check}, now))
+		if summary != "" {
+			body += "\n\n## Analysis\n\n" + summary
+		}
+		issue, isNew, err := github.CreateIssueIfNotExists(ctx, e.githubClient, owner, repo, title, body, []string{"automated", label})
+		if err != nil {
+			return created, err
+		}
+		if isNew {
+			created = append(created, issue.Number)
+		}
+	}
+	return created, nil
+}
+
+// executeDeduplicator finds open issues with near-identical titles and
+// bodies by TF-IDF cosine similarity, asks the LLM to confirm the top
+// candidates, and comments on each duplicate linking the older, canonical
+// issue. Nothing is ever closed.
+func (e *PluginExecutor) executeDeduplicator(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (*Result, error) {
+	issues, err := e.githubClient.ListIssues(ctx, "open")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+
+	// Compare only the requested issue against the rest
+	var only *github.Issue
+	if number, ok := e.extractIssueNumber(params); ok {
+		for _, issue := range issues {
+			if issue.Number == number {
+				only = issue
+				break
+			}
+		}
+		if only == nil {
+			return nil, fmt.Errorf("issue #%d not found among open issues", number)
+		}
+	}
+
+	threshold := configFloat(pluginAgent, "similarity_threshold", defaultSimilarityThreshold)
+	maxCandidates := configInt(pluginAgent, "max_candidates", 10)
+	confirm := true
+	if value, ok := pluginAgent.Config["llm_confirm"].(bool); ok {
+		confirm = value
+	}
+
+	candidates := similarIssues(issues, only, threshold)
+	if len(candidates) > maxCandidates {
+		candidates = candidates[:maxCandidates]
+	}
+
+	duplicates := []map[string]interface{}{}
+	flagged := make(map[*github.Issue]bool)
+	var errors []string
+	for _, pair := range candidates {
+		// Link each duplicate to its best match only
+		if flagged[pair.Newer] {
+			continue
+		}
+		if label, ok := agent.ProtectedBy(pair.Newer, e.options.SkipLabels); ok {
+			slog.Info("skipping protected issue", "issue", pair.Newer.Number, "label", label)
+			continue
+		}
+		// Check for an earlier notice before spending an LLM call on the pair
+		notified, err := e.duplicateNotified(ctx, pluginAgent, pair)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("#%d: %v", pair.Newer.Number, err))
+			continue
+		}
+		if !notified {
+			if confirm && !e.confirmDuplicate(ctx, pluginAgent, pair) {
+				continue
+			}
+			if err := e.commentDuplicate(ctx, pluginAgent, pair); err != nil {
+				errors = append(errors, fmt.Sprintf("#%d: %v", pair.Newer.Number, err))
+				continue
+			}
+		}
+		flagged[pair.Newer] = true
+		duplicates = append(duplicates, map[string]interface{}{
+			"issue":        pair.Newer.Number,
+			"duplicate_of": pair.Older.Number,
+			"similarity":   math.Round(pair.Similarity*100) / 100,
+			"commented":    !notified,
+		})
+	}
+
+	result := newResult(pluginAgent)
+	result.Message = fmt.Sprintf("Checked %d issues: %d suspected duplicates", len(issues), len(duplicates))
+	for _, duplicate := range duplicates {
+		if duplicate["commented"].(bool) {
+			result.affect(duplicate["issue"].(int))
+		}
+	}
+	result.Metrics["checked"] = float64(len(issues))
+	result.Metrics["candidates"] = float64(len(candidates))
+	result.Extra["duplicates"] = duplicates
+	result.Errors = errors
+	return result, nil
+}
+
+// defaultSimilarityThreshold is deliberately high so only near-identical
+// issues are flagged
+const defaultSimilarityThreshold = 0.6
+
+// confirmDuplicate asks the LLM whether a candidate pair describes the same
+// work. Any failure counts as "no" so the agent stays conservative.
+func (e *PluginExecutor) confirmDuplicate(ctx context.Context, pluginAgent *PluginAgent, pair similarPair) bool {
+	data := map[string]interface{}{
+		"CanonicalNumber": pair.Older.Number,
+		"CanonicalTitle":  pair.Older.Title,
+		"CanonicalBody":   pair.Older.Body,
+		"Number":          pair.Newer.Number,
+		"Title":           pair.Newer.Title,
+		"Body":            pair.Newer.Body,
+	}
+
+	prompt := e.renderPrompt(pluginAgent, data)
+
+	// Fallback prompt
+	if prompt == "" {
+		prompt = fmt.Sprintf(`Do these two GitHub issues ask for the same work?
+
+Issue #%d: %s
+%s
+
+Issue #%d: %s
+%s
+
+Answer DUPLICATE only if completing one would fully resolve the other; otherwise answer DISTINCT. Reply with the single word.`,
+			pair.Older.Number, pair.Older.Title, pair.Older.Body, pair.Newer.Number, pair.Newer.Title, pair.Newer.Body)
+	}
+
+	answer, err := e.llmClient.PromptContext(ctx, prompt)
+	if err != nil {
+		slog.Warn("not flagging duplicate, LLM check failed", "issue", pair.Newer.Number, "duplicate_of", pair.Older.Number, "error", err)
+		return false
+	}
+	return strings.HasPrefix(strings.ToUpper(strings.Trim(strings.TrimSpace(answer), "*`")), "DUPLICATE")
+}
+
+// duplicateCommentHeader starts every duplicate notice
+const duplicateCommentHeader = "🔁 **Possible Duplicate**"
+
+// duplicateNotified reports whether an earlier run already posted a notice on
+// the newer issue linking the canonical one
+func (e *PluginExecutor) duplicateNotified(ctx context.Context, pluginAgent *PluginAgent, pair similarPair) (bool, error) {
+	owner, repo := github.ParseRepoFromURL(pair.Newer.URL)
+	comments, err := e.githubClient.GetIssueComments(ctx, owner, repo, pair.Newer.Number)
+	if err != nil {
+		return false, fmt.Errorf("failed to read comments: %w", err)
+	}
+	for _, comment := range comments {
+		if e.isAgentComment(pluginAgent, duplicateCommentHeader, comment.Body) && strings.Contains(comment.Body, pair.Older.URL) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// commentDuplicate posts a notice on the newer issue linking the canonical one
+func (e *PluginExecutor) commentDuplicate(ctx context.Context, pluginAgent *PluginAgent, pair similarPair) error {
+	owner, repo := github.ParseRepoFromURL(pair.Newer.URL)
+	comment := e.signedComment(pluginAgent, duplicateCommentHeader, fmt.Sprintf("This issue looks like a duplicate of [#%d %s](%s) (%.0f%% similar).\n\n"+
+		"If it is, please close this one in favor of it; otherwise explain what's different so both can be tracked.",
+		pair.Older.Number, pair.Older.Title, pair.Older.URL, pair.Similarity*100))
+	return e.addComment(ctx, owner, repo, pair.Newer.Number, comment)
+}
+
 // executeExecutiveSummary generates an executive summary for C-level stakeholders
-func (e *PluginExecutor) executeExecutiveSummary(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (map[string]interface{}, error) {
+func (e *PluginExecutor) executeExecutiveSummary(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (*Result, error) {
 	// Get all issues for analysis
-	issues, err := e.githubClient.ListIssues(ctx, "open")
+	allIssues, err := e.githubClient.ListIssues(ctx, "open")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list issues: %w", err)
 	}
 
-	// Calculate metrics
-	totalIssues := len(issues)
+	// Bound the analysis on large projects
+	sample := e.sampleStrategy(pluginAgent)
+	issues := sample.Apply(allIssues)
+	sampleDescription := sample.Describe(len(issues), len(allIssues))
+
+	// Calculate metrics (counting is cheap, so use every issue)
+	totalIssues := len(allIssues)
 	var openIssues, inProgress, completed, blocked int
 	issuesByStatus := make(map[string]int)
 
-	for _, issue := range issues {
+	for _, issue := range allIssues {
 		issuesByStatus[issue.State]++
 		if issue.State == "open" {
 			openIssues++
@@ -463,20 +1120,14 @@ func (e *PluginExecutor) executeExecutiveSummary(ctx context.Context, pluginAgen
 		"Completed":      completed,
 		"Blocked":        blocked,
 		"IssuesByStatus": formatIssuesByStatus(issuesByStatus),
-		"RecentIssues":   formatRecentIssues(issues[:min(10, len(issues))]),
+		"RecentIssues":   formatRecentIssues(recentlyCreated(issues, 10)),
 		"Date":           time.Now().Format("2006-01-02"),
+		"Sample":         sampleDescription,
 	}
+	e.addRepoContext(ctx, pluginAgent, data, nil)
 
 	// Load and render prompt template
-	var prompt string
-	templateName := e.extractTemplateName(pluginAgent)
-
-	if e.promptLoader != nil && templateName != "" && e.promptLoader.HasTemplate(templateName) {
-		rendered, err := e.promptLoader.Render(templateName, data)
-		if err == nil {
-			prompt = rendered
-		}
-	}
+	prompt := e.renderPrompt(pluginAgent, data)
 
 	// Fallback prompt
 	if prompt == "" {
@@ -492,13 +1143,14 @@ Provide a high-level strategic overview focusing on business impact, risks, and
 	}
 
 	// Generate summary using LLM
-	summary, err := e.llmClient.Prompt(prompt)
+	summary, err := e.llmClient.PromptContext(ctx, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate executive summary: %w", err)
 	}
 
 	// Clean up response
 	summary = cleanMarkdownResponse(summary)
+	summary += fmt.Sprintf("\n\n---\n_Based on %s._", sampleDescription)
 
 	// Create summary issue
 	issueTitle := fmt.Sprintf("Executive Summary - %s", time.Now().Format("2006-01-02"))
@@ -506,57 +1158,45 @@ Provide a high-level strategic overview focusing on business impact, risks, and
 	// Get owner/repo from first issue (optional - CreateIssue can handle empty in project mode)
 	var owner, repo string
 	if len(issues) > 0 {
-		owner, repo = extractRepoFromURL(issues[0].URL)
+		owner, repo = github.ParseRepoFromURL(issues[0].URL)
 	} else {
 		// Try to get any issue to determine repo
 		if len(closedIssues) > 0 {
-			owner, repo = extractRepoFromURL(closedIssues[0].URL)
+			owner, repo = github.ParseRepoFromURL(closedIssues[0].URL)
 		}
 	}
 
 	// Always try to create issue (UnifiedClient handles empty owner/repo in project mode)
 	labels := []string{"automated", "executive-summary", "report"}
-	newIssue, err := e.githubClient.CreateIssue(ctx, owner, repo, issueTitle, summary, labels)
+	newIssue, published, err := e.publishReport(ctx, pluginAgent, "executive-summary", owner, repo, issueTitle, summary, labels)
+
+	result := newResult(pluginAgent)
+	result.Metrics["total_issues"] = float64(totalIssues)
+	result.Metrics["open"] = float64(openIssues)
+	result.Metrics["completed"] = float64(completed)
+	result.Metrics["blocked"] = float64(blocked)
+	result.Extra["summary"] = summary
+	result.setLegacy("metrics", map[string]interface{}{
+		"total_issues": totalIssues,
+		"open":         openIssues,
+		"completed":    completed,
+		"blocked":      blocked,
+	})
+	result.Extra["sample"] = sampleDescription
 	if err == nil {
-		result := map[string]interface{}{
-			"agent":                pluginAgent.Name,
-			"status":               "completed",
-			"summary":              summary,
-			"issue_created":        true,
-			"created_issue_number": newIssue.Number,
-			"created_issue_url":    newIssue.URL,
-			"metrics": map[string]interface{}{
-				"total_issues": totalIssues,
-				"open":         openIssues,
-				"completed":    completed,
-				"blocked":      blocked,
-			},
-			"message": fmt.Sprintf("Executive summary generated and issue #%d created", newIssue.Number),
-		}
+		result.Extra["assignee"] = newIssue.Assignee
+		addReportIssue(result, "Executive summary", newIssue, published)
 		return result, nil
 	}
 	// If issue creation fails, still return summary
-	fmt.Printf("Warning: failed to create executive summary issue: %v\n", err)
-
-	// Fallback: return summary even if issue creation failed
-	result := map[string]interface{}{
-		"agent":   pluginAgent.Name,
-		"status":  "completed",
-		"summary": summary,
-		"metrics": map[string]interface{}{
-			"total_issues": totalIssues,
-			"open":         openIssues,
-			"completed":    completed,
-			"blocked":      blocked,
-		},
-		"message": "Executive summary generated successfully (issue creation failed or repo not determined)",
-	}
+	slog.Warn("failed to create executive summary issue", "error", err)
+	result.Message = "Executive summary generated successfully (issue creation failed or repo not determined)"
 
 	return result, nil
 }
 
 // executePriorityCalculator calculates and suggests task priority
-func (e *PluginExecutor) executePriorityCalculator(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (map[string]interface{}, error) {
+func (e *PluginExecutor) executePriorityCalculator(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (*Result, error) {
 	// Get issue number
 	issueNum, hasIssue := e.extractIssueNumber(params)
 	if !hasIssue {
@@ -568,6 +1208,9 @@ func (e *PluginExecutor) executePriorityCalculator(ctx context.Context, pluginAg
 	if err != nil {
 		return nil, fmt.Errorf("failed to get issue: %w", err)
 	}
+	if result, ok := e.skipProtected(pluginAgent, issue); ok {
+		return result, nil
+	}
 
 	// Prepare data for prompt
 	data := map[string]interface{}{
@@ -577,19 +1220,12 @@ func (e *PluginExecutor) executePriorityCalculator(ctx context.Context, pluginAg
 		"State":        issue.State,
 		"Assignee":     issue.Assignee,
 		"CreatedAt":    issue.CreatedAt.Format("2006-01-02"),
-		"Dependencies": extractDependenciesFromBody(issue.Body),
+		"Dependencies": referenceStrings(extractDependenciesFromBody(issue.Body)),
 	}
+	e.addRepoContext(ctx, pluginAgent, data, issue)
 
 	// Load and render prompt template
-	var prompt string
-	templateName := e.extractTemplateName(pluginAgent)
-
-	if e.promptLoader != nil && templateName != "" && e.promptLoader.HasTemplate(templateName) {
-		rendered, err := e.promptLoader.Render(templateName, data)
-		if err == nil {
-			prompt = rendered
-		}
-	}
+	prompt := e.renderPrompt(pluginAgent, data)
 
 	// Fallback prompt
 	if prompt == "" {
@@ -599,50 +1235,67 @@ Title: %s
 Body: %s
 Labels: %s
 
-Consider: business value, effort, dependencies, strategic alignment, urgency.`,
+Consider: business value, effort, dependencies, strategic alignment, urgency.
+
+Respond with only a JSON object: {"priority": "P0|P1|P2|P3", "rationale": "<markdown explanation>"}`,
 			issue.Title, issue.Body, strings.Join(issue.Labels, ", "))
 	}
 
 	// Generate priority assessment
-	assessment, err := e.llmClient.Prompt(prompt)
+	assessment, suggestedPriority, err := e.assessPriority(ctx, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate priority: %w", err)
 	}
 
-	// Clean up response
-	assessment = cleanMarkdownResponse(assessment)
-
-	// Extract suggested priority from assessment
-	suggestedPriority := extractPriorityFromAssessment(assessment)
-
 	// Add comment with assessment
-	owner, repo := extractRepoFromURL(issue.URL)
-	comment := fmt.Sprintf("🎯 **Priority Assessment** (Generated by %s)\n\n%s", pluginAgent.Name, assessment)
-	if err := e.githubClient.AddComment(ctx, owner, repo, issueNum, comment); err != nil {
+	owner, repo := github.ParseRepoFromURL(issue.URL)
+	comment := e.signedComment(pluginAgent, "🎯 **Priority Assessment**", assessment)
+	if err := e.addComment(ctx, owner, repo, issueNum, comment); err != nil {
 		// Log error but don't fail - assessment was generated
-		fmt.Printf("Warning: failed to add priority comment: %v\n", err)
-	}
-
-	// Optionally apply priority label if configured
-	if suggestedPriority != "" {
-		// This would require label management - for now just return the suggestion
-	}
-
-	result := map[string]interface{}{
-		"agent":              pluginAgent.Name,
-		"issue":              issueNum,
-		"title":              issue.Title,
-		"status":             "completed",
-		"suggested_priority": suggestedPriority,
-		"assessment":         assessment,
-		"message":            fmt.Sprintf("Priority assessment generated for issue #%d", issueNum),
+		slog.Warn("failed to add priority comment", "issue", issueNum, "error", err)
+	}
+
+	result := newResult(pluginAgent)
+	result.Message = fmt.Sprintf("Priority assessment generated for issue #%d", issueNum)
+	result.affect(issueNum)
+	result.setLegacy("issue", issueNum)
+	result.setLegacy("title", issue.Title)
+	result.Extra["suggested_priority"] = suggestedPriority
+	result.Extra["assessment"] = assessment
+
+	// Apply the suggested priority label if configured. A label map names
+	// the repo's own priority labels; without one, auto_apply uses
+	// priority_labels.
+	labelMap, problems := priorityLabelMap(pluginAgent)
+	for _, problem := range problems {
+		slog.Warn("ignoring priority label mapping", "agent", pluginAgent.Name, "problem", problem)
+	}
+	if len(labelMap) > 0 && suggestedPriority != "" {
+		label, ok := labelMap[suggestedPriority]
+		if !ok {
+			slog.Info("no label mapped for priority", "issue", issueNum, "priority", suggestedPriority)
+		} else if added, removed, err := replacePriorityLabel(ctx, e.githubClient, issue, label, mappedPriorityLabel(labelMap)); err != nil {
+			slog.Warn("failed to apply priority label", "issue", issueNum, "error", err)
+		} else {
+			result.Extra["labels_added"] = added
+			result.Extra["labels_removed"] = removed
+		}
+	} else if autoApply, _ := pluginAgent.Config["auto_apply"].(bool); autoApply && suggestedPriority != "" {
+		label := priorityLabel(pluginAgent, suggestedPriority)
+		added, removed, err := reclassifyPriority(ctx, e.githubClient, issue, label, priorityLabelPrefix(label, suggestedPriority))
+		if err != nil {
+			slog.Warn("failed to apply priority label", "issue", issueNum, "error", err)
+		} else {
+			result.Extra["labels_added"] = added
+			result.Extra["labels_removed"] = removed
+		}
 	}
 
 	return result, nil
 }
 
 // executeDependencyTracker analyzes and tracks task dependencies
-func (e *PluginExecutor) executeDependencyTracker(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (map[string]interface{}, error) {
+func (e *PluginExecutor) executeDependencyTracker(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (*Result, error) {
 	// Get issue number
 	issueNum, hasIssue := e.extractIssueNumber(params)
 	if !hasIssue {
@@ -654,34 +1307,33 @@ func (e *PluginExecutor) executeDependencyTracker(ctx context.Context, pluginAge
 	if err != nil {
 		return nil, fmt.Errorf("failed to get issue: %w", err)
 	}
+	if result, ok := e.skipProtected(pluginAgent, issue); ok {
+		return result, nil
+	}
 
-	// Extract dependencies from body
+	// Extract dependencies from body and look up whether they are still open
 	dependencies := extractDependenciesFromBody(issue.Body)
 	blockers := extractBlockersFromBody(issue.Body)
+	statuses := dependencyStatuses(ctx, e.githubClient, issue, dependencies)
+	openDependencies := openDependencyRefs(statuses)
 
 	// Prepare data for prompt
 	data := map[string]interface{}{
-		"Title":        issue.Title,
-		"Body":         issue.Body,
-		"Number":       issueNum,
-		"Labels":       strings.Join(issue.Labels, ", "),
-		"State":        issue.State,
-		"Dependencies": formatDependencies(dependencies),
-		"Blockers":     formatDependencies(blockers),
-		"Blocked":      len(dependencies) > 0,
-		"Blocking":     len(blockers) > 0,
-	}
+		"Title":            issue.Title,
+		"Body":             issue.Body,
+		"Number":           issueNum,
+		"Labels":           strings.Join(issue.Labels, ", "),
+		"State":            issue.State,
+		"Dependencies":     formatDependencies(dependencies),
+		"Blockers":         formatDependencies(blockers),
+		"Blocked":          len(openDependencies) > 0,
+		"Blocking":         len(blockers) > 0,
+		"DependencyStatus": formatDependencyStatuses(statuses),
+	}
+	e.addRepoContext(ctx, pluginAgent, data, issue)
 
 	// Load and render prompt template
-	var prompt string
-	templateName := e.extractTemplateName(pluginAgent)
-
-	if e.promptLoader != nil && templateName != "" && e.promptLoader.HasTemplate(templateName) {
-		rendered, err := e.promptLoader.Render(templateName, data)
-		if err == nil {
-			prompt = rendered
-		}
-	}
+	prompt := e.renderPrompt(pluginAgent, data)
 
 	// Fallback prompt
 	if prompt == "" {
@@ -690,12 +1342,15 @@ func (e *PluginExecutor) executeDependencyTracker(ctx context.Context, pluginAge
 Title: %s
 Body: %s
 
+Dependency status:
+%s
+
 Identify: dependencies (depends on, requires, needs), blockers (blocks, prevents).`,
-			issue.Title, issue.Body)
+			issue.Title, issue.Body, formatDependencyStatuses(statuses))
 	}
 
 	// Generate dependency analysis
-	analysis, err := e.llmClient.Prompt(prompt)
+	analysis, err := e.llmClient.PromptContext(ctx, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze dependencies: %w", err)
 	}
@@ -704,28 +1359,31 @@ Identify: dependencies (depends on, requires, needs), blockers (blocks, prevents
 	analysis = cleanMarkdownResponse(analysis)
 
 	// Add comment with analysis
-	owner, repo := extractRepoFromURL(issue.URL)
-	comment := fmt.Sprintf("🔗 **Dependency Analysis** (Generated by %s)\n\n%s", pluginAgent.Name, analysis)
-	if err := e.githubClient.AddComment(ctx, owner, repo, issueNum, comment); err != nil {
-		fmt.Printf("Warning: failed to add dependency comment: %v\n", err)
-	}
-
-	result := map[string]interface{}{
-		"agent":        pluginAgent.Name,
-		"issue":        issueNum,
-		"title":        issue.Title,
-		"status":       "completed",
-		"dependencies": dependencies,
-		"blockers":     blockers,
-		"analysis":     analysis,
-		"message":      fmt.Sprintf("Dependency analysis completed for issue #%d", issueNum),
-	}
+	owner, repo := github.ParseRepoFromURL(issue.URL)
+	comment := e.signedComment(pluginAgent, "🔗 **Dependency Analysis**", analysis)
+	if err := e.addComment(ctx, owner, repo, issueNum, comment); err != nil {
+		slog.Warn("failed to add dependency comment", "issue", issueNum, "error", err)
+	}
+
+	result := newResult(pluginAgent)
+	result.Message = fmt.Sprintf("Dependency analysis completed for issue #%d", issueNum)
+	result.affect(issueNum)
+	result.setLegacy("issue", issueNum)
+	result.setLegacy("title", issue.Title)
+	result.Metrics["open_dependencies"] = float64(len(openDependencies))
+	result.Extra["dependencies"] = referenceStrings(dependencies)
+	result.Extra["blockers"] = referenceStrings(blockers)
+	result.Extra["dependency_states"] = dependencyStates(statuses)
+	result.Extra["open_dependencies"] = openDependencies
+	result.Extra["blocked"] = len(openDependencies) > 0
+	result.Extra["summary"] = blockedSummary(openDependencies)
+	result.Extra["analysis"] = analysis
 
 	return result, nil
 }
 
 // executeProgressReporter generates progress reports for stakeholders
-func (e *PluginExecutor) executeProgressReporter(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (map[string]interface{}, error) {
+func (e *PluginExecutor) executeProgressReporter(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (*Result, error) {
 	// Get all issues
 	openIssues, err := e.githubClient.ListIssues(ctx, "open")
 	if err != nil {
@@ -759,6 +1417,19 @@ func (e *PluginExecutor) executeProgressReporter(ctx context.Context, pluginAgen
 		}
 	}
 
+	// Aggregate checklist (subtask) progress across open issues
+	checklistDone, checklistTotal := 0, 0
+	var issueChecklists []issueChecklist
+	for _, issue := range openIssues {
+		checklist := markdown.ParseChecklist(issue.Body)
+		if checklist.Total() == 0 {
+			continue
+		}
+		checklistDone += checklist.Done()
+		checklistTotal += checklist.Total()
+		issueChecklists = append(issueChecklists, issueChecklist{Issue: issue, Checklist: checklist})
+	}
+
 	// Calculate velocity (tasks completed in last 7 days)
 	sevenDaysAgo := time.Now().AddDate(0, 0, -7)
 	recentCompleted := 0
@@ -769,32 +1440,35 @@ func (e *PluginExecutor) executeProgressReporter(ctx context.Context, pluginAgen
 	}
 	velocity := float64(recentCompleted) / 7.0 // tasks per day
 
+	milestones := groupByMilestone(openIssues, closedIssues)
+	milestoneRates := make(map[string]float64, len(milestones))
+	for _, m := range milestones {
+		milestoneRates[m.Title] = m.CompletionRate()
+	}
+
 	// Prepare data for prompt
 	data := map[string]interface{}{
-		"StartDate":       sevenDaysAgo.Format("2006-01-02"),
-		"EndDate":         time.Now().Format("2006-01-02"),
-		"TotalTasks":      totalTasks,
-		"CompletedTasks":  completedTasks,
-		"CompletionRate":  fmt.Sprintf("%.1f", completionRate),
-		"InProgressTasks": len(openIssues),
-		"OpenTasks":       openTasks,
-		"BlockedTasks":    blockedTasks,
-		"Velocity":        fmt.Sprintf("%.1f", velocity),
-		"Trend":           "Stable",                   // Could be calculated from historical data
-		"Milestones":      "No milestones configured", // Could be extracted from labels
-		"RecentActivity":  formatRecentActivity(closedIssues[:min(5, len(closedIssues))]),
-	}
+		"StartDate":         sevenDaysAgo.Format("2006-01-02"),
+		"EndDate":           time.Now().Format("2006-01-02"),
+		"TotalTasks":        totalTasks,
+		"CompletedTasks":    completedTasks,
+		"CompletionRate":    fmt.Sprintf("%.1f", completionRate),
+		"InProgressTasks":   len(openIssues),
+		"OpenTasks":         openTasks,
+		"BlockedTasks":      blockedTasks,
+		"Velocity":          fmt.Sprintf("%.1f", velocity),
+		"Trend":             "Stable", // Could be calculated from historical data
+		"Milestones":        formatMilestones(milestones),
+		"RecentActivity":    formatRecentActivity(recentlyClosed(closedIssues, 5)),
+		"ChecklistDone":     checklistDone,
+		"ChecklistTotal":    checklistTotal,
+		"ChecklistProgress": formatChecklistSummary(checklistDone, checklistTotal),
+		"IssueChecklists":   formatIssueChecklists(issueChecklists),
+	}
+	e.addRepoContext(ctx, pluginAgent, data, nil)
 
 	// Load and render prompt template
-	var prompt string
-	templateName := e.extractTemplateName(pluginAgent)
-
-	if e.promptLoader != nil && templateName != "" && e.promptLoader.HasTemplate(templateName) {
-		rendered, err := e.promptLoader.Render(templateName, data)
-		if err == nil {
-			prompt = rendered
-		}
-	}
+	prompt := e.renderPrompt(pluginAgent, data)
 
 	// Fallback prompt
 	if prompt == "" {
@@ -805,13 +1479,17 @@ Total Tasks: %d
 Completed: %d (%.1f%%)
 Blocked: %d
 Velocity: %.1f tasks/day
+Subtasks: %s
+Milestones:
+%s
 
 Provide a comprehensive progress report with metrics, achievements, risks, and recommendations.`,
-			totalTasks, completedTasks, completionRate, blockedTasks, velocity)
+			totalTasks, completedTasks, completionRate, blockedTasks, velocity, formatChecklistSummary(checklistDone, checklistTotal),
+			formatMilestones(milestones))
 	}
 
 	// Generate report using LLM
-	report, err := e.llmClient.Prompt(prompt)
+	report, err := e.llmClient.PromptContext(ctx, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate progress report: %w", err)
 	}
@@ -825,50 +1503,43 @@ Provide a comprehensive progress report with metrics, achievements, risks, and r
 	// Get owner/repo from first issue (optional - CreateIssue can handle empty in project mode)
 	var owner, repo string
 	if len(openIssues) > 0 {
-		owner, repo = extractRepoFromURL(openIssues[0].URL)
+		owner, repo = github.ParseRepoFromURL(openIssues[0].URL)
 	} else if len(closedIssues) > 0 {
-		owner, repo = extractRepoFromURL(closedIssues[0].URL)
+		owner, repo = github.ParseRepoFromURL(closedIssues[0].URL)
 	}
 
 	// Always try to create issue (UnifiedClient handles empty owner/repo in project mode)
 	labels := []string{"automated", "progress-report", "report"}
-	newIssue, err := e.githubClient.CreateIssue(ctx, owner, repo, issueTitle, report, labels)
+	newIssue, published, err := e.publishReport(ctx, pluginAgent, "progress-report", owner, repo, issueTitle, report, labels)
+
+	result := newResult(pluginAgent)
+	result.Metrics["total_tasks"] = float64(totalTasks)
+	result.Metrics["completed"] = float64(completedTasks)
+	result.Metrics["completion_rate"] = completionRate
+	result.Metrics["blocked"] = float64(blockedTasks)
+	result.Metrics["velocity"] = velocity
+	result.Metrics["checklist_done"] = float64(checklistDone)
+	result.Metrics["checklist_total"] = float64(checklistTotal)
+	result.Extra["milestones"] = milestoneRates
+	result.Extra["report"] = report
+	result.setLegacy("metrics", map[string]interface{}{
+		"total_tasks":     totalTasks,
+		"completed":       completedTasks,
+		"completion_rate": completionRate,
+		"blocked":         blockedTasks,
+		"velocity":        velocity,
+		"checklist_done":  checklistDone,
+		"checklist_total": checklistTotal,
+		"milestones":      milestoneRates,
+	})
 	if err == nil {
-		result := map[string]interface{}{
-			"agent":                pluginAgent.Name,
-			"status":               "completed",
-			"report":               report,
-			"issue_created":        true,
-			"created_issue_number": newIssue.Number,
-			"created_issue_url":    newIssue.URL,
-			"metrics": map[string]interface{}{
-				"total_tasks":     totalTasks,
-				"completed":       completedTasks,
-				"completion_rate": completionRate,
-				"blocked":         blockedTasks,
-				"velocity":        velocity,
-			},
-			"message": fmt.Sprintf("Progress report generated and issue #%d created", newIssue.Number),
-		}
+		result.Extra["assignee"] = newIssue.Assignee
+		addReportIssue(result, "Progress report", newIssue, published)
 		return result, nil
 	}
 	// If issue creation fails, still return report
-	fmt.Printf("Warning: failed to create progress report issue: %v\n", err)
-
-	// Fallback: return report even if issue creation failed
-	result := map[string]interface{}{
-		"agent":  pluginAgent.Name,
-		"status": "completed",
-		"report": report,
-		"metrics": map[string]interface{}{
-			"total_tasks":     totalTasks,
-			"completed":       completedTasks,
-			"completion_rate": completionRate,
-			"blocked":         blockedTasks,
-			"velocity":        velocity,
-		},
-		"message": "Progress report generated successfully (issue creation failed or repo not determined)",
-	}
+	slog.Warn("failed to create progress report issue", "error", err)
+	result.Message = "Progress report generated successfully (issue creation failed or repo not determined)"
 
 	return result, nil
 }
@@ -905,85 +1576,457 @@ func formatRecentActivity(issues []*github.Issue) string {
 	var parts []string
 	for _, issue := range issues {
 		parts = append(parts, fmt.Sprintf("- #%d: %s (Completed: %s)",
-			issue.Number, issue.Title, issue.UpdatedAt.Format("2006-01-02")))
+			issue.Number, issue.Title, closedAt(issue).Format("2006-01-02")))
 	}
 	return strings.Join(parts, "\n")
 }
 
-func extractDependenciesFromBody(body string) []string {
-	// Extract issue numbers mentioned with dependency keywords
-	var deps []string
-	lines := strings.Split(body, "\n")
-	for _, line := range lines {
-		lower := strings.ToLower(line)
-		if strings.Contains(lower, "depends on") ||
-			strings.Contains(lower, "requires") ||
-			strings.Contains(lower, "needs") ||
-			strings.Contains(lower, "waiting for") {
-			// Extract issue numbers (e.g., #123, issue 456)
-			// This is a simple extraction - could be enhanced
-			if strings.Contains(line, "#") {
-				// Extract number after #
-				parts := strings.Split(line, "#")
-				for i := 1; i < len(parts); i++ {
-					numStr := ""
-					for _, r := range parts[i] {
-						if r >= '0' && r <= '9' {
-							numStr += string(r)
-						} else {
-							break
-						}
-					}
-					if numStr != "" {
-						deps = append(deps, numStr)
-					}
-				}
-			}
+// recentlyCreated returns up to n issues, newest first by creation time
+func recentlyCreated(issues []*github.Issue, n int) []*github.Issue {
+	return mostRecent(issues, n, func(issue *github.Issue) time.Time { return issue.CreatedAt })
+}
+
+// recentlyClosed returns up to n issues, most recently closed first
+func recentlyClosed(issues []*github.Issue, n int) []*github.Issue {
+	return mostRecent(issues, n, closedAt)
+}
+
+// closedAt returns when an issue was closed, falling back to its last update
+// when the close time isn't known
+func closedAt(issue *github.Issue) time.Time {
+	if issue.ClosedAt.IsZero() {
+		return issue.UpdatedAt
+	}
+	return issue.ClosedAt
+}
+
+// mostRecent sorts a copy of issues by timestamp descending (ties broken by
+// issue number, newest first) and returns the first n
+func mostRecent(issues []*github.Issue, n int, timestamp func(*github.Issue) time.Time) []*github.Issue {
+	sorted := make([]*github.Issue, len(issues))
+	copy(sorted, issues)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ti, tj := timestamp(sorted[i]), timestamp(sorted[j])
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return sorted[i].Number > sorted[j].Number
+	})
+	return sorted[:min(n, len(sorted))]
+}
+
+// issueChecklist pairs an issue with the checklist parsed from its body
+type issueChecklist struct {
+	Issue     *github.Issue
+	Checklist markdown.Checklist
+}
+
+func formatChecklistSummary(done, total int) string {
+	if total == 0 {
+		return "No checklist items found in open issues"
+	}
+	return fmt.Sprintf("%d/%d checklist items done across open issues (%.1f%%)",
+		done, total, float64(done)/float64(total)*100)
+}
+
+func formatIssueChecklists(checklists []issueChecklist) string {
+	if len(checklists) == 0 {
+		return "No open issues with checklists"
+	}
+	var parts []string
+	for _, ic := range checklists {
+		parts = append(parts, fmt.Sprintf("- #%d: %s (%d/%d subtasks done)",
+			ic.Issue.Number, ic.Issue.Title, ic.Checklist.Done(), ic.Checklist.Total()))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// noMilestone groups the issues that aren't in any milestone
+const noMilestone = "No milestone"
+
+// milestoneProgress is the completion of the issues in one milestone
+type milestoneProgress struct {
+	Title  string
+	DueOn  time.Time // Zero when the milestone has no due date
+	Open   int
+	Closed int
+}
+
+// CompletionRate returns the percentage of the milestone's issues that are closed
+func (m milestoneProgress) CompletionRate() float64 {
+	if m.Open+m.Closed == 0 {
+		return 0
+	}
+	return float64(m.Closed) / float64(m.Open+m.Closed) * 100
+}
+
+// groupByMilestone tallies open and closed issues per milestone. Milestones
+// are ordered by due date, those without one after, and issues without a
+// milestone last.
+func groupByMilestone(openIssues, closedIssues []*github.Issue) []milestoneProgress {
+	index := make(map[string]int)
+	var groups []milestoneProgress
+	add := func(issue *github.Issue, closed bool) {
+		title := issue.Milestone
+		if title == "" {
+			title = noMilestone
+		}
+		i, ok := index[title]
+		if !ok {
+			i = len(groups)
+			index[title] = i
+			groups = append(groups, milestoneProgress{Title: title, DueOn: issue.MilestoneDueOn})
+		}
+		if closed {
+			groups[i].Closed++
+		} else {
+			groups[i].Open++
+		}
+	}
+	for _, issue := range openIssues {
+		add(issue, false)
+	}
+	for _, issue := range closedIssues {
+		add(issue, true)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if (a.Title == noMilestone) != (b.Title == noMilestone) {
+			return b.Title == noMilestone
+		}
+		if a.DueOn.IsZero() != b.DueOn.IsZero() {
+			return b.DueOn.IsZero()
+		}
+		if !a.DueOn.Equal(b.DueOn) {
+			return a.DueOn.Before(b.DueOn)
+		}
+		return a.Title < b.Title
+	})
+	return groups
+}
+
+func formatMilestones(milestones []milestoneProgress) string {
+	if len(milestones) == 0 || (len(milestones) == 1 && milestones[0].Title == noMilestone) {
+		return "No milestones configured"
+	}
+	var parts []string
+	for _, m := range milestones {
+		line := fmt.Sprintf("- %s: %d/%d done (%.1f%%)", m.Title, m.Closed, m.Open+m.Closed, m.CompletionRate())
+		if !m.DueOn.IsZero() {
+			line += fmt.Sprintf(", due %s", m.DueOn.Format("2006-01-02"))
 		}
+		parts = append(parts, line)
 	}
-	return deps
+	return strings.Join(parts, "\n")
+}
+
+// dependencyKeywords mark a line as listing issues this issue depends on
+var dependencyKeywords = []string{"depends on", "requires", "needs", "waiting for"}
+
+// blockerKeywords mark a line as listing issues this issue blocks
+var blockerKeywords = []string{"blocks", "prevents"}
+
+func extractDependenciesFromBody(body string) []markdown.Reference {
+	return referencesOnKeywordLines(body, dependencyKeywords)
+}
+
+func extractBlockersFromBody(body string) []markdown.Reference {
+	return referencesOnKeywordLines(body, blockerKeywords)
 }
 
-func extractBlockersFromBody(body string) []string {
-	// Similar to extractDependenciesFromBody but for blockers
-	var blockers []string
-	lines := strings.Split(body, "\n")
-	for _, line := range lines {
+// referencesOnKeywordLines returns the issue references on lines containing
+// any of keywords, without duplicates
+func referencesOnKeywordLines(body string, keywords []string) []markdown.Reference {
+	var lines []string
+	for _, line := range strings.Split(body, "\n") {
 		lower := strings.ToLower(line)
-		if strings.Contains(lower, "blocks") ||
-			strings.Contains(lower, "prevents") {
-			if strings.Contains(line, "#") {
-				parts := strings.Split(line, "#")
-				for i := 1; i < len(parts); i++ {
-					numStr := ""
-					for _, r := range parts[i] {
-						if r >= '0' && r <= '9' {
-							numStr += string(r)
-						} else {
-							break
-						}
-					}
-					if numStr != "" {
-						blockers = append(blockers, numStr)
-					}
-				}
+		for _, keyword := range keywords {
+			if strings.Contains(lower, keyword) {
+				lines = append(lines, line)
+				break
 			}
 		}
 	}
-	return blockers
+	return markdown.IssueReferences(strings.Join(lines, "\n"))
+}
+
+// referenceStrings formats references as GitHub writes them, e.g. "#12"
+func referenceStrings(refs []markdown.Reference) []string {
+	result := make([]string, len(refs))
+	for i, ref := range refs {
+		result[i] = ref.String()
+	}
+	return result
+}
+
+// Dependency states; unknown means the referenced issue couldn't be fetched
+const (
+	dependencyOpen    = "open"
+	dependencyClosed  = "closed"
+	dependencyUnknown = "unknown"
+)
+
+// dependencyStatus is the state of one referenced dependency
+type dependencyStatus struct {
+	Ref   markdown.Reference
+	State string
+	Title string
+}
+
+// dependencyStatuses fetches the dependencies of issue in one batch to find
+// whether each is still open. References without a repository are looked up
+// in the issue's own repository. Issues that can't be fetched, including
+// other repositories in repo mode, are reported as unknown rather than
+// failing the run.
+func dependencyStatuses(ctx context.Context, client github.UnifiedClient, issue *github.Issue, refs []markdown.Reference) []dependencyStatus {
+	owner, repo := github.ParseRepoFromURL(issue.URL)
+
+	statuses := make([]dependencyStatus, len(refs))
+	issueRefs := make([]github.IssueRef, len(refs))
+	var fetch []github.IssueRef
+	for i, ref := range refs {
+		statuses[i] = dependencyStatus{Ref: ref, State: dependencyUnknown}
+
+		issueRefs[i] = github.IssueRef{Owner: ref.Owner, Repo: ref.Repo, Number: ref.Number}
+		if ref.Owner == "" && ref.Repo == "" {
+			issueRefs[i].Owner, issueRefs[i].Repo = owner, repo
+		}
+		if client.GetMode() != "project" && owner != "" && (!strings.EqualFold(issueRefs[i].Owner, owner) || !strings.EqualFold(issueRefs[i].Repo, repo)) {
+			slog.Warn("can't check dependency in another repository in repo mode", "issue", issue.Number, "dependency", ref.String())
+			continue
+		}
+		fetch = append(fetch, issueRefs[i])
+	}
+	if len(fetch) == 0 {
+		return statuses
+	}
+
+	dependencies, err := client.GetIssues(ctx, fetch)
+	if err != nil {
+		slog.Warn("failed to get dependencies", "issue", issue.Number, "error", err)
+	}
+	for i := range statuses {
+		dependency, ok := dependencies[issueRefs[i]]
+		if !ok {
+			continue
+		}
+		statuses[i].Title = dependency.Title
+		if strings.EqualFold(dependency.State, dependencyClosed) {
+			statuses[i].State = dependencyClosed
+		} else {
+			statuses[i].State = dependencyOpen
+		}
+	}
+	return statuses
+}
+
+// openDependencyRefs returns the dependencies that are still open
+func openDependencyRefs(statuses []dependencyStatus) []string {
+	open := []string{}
+	for _, status := range statuses {
+		if status.State == dependencyOpen {
+			open = append(open, status.Ref.String())
+		}
+	}
+	return open
+}
+
+// dependencyStates maps each dependency to its state
+func dependencyStates(statuses []dependencyStatus) map[string]string {
+	states := make(map[string]string, len(statuses))
+	for _, status := range statuses {
+		states[status.Ref.String()] = status.State
+	}
+	return states
+}
+
+// blockedSummary says whether the issue is blocked, e.g. "blocked by 2 open
+// dependencies: #12, #15"
+func blockedSummary(open []string) string {
+	switch len(open) {
+	case 0:
+		return "not blocked"
+	case 1:
+		return fmt.Sprintf("blocked by 1 open dependency: %s", open[0])
+	default:
+		return fmt.Sprintf("blocked by %d open dependencies: %s", len(open), strings.Join(open, ", "))
+	}
+}
+
+// formatDependencyStatuses lists each dependency with its state for prompts
+func formatDependencyStatuses(statuses []dependencyStatus) string {
+	if len(statuses) == 0 {
+		return "None identified"
+	}
+	var parts []string
+	for _, status := range statuses {
+		line := fmt.Sprintf("- %s: %s", status.Ref, status.State)
+		if status.Title != "" {
+			line += fmt.Sprintf(" (%s)", status.Title)
+		}
+		parts = append(parts, line)
+	}
+	return strings.Join(parts, "\n")
 }
 
-func formatDependencies(deps []string) string {
+func formatDependencies(deps []markdown.Reference) string {
 	if len(deps) == 0 {
 		return "None identified"
 	}
 	var parts []string
 	for _, dep := range deps {
-		parts = append(parts, fmt.Sprintf("- #%s", dep))
+		parts = append(parts, fmt.Sprintf("- %s", dep))
 	}
 	return strings.Join(parts, "\n")
 }
 
+// priorityLabel returns the configured label for a priority such as "P1",
+// falling back to "priority:P1"
+func priorityLabel(pluginAgent *PluginAgent, priority string) string {
+	if labels, ok := pluginAgent.Config["priority_labels"].([]interface{}); ok {
+		for _, l := range labels {
+			label, ok := l.(string)
+			if ok && strings.HasSuffix(strings.ToLower(label), strings.ToLower(priority)) {
+				return label
+			}
+		}
+	}
+	return "priority:" + priority
+}
+
+// priorityLabelPrefix returns the part of a priority label shared by all
+// priority labels, e.g. "priority:" for "priority:P1"
+func priorityLabelPrefix(label, priority string) string {
+	if strings.HasSuffix(strings.ToLower(label), strings.ToLower(priority)) {
+		return label[:len(label)-len(priority)]
+	}
+	return "priority:"
+}
+
+// priorityLabelMap returns the "priority_label_map" config, which maps an
+// assessed priority (P0-P3) to the label to apply, e.g. P1: "priority:high".
+// Entries that are not a priority and a label name are returned as problems
+// and left out.
+func priorityLabelMap(pluginAgent *PluginAgent) (map[string]string, []string) {
+	raw, ok := pluginAgent.Config["priority_label_map"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	entries, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, []string{fmt.Sprintf("priority_label_map must map P0-P3 to label names, got %T", raw)}
+	}
+
+	labelMap := make(map[string]string, len(entries))
+	var problems []string
+	for key, value := range entries {
+		priority := normalizePriority(key)
+		label, isString := value.(string)
+		switch {
+		case priority == "":
+			problems = append(problems, fmt.Sprintf("priority_label_map: %q is not one of P0, P1, P2, P3", key))
+		case !isString || strings.TrimSpace(label) == "":
+			problems = append(problems, fmt.Sprintf("priority_label_map: %s must map to a label name", key))
+		default:
+			labelMap[priority] = strings.TrimSpace(label)
+		}
+	}
+	sort.Strings(problems)
+	return labelMap, problems
+}
+
+// mappedPriorityLabel reports whether a label is one of the mapped priority
+// labels
+func mappedPriorityLabel(labelMap map[string]string) func(string) bool {
+	return func(label string) bool {
+		for _, mapped := range labelMap {
+			if strings.EqualFold(label, mapped) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// reclassifyPriority replaces any existing priority label on the issue with
+// label. Labels without the priority prefix are left alone, and nothing
+// changes if the issue already has exactly that priority.
+func reclassifyPriority(ctx context.Context, client github.UnifiedClient, issue *github.Issue, label, prefix string) (added, removed []string, err error) {
+	return replacePriorityLabel(ctx, client, issue, label, func(existing string) bool {
+		return strings.HasPrefix(strings.ToLower(existing), strings.ToLower(prefix))
+	})
+}
+
+// replacePriorityLabel removes the issue's conflicting priority labels, as
+// reported by isPriority, then adds label unless the issue already has it
+func replacePriorityLabel(ctx context.Context, client github.UnifiedClient, issue *github.Issue, label string, isPriority func(string) bool) (added, removed []string, err error) {
+	owner, repo := github.ParseRepoFromURL(issue.URL)
+
+	hasLabel := false
+	for _, existing := range issue.Labels {
+		if strings.EqualFold(existing, label) {
+			hasLabel = true
+			continue
+		}
+		if !isPriority(existing) {
+			continue
+		}
+		if err := client.RemoveLabel(ctx, owner, repo, issue.Number, existing); err != nil {
+			return added, removed, fmt.Errorf("failed to remove %s: %w", existing, err)
+		}
+		removed = append(removed, existing)
+	}
+
+	if !hasLabel {
+		if err := client.AddLabel(ctx, owner, repo, issue.Number, label); err != nil {
+			return added, removed, fmt.Errorf("failed to add %s: %w", label, err)
+		}
+		added = append(added, label)
+	}
+	return added, removed, nil
+}
+
+// priorityReply is the JSON reply requested from the priority calculator
+type priorityReply struct {
+	Priority  string `json:"priority"`
+	Rationale string `json:"rationale"`
+}
+
+// assessPriority asks for a JSON priority assessment and returns it as
+// markdown with the suggested priority. Replies that are not valid JSON are
+// read as free text instead.
+func (e *PluginExecutor) assessPriority(ctx context.Context, prompt string) (assessment, priority string, err error) {
+	var reply priorityReply
+	err = e.llmClient.ChatJSONContext(ctx, prompt, &reply)
+	var jsonErr *llm.JSONError
+	if errors.As(err, &jsonErr) {
+		slog.Debug("priority reply is not JSON, falling back to text", "error", jsonErr.Err)
+		assessment = cleanMarkdownResponse(jsonErr.Response)
+		return assessment, extractPriorityFromAssessment(assessment), nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	rationale := cleanMarkdownResponse(reply.Rationale)
+	priority = normalizePriority(reply.Priority)
+	if priority == "" {
+		return rationale, extractPriorityFromAssessment(rationale), nil
+	}
+	return fmt.Sprintf("**Suggested Priority**: %s\n\n%s", priority, rationale), priority, nil
+}
+
+// normalizePriority returns p as one of P0-P3, or "" if it is not a priority
+func normalizePriority(p string) string {
+	p = strings.ToUpper(strings.TrimSpace(p))
+	switch p {
+	case "P0", "P1", "P2", "P3":
+		return p
+	}
+	return ""
+}
+
 func extractPriorityFromAssessment(assessment string) string {
 	// Extract priority (P0, P1, P2, P3) from assessment
 	assessmentLower := strings.ToLower(assessment)
@@ -1032,30 +2075,17 @@ func cleanMarkdownResponse(response string) string {
 	return strings.TrimSpace(response)
 }
 
-// extractRepoFromURL extracts owner and repo from a GitHub issue URL
-func extractRepoFromURL(url string) (string, string) {
-	// URL format: https://github.com/owner/repo/issues/123
-	parts := strings.Split(url, "/")
-	if len(parts) >= 5 {
-		return parts[3], parts[4] // owner, repo
-	}
-	return "", ""
-}
-
 // executeGeneric executes a generic plugin using intelligent action parsing
-func (e *PluginExecutor) executeGeneric(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (map[string]interface{}, error) {
-	result := map[string]interface{}{
-		"agent":   pluginAgent.Name,
-		"type":    pluginAgent.Type,
-		"status":  "executed",
-		"actions": pluginAgent.Actions,
-	}
+func (e *PluginExecutor) executeGeneric(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (*Result, error) {
+	result := newResult(pluginAgent)
+	result.Extra["actions"] = pluginAgent.Actions
+	result.legacyStatus = "executed"
+	result.setLegacy("type", pluginAgent.Type)
 
 	// Try to get issue number if actions suggest we need it
-	var issueNum int
-	var hasIssue bool
-	if issueNum, hasIssue = e.extractIssueNumber(params); hasIssue {
-		result["issue"] = issueNum
+	issueNum, hasIssue := e.extractIssueNumber(params)
+	if hasIssue {
+		result.setLegacy("issue", issueNum)
 	}
 
 	// Intelligent action execution based on action descriptions
@@ -1082,12 +2112,13 @@ func (e *PluginExecutor) executeGeneric(ctx context.Context, pluginAgent *Plugin
 				}
 
 				if len(issue.Body) < minLength {
-					result["status"] = "skipped"
-					result["message"] = fmt.Sprintf("Task is too short to summarize (%d chars, minimum %d)", len(issue.Body), minLength)
+					result.Status = StatusSkipped
+					result.legacyStatus = StatusSkipped
+					result.Message = fmt.Sprintf("Task is too short to summarize (%d chars, minimum %d)", len(issue.Body), minLength)
 					return result, nil
 				}
-				result["task_length"] = len(issue.Body)
-				result["length_check_passed"] = true
+				result.Metrics["task_length"] = float64(len(issue.Body))
+				result.Extra["length_check_passed"] = true
 			}
 		}
 
@@ -1105,11 +2136,11 @@ func (e *PluginExecutor) executeGeneric(ctx context.Context, pluginAgent *Plugin
 				llmResult := e.executeLLMAction(ctx, pluginAgent, issue, params)
 				if llmResult != nil {
 					if summary, ok := llmResult["summary"].(string); ok && summary != "" {
-						result["summary"] = summary
-						result["llm_called"] = true
+						result.Extra["summary"] = summary
+						result.Extra["llm_called"] = true
 					} else if content, ok := llmResult["content"].(string); ok && content != "" {
-						result["summary"] = content
-						result["llm_called"] = true
+						result.Extra["summary"] = content
+						result.Extra["llm_called"] = true
 					}
 				}
 			}
@@ -1125,41 +2156,104 @@ func (e *PluginExecutor) executeGeneric(ctx context.Context, pluginAgent *Plugin
 					}
 				}
 
+				if _, ok := agent.ProtectedBy(issue, e.options.SkipLabels); ok {
+					result.Extra["protected"] = []int{issueNum}
+					continue
+				}
+
 				// Get content to add as comment
 				var commentContent string
-				if summary, ok := result["summary"].(string); ok && summary != "" {
+				if summary, ok := result.Extra["summary"].(string); ok && summary != "" {
 					commentContent = summary
 				} else {
 					// If no summary, create a basic comment
-					commentContent = fmt.Sprintf("🤖 **%s** executed successfully.", pluginAgent.Name)
+					commentContent = fmt.Sprintf("%s executed successfully.", e.options.Identity.Prefix(pluginAgent.Name))
 				}
 
 				if err := e.addCommentToIssue(ctx, pluginAgent, issueNum, commentContent); err == nil {
-					result["comment_added"] = true
-					if result["message"] == nil {
-						result["message"] = fmt.Sprintf("Summary generated and added as comment to issue #%d", issueNum)
-					} else {
-						// Update message if summary was generated
-						if result["summary"] != nil {
-							result["message"] = fmt.Sprintf("Summary generated and added as comment to issue #%d", issueNum)
-						}
+					result.affect(issueNum)
+					result.legacyStatus = StatusCompleted
+					result.Extra["comment_added"] = true
+					// Update message if summary was generated
+					if result.Message == "" || result.Extra["summary"] != nil {
+						result.Message = fmt.Sprintf("Summary generated and added as comment to issue #%d", issueNum)
 					}
-					result["status"] = "completed"
 				} else {
-					result["comment_error"] = err.Error()
+					result.Errors = append(result.Errors, fmt.Sprintf("failed to add comment: %v", err))
+					result.setLegacy("comment_error", err.Error())
+				}
+			}
+		}
+
+		// Pattern: "Add label triaged" or "Apply label" with Config["label"]
+		if label, ok := labelFromAction(action, pluginAgent); ok && hasIssue {
+			if issue == nil {
+				issue, issueErr = e.githubClient.GetIssue(ctx, "", "", issueNum)
+				if issueErr != nil {
+					return nil, fmt.Errorf("failed to get issue: %w", issueErr)
 				}
 			}
+
+			if _, ok := agent.ProtectedBy(issue, e.options.SkipLabels); ok {
+				result.Extra["protected"] = []int{issueNum}
+				continue
+			}
+
+			owner, repo := github.ParseRepoFromURL(issue.URL)
+			if err := e.githubClient.AddLabel(ctx, owner, repo, issueNum, label); err == nil {
+				result.affect(issueNum)
+				result.legacyStatus = StatusCompleted
+				added, _ := result.Extra["labels_added"].([]string)
+				result.Extra["labels_added"] = append(added, label)
+			} else {
+				result.Errors = append(result.Errors, fmt.Sprintf("failed to add label %s: %v", label, err))
+				result.setLegacy("label_error", err.Error())
+			}
 		}
 	}
 
 	// If no specific actions matched, return basic execution result
-	if result["message"] == nil {
-		result["message"] = fmt.Sprintf("Plugin '%s' executed with %d actions", pluginAgent.Name, len(pluginAgent.Actions))
+	if result.Message == "" {
+		result.Message = fmt.Sprintf("Plugin '%s' executed with %d actions", pluginAgent.Name, len(pluginAgent.Actions))
 	}
 
 	return result, nil
 }
 
+// labelActionPattern matches "Add label X", "Apply the label `X`" and
+// "Add `X` label" actions; the label name is optional
+var labelActionPattern = regexp.MustCompile("(?i)\\b(?:add|apply)\\s+(?:an?\\s+|the\\s+)?(?:label\\b\\s*(.*)|`([^`]+)`\\s+label\\b)")
+
+// quotedLabelPattern matches a label name in backticks or quotes
+var quotedLabelPattern = regexp.MustCompile("^[`'\"]([^`'\"]+)[`'\"]")
+
+// labelFillerWords follow "label" in actions that don't name one, as in
+// "Add label to the issue"
+var labelFillerWords = map[string]bool{"to": true, "on": true, "for": true, "from": true, "as": true, "if": true, "when": true}
+
+// labelFromAction returns the label an "add label" action applies: the name
+// in the action text, or Config["label"] when the action doesn't name one
+func labelFromAction(action string, pluginAgent *PluginAgent) (string, bool) {
+	match := labelActionPattern.FindStringSubmatch(action)
+	if match == nil {
+		return "", false
+	}
+	if match[2] != "" {
+		return match[2], true
+	}
+
+	rest := strings.TrimSpace(match[1])
+	if quoted := quotedLabelPattern.FindStringSubmatch(rest); quoted != nil {
+		return quoted[1], true
+	}
+	if fields := strings.Fields(rest); len(fields) > 0 && !labelFillerWords[strings.ToLower(fields[0])] {
+		return strings.TrimRight(fields[0], ".,;:"), true
+	}
+
+	label := configString(pluginAgent, "label", "")
+	return label, label != ""
+}
+
 // extractIssueNumber extracts issue number from params (supports multiple formats)
 func (e *PluginExecutor) extractIssueNumber(params map[string]interface{}) (int, bool) {
 	var issueNum int
@@ -1189,10 +2283,6 @@ func (e *PluginExecutor) extractIssueNumber(params map[string]interface{}) (int,
 
 // executeLLMAction executes an LLM-based action using the agent's prompt template
 func (e *PluginExecutor) executeLLMAction(ctx context.Context, pluginAgent *PluginAgent, issue *github.Issue, params map[string]interface{}) map[string]interface{} {
-	// Load and render prompt template
-	var prompt string
-	templateName := e.extractTemplateName(pluginAgent)
-
 	// Build data map for template rendering
 	data := make(map[string]interface{})
 
@@ -1226,18 +2316,15 @@ func (e *PluginExecutor) executeLLMAction(ctx context.Context, pluginAgent *Plug
 		}
 	}
 
+	e.addRepoContext(ctx, pluginAgent, data, issue)
+
 	// Add any additional params
 	for k, v := range params {
 		data[k] = v
 	}
 
 	// Try to load template - the multi-path loader will search all configured paths
-	if e.promptLoader != nil && templateName != "" && e.promptLoader.HasTemplate(templateName) {
-		rendered, err := e.promptLoader.Render(templateName, data)
-		if err == nil {
-			prompt = rendered
-		}
-	}
+	prompt := e.renderPrompt(pluginAgent, data)
 
 	// Fallback to a generic prompt if template not available
 	if prompt == "" {
@@ -1273,7 +2360,7 @@ Provide a clear, structured analysis.`, dataSummary)
 	}
 
 	// Call LLM
-	summary, err := e.llmClient.Prompt(prompt)
+	summary, err := e.llmClient.PromptContext(ctx, prompt)
 	if err != nil {
 		return map[string]interface{}{
 			"error": fmt.Sprintf("LLM call failed: %v", err),
@@ -1351,11 +2438,11 @@ func (e *PluginExecutor) addCommentToIssue(ctx context.Context, pluginAgent *Plu
 		return err
 	}
 
-	owner, repo := extractRepoFromURL(issue.URL)
+	owner, repo := github.ParseRepoFromURL(issue.URL)
 
 	// Format comment - ensure proper markdown spacing
 	// GitHub requires double newlines for proper rendering
-	commentPrefix := fmt.Sprintf("🤖 **%s**\n\n", pluginAgent.Name)
+	commentPrefix := e.options.Identity.Prefix(pluginAgent.Name) + "\n\n"
 
 	// Ensure content starts with proper spacing
 	content = strings.TrimSpace(content)
@@ -1365,7 +2452,45 @@ func (e *PluginExecutor) addCommentToIssue(ctx context.Context, pluginAgent *Plu
 
 	comment := commentPrefix + content
 
-	return e.githubClient.AddComment(ctx, owner, repo, issueNum, comment)
+	return e.addComment(ctx, owner, repo, issueNum, comment)
+}
+
+// skipProtected returns the result of leaving a protected issue alone, and
+// whether the issue is protected
+func (e *PluginExecutor) skipProtected(pluginAgent *PluginAgent, issue *github.Issue) (*Result, bool) {
+	label, ok := agent.ProtectedBy(issue, e.options.SkipLabels)
+	if !ok {
+		return nil, false
+	}
+	slog.Info("skipping protected issue", "issue", issue.Number, "label", label)
+	result := newResult(pluginAgent)
+	result.Message = fmt.Sprintf("Issue #%d %s by label %s", issue.Number, agent.SkipProtected, label)
+	result.Extra["protected"] = []int{issue.Number}
+	return result, true
+}
+
+// signedComment formats a comment pluginAgent posts, signed with the bot
+// identity and headed by header (e.g. "🔍 **Code Review**") so later runs
+// can recognize it with isAgentComment
+func (e *PluginExecutor) signedComment(pluginAgent *PluginAgent, header, body string) string {
+	return e.options.Identity.Format(pluginAgent.Name, header) + "\n\n" + body
+}
+
+// isAgentComment reports whether body is a comment signedComment made for
+// pluginAgent with header. Older versions started such comments with the
+// header itself, followed by "(Generated by <agent>)".
+func (e *PluginExecutor) isAgentComment(pluginAgent *PluginAgent, header, body string) bool {
+	return strings.HasPrefix(body, e.options.Identity.Format(pluginAgent.Name, header)) ||
+		strings.HasPrefix(body, header+" (Generated by ")
+}
+
+// addComment posts a comment and counts it in the metrics
+func (e *PluginExecutor) addComment(ctx context.Context, owner, repo string, number int, body string) error {
+	if err := e.githubClient.AddComment(ctx, owner, repo, number, body); err != nil {
+		return err
+	}
+	metrics.CommentsPosted.Inc()
+	return nil
 }
 
 // gatherProjectStats gathers project-wide statistics for agents that need them
@@ -1381,15 +2506,12 @@ func (e *PluginExecutor) gatherProjectStats(ctx context.Context) map[string]inte
 		inProgress := 0
 		blocked := 0
 		for _, issue := range openIssues {
-			// Check labels for status
-			for _, label := range issue.Labels {
-				labelLower := strings.ToLower(label)
-				if strings.Contains(labelLower, "in progress") || strings.Contains(labelLower, "in-progress") {
-					inProgress++
-				}
-				if strings.Contains(labelLower, "blocked") || strings.Contains(labelLower, "blocker") {
-					blocked++
-				}
+			isInProgress, isBlocked := issueStatus(issue)
+			if isInProgress {
+				inProgress++
+			}
+			if isBlocked {
+				blocked++
 			}
 		}
 		stats["InProgressTasks"] = inProgress
@@ -1427,13 +2549,65 @@ func (e *PluginExecutor) gatherProjectStats(ctx context.Context) map[string]inte
 	return stats
 }
 
-// extractTemplateName extracts template name from prompt path
+// issueStatus reports whether an issue is in progress or blocked. The
+// project board's Status field is used when available; otherwise the
+// status is guessed from the issue's labels.
+func issueStatus(issue *github.Issue) (inProgress, blocked bool) {
+	if status, ok := issue.Field("Status"); ok {
+		status = strings.ToLower(status)
+		return status == "in progress", strings.Contains(status, "blocked")
+	}
+
+	for _, label := range issue.Labels {
+		labelLower := strings.ToLower(label)
+		if strings.Contains(labelLower, "in progress") || strings.Contains(labelLower, "in-progress") {
+			inProgress = true
+		}
+		if strings.Contains(labelLower, "blocked") || strings.Contains(labelLower, "blocker") {
+			blocked = true
+		}
+	}
+	return inProgress, blocked
+}
+
+// addRepoContext exposes repository metadata to templates as RepoInfo for
+// agents that opt in with "include_repo_context: true" in their configuration.
+// The repository is taken from the issue when given, otherwise the default repository.
+func (e *PluginExecutor) addRepoContext(ctx context.Context, pluginAgent *PluginAgent, data map[string]interface{}, issue *github.Issue) {
+	if enabled, _ := pluginAgent.Config["include_repo_context"].(bool); !enabled {
+		return
+	}
+
+	var owner, repo string
+	if issue != nil {
+		owner, repo = github.ParseRepoFromURL(issue.URL)
+	}
+
+	key := owner + "/" + repo
+	e.repoInfoMu.Lock()
+	defer e.repoInfoMu.Unlock()
+	info, ok := e.repoInfo[key]
+	if !ok {
+		var err error
+		info, err = e.githubClient.GetRepository(ctx, owner, repo)
+		if err != nil {
+			slog.Warn("failed to get repository context", "error", err)
+		}
+		e.repoInfo[key] = info // Cache failures too so we don't retry every call
+	}
+
+	if info != nil {
+		data["RepoInfo"] = info
+	}
+}
+
+// promptTemplateName extracts template name from prompt path
 // Supports multiple path formats:
 // - "prompts/summarizer.md" -> "summarizer"
 // - ".github/agents/custom/prompts/summarizer.md" -> "summarizer"
 // - "summarizer.md" -> "summarizer"
 // - Relative paths from agent file location
-func (e *PluginExecutor) extractTemplateName(pluginAgent *PluginAgent) string {
+func promptTemplateName(pluginAgent *PluginAgent) string {
 	if pluginAgent.PromptPath == "" {
 		// Try to infer from agent name (e.g., "Task Summarizer" -> "summarizer")
 		name := strings.ToLower(pluginAgent.Name)
@@ -1447,7 +2621,7 @@ func (e *PluginExecutor) extractTemplateName(pluginAgent *PluginAgent) string {
 
 	// Extract template name from path
 	// Handle various path formats
-	path := pluginAgent.PromptPath
+	path := strings.Trim(pluginAgent.PromptPath, "`\"'")
 
 	// Remove .md extension
 	path = strings.TrimSuffix(path, ".md")
@@ -1466,6 +2640,23 @@ func (e *PluginExecutor) extractTemplateName(pluginAgent *PluginAgent) string {
 	return path
 }
 
+// renderPrompt renders the agent's prompt template with data. It returns ""
+// when the agent has no template or rendering fails, and callers then use
+// their built-in prompt. Failures are logged: with strict prompts they mean
+// the template references data the agent doesn't provide.
+func (e *PluginExecutor) renderPrompt(pluginAgent *PluginAgent, data map[string]interface{}) string {
+	templateName := promptTemplateName(pluginAgent)
+	if e.promptLoader == nil || templateName == "" || !e.promptLoader.HasTemplate(templateName) {
+		return ""
+	}
+	rendered, err := e.promptLoader.Render(templateName, data)
+	if err != nil {
+		slog.Warn("failed to render prompt template, using the built-in prompt", "agent", pluginAgent.Name, "template", templateName, "error", err)
+		return ""
+	}
+	return rendered
+}
+
 // loadPrompt loads the prompt for an agent
 func (e *PluginExecutor) loadPrompt(pluginAgent *PluginAgent) (string, error) {
 	// Try to load from PromptPath if specified
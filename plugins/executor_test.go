@@ -0,0 +1,945 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kaskol10/github-project-agent/bot"
+	"github.com/kaskol10/github-project-agent/github"
+	"github.com/kaskol10/github-project-agent/github/githubtest"
+	"github.com/kaskol10/github-project-agent/llm"
+	"github.com/kaskol10/github-project-agent/markdown"
+	"github.com/kaskol10/github-project-agent/prompts"
+)
+
+func TestRecentlyCreated(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	issues := []*github.Issue{
+		{Number: 1, CreatedAt: base},
+		{Number: 2, CreatedAt: base.Add(48 * time.Hour)},
+		{Number: 3, CreatedAt: base.Add(24 * time.Hour)},
+		{Number: 4, CreatedAt: base.Add(48 * time.Hour)},
+	}
+
+	got := recentlyCreated(issues, 3)
+	want := []int{4, 2, 3}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d issues, got %d", len(want), len(got))
+	}
+	for i, number := range want {
+		if got[i].Number != number {
+			t.Errorf("position %d: expected #%d, got #%d", i, number, got[i].Number)
+		}
+	}
+
+	if issues[0].Number != 1 {
+		t.Error("input slice should not be reordered")
+	}
+}
+
+func TestRecentlyClosed(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	issues := []*github.Issue{
+		{Number: 1, ClosedAt: base.Add(24 * time.Hour), UpdatedAt: base.Add(72 * time.Hour)},
+		{Number: 2, ClosedAt: base.Add(48 * time.Hour)},
+		{Number: 3, UpdatedAt: base.Add(36 * time.Hour)}, // close time unknown
+	}
+
+	got := recentlyClosed(issues, 5)
+	want := []int{2, 3, 1}
+	for i, number := range want {
+		if got[i].Number != number {
+			t.Errorf("position %d: expected #%d, got #%d", i, number, got[i].Number)
+		}
+	}
+}
+
+func TestGroupByMilestone(t *testing.T) {
+	due := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	openIssues := []*github.Issue{
+		{Number: 1, Milestone: "v2.0"},
+		{Number: 2, Milestone: "v1.0", MilestoneDueOn: due},
+		{Number: 3},
+	}
+	closedIssues := []*github.Issue{
+		{Number: 4, Milestone: "v1.0", MilestoneDueOn: due},
+		{Number: 5, Milestone: "v1.0", MilestoneDueOn: due},
+	}
+
+	got := groupByMilestone(openIssues, closedIssues)
+	want := []milestoneProgress{
+		{Title: "v1.0", DueOn: due, Open: 1, Closed: 2},
+		{Title: "v2.0", Open: 1},
+		{Title: noMilestone, Open: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("groupByMilestone() = %+v, want %+v", got, want)
+	}
+
+	formatted := formatMilestones(got)
+	if !strings.Contains(formatted, "- v1.0: 2/3 done (66.7%), due 2024-03-01") {
+		t.Errorf("unexpected milestone summary:\n%s", formatted)
+	}
+	if summary := formatMilestones(groupByMilestone(openIssues[2:], nil)); summary != "No milestones configured" {
+		t.Errorf("expected no milestones, got %q", summary)
+	}
+}
+
+func TestReclassifyPriority(t *testing.T) {
+	tests := []struct {
+		name        string
+		labels      []string
+		label       string
+		wantLabels  []string
+		wantAdded   []string
+		wantRemoved []string
+	}{
+		{
+			name:       "adds priority to unprioritized issue",
+			labels:     []string{"bug"},
+			label:      "priority:P1",
+			wantLabels: []string{"bug", "priority:P1"},
+			wantAdded:  []string{"priority:P1"},
+		},
+		{
+			name:        "replaces existing priority",
+			labels:      []string{"priority:P3", "bug", "Priority:P2"},
+			label:       "priority:P0",
+			wantLabels:  []string{"bug", "priority:P0"},
+			wantAdded:   []string{"priority:P0"},
+			wantRemoved: []string{"priority:P3", "Priority:P2"},
+		},
+		{
+			name:       "no-op when priority already set",
+			labels:     []string{"priority:P2", "team:infra"},
+			label:      "priority:P2",
+			wantLabels: []string{"priority:P2", "team:infra"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := &github.Issue{Number: 1, Labels: tt.labels}
+			client := githubtest.NewFakeClient(issue)
+			client.Labels[1] = append([]string(nil), tt.labels...)
+
+			added, removed, err := reclassifyPriority(context.Background(), client, issue, tt.label, priorityLabelPrefix(tt.label, tt.label[len(tt.label)-2:]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(client.Labels[1], tt.wantLabels) {
+				t.Errorf("labels = %v, want %v", client.Labels[1], tt.wantLabels)
+			}
+			if !reflect.DeepEqual(added, tt.wantAdded) {
+				t.Errorf("added = %v, want %v", added, tt.wantAdded)
+			}
+			if !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("removed = %v, want %v", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestPriorityLabel(t *testing.T) {
+	configured := &PluginAgent{Config: map[string]interface{}{
+		"priority_labels": []interface{}{"prio/p0", "prio/p1"},
+	}}
+	if got := priorityLabel(configured, "P1"); got != "prio/p1" {
+		t.Errorf("expected configured label, got %q", got)
+	}
+	if got := priorityLabel(&PluginAgent{}, "P2"); got != "priority:P2" {
+		t.Errorf("expected default label, got %q", got)
+	}
+	if got := priorityLabelPrefix("prio/p1", "P1"); got != "prio/" {
+		t.Errorf("expected prefix prio/, got %q", got)
+	}
+}
+
+func TestIssueStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		issue          *github.Issue
+		wantInProgress bool
+		wantBlocked    bool
+	}{
+		{"status field", &github.Issue{Fields: map[string]string{"Status": "In Progress"}}, true, false},
+		{"status field wins over labels", &github.Issue{Labels: []string{"in-progress"}, Fields: map[string]string{"status": "Todo"}}, false, false},
+		{"blocked status", &github.Issue{Fields: map[string]string{"Status": "Blocked"}}, false, true},
+		{"labels without fields", &github.Issue{Labels: []string{"In Progress", "blocker"}}, true, true},
+		{"other fields only", &github.Issue{Labels: []string{"in-progress"}, Fields: map[string]string{"Iteration": "Sprint 3"}}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inProgress, blocked := issueStatus(tt.issue)
+			if inProgress != tt.wantInProgress || blocked != tt.wantBlocked {
+				t.Errorf("issueStatus() = (%v, %v), want (%v, %v)", inProgress, blocked, tt.wantInProgress, tt.wantBlocked)
+			}
+		})
+	}
+}
+
+func TestExecuteCodeReview(t *testing.T) {
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req llm.ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		prompts = append(prompts, req.Messages[len(req.Messages)-1].Content)
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"Looks good."}}]}`))
+	}))
+	defer server.Close()
+
+	client := githubtest.NewFakeClient()
+	client.PullRequests = []*github.PullRequest{
+		{Number: 1, Title: "Labeled", Labels: []string{"needs-review"}, URL: "https://github.com/o/r/pull/1"},
+		{Number: 2, Title: "Unlabeled", URL: "https://github.com/o/r/pull/2"},
+	}
+	client.Diffs[1] = "line one\nline two\nline three\n"
+	client.Diffs[2] = "small\n"
+	executor := NewPluginExecutor(llm.NewClient(server.URL, "m", "", time.Second), client, nil)
+	reviewer := &PluginAgent{Name: "Code Review Enforcer", Config: map[string]interface{}{"max_diff_bytes": 12}}
+
+	result, err := executor.Execute(context.Background(), reviewer, map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reviewed := result.IssuesAffected; len(reviewed) != 1 || reviewed[0] != 1 {
+		t.Errorf("expected only the labeled PR to be reviewed, got %v", reviewed)
+	}
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "line one\n") || strings.Contains(prompts[0], "line two") {
+		t.Errorf("expected the diff truncated at a line boundary, got prompt %q", prompts)
+	}
+	if comments := client.Comments[1]; len(comments) != 1 || !strings.Contains(comments[0], "Looks good.") || !strings.Contains(comments[0], "first 12 bytes") {
+		t.Errorf("unexpected review comments %q", comments)
+	}
+
+	// An explicit number reviews that PR regardless of labels
+	if _, err := executor.Execute(context.Background(), reviewer, map[string]interface{}{"issue_number": 2}); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.Comments[2]) != 1 {
+		t.Error("expected requested PR to be reviewed")
+	}
+	if _, err := executor.Execute(context.Background(), reviewer, map[string]interface{}{"issue_number": 9}); err == nil {
+		t.Error("expected error for unknown PR")
+	}
+}
+
+func TestTruncateDiff(t *testing.T) {
+	if got, truncated := truncateDiff("abc\n", 0); got != "abc\n" || truncated {
+		t.Errorf("zero limit should disable truncation, got %q", got)
+	}
+	if got, truncated := truncateDiff("abcdef", 3); got != "abc" || !truncated {
+		t.Errorf("expected hard cut without newline, got %q", got)
+	}
+}
+
+func TestExecuteDeployment(t *testing.T) {
+	llmCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		llmCalls++
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"Production health checks are failing."}}]}`))
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	client := githubtest.NewFakeClient()
+	client.Deployments = []*github.Deployment{
+		{ID: 1, Environment: "production", Ref: "main", SHA: "aaaaaaaaaa", CreatedAt: now.Add(-time.Hour)},
+		{ID: 2, Environment: "staging", Ref: "main", SHA: "bbbbbbbbbb", CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: 3, Environment: "production", Ref: "v1", SHA: "cccccccccc", CreatedAt: now.Add(-72 * time.Hour)},
+		{ID: 4, Environment: "staging", Ref: "v2", SHA: "dddddddddd", CreatedAt: now.Add(-10 * time.Minute)},
+		{ID: 5, Environment: "staging", Ref: "v0", SHA: "eeeeeeeeee", CreatedAt: now.Add(-30 * 24 * time.Hour)},
+		{ID: 6, Environment: "production", Ref: "v0", SHA: "ffffffffff", CreatedAt: now.Add(-30 * 24 * time.Hour)},
+	}
+	client.DeploymentStatuses = map[int64][]*github.DeploymentStatus{
+		1: {{State: "failure", Description: "health check failed", CreatedAt: now.Add(-50 * time.Minute)}},
+		2: {{State: "queued", CreatedAt: now.Add(-3 * time.Hour)}}, // stuck
+		3: {{State: "error", CreatedAt: now.Add(-72 * time.Hour)}}, // outside the lookback
+		// 4 has no status yet but is recent
+		// 5 and 6 were abandoned long ago, without a status or still pending
+		6: {{State: "pending", CreatedAt: now.Add(-30 * 24 * time.Hour)}},
+	}
+	executor := NewPluginExecutor(llm.NewClient(server.URL, "m", "", time.Second), client, nil)
+	checker := &PluginAgent{Name: "Deployment Checker", Config: map[string]interface{}{
+		"pending_timeout":       "2h",
+		"create_issue_on_stuck": true,
+	}}
+
+	result, err := executor.Execute(context.Background(), checker, map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Extra["failed"], []int64{1}) || !reflect.DeepEqual(result.Extra["stuck"], []int64{2}) {
+		t.Errorf("failed = %v, stuck = %v", result.Extra["failed"], result.Extra["stuck"])
+	}
+	if result.Extra["summary"] != "Production health checks are failing." || llmCalls != 1 {
+		t.Errorf("expected one LLM summary, got %q after %d calls", result.Extra["summary"], llmCalls)
+	}
+	want := "Deployment stuck: staging deployment of main (bbbbbbb)"
+	if len(client.CreatedIssues) != 1 || client.CreatedIssues[0].Title != want {
+		t.Errorf("created issues = %v, want one titled %q", client.CreatedIssues, want)
+	}
+
+	// An open issue for the stuck deployment isn't duplicated, whatever its
+	// title's case
+	client.Issues = []*github.Issue{{Title: strings.ToLower(want), Labels: []string{"automated", "deployment-stuck"}}}
+	if _, err := executor.Execute(context.Background(), checker, map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.CreatedIssues) != 1 {
+		t.Errorf("expected no duplicate issue, got %v", client.CreatedIssues)
+	}
+}
+
+func TestExecuteDeployment_Healthy(t *testing.T) {
+	client := githubtest.NewFakeClient()
+	client.Deployments = []*github.Deployment{{ID: 1, Environment: "production", CreatedAt: time.Now()}}
+	client.DeploymentStatuses[1] = []*github.DeploymentStatus{{State: "success", CreatedAt: time.Now()}}
+	// No LLM server: a healthy run must not call it
+	executor := NewPluginExecutor(llm.NewClient("http://127.0.0.1:0", "m", "", time.Second), client, nil)
+
+	result, err := executor.Execute(context.Background(), &PluginAgent{Name: "Deployment Checker"}, map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != StatusCompleted || result.Metrics["checked"] != 1 {
+		t.Errorf("unexpected result: %v", result)
+	}
+}
+
+func TestExecuteDeduplicator(t *testing.T) {
+	answer := "DUPLICATE"
+	llmCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		llmCalls++
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"` + answer + `"}}]}`))
+	}))
+	defer server.Close()
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newClient := func() *githubtest.FakeClient {
+		return githubtest.NewFakeClient(
+			&github.Issue{Number: 7, Title: "Export reports as CSV", Body: "Finance wants to download the monthly report as a CSV file.", CreatedAt: base.Add(48 * time.Hour), URL: "https://github.com/o/r/issues/7"},
+			&github.Issue{Number: 3, Title: "CSV export for reports", Body: "Allow downloading the monthly report as a CSV file for finance.", CreatedAt: base, URL: "https://github.com/o/r/issues/3"},
+			&github.Issue{Number: 5, Title: "Dark mode", Body: "The dashboard hurts at night; add a dark theme.", CreatedAt: base.Add(24 * time.Hour), URL: "https://github.com/o/r/issues/5"},
+		)
+	}
+	deduper := &PluginAgent{Name: "Deduplicator", Config: map[string]interface{}{}}
+
+	client := newClient()
+	executor := NewPluginExecutor(llm.NewClient(server.URL, "m", "", time.Second), client, nil)
+	result, err := executor.Execute(context.Background(), deduper, map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	duplicates := result.Extra["duplicates"].([]map[string]interface{})
+	if len(duplicates) != 1 || duplicates[0]["issue"] != 7 || duplicates[0]["duplicate_of"] != 3 {
+		t.Fatalf("expected #7 flagged as a duplicate of #3, got %v", duplicates)
+	}
+	if comments := client.Comments[7]; len(comments) != 1 || !strings.Contains(comments[0], "https://github.com/o/r/issues/3") {
+		t.Errorf("expected one comment linking the canonical issue, got %q", comments)
+	}
+	if len(client.Comments) != 1 {
+		t.Errorf("expected only the newer issue to get a comment, got %v", client.Comments)
+	}
+
+	// A second run doesn't repeat the notice, nor ask the LLM again
+	client.IssueComments[7] = []github.Comment{{Body: client.Comments[7][0]}}
+	delete(client.Comments, 7)
+	llmCalls = 0
+	result, err = executor.Execute(context.Background(), deduper, map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(client.Comments) != 0 {
+		t.Errorf("expected no repeated notice, got %v", client.Comments)
+	}
+	if llmCalls != 0 {
+		t.Errorf("expected an already-notified pair to skip the LLM check, got %d calls", llmCalls)
+	}
+	duplicates = result.Extra["duplicates"].([]map[string]interface{})
+	if len(duplicates) != 1 || duplicates[0]["commented"] != false {
+		t.Errorf("expected #7 still reported without a new comment, got %v", duplicates)
+	}
+
+	// Candidates the LLM rejects are left alone
+	answer = "DISTINCT"
+	client = newClient()
+	executor = NewPluginExecutor(llm.NewClient(server.URL, "m", "", time.Second), client, nil)
+	result, err = executor.Execute(context.Background(), deduper, map[string]interface{}{"issue_number": 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Metrics["candidates"] != 1 || len(client.Comments) != 0 {
+		t.Errorf("expected one rejected candidate and no comments, got %v / %v", result, client.Comments)
+	}
+}
+
+func TestExecuteValidator_Concurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"## Description\nA rewritten description that is long enough to pass.\n\n## Acceptance Criteria\n- Done"}}]}`))
+	}))
+	defer server.Close()
+
+	validBody := "## Description\nThis task has a description that is long enough to pass validation.\n\n## Acceptance Criteria\n- It works"
+	client := githubtest.NewFakeClient()
+	for i := 1; i <= 20; i++ {
+		issue := &github.Issue{Number: i, Title: fmt.Sprintf("Task %d", i), Labels: []string{"priority:P2"},
+			URL: fmt.Sprintf("https://github.com/o/repo%d/issues/%d", i%3, i)}
+		if i%2 == 0 {
+			issue.Body = validBody
+		}
+		client.Issues = append(client.Issues, issue)
+	}
+
+	executor := NewPluginExecutorWithOptions(llm.NewClient(server.URL, "m", "", time.Second), client, nil, ExecutorOptions{Concurrency: 4})
+	result, err := executor.Execute(context.Background(), &PluginAgent{Name: "Task Validator"}, map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Metrics["validated_count"] != 20 || result.Metrics["fixed_count"] != 10 {
+		t.Errorf("expected 20 validated and 10 fixed, got %v / %v (errors %v)", result.Metrics["validated_count"], result.Metrics["fixed_count"], result.Errors)
+	}
+	validated := result.Extra["validated_issues"].([]map[string]interface{})
+	for i, entry := range validated {
+		if entry["number"] != i+1 {
+			t.Fatalf("expected results in issue order, got #%v at position %d", entry["number"], i)
+		}
+		if fixed := entry["fixed"].(bool); fixed != ((i+1)%2 == 1) {
+			t.Errorf("issue #%d: fixed = %v", i+1, fixed)
+		}
+	}
+	for i := 1; i <= 20; i++ {
+		if !github.HasLabels(client.Labels[i], []string{"agent-validator"}) {
+			t.Errorf("issue #%d was not labeled as validated: %v", i, client.Labels[i])
+		}
+		if updated := client.UpdatedIssues[i] != nil; updated != (i%2 == 1) {
+			t.Errorf("issue #%d: updated = %v", i, updated)
+		}
+	}
+}
+
+func TestExtractDependenciesFromBody(t *testing.T) {
+	body := "Depends on #12 and GH-13\nRequires acme/api#7, see https://github.com/acme/web/issues/8\nRelated to #99\nWaiting for #12 again\nThis blocks #20 and prevents acme/api#21"
+
+	want := []markdown.Reference{
+		{Number: 12},
+		{Number: 13},
+		{Owner: "acme", Repo: "api", Number: 7},
+		{Owner: "acme", Repo: "web", Number: 8},
+	}
+	if got := extractDependenciesFromBody(body); !reflect.DeepEqual(got, want) {
+		t.Errorf("extractDependenciesFromBody() = %+v, want %+v", got, want)
+	}
+
+	wantBlockers := []markdown.Reference{{Number: 20}, {Owner: "acme", Repo: "api", Number: 21}}
+	if got := extractBlockersFromBody(body); !reflect.DeepEqual(got, wantBlockers) {
+		t.Errorf("extractBlockersFromBody() = %+v, want %+v", got, wantBlockers)
+	}
+
+	if got := formatDependencies(wantBlockers); got != "- #20\n- acme/api#21" {
+		t.Errorf("formatDependencies() = %q", got)
+	}
+}
+
+func TestExecuteDependencyTracker_BlockerStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"## Dependency Analysis"}}]}`))
+	}))
+	defer server.Close()
+
+	// The fake serves issues by number, so other/lib#3 is the only issue 3
+	newClient := func(mode string, otherState string) *githubtest.FakeClient {
+		client := githubtest.NewFakeClient(
+			&github.Issue{Number: 1, Title: "Ship it", URL: "https://github.com/o/r/issues/1", Body: "Depends on #12, #15 and #16\nRequires other/lib#3\nWaiting for #404"},
+			&github.Issue{Number: 12, Title: "API", State: "open"},
+			&github.Issue{Number: 15, Title: "UI", State: "OPEN"},
+			&github.Issue{Number: 16, Title: "Docs", State: "closed"},
+			&github.Issue{Number: 3, Title: "Lib", State: otherState},
+		)
+		client.Mode = mode
+		return client
+	}
+	tracker := &PluginAgent{Name: "Dependency Tracker"}
+
+	client := newClient("repo", "open")
+	executor := NewPluginExecutor(llm.NewClient(server.URL, "m", "", time.Second), client, nil)
+	result, err := executor.executeDependencyTracker(context.Background(), tracker, map[string]interface{}{"issue_number": 1})
+	if err != nil {
+		t.Fatalf("executeDependencyTracker() error = %v", err)
+	}
+	if blocked, _ := result.Extra["blocked"].(bool); !blocked {
+		t.Error("expected the issue to be blocked")
+	}
+	if got, want := result.Extra["open_dependencies"], []string{"#12", "#15"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("open_dependencies = %v, want %v", got, want)
+	}
+	if got := result.Extra["summary"]; got != "blocked by 2 open dependencies: #12, #15" {
+		t.Errorf("summary = %q", got)
+	}
+	wantStates := map[string]string{"#12": "open", "#15": "open", "#16": "closed", "other/lib#3": "unknown", "#404": "unknown"}
+	if got := result.Extra["dependency_states"]; !reflect.DeepEqual(got, wantStates) {
+		t.Errorf("dependency_states = %v, want %v", got, wantStates)
+	}
+	for _, call := range client.CallsTo("GetIssue") {
+		if call.Owner == "other" {
+			t.Error("expected other repositories not to be fetched in repo mode")
+		}
+	}
+
+	// Project mode can see other repositories; closed dependencies don't block
+	client = newClient("project", "closed")
+	client.Issues[1].State = "closed"
+	client.Issues[2].State = "closed"
+	executor = NewPluginExecutor(llm.NewClient(server.URL, "m", "", time.Second), client, nil)
+	result, err = executor.executeDependencyTracker(context.Background(), tracker, map[string]interface{}{"issue_number": 1})
+	if err != nil {
+		t.Fatalf("executeDependencyTracker() error = %v", err)
+	}
+	if blocked, _ := result.Extra["blocked"].(bool); blocked || result.Extra["summary"] != "not blocked" {
+		t.Errorf("expected the issue not to be blocked, got %v (%v)", result.Extra["blocked"], result.Extra["summary"])
+	}
+	if states := result.Extra["dependency_states"].(map[string]string); states["other/lib#3"] != "closed" {
+		t.Errorf("expected the cross-repo dependency to be checked, got %v", states)
+	}
+}
+
+func TestExecutePriorityCalculator_JSONReply(t *testing.T) {
+	tests := []struct {
+		name         string
+		reply        string
+		wantPriority string
+		wantComment  string
+	}{
+		{
+			name:         "json reply",
+			reply:        `{"priority":"p1","rationale":"Not P0, but previously P3 was too low."}`,
+			wantPriority: "P1",
+			wantComment:  "**Suggested Priority**: P1\n\nNot P0, but previously P3 was too low.",
+		},
+		{
+			name:         "free text falls back to the heuristic",
+			reply:        "## Priority Assessment\n\n**Suggested Priority**: P2",
+			wantPriority: "P2",
+			wantComment:  "**Suggested Priority**: P2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := json.Marshal(tt.reply)
+				w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":` + string(data) + `}}]}`))
+			}))
+			defer server.Close()
+
+			client := githubtest.NewFakeClient(&github.Issue{Number: 7, Title: "Checkout fails", URL: "https://github.com/o/r/issues/7"})
+			executor := NewPluginExecutor(llm.NewClient(server.URL, "m", "", time.Second), client, nil)
+			result, err := executor.executePriorityCalculator(context.Background(), &PluginAgent{Name: "Priority Calculator"}, map[string]interface{}{"issue_number": 7})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := result.Extra["suggested_priority"]; got != tt.wantPriority {
+				t.Errorf("suggested_priority = %v, want %s", got, tt.wantPriority)
+			}
+			if got := result.Extra["assessment"].(string); !strings.Contains(got, tt.wantComment) {
+				t.Errorf("assessment = %q, want it to contain %q", got, tt.wantComment)
+			}
+		})
+	}
+}
+
+func TestExecutePriorityCalculator_LabelMap(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"{\"priority\":\"P1\",\"rationale\":\"Blocks checkout.\"}"}}]}`))
+	}))
+	defer server.Close()
+
+	labelMap := map[string]interface{}{
+		"P0": "priority:critical",
+		"p1": "priority:high",
+		"P2": "priority:medium",
+	}
+	tests := []struct {
+		name        string
+		config      map[string]interface{}
+		wantLabels  []string
+		wantAdded   []string
+		wantRemoved []string
+	}{
+		{
+			name:        "mapped label replaces conflicting priority labels",
+			config:      map[string]interface{}{"priority_label_map": labelMap},
+			wantLabels:  []string{"bug", "priority:P3", "priority:high"},
+			wantAdded:   []string{"priority:high"},
+			wantRemoved: []string{"Priority:Medium"},
+		},
+		{
+			name:       "no mapping skips labeling",
+			config:     map[string]interface{}{},
+			wantLabels: []string{"bug", "Priority:Medium", "priority:P3"},
+		},
+		{
+			name:       "unmapped priority skips labeling",
+			config:     map[string]interface{}{"priority_label_map": map[string]interface{}{"P0": "priority:critical"}},
+			wantLabels: []string{"bug", "Priority:Medium", "priority:P3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := []string{"bug", "Priority:Medium", "priority:P3"}
+			client := githubtest.NewFakeClient(&github.Issue{Number: 7, Title: "Checkout fails", Labels: existing, URL: "https://github.com/o/r/issues/7"})
+			client.Labels[7] = append([]string(nil), existing...)
+			executor := NewPluginExecutor(llm.NewClient(server.URL, "m", "", time.Second), client, nil)
+			agent := &PluginAgent{Name: "Priority Calculator", Config: tt.config}
+			result, err := executor.executePriorityCalculator(context.Background(), agent, map[string]interface{}{"issue_number": 7})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(client.Labels[7], tt.wantLabels) {
+				t.Errorf("labels = %v, want %v", client.Labels[7], tt.wantLabels)
+			}
+			added, _ := result.Extra["labels_added"].([]string)
+			removed, _ := result.Extra["labels_removed"].([]string)
+			if !reflect.DeepEqual(added, tt.wantAdded) || !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("added %v and removed %v, want %v and %v", added, removed, tt.wantAdded, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestPriorityLabelMap(t *testing.T) {
+	agent := &PluginAgent{Config: map[string]interface{}{"priority_label_map": map[string]interface{}{
+		"P0":     "priority:critical",
+		"urgent": "priority:critical",
+		"P3":     3,
+	}}}
+	labelMap, problems := priorityLabelMap(agent)
+	if !reflect.DeepEqual(labelMap, map[string]string{"P0": "priority:critical"}) {
+		t.Errorf("unexpected label map %v", labelMap)
+	}
+	if len(problems) != 2 {
+		t.Errorf("expected the bad key and value to be reported, got %v", problems)
+	}
+}
+
+func TestExecuteGeneric_AddLabel(t *testing.T) {
+	client := githubtest.NewFakeClient(&github.Issue{Number: 3, Title: "Crash", URL: "https://github.com/o/r/issues/3"})
+	executor := NewPluginExecutor(nil, client, nil)
+	tagger := &PluginAgent{
+		Name:    "Tagger",
+		Type:    "custom",
+		Actions: []string{"Add label triaged", "Apply label"},
+		Config:  map[string]interface{}{"label": "summarized"},
+	}
+
+	result, err := executor.executeGeneric(context.Background(), tagger, map[string]interface{}{"issue_number": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"triaged", "summarized"}
+	if got := result.Extra["labels_added"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("labels_added = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(client.Labels[3], want) {
+		t.Errorf("labels applied = %v, want %v", client.Labels[3], want)
+	}
+}
+
+func TestLabelFromAction(t *testing.T) {
+	configured := &PluginAgent{Config: map[string]interface{}{"label": "summarized"}}
+	tests := []struct {
+		action string
+		want   string
+		wantOK bool
+	}{
+		{"Add label triaged", "triaged", true},
+		{"Apply the label `needs review`", "needs review", true},
+		{"Add `good first issue` label", "good first issue", true},
+		{"Add label to the issue", "summarized", true},
+		{"Apply label", "summarized", true},
+		{"Add summary as a comment", "", false},
+		{"Check labels", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := labelFromAction(tt.action, configured)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("labelFromAction(%q) = %q, %v, want %q, %v", tt.action, got, ok, tt.want, tt.wantOK)
+		}
+	}
+	if _, ok := labelFromAction("Apply label", &PluginAgent{}); ok {
+		t.Error("expected no label without a name or Config[\"label\"]")
+	}
+}
+
+func TestExecuteExecutiveSummary_ReportMode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"All good."}}]}`))
+	}))
+	defer server.Close()
+
+	reportLabels := []string{"automated", "executive-summary", "report"}
+	tests := []struct {
+		name        string
+		mode        string
+		wantUpdated int // 0 when a new issue should be created
+	}{
+		{name: "create mode opens a new issue", mode: ReportModeCreate},
+		{name: "update mode rewrites the latest report", mode: ReportModeUpdate, wantUpdated: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			client := githubtest.NewFakeClient(
+				&github.Issue{Number: 4, Title: "Executive Summary - old", State: "open", Labels: reportLabels, CreatedAt: now.AddDate(0, 0, -14), URL: "https://github.com/o/r/issues/4"},
+				&github.Issue{Number: 5, Title: "Executive Summary - last week", State: "open", Labels: reportLabels, CreatedAt: now.AddDate(0, 0, -7), URL: "https://github.com/o/r/issues/5"},
+				&github.Issue{Number: 6, Title: "Weekly report", State: "open", Labels: []string{"report"}, CreatedAt: now, URL: "https://github.com/o/r/issues/6"},
+			)
+			executor := NewPluginExecutorWithOptions(llm.NewClient(server.URL, "m", "", time.Second), client, nil, ExecutorOptions{ReportMode: tt.mode})
+
+			result, err := executor.executeExecutiveSummary(context.Background(), &PluginAgent{Name: "Executive Summary"}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantUpdated == 0 {
+				if len(client.CreatedIssues) != 1 || len(client.UpdatedIssues) != 0 || result.Extra["issue_created"] != true {
+					t.Errorf("expected a new report issue, got created %v, updated %v", client.CreatedIssues, client.UpdatedIssues)
+				}
+				return
+			}
+			updated := client.UpdatedIssues[tt.wantUpdated]
+			if len(client.CreatedIssues) != 0 || len(client.UpdatedIssues) != 1 || updated == nil {
+				t.Fatalf("expected only #%d to be updated, got created %v, updated %v", tt.wantUpdated, client.CreatedIssues, client.UpdatedIssues)
+			}
+			if !strings.Contains(updated.Body, "All good.") || !strings.HasPrefix(updated.Title, "Executive Summary - "+now.Format("2006-01-02")) {
+				t.Errorf("unexpected updated report %+v", updated)
+			}
+			if result.Extra["issue_updated"] != true || result.Extra["updated_issue_number"] != tt.wantUpdated {
+				t.Errorf("unexpected result %v", result)
+			}
+		})
+	}
+}
+
+func TestExecuteExecutiveSummary_ExistingReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"All good."}}]}`))
+	}))
+	defer server.Close()
+
+	title := "Executive Summary - " + time.Now().Format("2006-01-02")
+	client := githubtest.NewFakeClient(&github.Issue{
+		Number: 9, Title: title, State: "open", URL: "https://github.com/o/r/issues/9",
+		Labels: []string{"automated", "executive-summary", "report"},
+	})
+	executor := NewPluginExecutor(llm.NewClient(server.URL, "m", "", time.Second), client, nil)
+
+	result, err := executor.executeExecutiveSummary(context.Background(), &PluginAgent{Name: "Executive Summary"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(client.CreatedIssues) != 0 {
+		t.Errorf("expected no duplicate report issue, got %v", client.CreatedIssues)
+	}
+	if result.Extra["issue_exists"] != true || result.Extra["existing_issue_number"] != 9 || result.Extra["issue_created"] != nil {
+		t.Errorf("unexpected result %v", result)
+	}
+}
+
+func TestReportMode_AgentOverride(t *testing.T) {
+	executor := NewPluginExecutorWithOptions(nil, nil, nil, ExecutorOptions{ReportMode: ReportModeUpdate})
+	if got := executor.reportMode(&PluginAgent{}); got != ReportModeUpdate {
+		t.Errorf("reportMode() = %q, want the executor default", got)
+	}
+	if got := executor.reportMode(&PluginAgent{Config: map[string]interface{}{"report_mode": "create"}}); got != ReportModeCreate {
+		t.Errorf("reportMode() = %q, want the agent override", got)
+	}
+}
+
+func TestAddRepoContext_ConcurrentAgents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"All good."}}]}`))
+	}))
+	defer server.Close()
+
+	client := githubtest.NewFakeClient(&github.Issue{Number: 1, Title: "Task", State: "open", URL: "https://github.com/o/r/issues/1"})
+	executor := NewPluginExecutor(llm.NewClient(server.URL, "m", "", time.Second), client, nil)
+	config := map[string]interface{}{"include_repo_context": true}
+	agents := []*PluginAgent{
+		{Name: "Executive Summary", Config: config},
+		{Name: "Progress Reporter", Config: config},
+	}
+
+	// Run with -race: both agents fill the shared repository cache at once
+	var wg sync.WaitGroup
+	for _, pluginAgent := range agents {
+		wg.Add(1)
+		go func(pluginAgent *PluginAgent) {
+			defer wg.Done()
+			if _, err := executor.Execute(context.Background(), pluginAgent, map[string]interface{}{}); err != nil {
+				t.Error(err)
+			}
+		}(pluginAgent)
+	}
+	wg.Wait()
+
+	if calls := client.CallsTo("GetRepository"); len(calls) != 1 {
+		t.Errorf("expected the repository context fetched once, got %d calls", len(calls))
+	}
+}
+
+func TestSignedComment(t *testing.T) {
+	identity, err := bot.New("Robo", "**{{.Bot}} / {{.Name}}**")
+	if err != nil {
+		t.Fatal(err)
+	}
+	executor := NewPluginExecutorWithOptions(nil, nil, nil, ExecutorOptions{Identity: identity})
+	dedupe := &PluginAgent{Name: "Deduplicator"}
+
+	comment := executor.signedComment(dedupe, duplicateCommentHeader, "Looks like #1")
+	if want := "**Robo / Deduplicator**: " + duplicateCommentHeader + "\n\nLooks like #1"; comment != want {
+		t.Errorf("signedComment() = %q, want %q", comment, want)
+	}
+
+	for body, want := range map[string]bool{
+		comment: true,
+		duplicateCommentHeader + " (Generated by Deduplicator)\n\nLooks like #1": true, // Posted by older versions
+		"**Robo / Code Review Enforcer**: " + duplicateCommentHeader:             false,
+		"I think this is a " + duplicateCommentHeader:                            false,
+	} {
+		if got := executor.isAgentComment(dedupe, duplicateCommentHeader, body); got != want {
+			t.Errorf("isAgentComment(%q) = %v, want %v", body, got, want)
+		}
+	}
+}
+
+func TestExecuteCodeReview_SkipsReviewedHead(t *testing.T) {
+	var llmCalls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		llmCalls++
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"Looks good."}}]}`))
+	}))
+	defer server.Close()
+
+	pr := &github.PullRequest{Number: 1, Title: "Change", Labels: []string{"needs-review"}, HeadSHA: "abc123", URL: "https://github.com/o/r/pull/1"}
+	client := githubtest.NewFakeClient()
+	client.PullRequests = []*github.PullRequest{pr}
+	executor := NewPluginExecutor(llm.NewClient(server.URL, "m", "", time.Second), client, nil)
+	reviewer := &PluginAgent{Name: "Code Review Enforcer"}
+
+	if _, err := executor.Execute(context.Background(), reviewer, map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.Comments[1]) != 1 || !strings.Contains(client.Comments[1][0], "abc123") {
+		t.Fatalf("expected a review recording the head commit, got %q", client.Comments[1])
+	}
+
+	// The next run finds the review of the same head commit
+	client.IssueComments[1] = []github.Comment{{Body: client.Comments[1][0]}}
+	result, err := executor.Execute(context.Background(), reviewer, map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(client.Comments[1]) != 1 || llmCalls != 1 || result.Metrics["already_reviewed"] != 1 {
+		t.Errorf("expected the reviewed head skipped, got %d comments, %d LLM calls, metrics %v", len(client.Comments[1]), llmCalls, result.Metrics)
+	}
+
+	// New commits get a new review
+	pr.HeadSHA = "def456"
+	if _, err := executor.Execute(context.Background(), reviewer, map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.Comments[1]) != 2 || !strings.Contains(client.Comments[1][1], "def456") {
+		t.Errorf("expected the new head commit reviewed, got %q", client.Comments[1])
+	}
+}
+
+func TestExecute_SkipsProtected(t *testing.T) {
+	var llmCalls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		llmCalls++
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"DUPLICATE"}}]}`))
+	}))
+	defer server.Close()
+
+	protected := []string{"needs-review", "No-Agent"}
+	client := githubtest.NewFakeClient(
+		&github.Issue{Number: 1, Title: "Checkout fails on Safari", Body: "The checkout button does nothing on Safari", URL: "https://github.com/o/r/issues/1"},
+		&github.Issue{Number: 2, Title: "Checkout fails on Safari", Body: "The checkout button does nothing on Safari", Labels: protected, URL: "https://github.com/o/r/issues/2"},
+	)
+	client.PullRequests = []*github.PullRequest{{Number: 3, Title: "Change", Labels: protected, HeadSHA: "abc123", URL: "https://github.com/o/r/pull/3"}}
+	executor := NewPluginExecutorWithOptions(llm.NewClient(server.URL, "m", "", time.Second), client, nil, ExecutorOptions{SkipLabels: []string{"no-agent"}})
+
+	ctx := context.Background()
+	run := map[string]func() (*Result, error){
+		"priority": func() (*Result, error) {
+			return executor.executePriorityCalculator(ctx, &PluginAgent{Name: "Priority Calculator"}, map[string]interface{}{"issue_number": 2})
+		},
+		"dependency": func() (*Result, error) {
+			return executor.executeDependencyTracker(ctx, &PluginAgent{Name: "Dependency Tracker"}, map[string]interface{}{"issue_number": 2})
+		},
+		"code review": func() (*Result, error) {
+			return executor.Execute(ctx, &PluginAgent{Name: "Code Review Enforcer"}, map[string]interface{}{})
+		},
+		"deduplicator": func() (*Result, error) {
+			return executor.Execute(ctx, &PluginAgent{Name: "Issue Deduplicator"}, map[string]interface{}{})
+		},
+		"generic": func() (*Result, error) {
+			return executor.Execute(ctx, &PluginAgent{Name: "Triage", Actions: []string{"Add label `triaged`", "Add comment"}}, map[string]interface{}{"issue_number": 2})
+		},
+	}
+	for name, fn := range run {
+		result, err := fn()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(result.IssuesAffected) != 0 {
+			t.Errorf("%s: expected the protected issue left alone, affected %v", name, result.IssuesAffected)
+		}
+	}
+	if len(client.Comments) != 0 || len(client.Labels) != 0 || llmCalls != 0 {
+		t.Errorf("expected no changes or LLM calls, got comments %v, labels %v, %d LLM calls", client.Comments, client.Labels, llmCalls)
+	}
+}
+
+func TestRenderPrompt_StrictFailureFallsBack(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "typo.md"), []byte("Summarize {{.Titel}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pluginAgent := &PluginAgent{Name: "Typo", PromptPath: "prompts/typo.md"}
+	data := map[string]interface{}{"Title": "login"}
+
+	lenient, err := prompts.NewMultiPathLoader([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := NewPluginExecutor(nil, nil, lenient).renderPrompt(pluginAgent, data); got != "Summarize <no value>" {
+		t.Errorf("lenient renderPrompt() = %q", got)
+	}
+
+	strict, err := prompts.NewMultiPathLoaderWithOptions([]string{dir}, prompts.LoaderOptions{Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := NewPluginExecutor(nil, nil, strict).renderPrompt(pluginAgent, data); got != "" {
+		t.Errorf("expected a strict render failure to fall back to the built-in prompt, got %q", got)
+	}
+}
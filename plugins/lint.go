@@ -0,0 +1,95 @@
+package plugins
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kaskol10/github-project-agent/prompts"
+)
+
+// LintResult reports the problems found in one agent plugin file. Errors
+// stop the agent from loading or running as written; warnings are likely
+// mistakes.
+type LintResult struct {
+	Path     string   `json:"path"`
+	Name     string   `json:"name,omitempty"`
+	Errors   []string `json:"errors,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// OK reports whether the file has no errors
+func (r LintResult) OK() bool {
+	return len(r.Errors) == 0
+}
+
+// LintPlugins checks every agent file under basePath's core and custom
+// directories. Prompt templates referenced by the agents are looked up in
+// templates.
+func LintPlugins(basePath string, options LoadOptions, templates *prompts.Loader) ([]LintResult, error) {
+	var results []LintResult
+	for _, agentType := range []string{"core", "custom"} {
+		dirPath := filepath.Join(basePath, agentType)
+		entries, err := os.ReadDir(dirPath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read directory %s: %w", dirPath, err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+				continue
+			}
+			results = append(results, lintAgentFile(filepath.Join(dirPath, entry.Name()), agentType, options, templates))
+		}
+	}
+	return results, nil
+}
+
+// lintAgentFile loads one agent file and checks what it parsed to
+func lintAgentFile(filePath, agentType string, options LoadOptions, templates *prompts.Loader) LintResult {
+	result := LintResult{Path: filePath}
+	agent, err := loadAgentFromFile(filePath, agentType, options)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("failed to load: %v", err))
+		return result
+	}
+	result.Name = agent.Name
+
+	// Documents kept beside the agents, such as a validation schema, parse
+	// to nothing
+	if strings.TrimSpace(agent.Name) == "" && strings.TrimSpace(agent.Purpose) == "" &&
+		len(agent.Actions) == 0 && len(agent.Triggers) == 0 {
+		result.Warnings = append(result.Warnings, `not an agent definition (no "# Agent: <name>" heading or frontmatter)`)
+		return result
+	}
+
+	if strings.TrimSpace(agent.Name) == "" {
+		result.Errors = append(result.Errors, `no name (add a "# Agent: <name>" heading or "name" in the frontmatter)`)
+	}
+	if strings.TrimSpace(agent.Purpose) == "" {
+		result.Warnings = append(result.Warnings, "no purpose")
+	}
+	if len(agent.Actions) == 0 {
+		result.Warnings = append(result.Warnings, "no actions")
+	}
+	if len(agent.Triggers) == 0 {
+		result.Warnings = append(result.Warnings, "no triggers; the agent only runs when invoked by name")
+	}
+
+	result.Errors = append(result.Errors, agent.ScheduleErrors...)
+	result.Errors = append(result.Errors, agent.ConditionErrors...)
+	if _, problems := priorityLabelMap(agent); len(problems) > 0 {
+		result.Errors = append(result.Errors, problems...)
+	}
+
+	if agent.PromptPath != "" && templates != nil {
+		if name := promptTemplateName(agent); !templates.HasTemplate(name) {
+			result.Errors = append(result.Errors, fmt.Sprintf("prompt template %q (from %s) not found", name, agent.PromptPath))
+		}
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kaskol10/github-project-agent/prompts"
+)
+
+func TestLintPlugins(t *testing.T) {
+	base := t.TempDir()
+	files := map[string]string{
+		"core/good.md": "---\nname: Good Agent\npurpose: Works\nprompt_path: prompts/summarizer.md\n" +
+			"triggers:\n  - schedule: \"0 9 * * 1\"\nactions:\n  - Summarize\n---\n",
+		"custom/bad-cron.md": "---\nname: Bad Cron\npurpose: Broken\ntriggers:\n  - schedule: \"every monday\"\nactions:\n  - Run\n---\n",
+		"custom/missing-prompt.md": "---\nname: Missing Prompt\npurpose: Broken\nprompt_path: prompts/nope.md\n" +
+			"triggers:\n  - manual: true\nactions:\n  - Run\n---\n",
+		"custom/typo.md":    "---\nname: Typo\npurpos: Broken\n---\n",
+		"custom/no-name.md": "# My Agent\n\n## Actions\n1. Run\n",
+		"custom/schema.md":  "# Validation Schema\n\nSome notes\n",
+		"custom/notes.txt":  "ignored",
+	}
+	for name, content := range files {
+		path := filepath.Join(base, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	templates, err := prompts.NewMultiPathLoader(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := LintPlugins(base, LoadOptions{}, templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byFile := make(map[string]LintResult)
+	for _, result := range results {
+		rel, _ := filepath.Rel(base, result.Path)
+		byFile[filepath.ToSlash(rel)] = result
+	}
+	if len(byFile) != 6 {
+		t.Fatalf("expected the 6 markdown files to be linted, got %v", byFile)
+	}
+
+	if good := byFile["core/good.md"]; !good.OK() || len(good.Warnings) != 0 || good.Name != "Good Agent" {
+		t.Errorf("expected a clean result, got %+v", good)
+	}
+	wantErrors := map[string]string{
+		"custom/bad-cron.md":       `invalid schedule "every monday"`,
+		"custom/missing-prompt.md": `prompt template "nope"`,
+		"custom/typo.md":           "failed to load",
+		"custom/no-name.md":        "no name",
+	}
+	for file, want := range wantErrors {
+		result := byFile[file]
+		if result.OK() || !strings.Contains(strings.Join(result.Errors, "\n"), want) {
+			t.Errorf("%s: expected an error containing %q, got %+v", file, want, result)
+		}
+	}
+	if warnings := byFile["custom/no-name.md"].Warnings; len(warnings) != 2 {
+		t.Errorf("expected purpose and triggers warnings, got %v", warnings)
+	}
+	if schema := byFile["custom/schema.md"]; !schema.OK() || len(schema.Warnings) != 1 {
+		t.Errorf("expected a document that isn't an agent to only warn, got %+v", schema)
+	}
+}
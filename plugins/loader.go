@@ -2,49 +2,116 @@ package plugins
 
 import (
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
+	"github.com/kaskol10/github-project-agent/github"
+	"github.com/robfig/cron/v3"
 	"gopkg.in/yaml.v3"
 )
 
 // PluginAgent represents a plugin-based agent loaded from .md files
 type PluginAgent struct {
-	Name       string
-	Type       string // "core" or "custom"
-	Purpose    string
-	Triggers   []Trigger
-	Guidelines map[string]interface{}
-	Actions    []string
-	Config     map[string]interface{}
-	PromptPath string
-	RawContent string
-	FilePath   string
+	Name            string                 `json:"name"`
+	Type            string                 `json:"type"` // "core" or "custom"
+	Purpose         string                 `json:"purpose,omitempty"`
+	Triggers        []Trigger              `json:"triggers,omitempty"`
+	Guidelines      map[string]interface{} `json:"guidelines,omitempty"`
+	Actions         []string               `json:"actions,omitempty"`
+	Config          map[string]interface{} `json:"config,omitempty"`
+	PromptPath      string                 `json:"prompt_path,omitempty"`
+	ScheduleErrors  []string               `json:"schedule_errors,omitempty"`  // Trigger schedules that failed to parse; the agent never runs on them
+	ConditionErrors []string               `json:"condition_errors,omitempty"` // Trigger conditions that failed to parse; their triggers never match
+	RawContent      string                 `json:"-"`
+	FilePath        string                 `json:"-"`
+
+	schedules  map[string]cron.Schedule // Schedules parsed at load time, by expression
+	conditions map[string]*Condition    // Conditions parsed at load time, by expression
+}
+
+// Summary describes the agent for external tools with a fixed set of keys.
+// Triggers and actions are always lists, and config, guidelines,
+// schedule_errors and condition_errors are only present when the agent has
+// them. The raw markdown and file path are left
+// out.
+func (p *PluginAgent) Summary() map[string]interface{} {
+	triggers := p.Triggers
+	if triggers == nil {
+		triggers = []Trigger{}
+	}
+	actions := p.Actions
+	if actions == nil {
+		actions = []string{}
+	}
+
+	summary := map[string]interface{}{
+		"name":        p.Name,
+		"type":        p.Type,
+		"purpose":     p.Purpose,
+		"triggers":    triggers,
+		"actions":     actions,
+		"prompt_path": p.PromptPath,
+	}
+	if len(p.Config) > 0 {
+		summary["config"] = p.Config
+	}
+	if len(p.Guidelines) > 0 {
+		summary["guidelines"] = p.Guidelines
+	}
+	if len(p.ScheduleErrors) > 0 {
+		summary["schedule_errors"] = p.ScheduleErrors
+	}
+	if len(p.ConditionErrors) > 0 {
+		summary["condition_errors"] = p.ConditionErrors
+	}
+	return summary
 }
 
 // Trigger defines when an agent should run
 type Trigger struct {
-	Event     string   // e.g., "issues.opened", "pull_request.opened"
-	Schedule  string   // Cron expression
-	Condition string   // e.g., "labels.contains('needs-review')"
-	Manual    bool     // Can be triggered manually
-	Labels    []string // Required labels
+	Event     string   `json:"event,omitempty"`     // e.g., "issues.opened", "pull_request.opened"
+	Schedule  string   `json:"schedule,omitempty"`  // Cron expression
+	Condition string   `json:"condition,omitempty"` // e.g., "labels.contains('needs-review') && state == 'open'"; see ParseCondition
+	Manual    bool     `json:"manual,omitempty"`    // Can be triggered manually
+	Labels    []string `json:"labels,omitempty"`    // Required labels
+}
+
+// ParseSchedule parses a standard five-field cron expression or a descriptor
+// such as "@daily"
+func ParseSchedule(expr string) (cron.Schedule, error) {
+	return cron.ParseStandard(strings.TrimSpace(expr))
+}
+
+// LoadOptions configures how agent plugins are loaded
+type LoadOptions struct {
+	// StrictEnv fails loading an agent whose configuration references an
+	// unset environment variable without a default, instead of leaving the
+	// reference as-is
+	StrictEnv bool
 }
 
 // LoadPlugins loads all agent plugins from the specified directory
 func LoadPlugins(basePath string) ([]*PluginAgent, error) {
+	return LoadPluginsWithOptions(basePath, LoadOptions{})
+}
+
+// LoadPluginsWithOptions loads all agent plugins with loading behavior configured
+func LoadPluginsWithOptions(basePath string, options LoadOptions) ([]*PluginAgent, error) {
 	var agents []*PluginAgent
 
 	// Load from core directory
 	corePath := filepath.Join(basePath, "core")
-	if coreAgents, err := loadAgentsFromDir(corePath, "core"); err == nil {
+	if coreAgents, err := loadAgentsFromDir(corePath, "core", options); err == nil {
 		agents = append(agents, coreAgents...)
 	}
 
 	// Load from custom directory
 	customPath := filepath.Join(basePath, "custom")
-	if customAgents, err := loadAgentsFromDir(customPath, "custom"); err == nil {
+	if customAgents, err := loadAgentsFromDir(customPath, "custom", options); err == nil {
 		agents = append(agents, customAgents...)
 	}
 
@@ -52,7 +119,7 @@ func LoadPlugins(basePath string) ([]*PluginAgent, error) {
 }
 
 // loadAgentsFromDir loads all .md files from a directory as agents
-func loadAgentsFromDir(dirPath, agentType string) ([]*PluginAgent, error) {
+func loadAgentsFromDir(dirPath, agentType string, options LoadOptions) ([]*PluginAgent, error) {
 	var agents []*PluginAgent
 
 	// Check if directory exists
@@ -71,10 +138,10 @@ func loadAgentsFromDir(dirPath, agentType string) ([]*PluginAgent, error) {
 		}
 
 		filePath := filepath.Join(dirPath, entry.Name())
-		agent, err := loadAgentFromFile(filePath, agentType)
+		agent, err := loadAgentFromFile(filePath, agentType, options)
 		if err != nil {
 			// Log error but continue loading other agents
-			fmt.Printf("Warning: failed to load agent from %s: %v\n", filePath, err)
+			slog.Warn("failed to load agent", "path", filePath, "error", err)
 			continue
 		}
 
@@ -87,7 +154,7 @@ func loadAgentsFromDir(dirPath, agentType string) ([]*PluginAgent, error) {
 }
 
 // loadAgentFromFile loads a single agent from a markdown file
-func loadAgentFromFile(filePath, agentType string) (*PluginAgent, error) {
+func loadAgentFromFile(filePath, agentType string, options LoadOptions) (*PluginAgent, error) {
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
@@ -101,7 +168,20 @@ func loadAgentFromFile(filePath, agentType string) (*PluginAgent, error) {
 		Config:     make(map[string]interface{}),
 	}
 
-	// Parse the markdown file
+	front, hasFrontmatter, err := splitFrontmatter(string(content))
+	if err != nil {
+		return nil, err
+	}
+	if hasFrontmatter {
+		if err := agent.applyFrontmatter(front, options); err != nil {
+			return nil, err
+		}
+		agent.parseSchedules()
+		agent.parseConditions()
+		return agent, nil
+	}
+
+	// No frontmatter: parse the legacy headings of the markdown file
 	lines := strings.Split(string(content), "\n")
 
 	var currentSection string
@@ -130,12 +210,12 @@ func loadAgentFromFile(filePath, agentType string) (*PluginAgent, error) {
 		// Parse sections
 		if strings.HasPrefix(line, "## ") {
 			currentSection = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(line, "## ")))
-			continue
-		}
 
-		// Parse triggers
-		if currentSection == "trigger" || currentSection == "triggers" {
-			agent.Triggers = parseTriggers(lines, i)
+			// Parse triggers once, from the line after the heading
+			if currentSection == "trigger" || currentSection == "triggers" {
+				agent.Triggers = parseTriggers(lines, i+1)
+			}
+			continue
 		}
 
 		// Parse actions - only parse once when we first encounter a list item
@@ -162,8 +242,14 @@ func loadAgentFromFile(filePath, agentType string) (*PluginAgent, error) {
 
 		if inYamlBlock {
 			if strings.TrimSpace(line) == "```" {
+				// Resolve ${VAR} references before parsing
+				block, err := interpolateEnv(yamlBlock.String(), options.StrictEnv)
+				if err != nil {
+					return nil, fmt.Errorf("failed to interpolate configuration: %w", err)
+				}
+
 				// Parse YAML
-				if err := yaml.Unmarshal([]byte(yamlBlock.String()), &agent.Config); err == nil {
+				if err := yaml.Unmarshal([]byte(block), &agent.Config); err == nil {
 					// Successfully parsed
 				}
 				inYamlBlock = false
@@ -183,50 +269,219 @@ func loadAgentFromFile(filePath, agentType string) (*PluginAgent, error) {
 		}
 	}
 
+	agent.parseSchedules()
+	agent.parseConditions()
 	return agent, nil
 }
 
-// parseTriggers extracts trigger information from markdown
+// parseSchedules parses the triggers' cron schedules, recording the invalid
+// ones in ScheduleErrors so a typo doesn't silently stop the agent running
+func (a *PluginAgent) parseSchedules() {
+	for _, expr := range a.GetSchedules() {
+		schedule, err := ParseSchedule(expr)
+		if err != nil {
+			slog.Warn("invalid schedule, the agent won't run on it", "agent", a.Name, "path", a.FilePath, "schedule", expr, "error", err)
+			a.ScheduleErrors = append(a.ScheduleErrors, fmt.Sprintf("invalid schedule %q: %v", expr, err))
+			continue
+		}
+		if a.schedules == nil {
+			a.schedules = make(map[string]cron.Schedule)
+		}
+		a.schedules[expr] = schedule
+	}
+}
+
+// parseConditions parses the triggers' conditions, recording the invalid ones
+// in ConditionErrors
+func (a *PluginAgent) parseConditions() {
+	for _, trigger := range a.Triggers {
+		expr := trigger.Condition
+		if expr == "" || a.conditions[expr] != nil {
+			continue
+		}
+		condition, err := ParseCondition(expr)
+		if err != nil {
+			slog.Warn("invalid trigger condition, the trigger won't match", "agent", a.Name, "path", a.FilePath, "condition", expr, "error", err)
+			a.ConditionErrors = append(a.ConditionErrors, fmt.Sprintf("invalid condition %q: %v", expr, err))
+			continue
+		}
+		if a.conditions == nil {
+			a.conditions = make(map[string]*Condition)
+		}
+		a.conditions[expr] = condition
+	}
+}
+
+// ParsedSchedule returns expr, one of the agent's schedules, as parsed at
+// load time, parsing it now for agents built in code
+func (a *PluginAgent) ParsedSchedule(expr string) (cron.Schedule, error) {
+	if schedule, ok := a.schedules[expr]; ok {
+		return schedule, nil
+	}
+	return ParseSchedule(expr)
+}
+
+// agentFrontmatter is the YAML frontmatter of an agent markdown file
+type agentFrontmatter struct {
+	Name       string                 `yaml:"name"`
+	Type       string                 `yaml:"type"`
+	Purpose    string                 `yaml:"purpose"`
+	PromptPath string                 `yaml:"prompt_path"`
+	Triggers   []Trigger              `yaml:"triggers"`
+	Actions    []string               `yaml:"actions"`
+	Guidelines map[string]interface{} `yaml:"guidelines"`
+	Config     map[string]interface{} `yaml:"config"`
+}
+
+// splitFrontmatter returns the YAML between a leading "---" line and the next
+// "---" line, and whether the content starts with frontmatter at all
+func splitFrontmatter(content string) (string, bool, error) {
+	lines := strings.Split(strings.TrimPrefix(content, "\ufeff"), "\n")
+	if len(lines) == 0 || strings.TrimRight(lines[0], " \r") != "---" {
+		return "", false, nil
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], " \r") == "---" {
+			return strings.Join(lines[1:i], "\n"), true, nil
+		}
+	}
+	return "", false, fmt.Errorf("frontmatter is missing its closing ---")
+}
+
+// applyFrontmatter sets the agent's metadata from YAML frontmatter. Unknown
+// keys are rejected so a typo doesn't silently drop a setting.
+func (a *PluginAgent) applyFrontmatter(front string, options LoadOptions) error {
+	front, err := interpolateEnv(front, options.StrictEnv)
+	if err != nil {
+		return fmt.Errorf("failed to interpolate frontmatter: %w", err)
+	}
+
+	var meta agentFrontmatter
+	decoder := yaml.NewDecoder(strings.NewReader(front))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&meta); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	if strings.TrimSpace(meta.Name) == "" {
+		return fmt.Errorf("frontmatter has no name")
+	}
+
+	a.Name = meta.Name
+	if meta.Type != "" {
+		a.Type = meta.Type
+	}
+	a.Purpose = meta.Purpose
+	a.PromptPath = meta.PromptPath
+	a.Triggers = meta.Triggers
+	a.Actions = meta.Actions
+	if meta.Guidelines != nil {
+		a.Guidelines = meta.Guidelines
+	}
+	if meta.Config != nil {
+		a.Config = meta.Config
+	}
+	return nil
+}
+
+// envReferencePattern matches ${VAR} and ${VAR:-default}
+var envReferencePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// interpolateEnv replaces ${VAR} references with values from the environment.
+// ${VAR:-default} uses the default when VAR is unset or empty. References to
+// unset variables without a default are left as-is, or returned as an error
+// when strict is set.
+func interpolateEnv(text string, strict bool) (string, error) {
+	var missing []string
+	result := envReferencePattern.ReplaceAllStringFunc(text, func(ref string) string {
+		match := envReferencePattern.FindStringSubmatch(ref)
+		name := match[1]
+		hasDefault := strings.Contains(ref, ":-")
+
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+		if hasDefault {
+			return match[2]
+		}
+		if _, set := os.LookupEnv(name); set {
+			return ""
+		}
+		missing = append(missing, name)
+		return ref
+	})
+
+	if strict && len(missing) > 0 {
+		return "", fmt.Errorf("unset environment variables: %s", strings.Join(missing, ", "))
+	}
+	return result, nil
+}
+
+// parseTriggers extracts trigger information from markdown. Each event,
+// schedule and manual line is its own trigger. A condition or labels line
+// applies to the event triggers listed directly above it, so
+//
+//   - event: issues.opened
+//   - event: issues.edited
+//   - condition: labels.contains("needs-priority")
+//
+// guards both events.
 func parseTriggers(lines []string, startIdx int) []Trigger {
 	var triggers []Trigger
-	currentTrigger := Trigger{}
+	groupStart := 0        // First trigger a condition or labels line applies to
+	groupModified := false // The group already has a condition or labels
+
+	// startTrigger appends a trigger, starting a new group unless it
+	// continues a run of event triggers
+	startTrigger := func(trigger Trigger) {
+		last := len(triggers) - 1
+		if trigger.Event == "" || last < groupStart || triggers[last].Event == "" || groupModified {
+			groupStart = len(triggers)
+			groupModified = false
+		}
+		triggers = append(triggers, trigger)
+	}
 
 	for i := startIdx; i < len(lines) && i < startIdx+20; i++ {
 		line := strings.TrimSpace(lines[i])
 
-		if line == "" || strings.HasPrefix(line, "##") {
-			if currentTrigger.Event != "" || currentTrigger.Schedule != "" || currentTrigger.Manual {
-				triggers = append(triggers, currentTrigger)
-				currentTrigger = Trigger{}
-			}
-			if strings.HasPrefix(line, "##") {
-				break
-			}
+		if strings.HasPrefix(line, "##") {
+			break
+		}
+		if line == "" {
+			// A blank line ends the group
+			groupStart = len(triggers)
 			continue
 		}
 
 		if strings.HasPrefix(line, "- event:") {
-			currentTrigger.Event = strings.TrimSpace(strings.TrimPrefix(line, "- event:"))
+			startTrigger(Trigger{Event: strings.TrimSpace(strings.TrimPrefix(line, "- event:"))})
 		} else if strings.HasPrefix(line, "- schedule:") {
 			schedule := strings.TrimSpace(strings.TrimPrefix(line, "- schedule:"))
-			// Remove quotes if present
-			schedule = strings.Trim(schedule, "\"")
-			currentTrigger.Schedule = schedule
-		} else if strings.HasPrefix(line, "- condition:") {
-			currentTrigger.Condition = strings.TrimSpace(strings.TrimPrefix(line, "- condition:"))
+			// Remove trailing comments and quotes if present
+			if idx := strings.Index(schedule, "#"); idx >= 0 {
+				schedule = strings.TrimSpace(schedule[:idx])
+			}
+			startTrigger(Trigger{Schedule: strings.Trim(schedule, "\"")})
 		} else if strings.HasPrefix(line, "- manual:") {
 			manualStr := strings.TrimSpace(strings.TrimPrefix(line, "- manual:"))
-			currentTrigger.Manual = strings.ToLower(manualStr) == "true"
+			if strings.ToLower(manualStr) == "true" {
+				startTrigger(Trigger{Manual: true})
+			}
+		} else if strings.HasPrefix(line, "- condition:") {
+			condition := strings.TrimSpace(strings.TrimPrefix(line, "- condition:"))
+			for j := groupStart; j < len(triggers); j++ {
+				triggers[j].Condition = condition
+			}
+			groupModified = true
 		} else if strings.HasPrefix(line, "- labels:") {
-			labelsStr := strings.TrimSpace(strings.TrimPrefix(line, "- labels:"))
-			currentTrigger.Labels = parseStringList(labelsStr)
+			labels := parseStringList(strings.TrimSpace(strings.TrimPrefix(line, "- labels:")))
+			for j := groupStart; j < len(triggers); j++ {
+				triggers[j].Labels = labels
+			}
+			groupModified = true
 		}
 	}
 
-	if currentTrigger.Event != "" || currentTrigger.Schedule != "" || currentTrigger.Manual {
-		triggers = append(triggers, currentTrigger)
-	}
-
 	return triggers
 }
 
@@ -291,42 +546,37 @@ func parseStringList(s string) []string {
 	return result
 }
 
-// MatchTrigger checks if an agent should run based on the given event
-func (a *PluginAgent) MatchTrigger(event string, labels []string) bool {
+// MatchEvent checks if any of the agent's triggers matches event: a manual
+// trigger for a "manual" event, or an event trigger with the same name whose
+// required labels are all present and whose condition holds
+func (a *PluginAgent) MatchEvent(event Event) bool {
 	for _, trigger := range a.Triggers {
-		// Check event match
-		if trigger.Event != "" && trigger.Event == event {
-			// Check label conditions if specified
-			if len(trigger.Labels) > 0 {
-				hasAllLabels := true
-				for _, requiredLabel := range trigger.Labels {
-					found := false
-					for _, label := range labels {
-						if label == requiredLabel {
-							found = true
-							break
-						}
-					}
-					if !found {
-						hasAllLabels = false
-						break
-					}
-				}
-				if !hasAllLabels {
-					continue
-				}
-			}
+		if trigger.Manual && event.Name == "manual" {
 			return true
 		}
-
-		// Check manual trigger
-		if trigger.Manual && event == "manual" {
-			return true
+		if trigger.Event == "" || trigger.Event != event.Name || !github.HasLabels(event.Labels, trigger.Labels) {
+			continue
+		}
+		if trigger.Condition != "" {
+			condition, err := a.parsedCondition(trigger.Condition)
+			if err != nil || !condition.Matches(event) {
+				continue
+			}
 		}
+		return true
 	}
 	return false
 }
 
+// parsedCondition returns expr as parsed at load time, parsing it now for
+// agents built in code
+func (a *PluginAgent) parsedCondition(expr string) (*Condition, error) {
+	if condition, ok := a.conditions[expr]; ok {
+		return condition, nil
+	}
+	return ParseCondition(expr)
+}
+
 // HasSchedule checks if the agent has a scheduled trigger
 func (a *PluginAgent) HasSchedule() bool {
 	for _, trigger := range a.Triggers {
@@ -346,3 +596,14 @@ func (a *PluginAgent) GetSchedule() string {
 	}
 	return ""
 }
+
+// GetSchedules returns every cron schedule the agent is triggered on
+func (a *PluginAgent) GetSchedules() []string {
+	var schedules []string
+	for _, trigger := range a.Triggers {
+		if trigger.Schedule != "" {
+			schedules = append(schedules, trigger.Schedule)
+		}
+	}
+	return schedules
+}
@@ -0,0 +1,220 @@
+package plugins
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInterpolateEnv(t *testing.T) {
+	t.Setenv("AGENT_TEST_CHANNEL", "#eng")
+	t.Setenv("AGENT_TEST_EMPTY", "")
+
+	tests := []struct {
+		name    string
+		input   string
+		strict  bool
+		want    string
+		wantErr bool
+	}{
+		{name: "present", input: "channel: ${AGENT_TEST_CHANNEL}", want: "channel: #eng"},
+		{name: "present ignores default", input: "channel: ${AGENT_TEST_CHANNEL:-#ops}", want: "channel: #eng"},
+		{name: "absent with default", input: "days: ${AGENT_TEST_MISSING:-7}", want: "days: 7"},
+		{name: "empty with default", input: "days: ${AGENT_TEST_EMPTY:-7}", want: "days: 7"},
+		{name: "empty default", input: "days: ${AGENT_TEST_MISSING:-}", want: "days: "},
+		{name: "set but empty", input: "x: ${AGENT_TEST_EMPTY}", want: "x: "},
+		{name: "absent left as-is", input: "x: ${AGENT_TEST_MISSING}", want: "x: ${AGENT_TEST_MISSING}"},
+		{name: "absent strict", input: "x: ${AGENT_TEST_MISSING}", strict: true, wantErr: true},
+		{name: "absent strict with default", input: "x: ${AGENT_TEST_MISSING:-y}", strict: true, want: "x: y"},
+		{name: "no references", input: "x: $HOME", want: "x: $HOME"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := interpolateEnv(tt.input, tt.strict)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("interpolateEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("interpolateEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAgentFromFile_InterpolatesConfig(t *testing.T) {
+	t.Setenv("AGENT_TEST_THRESHOLD", "14")
+
+	content := "# Agent: Env Agent\n\n## Configuration\n\n```yaml\nstale_threshold_days: ${AGENT_TEST_THRESHOLD}\nchannel: \"${AGENT_TEST_MISSING:-#general}\"\n```\n"
+	path := filepath.Join(t.TempDir(), "env-agent.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	agent, err := loadAgentFromFile(path, "custom", LoadOptions{})
+	if err != nil {
+		t.Fatalf("loadAgentFromFile() error = %v", err)
+	}
+	if agent.Config["stale_threshold_days"] != 14 {
+		t.Errorf("stale_threshold_days = %v, want 14", agent.Config["stale_threshold_days"])
+	}
+	if agent.Config["channel"] != "#general" {
+		t.Errorf("channel = %v, want #general", agent.Config["channel"])
+	}
+}
+
+func writeAgentFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "agent.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadAgentFromFile_Frontmatter(t *testing.T) {
+	t.Setenv("AGENT_TEST_CHANNEL", "#eng")
+
+	content := `---
+name: Weekly Report
+type: core
+purpose: Summarize the week's progress
+prompt_path: prompts/progress-reporter.md
+triggers:
+  - schedule: "0 9 * * 1"
+  - event: issues.labeled
+    labels: [report]
+  - manual: true
+actions:
+  - create_issue
+config:
+  channel: "${AGENT_TEST_CHANNEL}"
+  days: 7
+---
+
+# Agent: Ignored Heading
+
+**Purpose**: Ignored too
+`
+	agent, err := loadAgentFromFile(writeAgentFile(t, content), "custom", LoadOptions{})
+	if err != nil {
+		t.Fatalf("loadAgentFromFile() error = %v", err)
+	}
+
+	if agent.Name != "Weekly Report" || agent.Type != "core" || agent.Purpose != "Summarize the week's progress" {
+		t.Errorf("unexpected metadata: %+v", agent)
+	}
+	if agent.PromptPath != "prompts/progress-reporter.md" {
+		t.Errorf("PromptPath = %q", agent.PromptPath)
+	}
+	wantTriggers := []Trigger{{Schedule: "0 9 * * 1"}, {Event: "issues.labeled", Labels: []string{"report"}}, {Manual: true}}
+	if !reflect.DeepEqual(agent.Triggers, wantTriggers) {
+		t.Errorf("Triggers = %+v, want %+v", agent.Triggers, wantTriggers)
+	}
+	if !reflect.DeepEqual(agent.Actions, []string{"create_issue"}) {
+		t.Errorf("Actions = %v", agent.Actions)
+	}
+	if agent.Config["channel"] != "#eng" || agent.Config["days"] != 7 {
+		t.Errorf("Config = %v", agent.Config)
+	}
+	if agent.RawContent != content {
+		t.Error("expected the raw content to be kept")
+	}
+}
+
+func TestLoadAgentFromFile_FrontmatterErrors(t *testing.T) {
+	tests := map[string]string{
+		"unterminated": "---\nname: Broken\n",
+		"unknown key":  "---\nname: Typo\npurpsoe: oops\n---\n",
+		"no name":      "---\npurpose: Nameless\n---\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := loadAgentFromFile(writeAgentFile(t, content), "custom", LoadOptions{}); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestLoadAgentFromFile_Legacy(t *testing.T) {
+	content := "# Agent: Legacy Agent\n\n**Purpose**: Check things\n\n## Triggers\n\n- schedule: \"0 8 * * *\"\n- manual: true\n\n## Actions\n\n1. Add comment\n2. Add label\n\n## Prompt\n\n- path: prompts/legacy.md\n"
+	agent, err := loadAgentFromFile(writeAgentFile(t, content), "custom", LoadOptions{})
+	if err != nil {
+		t.Fatalf("loadAgentFromFile() error = %v", err)
+	}
+
+	if agent.Name != "Legacy Agent" || agent.Type != "custom" || agent.Purpose != "Check things" {
+		t.Errorf("unexpected metadata: %+v", agent)
+	}
+	if len(agent.Triggers) == 0 || agent.Triggers[0].Schedule != "0 8 * * *" {
+		t.Errorf("Triggers = %+v", agent.Triggers)
+	}
+	if !reflect.DeepEqual(agent.Actions, []string{"Add comment", "Add label"}) {
+		t.Errorf("Actions = %v", agent.Actions)
+	}
+	if agent.PromptPath != "prompts/legacy.md" {
+		t.Errorf("PromptPath = %q", agent.PromptPath)
+	}
+}
+
+func TestPluginAgent_Summary(t *testing.T) {
+	pluginAgent := &PluginAgent{
+		Name:       "Priority",
+		Type:       "core",
+		Triggers:   []Trigger{{Event: "issues.opened"}},
+		RawContent: "# Agent: Priority",
+		FilePath:   ".github/agents/core/priority.md",
+	}
+
+	data, err := json.Marshal(pluginAgent.Summary())
+	if err != nil {
+		t.Fatalf("failed to marshal summary: %v", err)
+	}
+	want := `{"actions":[],"name":"Priority","prompt_path":"","purpose":"","triggers":[{"event":"issues.opened"}],"type":"core"}`
+	if string(data) != want {
+		t.Errorf("Summary() = %s, want %s", data, want)
+	}
+
+	data, err = json.Marshal(pluginAgent)
+	if err != nil {
+		t.Fatalf("failed to marshal agent: %v", err)
+	}
+	if strings.Contains(string(data), "Agent: Priority") || strings.Contains(string(data), "priority.md") {
+		t.Errorf("expected raw content and file path to be left out, got %s", data)
+	}
+}
+
+func TestLoadAgentFromFile_ParsesSchedules(t *testing.T) {
+	content := "---\nname: Reporter\ntriggers:\n  - schedule: \"0 9 * * 1\"\n  - schedule: \"@daily\"\n  - schedule: \"0 25 * * *\"\n---\n"
+	agent, err := loadAgentFromFile(writeAgentFile(t, content), "custom", LoadOptions{})
+	if err != nil {
+		t.Fatalf("loadAgentFromFile() error = %v", err)
+	}
+
+	if len(agent.ScheduleErrors) != 1 || !strings.Contains(agent.ScheduleErrors[0], `"0 25 * * *"`) {
+		t.Errorf("expected only the out-of-range hour to be reported, got %v", agent.ScheduleErrors)
+	}
+	if _, ok := agent.Summary()["schedule_errors"]; !ok {
+		t.Error("expected the summary to include schedule_errors")
+	}
+
+	schedule, err := agent.ParsedSchedule("0 9 * * 1")
+	if err != nil {
+		t.Fatalf("ParsedSchedule() error = %v", err)
+	}
+	if schedule != agent.schedules["0 9 * * 1"] {
+		t.Error("expected the schedule parsed at load time to be reused")
+	}
+	monday := time.Date(2024, 5, 6, 8, 0, 0, 0, time.UTC)
+	if next := schedule.Next(monday); !next.Equal(monday.Add(time.Hour)) {
+		t.Errorf("Next() = %v", next)
+	}
+	if _, err := agent.ParsedSchedule("0 25 * * *"); err == nil {
+		t.Error("expected the invalid schedule to fail to parse")
+	}
+}
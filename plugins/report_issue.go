@@ -0,0 +1,90 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kaskol10/github-project-agent/github"
+)
+
+// Report modes for agents that publish report issues
+const (
+	ReportModeCreate = "create" // Open a new report issue on every run
+	ReportModeUpdate = "update" // Rewrite the latest open report issue, creating one only if none is open
+)
+
+// reportMode returns the agent's report mode, falling back to the executor
+// default
+func (e *PluginExecutor) reportMode(pluginAgent *PluginAgent) string {
+	mode := configString(pluginAgent, "report_mode", e.options.ReportMode)
+	if strings.EqualFold(mode, ReportModeUpdate) {
+		return ReportModeUpdate
+	}
+	return ReportModeCreate
+}
+
+// How publishReport published a report
+const (
+	reportCreated  = "created"
+	reportUpdated  = "updated"
+	reportExisting = "existing" // An open issue with the same title and labels already existed
+)
+
+// publishReport creates a report issue with labels, or in update mode
+// rewrites the title and body of the latest open issue carrying all of them.
+// An open report with the same title is never duplicated. It returns the
+// issue and how it was published.
+func (e *PluginExecutor) publishReport(ctx context.Context, pluginAgent *PluginAgent, reportType, owner, repo, title, body string, labels []string) (*github.Issue, string, error) {
+	if e.reportMode(pluginAgent) == ReportModeUpdate {
+		openIssues, err := e.githubClient.ListIssues(ctx, "open")
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list issues: %w", err)
+		}
+		issue, created, err := github.UpdateOrCreateIssue(ctx, e.githubClient, openIssues, owner, repo, title, body, labels,
+			e.reportAssignee(pluginAgent, reportType), true)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to publish report issue: %w", err)
+		}
+		if created {
+			return issue, reportCreated, nil
+		}
+		return issue, reportUpdated, nil
+	}
+
+	issue, created, err := github.CreateAssignedIssueIfNotExists(ctx, e.githubClient, owner, repo, title, body, labels,
+		e.reportAssignee(pluginAgent, reportType))
+	if err != nil {
+		return nil, "", err
+	}
+	if !created {
+		return issue, reportExisting, nil
+	}
+	return issue, reportCreated, nil
+}
+
+// addReportIssue records the published report issue in result: the
+// created_issue_* extras for a new issue, updated_issue_* for an updated one
+// and existing_issue_* when an identical report was already open
+func addReportIssue(result *Result, report string, issue *github.Issue, published string) {
+	switch published {
+	case reportUpdated:
+		result.affect(issue.Number)
+		result.Extra["issue_updated"] = true
+		result.Extra["updated_issue_number"] = issue.Number
+		result.Extra["updated_issue_url"] = issue.URL
+		result.Message = fmt.Sprintf("%s generated and issue #%d updated", report, issue.Number)
+		return
+	case reportExisting:
+		result.Extra["issue_exists"] = true
+		result.Extra["existing_issue_number"] = issue.Number
+		result.Extra["existing_issue_url"] = issue.URL
+		result.Message = fmt.Sprintf("%s generated; issue #%d with the same title is already open", report, issue.Number)
+		return
+	}
+	result.affect(issue.Number)
+	result.Extra["issue_created"] = true
+	result.Extra["created_issue_number"] = issue.Number
+	result.Extra["created_issue_url"] = issue.URL
+	result.Message = fmt.Sprintf("%s generated and issue #%d created", report, issue.Number)
+}
@@ -0,0 +1,106 @@
+package plugins
+
+import "math"
+
+// Result statuses shared by all agents
+const (
+	StatusCompleted = "completed" // The agent ran; IssuesAffected lists what it touched
+	StatusSkipped   = "skipped"   // There was nothing for the agent to do
+	StatusAttention = "attention" // The agent found something that needs a person
+)
+
+// Result is the outcome of running a plugin agent. The standard fields mean
+// the same for every agent; Extra holds what is specific to one, such as a
+// generated summary.
+type Result struct {
+	Agent          string                 `json:"agent"`
+	Status         string                 `json:"status"`
+	Message        string                 `json:"message,omitempty"`
+	IssuesAffected []int                  `json:"issues_affected,omitempty"`
+	Metrics        map[string]float64     `json:"metrics,omitempty"`
+	Errors         []string               `json:"errors,omitempty"`
+	Extra          map[string]interface{} `json:"extra,omitempty"`
+
+	// legacy holds keys only the map form carries, as agents returned them
+	// before Result existed, and legacyStatus the status they reported
+	legacy       map[string]interface{}
+	legacyStatus string
+}
+
+// newResult returns a completed result for pluginAgent with empty metrics
+// and extras
+func newResult(pluginAgent *PluginAgent) *Result {
+	return &Result{
+		Agent:   pluginAgent.Name,
+		Status:  StatusCompleted,
+		Metrics: map[string]float64{},
+		Extra:   map[string]interface{}{},
+	}
+}
+
+// affect records issue numbers the agent acted on, once each
+func (r *Result) affect(numbers ...int) {
+	for _, number := range numbers {
+		seen := false
+		for _, existing := range r.IssuesAffected {
+			if existing == number {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			r.IssuesAffected = append(r.IssuesAffected, number)
+		}
+	}
+}
+
+// setLegacy records a key for the map form only
+func (r *Result) setLegacy(key string, value interface{}) {
+	if r.legacy == nil {
+		r.legacy = map[string]interface{}{}
+	}
+	r.legacy[key] = value
+}
+
+// Map returns the result as a flat map, the form agents returned before
+// Result existed: the metrics and extras at the top level next to agent,
+// status, message, issues_affected and errors, plus the keys and status
+// only that form had. Whole-number metrics are ints, as the old counts
+// were; agents that nested their metrics keep them under "metrics". A nil
+// result has no map.
+func (r *Result) Map() map[string]interface{} {
+	if r == nil {
+		return nil
+	}
+	m := make(map[string]interface{}, len(r.Extra)+len(r.Metrics)+len(r.legacy)+5)
+	if _, nested := r.legacy["metrics"]; !nested {
+		for key, value := range r.Metrics {
+			if value == math.Trunc(value) {
+				m[key] = int(value)
+			} else {
+				m[key] = value
+			}
+		}
+	}
+	for key, value := range r.Extra {
+		m[key] = value
+	}
+	m["agent"] = r.Agent
+	m["status"] = r.Status
+	if r.legacyStatus != "" {
+		m["status"] = r.legacyStatus
+	}
+	if r.Message != "" {
+		m["message"] = r.Message
+	}
+	if len(r.IssuesAffected) > 0 {
+		m["issues_affected"] = r.IssuesAffected
+	}
+	if len(r.Errors) > 0 {
+		m["errors"] = r.Errors
+	}
+	for key, value := range r.legacy {
+		m[key] = value
+	}
+	return m
+}
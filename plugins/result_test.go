@@ -0,0 +1,269 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kaskol10/github-project-agent/github"
+	"github.com/kaskol10/github-project-agent/github/githubtest"
+	"github.com/kaskol10/github-project-agent/llm"
+)
+
+func TestResult_Map(t *testing.T) {
+	result := newResult(&PluginAgent{Name: "Stale Task Monitor"})
+	result.Status = StatusAttention
+	result.Message = "Checked 3 issues, found 1 stale"
+	result.affect(4, 4)
+	result.Metrics["total_checked"] = 3
+	result.Metrics["velocity"] = 0.5
+	result.Extra["stale_issues"] = []int{4}
+	result.Extra["status"] = "shadowed"
+	result.legacyStatus = "monitored"
+	result.setLegacy("stale_threshold", "7 days")
+
+	want := map[string]interface{}{
+		"agent":           "Stale Task Monitor",
+		"status":          "monitored",
+		"message":         "Checked 3 issues, found 1 stale",
+		"issues_affected": []int{4},
+		"total_checked":   3,
+		"velocity":        0.5,
+		"stale_issues":    []int{4},
+		"stale_threshold": "7 days",
+	}
+	if got := result.Map(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Map() = %v, want %v", got, want)
+	}
+	if (*Result)(nil).Map() != nil {
+		t.Error("expected no map for a nil result")
+	}
+
+	data, err := json.Marshal(newResult(&PluginAgent{Name: "Roaster"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"agent":"Roaster","status":"completed"}` {
+		t.Errorf("unexpected JSON %s", data)
+	}
+}
+
+// mapScenario runs one agent against a fake client for TestResult_MapGolden
+type mapScenario struct {
+	name   string
+	issues []*github.Issue
+	prs    []*github.PullRequest
+	errors map[string]error
+	llmErr bool // The LLM fails every request
+	run    func(ctx context.Context, e *PluginExecutor) (*Result, error)
+}
+
+// TestResult_MapGolden pins Map to the maps the executors returned before
+// Result existed. testdata/golden/result_map.golden.json was captured from
+// that code, with the Go type of every value; it must not be regenerated
+// from Map itself. Map may add keys, but every old key keeps its type and value.
+func TestResult_MapGolden(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "golden", "result_map.golden.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var golden map[string]map[string]goldenValue
+	if err := json.Unmarshal(data, &golden); err != nil {
+		t.Fatal(err)
+	}
+
+	fresh := time.Now().Add(-time.Hour)
+	stale := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	body := "## Description\nCheckout fails when the cart holds more than ten items on Safari.\n\n## Acceptance Criteria\n- Works"
+	scenarios := []mapScenario{
+		{
+			name: "monitor",
+			issues: []*github.Issue{
+				{Number: 1, Title: "Stale", Assignee: "octocat", State: "open", UpdatedAt: stale, URL: "https://github.com/o/r/issues/1"},
+				{Number: 2, Title: "Fresh", Assignee: "octocat", State: "open", UpdatedAt: fresh, URL: "https://github.com/o/r/issues/2"},
+				{Number: 3, Title: "Unassigned", State: "open", UpdatedAt: stale, URL: "https://github.com/o/r/issues/3"},
+			},
+			run: func(ctx context.Context, e *PluginExecutor) (*Result, error) {
+				return e.Execute(ctx, &PluginAgent{Name: "Stale Task Monitor"}, map[string]interface{}{})
+			},
+		},
+		{
+			name: "monitor_failed_comment",
+			issues: []*github.Issue{
+				{Number: 1, Title: "Stale", Assignee: "octocat", State: "open", UpdatedAt: stale, URL: "https://github.com/o/r/issues/1"},
+			},
+			errors: map[string]error{"AddComment": fmt.Errorf("forbidden")},
+			run: func(ctx context.Context, e *PluginExecutor) (*Result, error) {
+				return e.Execute(ctx, &PluginAgent{Name: "Stale Task Monitor"}, map[string]interface{}{})
+			},
+		},
+		{
+			name: "monitor_issue",
+			issues: []*github.Issue{
+				{Number: 2, Title: "Fresh", Assignee: "octocat", State: "open", UpdatedAt: fresh, URL: "https://github.com/o/r/issues/2"},
+			},
+			run: func(ctx context.Context, e *PluginExecutor) (*Result, error) {
+				return e.Execute(ctx, &PluginAgent{Name: "Stale Task Monitor"}, map[string]interface{}{"issue_number": 2})
+			},
+		},
+		{
+			name: "validator_already_validated",
+			issues: []*github.Issue{
+				{Number: 4, Title: "Done", Labels: []string{"agent-validator"}, State: "open", URL: "https://github.com/o/r/issues/4"},
+			},
+			run: func(ctx context.Context, e *PluginExecutor) (*Result, error) {
+				return e.Execute(ctx, &PluginAgent{Name: "Task Validator"}, map[string]interface{}{"issue_number": 4})
+			},
+		},
+		{
+			name: "validator",
+			issues: []*github.Issue{
+				{Number: 4, Title: "Checkout fails", Body: body, Labels: []string{"priority:high"}, State: "open", URL: "https://github.com/o/r/issues/4"},
+				{Number: 5, Title: "Broken", Body: "todo", State: "open", URL: "https://github.com/o/r/issues/5"},
+			},
+			llmErr: true,
+			run: func(ctx context.Context, e *PluginExecutor) (*Result, error) {
+				return e.Execute(ctx, &PluginAgent{Name: "Task Validator"}, map[string]interface{}{"issue_number": 4})
+			},
+		},
+		{
+			name:   "roaster",
+			issues: []*github.Issue{{Number: 1, Title: "A", State: "open"}, {Number: 2, Title: "B", State: "closed"}},
+			run: func(ctx context.Context, e *PluginExecutor) (*Result, error) {
+				return e.Execute(ctx, &PluginAgent{Name: "Product Roaster"}, map[string]interface{}{})
+			},
+		},
+		{
+			name: "code_review",
+			prs:  []*github.PullRequest{{Number: 7, Title: "Change", Labels: []string{"needs-review"}, URL: "https://github.com/o/r/pull/7"}},
+			run: func(ctx context.Context, e *PluginExecutor) (*Result, error) {
+				return e.Execute(ctx, &PluginAgent{Name: "Code Review Enforcer"}, map[string]interface{}{})
+			},
+		},
+		{
+			name: "deployment",
+			run: func(ctx context.Context, e *PluginExecutor) (*Result, error) {
+				return e.Execute(ctx, &PluginAgent{Name: "Deployment Checker", Config: map[string]interface{}{"repo": "o/r"}}, map[string]interface{}{})
+			},
+		},
+		{
+			name: "deduplicator",
+			issues: []*github.Issue{
+				{Number: 1, Title: "Checkout fails on Safari", Body: "The checkout button does nothing on Safari", State: "open", URL: "https://github.com/o/r/issues/1"},
+				{Number: 2, Title: "Checkout fails on Safari", Body: "The checkout button does nothing on Safari", State: "open", URL: "https://github.com/o/r/issues/2"},
+			},
+			run: func(ctx context.Context, e *PluginExecutor) (*Result, error) {
+				return e.Execute(ctx, &PluginAgent{Name: "Issue Deduplicator", Config: map[string]interface{}{"llm_confirm": false}}, map[string]interface{}{})
+			},
+		},
+		{
+			name:   "priority_calculator",
+			issues: []*github.Issue{{Number: 7, Title: "Checkout fails", State: "open", URL: "https://github.com/o/r/issues/7"}},
+			run: func(ctx context.Context, e *PluginExecutor) (*Result, error) {
+				return e.executePriorityCalculator(ctx, &PluginAgent{Name: "Priority Calculator"}, map[string]interface{}{"issue_number": 7})
+			},
+		},
+		{
+			name:   "dependency_tracker",
+			issues: []*github.Issue{{Number: 7, Title: "Checkout fails", Body: "Depends on #8", State: "open", URL: "https://github.com/o/r/issues/7"}, {Number: 8, Title: "Payments", State: "open", URL: "https://github.com/o/r/issues/8"}},
+			run: func(ctx context.Context, e *PluginExecutor) (*Result, error) {
+				return e.executeDependencyTracker(ctx, &PluginAgent{Name: "Dependency Tracker"}, map[string]interface{}{"issue_number": 7})
+			},
+		},
+		{
+			name:   "executive_summary",
+			issues: []*github.Issue{{Number: 1, Title: "A", State: "open", Labels: []string{"blocked"}, URL: "https://github.com/o/r/issues/1"}},
+			run: func(ctx context.Context, e *PluginExecutor) (*Result, error) {
+				return e.Execute(ctx, &PluginAgent{Name: "Executive Summary Generator"}, map[string]interface{}{})
+			},
+		},
+		{
+			name:   "progress_reporter",
+			issues: []*github.Issue{{Number: 1, Title: "A", State: "open", URL: "https://github.com/o/r/issues/1"}},
+			run: func(ctx context.Context, e *PluginExecutor) (*Result, error) {
+				return e.Execute(ctx, &PluginAgent{Name: "Progress Reporter"}, map[string]interface{}{})
+			},
+		},
+		{
+			name:   "generic_label",
+			issues: []*github.Issue{{Number: 7, Title: "Checkout fails", State: "open", URL: "https://github.com/o/r/issues/7"}},
+			run: func(ctx context.Context, e *PluginExecutor) (*Result, error) {
+				return e.Execute(ctx, &PluginAgent{Name: "Triage", Type: "triage", Actions: []string{"Add label `triaged`"}}, map[string]interface{}{"issue_number": 7})
+			},
+		},
+		{
+			name:   "generic_failed_comment",
+			issues: []*github.Issue{{Number: 7, Title: "Checkout fails", State: "open", URL: "https://github.com/o/r/issues/7"}},
+			errors: map[string]error{"AddComment": fmt.Errorf("forbidden")},
+			run: func(ctx context.Context, e *PluginExecutor) (*Result, error) {
+				return e.Execute(ctx, &PluginAgent{Name: "Notifier", Actions: []string{"Add comment"}}, map[string]interface{}{"issue_number": 7})
+			},
+		},
+		{
+			name:   "generic_too_short",
+			issues: []*github.Issue{{Number: 7, Title: "Checkout fails", Body: "short", State: "open", URL: "https://github.com/o/r/issues/7"}},
+			run: func(ctx context.Context, e *PluginExecutor) (*Result, error) {
+				return e.Execute(ctx, &PluginAgent{Name: "Summarizer", Actions: []string{"Check if task body is long enough"}}, map[string]interface{}{"issue_number": 7})
+			},
+		},
+	}
+
+	var llmErr bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if llmErr {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"{\"priority\":\"P1\",\"rationale\":\"Blocks checkout.\"}"}}]}`))
+	}))
+	defer server.Close()
+
+	for _, sc := range scenarios {
+		t.Run(sc.name, func(t *testing.T) {
+			client := githubtest.NewFakeClient(sc.issues...)
+			client.PullRequests = sc.prs
+			llmErr = sc.llmErr
+			for method, err := range sc.errors {
+				client.Errors[method] = err
+			}
+			executor := NewPluginExecutor(llm.NewClient(server.URL, "m", "", time.Second), client, nil)
+			result, err := sc.run(context.Background(), executor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := result.Map()
+			for key, want := range golden[sc.name] {
+				value, ok := got[key]
+				if !ok {
+					t.Errorf("missing key %q", key)
+					continue
+				}
+				if typ := fmt.Sprintf("%T", value); typ != want.Type {
+					t.Errorf("%s has type %s, want %s", key, typ, want.Type)
+				}
+				gotJSON, _ := json.Marshal(value)
+				var wantJSON bytes.Buffer
+				if err := json.Compact(&wantJSON, want.Value); err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(gotJSON, wantJSON.Bytes()) {
+					t.Errorf("%s = %s, want %s", key, gotJSON, wantJSON.Bytes())
+				}
+			}
+		})
+	}
+}
+
+// goldenValue is one captured map value and its Go type
+type goldenValue struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
@@ -0,0 +1,164 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Runner executes a plugin agent. *PluginExecutor implements it.
+type Runner interface {
+	Execute(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (*Result, error)
+}
+
+// Scheduler runs plugin agents on the cron schedules declared in their triggers.
+// Schedules are evaluated in UTC.
+type Scheduler struct {
+	runner  Runner
+	cron    *cron.Cron
+	jobs    int
+	options SchedulerOptions
+}
+
+// SchedulerOptions configures optional scheduler behavior
+type SchedulerOptions struct {
+	// RunTimeout cancels a scheduled run that takes longer. Zero means no
+	// deadline beyond the scheduler's context.
+	RunTimeout time.Duration
+}
+
+// NewScheduler creates a scheduler with a job for every schedule of the given
+// agents. Invalid schedules are reported and skipped.
+func NewScheduler(ctx context.Context, runner Runner, agents []*PluginAgent) *Scheduler {
+	return NewSchedulerWithOptions(ctx, runner, agents, SchedulerOptions{})
+}
+
+// NewSchedulerWithOptions creates a scheduler with optional behavior configured
+func NewSchedulerWithOptions(ctx context.Context, runner Runner, agents []*PluginAgent, options SchedulerOptions) *Scheduler {
+	s := &Scheduler{
+		runner:  runner,
+		cron:    cron.New(cron.WithLocation(time.UTC)),
+		options: options,
+	}
+
+	for _, pluginAgent := range agents {
+		// One guard per agent so overlapping runs are skipped even when the
+		// agent has several schedules
+		guard := &sync.Mutex{}
+		for _, expr := range pluginAgent.GetSchedules() {
+			schedule, err := pluginAgent.ParsedSchedule(expr)
+			if err != nil {
+				slog.Warn("skipping invalid schedule", "agent", pluginAgent.Name, "schedule", expr, "error", err)
+				continue
+			}
+			s.cron.Schedule(schedule, cron.FuncJob(s.job(ctx, pluginAgent, expr, guard)))
+			s.jobs++
+			slog.Info("scheduled agent", "agent", pluginAgent.Name, "schedule", expr)
+		}
+	}
+
+	return s
+}
+
+// Jobs returns the number of scheduled jobs
+func (s *Scheduler) Jobs() int {
+	return s.jobs
+}
+
+// Start begins running scheduled agents in the background
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop stops scheduling new runs and returns a context that is done once
+// running agents have finished
+func (s *Scheduler) Stop() context.Context {
+	return s.cron.Stop()
+}
+
+// job returns the cron function running pluginAgent, skipping the run when the
+// previous one is still in progress
+func (s *Scheduler) job(ctx context.Context, pluginAgent *PluginAgent, schedule string, guard *sync.Mutex) func() {
+	return func() {
+		if !guard.TryLock() {
+			slog.Warn("skipping scheduled run: previous run still in progress", "agent", pluginAgent.Name, "schedule", schedule)
+			return
+		}
+		defer guard.Unlock()
+
+		s.run(ctx, pluginAgent, schedule)
+	}
+}
+
+// run executes pluginAgent once and logs the outcome
+func (s *Scheduler) run(ctx context.Context, pluginAgent *PluginAgent, schedule string) {
+	slog.Info("running scheduled agent", "agent", pluginAgent.Name, "schedule", schedule)
+	start := time.Now()
+
+	if s.options.RunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.options.RunTimeout)
+		defer cancel()
+	}
+
+	result, err := s.runner.Execute(ctx, pluginAgent, map[string]interface{}{
+		"trigger":  "schedule",
+		"schedule": schedule,
+	})
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		slog.Error("scheduled agent failed", "agent", pluginAgent.Name, "elapsed", elapsed, "error", err)
+		return
+	}
+
+	slog.Info("scheduled agent finished", "agent", pluginAgent.Name, "elapsed", elapsed, "result", summarizeResult(result.Map()))
+}
+
+// maxSummaryValue bounds how much of a string result value is logged
+const maxSummaryValue = 80
+
+// summarizeResult renders an execution result as a single log line. Lists
+// and maps are reported by size.
+func summarizeResult(result map[string]interface{}) string {
+	var keys []string
+	for key := range result {
+		if key == "agent" || key == "type" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return "no result"
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		var value string
+		switch v := result[key].(type) {
+		case string:
+			value = strings.Join(strings.Fields(v), " ")
+			if len(value) > maxSummaryValue {
+				value = value[:maxSummaryValue] + "..."
+			}
+		default:
+			switch rv := reflect.ValueOf(v); rv.Kind() {
+			case reflect.Slice, reflect.Array:
+				value = fmt.Sprintf("%d items", rv.Len())
+			case reflect.Map:
+				value = fmt.Sprintf("%d entries", rv.Len())
+			default:
+				value = fmt.Sprintf("%v", v)
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%s=%s", key, value))
+	}
+	return strings.Join(parts, ", ")
+}
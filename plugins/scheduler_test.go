@@ -0,0 +1,131 @@
+package plugins
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockingRunner counts executions and blocks each one until release is closed
+type blockingRunner struct {
+	calls   int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (r *blockingRunner) Execute(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (*Result, error) {
+	atomic.AddInt32(&r.calls, 1)
+	r.started <- struct{}{}
+	<-r.release
+	return &Result{Agent: pluginAgent.Name, Status: StatusCompleted, Message: "done"}, nil
+}
+
+func TestNewScheduler_SkipsInvalidSchedules(t *testing.T) {
+	agents := []*PluginAgent{
+		{Name: "Reporter", Triggers: []Trigger{{Schedule: "0 9 * * 1"}, {Schedule: "0 17 * * 5"}}},
+		{Name: "Broken", Triggers: []Trigger{{Schedule: "not a schedule"}}},
+		{Name: "Manual", Triggers: []Trigger{{Manual: true}}},
+	}
+
+	scheduler := NewScheduler(context.Background(), &blockingRunner{}, agents)
+	if scheduler.Jobs() != 2 {
+		t.Errorf("expected 2 jobs, got %d", scheduler.Jobs())
+	}
+}
+
+func TestScheduler_SkipsOverlappingRuns(t *testing.T) {
+	runner := &blockingRunner{started: make(chan struct{}), release: make(chan struct{})}
+	scheduler := NewScheduler(context.Background(), runner, nil)
+	pluginAgent := &PluginAgent{Name: "Reporter"}
+	guard := &sync.Mutex{}
+
+	first := scheduler.job(context.Background(), pluginAgent, "* * * * *", guard)
+	second := scheduler.job(context.Background(), pluginAgent, "0 9 * * *", guard)
+
+	done := make(chan struct{})
+	go func() {
+		first()
+		close(done)
+	}()
+	<-runner.started
+
+	// The agent is still running, so this run is skipped
+	second()
+
+	close(runner.release)
+	<-done
+	if calls := atomic.LoadInt32(&runner.calls); calls != 1 {
+		t.Errorf("expected 1 execution, got %d", calls)
+	}
+
+	// Once finished, the agent runs again
+	go func() { <-runner.started }()
+	second()
+	if calls := atomic.LoadInt32(&runner.calls); calls != 2 {
+		t.Errorf("expected 2 executions, got %d", calls)
+	}
+}
+
+func TestSummarizeResult(t *testing.T) {
+	got := summarizeResult(map[string]interface{}{
+		"agent":         "Reporter",
+		"type":          "custom",
+		"issue_url":     "https://github.com/o/r/issues/1",
+		"issues_found":  3,
+		"blocked":       []string{"#1", "#2"},
+		"summary":       strings.Repeat("word ", 40),
+		"priority_dist": map[string]int{"P1": 2},
+	})
+	want := "blocked=2 items, issue_url=https://github.com/o/r/issues/1, issues_found=3, priority_dist=1 entries, summary=" +
+		strings.Repeat("word ", 16)[:maxSummaryValue] + "..."
+	if got != want {
+		t.Errorf("summarizeResult() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := summarizeResult(map[string]interface{}{"agent": "Reporter"}); got != "no result" {
+		t.Errorf("expected no result, got %q", got)
+	}
+}
+
+func TestParseTriggers_Schedules(t *testing.T) {
+	lines := strings.Split(`## Trigger
+
+- schedule: "0 9 * * 1"  # Every Monday at 9 AM UTC
+- schedule: "0 17 * * 5"  # Every Friday at 5 PM UTC
+- manual: true
+`, "\n")
+
+	agent := &PluginAgent{Triggers: parseTriggers(lines, 1)}
+	schedules := agent.GetSchedules()
+	if len(schedules) != 2 || schedules[0] != "0 9 * * 1" || schedules[1] != "0 17 * * 5" {
+		t.Errorf("unexpected schedules: %q", schedules)
+	}
+}
+
+// deadlineRunner reports whether its context had a deadline
+type deadlineRunner struct {
+	hadDeadline bool
+}
+
+func (r *deadlineRunner) Execute(ctx context.Context, pluginAgent *PluginAgent, params map[string]interface{}) (*Result, error) {
+	_, r.hadDeadline = ctx.Deadline()
+	return nil, nil
+}
+
+func TestScheduler_RunTimeout(t *testing.T) {
+	runner := &deadlineRunner{}
+	scheduler := NewSchedulerWithOptions(context.Background(), runner, nil, SchedulerOptions{RunTimeout: time.Minute})
+	scheduler.run(context.Background(), &PluginAgent{Name: "Reporter"}, "* * * * *")
+	if !runner.hadDeadline {
+		t.Error("expected scheduled runs to get a deadline")
+	}
+
+	scheduler = NewScheduler(context.Background(), runner, nil)
+	scheduler.run(context.Background(), &PluginAgent{Name: "Reporter"}, "* * * * *")
+	if runner.hadDeadline {
+		t.Error("expected no deadline without a run timeout")
+	}
+}
@@ -0,0 +1,133 @@
+package plugins
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kaskol10/github-project-agent/github"
+)
+
+// similarPair is two issues whose text is alike, Older being the canonical one
+type similarPair struct {
+	Older      *github.Issue
+	Newer      *github.Issue
+	Similarity float64
+}
+
+// stopWords are common words that say nothing about what an issue is about
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true,
+	"you": true, "all": true, "can": true, "has": true, "have": true, "was": true,
+	"this": true, "that": true, "with": true, "from": true, "should": true,
+	"would": true, "could": true, "when": true, "what": true, "which": true,
+	"there": true, "their": true, "will": true, "into": true, "than": true,
+	"then": true, "them": true, "they": true, "its": true, "our": true,
+	"also": true, "been": true, "being": true, "does": true, "some": true,
+	"such": true, "only": true, "other": true, "more": true, "any": true,
+	"need": true, "needs": true, "like": true, "want": true, "use": true,
+}
+
+// tokenize splits text into lowercase terms, dropping stop words and
+// terms shorter than three characters
+func tokenize(text string) []string {
+	var terms []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) < 3 || stopWords[word] {
+			continue
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// issueTerms returns the terms of an issue. The title is counted twice since
+// it is the best summary of what the issue is about.
+func issueTerms(issue *github.Issue) []string {
+	title := tokenize(issue.Title)
+	return append(append(title, title...), tokenize(issue.Body)...)
+}
+
+// tfidfVectors builds a unit-length TF-IDF vector for each document
+func tfidfVectors(documents [][]string) []map[string]float64 {
+	documentFrequency := make(map[string]int)
+	for _, terms := range documents {
+		seen := make(map[string]bool)
+		for _, term := range terms {
+			if !seen[term] {
+				seen[term] = true
+				documentFrequency[term]++
+			}
+		}
+	}
+
+	vectors := make([]map[string]float64, len(documents))
+	for i, terms := range documents {
+		vector := make(map[string]float64)
+		for _, term := range terms {
+			vector[term]++
+		}
+		var norm float64
+		for term, count := range vector {
+			// Smoothed IDF so terms in every document still count a little
+			idf := math.Log(float64(1+len(documents))/float64(1+documentFrequency[term])) + 1
+			vector[term] = count * idf
+			norm += vector[term] * vector[term]
+		}
+		norm = math.Sqrt(norm)
+		for term := range vector {
+			vector[term] /= norm
+		}
+		vectors[i] = vector
+	}
+	return vectors
+}
+
+// cosine returns the cosine similarity of two unit-length vectors
+func cosine(a, b map[string]float64) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	var dot float64
+	for term, weight := range a {
+		dot += weight * b[term]
+	}
+	return dot
+}
+
+// similarIssues returns the pairs of issues with a similarity of at least
+// threshold, most similar first. When only is non-nil, just the pairs
+// containing that issue are returned.
+func similarIssues(issues []*github.Issue, only *github.Issue, threshold float64) []similarPair {
+	documents := make([][]string, len(issues))
+	for i, issue := range issues {
+		documents[i] = issueTerms(issue)
+	}
+	vectors := tfidfVectors(documents)
+
+	var pairs []similarPair
+	for i := range issues {
+		for j := i + 1; j < len(issues); j++ {
+			if only != nil && issues[i] != only && issues[j] != only {
+				continue
+			}
+			similarity := cosine(vectors[i], vectors[j])
+			if similarity < threshold {
+				continue
+			}
+			older, newer := issues[i], issues[j]
+			if newer.CreatedAt.Before(older.CreatedAt) || (newer.CreatedAt.Equal(older.CreatedAt) && newer.Number < older.Number) {
+				older, newer = newer, older
+			}
+			pairs = append(pairs, similarPair{Older: older, Newer: newer, Similarity: similarity})
+		}
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].Similarity > pairs[j].Similarity
+	})
+	return pairs
+}
@@ -0,0 +1,27 @@
+package plugins
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kaskol10/github-project-agent/github"
+)
+
+func TestSimilarIssues(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := &github.Issue{Number: 1, Title: "Login fails with SSO", Body: "SSO login returns a 500 error", CreatedAt: base}
+	b := &github.Issue{Number: 2, Title: "SSO login fails", Body: "Login through SSO returns error 500", CreatedAt: base.Add(time.Hour)}
+	c := &github.Issue{Number: 3, Title: "Add pagination to the API", Body: "List endpoints return everything at once", CreatedAt: base}
+
+	pairs := similarIssues([]*github.Issue{a, b, c}, nil, 0.5)
+	if len(pairs) != 1 || pairs[0].Older != a || pairs[0].Newer != b {
+		t.Fatalf("expected only #1/#2 to be similar, got %+v", pairs)
+	}
+	if pairs := similarIssues([]*github.Issue{a, b, c}, c, 0.5); len(pairs) != 0 {
+		t.Errorf("expected no pairs for #3, got %+v", pairs)
+	}
+	if sim := cosine(tfidfVectors([][]string{tokenize("same words here")})[0], tfidfVectors([][]string{tokenize("same words here")})[0]); math.Abs(sim-1) > 1e-9 {
+		t.Errorf("expected identical documents to have similarity 1, got %v", sim)
+	}
+}
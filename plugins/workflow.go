@@ -0,0 +1,125 @@
+package plugins
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Workflow is an ordered list of agent steps run in sequence
+type Workflow struct {
+	Name     string         `yaml:"name"`
+	Purpose  string         `yaml:"purpose"`
+	Steps    []WorkflowStep `yaml:"steps"`
+	FilePath string         `yaml:"-"`
+}
+
+// WorkflowStep runs one agent, with optional parameters merged over the
+// workflow parameters
+type WorkflowStep struct {
+	Agent  string                 `yaml:"agent"`
+	Params map[string]interface{} `yaml:"params"`
+}
+
+// LoadWorkflows loads all workflows (.md, .yaml or .yml files) from the
+// specified directory. A missing directory yields no workflows.
+func LoadWorkflows(dirPath string) ([]*Workflow, error) {
+	var workflows []*Workflow
+
+	entries, err := os.ReadDir(dirPath)
+	if os.IsNotExist(err) {
+		return workflows, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dirPath, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		filePath := filepath.Join(dirPath, entry.Name())
+		var workflow *Workflow
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".md":
+			workflow, err = loadWorkflowFromMarkdown(filePath)
+		case ".yaml", ".yml":
+			workflow, err = loadWorkflowFromYAML(filePath)
+		default:
+			continue
+		}
+		if err == nil {
+			err = workflow.validate()
+		}
+		if err != nil {
+			// Log error but continue loading other workflows
+			slog.Warn("failed to load workflow", "path", filePath, "error", err)
+			continue
+		}
+
+		workflow.FilePath = filePath
+		workflows = append(workflows, workflow)
+	}
+
+	return workflows, nil
+}
+
+// loadWorkflowFromYAML loads a workflow from a YAML file
+func loadWorkflowFromYAML(filePath string) (*Workflow, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	workflow := &Workflow{}
+	if err := yaml.Unmarshal(content, workflow); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow: %w", err)
+	}
+	return workflow, nil
+}
+
+// loadWorkflowFromMarkdown loads a workflow from a markdown file with a
+// "# Workflow: Name" heading and a "## Steps" list of agent names
+func loadWorkflowFromMarkdown(filePath string) (*Workflow, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	workflow := &Workflow{}
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "# Workflow:"):
+			workflow.Name = strings.TrimSpace(strings.TrimPrefix(line, "# Workflow:"))
+		case strings.HasPrefix(line, "**Purpose**:"):
+			workflow.Purpose = strings.TrimSpace(strings.TrimPrefix(line, "**Purpose**:"))
+		case strings.ToLower(strings.TrimSpace(line)) == "## steps" && len(workflow.Steps) == 0:
+			for _, item := range parseListItems(lines, i+1) {
+				workflow.Steps = append(workflow.Steps, WorkflowStep{Agent: strings.Trim(item, "`*")})
+			}
+		}
+	}
+	return workflow, nil
+}
+
+// validate checks the workflow has a name and only named steps
+func (w *Workflow) validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("workflow has no name")
+	}
+	if len(w.Steps) == 0 {
+		return fmt.Errorf("workflow %s has no steps", w.Name)
+	}
+	for i, step := range w.Steps {
+		if strings.TrimSpace(step.Agent) == "" {
+			return fmt.Errorf("workflow %s step %d has no agent", w.Name, i+1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Package pool runs independent work items on bounded worker pools
+package pool
+
+import "sync"
+
+// DefaultLimit is the number of concurrent workers used when none is configured
+const DefaultLimit = 4
+
+// ForEach calls fn for every index in [0, n) and returns once all calls have
+// finished. Items are grouped by key (for example the repository an issue
+// belongs to) and each group gets its own pool of at most limit workers, so
+// one busy repository can't starve another. A nil key puts every item in a
+// single pool; a limit below 1 processes each group one item at a time.
+//
+// Calls may run concurrently, so fn should record its outcome by index and
+// leave aggregation to the caller to keep results in input order.
+func ForEach(n, limit int, key func(i int) string, fn func(i int)) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	var keys []string
+	groups := make(map[string][]int)
+	for i := 0; i < n; i++ {
+		k := ""
+		if key != nil {
+			k = key(i)
+		}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], i)
+	}
+
+	var wg sync.WaitGroup
+	for _, k := range keys {
+		items := make(chan int, len(groups[k]))
+		for _, i := range groups[k] {
+			items <- i
+		}
+		close(items)
+
+		workers := limit
+		if len(groups[k]) < workers {
+			workers = len(groups[k])
+		}
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range items {
+					fn(i)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+}
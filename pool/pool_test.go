@@ -0,0 +1,72 @@
+package pool
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEach(t *testing.T) {
+	const n = 50
+	results := make([]int, n)
+	var running, maxRunning int32
+	var mu sync.Mutex
+	perKey := map[string]int32{}
+	maxPerKey := int32(0)
+
+	key := func(i int) string { return []string{"a/x", "b/y"}[i%2] }
+	ForEach(n, 3, key, func(i int) {
+		current := atomic.AddInt32(&running, 1)
+		mu.Lock()
+		perKey[key(i)]++
+		if perKey[key(i)] > maxPerKey {
+			maxPerKey = perKey[key(i)]
+		}
+		if current > maxRunning {
+			maxRunning = current
+		}
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+		results[i] = i * i
+
+		mu.Lock()
+		perKey[key(i)]--
+		mu.Unlock()
+		atomic.AddInt32(&running, -1)
+	})
+
+	for i, result := range results {
+		if result != i*i {
+			t.Fatalf("item %d: got %d, want %d", i, result, i*i)
+		}
+	}
+	if maxPerKey > 3 {
+		t.Errorf("expected at most 3 workers per key, saw %d", maxPerKey)
+	}
+	if maxRunning < 2 {
+		t.Errorf("expected items to run concurrently, max in flight was %d", maxRunning)
+	}
+}
+
+func TestForEach_Sequential(t *testing.T) {
+	var order []int
+	ForEach(5, 0, nil, func(i int) {
+		order = append(order, i)
+	})
+	for i, got := range order {
+		if got != i {
+			t.Fatalf("expected in-order processing with limit < 1, got %v", order)
+		}
+	}
+	if len(order) != 5 {
+		t.Fatalf("expected 5 items, got %v", order)
+	}
+
+	called := false
+	ForEach(0, 4, nil, func(i int) { called = true })
+	if called {
+		t.Error("expected no calls for zero items")
+	}
+}
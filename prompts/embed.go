@@ -0,0 +1,9 @@
+package prompts
+
+import "embed"
+
+// defaultTemplates are the maintained templates compiled into the binary, so
+// it works without a prompts/ directory on disk
+//
+//go:embed *.md
+var defaultTemplates embed.FS
@@ -0,0 +1,95 @@
+package prompts
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// funcs are available in every prompt template. Arguments are ordered so the
+// value can be piped in, e.g. {{ .Labels | join ", " }}.
+var funcs = template.FuncMap{
+	"join":       join,
+	"upper":      strings.ToUpper,
+	"lower":      strings.ToLower,
+	"default":    defaultValue,
+	"formatDate": formatDate,
+	"truncate":   truncate,
+}
+
+// join joins the elements of a slice with sep. A string is returned as is,
+// so templates work with values the agents already joined.
+func join(sep string, items interface{}) string {
+	switch items := items.(type) {
+	case nil:
+		return ""
+	case string:
+		return items
+	case []string:
+		return strings.Join(items, sep)
+	}
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Sprint(items)
+	}
+	parts := make([]string, v.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return strings.Join(parts, sep)
+}
+
+// defaultValue returns value, or fallback when value is empty (nil, zero,
+// or an empty string, slice or map)
+func defaultValue(fallback, value interface{}) interface{} {
+	if value == nil {
+		return fallback
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		if v.Len() == 0 {
+			return fallback
+		}
+	default:
+		if v.IsZero() {
+			return fallback
+		}
+	}
+	return value
+}
+
+// formatDate formats a time with a Go layout such as "2006-01-02". The zero
+// time formats as an empty string.
+func formatDate(layout string, value interface{}) (string, error) {
+	var t time.Time
+	switch value := value.(type) {
+	case time.Time:
+		t = value
+	case *time.Time:
+		if value != nil {
+			t = *value
+		}
+	case nil:
+	default:
+		return "", fmt.Errorf("formatDate: expected a time, got %T", value)
+	}
+	if t.IsZero() {
+		return "", nil
+	}
+	return t.Format(layout), nil
+}
+
+// truncate shortens s to at most n characters, ending in "..." when cut
+func truncate(n int, s string) string {
+	runes := []rune(s)
+	if n < 0 || len(runes) <= n {
+		return s
+	}
+	if n <= 3 {
+		return string(runes[:n])
+	}
+	return string(runes[:n-3]) + "..."
+}
@@ -0,0 +1,58 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoader_TemplateFuncs(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := `{{ .Labels | join ", " }}|{{ .Joined | join ", " }}|{{ .Title | upper }}|{{ .Title | lower }}|` +
+		`{{ .Assignee | default "unassigned" }}|{{ .Created | formatDate "2006-01-02" }}|{{ .Body | truncate 8 }}`
+	if err := os.WriteFile(filepath.Join(dir, "funcs.md"), []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loader, err := NewLoader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := loader.Render("funcs", map[string]interface{}{
+		"Labels":   []string{"bug", "ui"},
+		"Joined":   "bug, ui",
+		"Title":    "Fix Login",
+		"Assignee": "",
+		"Created":  time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		"Body":     "A very long description",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "bug, ui|bug, ui|FIX LOGIN|fix login|unassigned|2024-05-01|A ver..."
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	if got := join("-", []interface{}{1, "a"}); got != "1-a" {
+		t.Errorf("join() = %q", got)
+	}
+	if got := defaultValue("none", []string{}); got != "none" {
+		t.Errorf("defaultValue(empty slice) = %v", got)
+	}
+	if got := defaultValue("none", 3); got != 3 {
+		t.Errorf("defaultValue(3) = %v", got)
+	}
+	if got, err := formatDate("2006", time.Time{}); got != "" || err != nil {
+		t.Errorf("formatDate(zero) = %q, %v", got, err)
+	}
+	if _, err := formatDate("2006", "yesterday"); err == nil {
+		t.Error("expected formatDate to reject a string")
+	}
+	if got := truncate(10, "short"); got != "short" {
+		t.Errorf("truncate() = %q", got)
+	}
+}
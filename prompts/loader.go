@@ -1,17 +1,30 @@
 package prompts
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
-	"path/filepath"
 	"strings"
+	"sync"
 	"text/template"
 )
 
 // Loader loads and renders prompt templates from multiple locations
 type Loader struct {
+	mu        sync.RWMutex // Guards templates against a concurrent Reload
 	templates map[string]*template.Template
 	basePaths []string // Multiple paths to search for templates
+	options   LoaderOptions
+}
+
+// LoaderOptions configures optional loader behavior
+type LoaderOptions struct {
+	// Strict makes rendering fail when a template references a missing map
+	// key instead of printing "<no value>", so callers fall back to their
+	// hardcoded prompt rather than sending a broken one
+	Strict bool
 }
 
 // NewLoader creates a new prompt loader with a single base path
@@ -19,38 +32,86 @@ func NewLoader(basePath string) (*Loader, error) {
 	return NewMultiPathLoader([]string{basePath})
 }
 
+// NewLoaderWithOptions creates a prompt loader with a single base path and
+// optional behavior configured
+func NewLoaderWithOptions(basePath string, options LoaderOptions) (*Loader, error) {
+	return NewMultiPathLoaderWithOptions([]string{basePath}, options)
+}
+
 // NewMultiPathLoader creates a new prompt loader that searches multiple paths
-// Templates are loaded in order, with later paths overriding earlier ones
+// Templates are loaded in order, with later paths overriding earlier ones.
+// The embedded default templates are loaded first, so files on disk override
+// them and a template missing on disk falls back to its default.
 func NewMultiPathLoader(basePaths []string) (*Loader, error) {
-	loader := &Loader{
-		templates: make(map[string]*template.Template),
-		basePaths: basePaths,
+	return NewMultiPathLoaderWithOptions(basePaths, LoaderOptions{})
+}
+
+// NewMultiPathLoaderWithOptions creates a multi-path loader with optional
+// behavior configured
+func NewMultiPathLoaderWithOptions(basePaths []string, options LoaderOptions) (*Loader, error) {
+	loader := &Loader{basePaths: basePaths, options: options}
+
+	templates, errs := loader.load()
+	for _, err := range errs {
+		// Log error but continue with other paths
+		slog.Warn("failed to load prompts", "error", err)
+	}
+	loader.templates = templates
+
+	return loader, nil
+}
+
+// Reload re-reads the templates from the embedded defaults and all base
+// paths, e.g. on each daemon tick so edited prompts apply without a restart.
+// If any template fails to load, the current templates are kept.
+func (l *Loader) Reload() error {
+	templates, errs := l.load()
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to reload prompts: %w", errors.Join(errs...))
 	}
 
-	// Load all templates from all paths
-	for _, basePath := range basePaths {
+	l.mu.Lock()
+	l.templates = templates
+	l.mu.Unlock()
+	return nil
+}
+
+// load parses the embedded templates and then those in each base path, with
+// later paths overriding earlier ones
+func (l *Loader) load() (map[string]*template.Template, []error) {
+	templates := make(map[string]*template.Template)
+	var errs []error
+
+	if err := l.loadTemplates(templates, defaultTemplates, "embedded"); err != nil {
+		errs = append(errs, err)
+	}
+	for _, basePath := range l.basePaths {
 		if basePath == "" {
 			continue
 		}
-		if err := loader.loadTemplatesFromPath(basePath); err != nil {
-			// Log error but continue with other paths
-			fmt.Printf("Warning: failed to load prompts from %s: %v\n", basePath, err)
+		if err := l.loadTemplatesFromPath(templates, basePath); err != nil {
+			errs = append(errs, err)
 		}
 	}
-
-	return loader, nil
+	return templates, errs
 }
 
 // loadTemplatesFromPath loads all .md files from a specific path
-func (l *Loader) loadTemplatesFromPath(basePath string) error {
+func (l *Loader) loadTemplatesFromPath(templates map[string]*template.Template, basePath string) error {
 	// Check if path exists
 	if _, err := os.Stat(basePath); os.IsNotExist(err) {
 		return nil // Path doesn't exist, skip silently
 	}
 
-	entries, err := os.ReadDir(basePath)
+	return l.loadTemplates(templates, os.DirFS(basePath), basePath)
+}
+
+// loadTemplates loads all .md files from the root of fsys into templates,
+// with source describing fsys in errors
+func (l *Loader) loadTemplates(templates map[string]*template.Template, fsys fs.FS, source string) error {
+	entries, err := fs.ReadDir(fsys, ".")
 	if err != nil {
-		return fmt.Errorf("failed to read prompts directory %s: %w", basePath, err)
+		return fmt.Errorf("failed to read prompts directory %s: %w", source, err)
 	}
 
 	for _, entry := range entries {
@@ -63,23 +124,26 @@ func (l *Loader) loadTemplatesFromPath(basePath string) error {
 			continue
 		}
 
-		filePath := filepath.Join(basePath, entry.Name())
-		content, err := os.ReadFile(filePath)
+		content, err := fs.ReadFile(fsys, entry.Name())
 		if err != nil {
-			return fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+			return fmt.Errorf("failed to read %s in %s: %w", entry.Name(), source, err)
 		}
 
 		// Extract template name (filename without .md)
 		templateName := strings.TrimSuffix(entry.Name(), ".md")
 
 		// Parse template
-		tmpl, err := template.New(templateName).Parse(string(content))
+		tmpl := template.New(templateName).Funcs(funcs)
+		if l.options.Strict {
+			tmpl = tmpl.Option("missingkey=error")
+		}
+		tmpl, err = tmpl.Parse(string(content))
 		if err != nil {
-			return fmt.Errorf("failed to parse template %s: %w", entry.Name(), err)
+			return fmt.Errorf("failed to parse template %s in %s: %w", entry.Name(), source, err)
 		}
 
 		// Later paths override earlier ones (allows customization)
-		l.templates[templateName] = tmpl
+		templates[templateName] = tmpl
 	}
 
 	return nil
@@ -87,7 +151,9 @@ func (l *Loader) loadTemplatesFromPath(basePath string) error {
 
 // Render renders a template with the given data
 func (l *Loader) Render(templateName string, data interface{}) (string, error) {
+	l.mu.RLock()
 	tmpl, ok := l.templates[templateName]
+	l.mu.RUnlock()
 	if !ok {
 		return "", fmt.Errorf("template %s not found", templateName)
 	}
@@ -102,12 +168,16 @@ func (l *Loader) Render(templateName string, data interface{}) (string, error) {
 
 // HasTemplate checks if a template exists
 func (l *Loader) HasTemplate(templateName string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
 	_, ok := l.templates[templateName]
 	return ok
 }
 
 // ListTemplates returns all available template names
 func (l *Loader) ListTemplates() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
 	var names []string
 	for name := range l.templates {
 		names = append(names, name)
@@ -0,0 +1,122 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoader_EmbeddedFallback(t *testing.T) {
+	// No templates on disk: every default comes from the binary
+	loader, err := NewLoader(filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"roaster", "validator", "monitor", "executive-summary"} {
+		if !loader.HasTemplate(name) {
+			t.Errorf("expected embedded template %q, have %v", name, loader.ListTemplates())
+		}
+	}
+	if loader.HasTemplate("README") {
+		t.Error("README should not be loaded as a template")
+	}
+}
+
+func TestLoader_DiskOverridesEmbedded(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "roaster.md"), []byte("Custom roast for {{.Name}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader, err := NewLoader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := loader.Render("roaster", map[string]string{"Name": "acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Custom roast for acme" {
+		t.Errorf("expected the on-disk template, got %q", got)
+	}
+
+	// Templates not on disk still fall back to the embedded ones
+	if !loader.HasTemplate("validator") {
+		t.Errorf("expected the embedded validator template, have %v", loader.ListTemplates())
+	}
+}
+
+func TestLoader_Reload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "greeting.md")
+	if err := os.WriteFile(path, []byte("Hello {{.}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loader, err := NewLoader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Render concurrently with the reloads below
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			loader.Render("greeting", "world")
+		}
+	}()
+
+	if err := os.WriteFile(path, []byte("Hi {{.}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loader.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	<-done
+	if got, _ := loader.Render("greeting", "world"); got != "Hi world" {
+		t.Errorf("expected the edited template after Reload, got %q", got)
+	}
+
+	// A broken edit keeps the previous templates
+	if err := os.WriteFile(path, []byte("Hi {{.}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loader.Reload(); err == nil {
+		t.Fatal("expected Reload to fail on an invalid template")
+	}
+	if got, _ := loader.Render("greeting", "world"); got != "Hi world" {
+		t.Errorf("expected the previous template to be kept, got %q", got)
+	}
+}
+
+func TestLoader_Strict(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "typo.md"), []byte("Fix {{.Titel}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data := map[string]string{"Title": "login"}
+
+	lenient, err := NewMultiPathLoader([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := lenient.Render("typo", data); err != nil || got != "Fix <no value>" {
+		t.Errorf("lenient Render() = %q, %v", got, err)
+	}
+
+	strict, err := NewMultiPathLoaderWithOptions([]string{dir}, LoaderOptions{Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := strict.Render("typo", data); err == nil {
+		t.Errorf("expected strict Render() to fail on a missing key, got %q", got)
+	}
+
+	// Strictness survives a reload
+	if err := strict.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := strict.Render("typo", data); err == nil {
+		t.Error("expected strict Render() to fail after Reload")
+	}
+}
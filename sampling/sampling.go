@@ -0,0 +1,137 @@
+package sampling
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kaskol10/github-project-agent/github"
+)
+
+// Sampling strategies
+const (
+	StrategyAll      = "all"
+	StrategyRecent   = "recent"
+	StrategyRandom   = "random"
+	StrategyPriority = "priority"
+)
+
+// defaultSize is the sample size when a strategy is given without one
+const defaultSize = 100
+
+// priorityRanks orders priority labels from most to least urgent; P0-P3
+// rank the same as the named priorities
+var priorityRanks = map[string]int{
+	"critical": 0,
+	"urgent":   0,
+	"p0":       0,
+	"high":     1,
+	"p1":       1,
+	"medium":   2,
+	"p2":       2,
+	"low":      3,
+	"p3":       3,
+}
+
+// Strategy selects a bounded, representative subset of issues for analysis
+type Strategy struct {
+	Kind string // all, recent, random or priority
+	Size int    // Maximum number of issues to keep
+
+	rand *rand.Rand // Source for random sampling (seeded from time when nil)
+}
+
+// Parse parses a strategy such as "recent:100", "random:50" or "priority".
+// An empty string means no sampling.
+func Parse(s string) (Strategy, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == StrategyAll {
+		return Strategy{Kind: StrategyAll}, nil
+	}
+
+	kind, sizeStr, hasSize := strings.Cut(s, ":")
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	switch kind {
+	case StrategyRecent, StrategyRandom, StrategyPriority:
+	default:
+		return Strategy{}, fmt.Errorf("unknown sampling strategy %q (use recent:N, random:N or priority:N)", kind)
+	}
+
+	size := defaultSize
+	if hasSize {
+		n, err := strconv.Atoi(strings.TrimSpace(sizeStr))
+		if err != nil || n <= 0 {
+			return Strategy{}, fmt.Errorf("invalid sample size %q", sizeStr)
+		}
+		size = n
+	}
+
+	return Strategy{Kind: kind, Size: size}, nil
+}
+
+// Apply returns the sampled issues. The input slice is not modified.
+func (s Strategy) Apply(issues []*github.Issue) []*github.Issue {
+	if s.Kind == "" || s.Kind == StrategyAll || len(issues) <= s.Size {
+		return issues
+	}
+
+	sampled := make([]*github.Issue, len(issues))
+	copy(sampled, issues)
+
+	switch s.Kind {
+	case StrategyRecent:
+		sort.SliceStable(sampled, func(i, j int) bool {
+			return sampled[i].CreatedAt.After(sampled[j].CreatedAt)
+		})
+	case StrategyRandom:
+		r := s.rand
+		if r == nil {
+			r = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+		r.Shuffle(len(sampled), func(i, j int) { sampled[i], sampled[j] = sampled[j], sampled[i] })
+	case StrategyPriority:
+		sort.SliceStable(sampled, func(i, j int) bool {
+			ri, rj := priorityRank(sampled[i]), priorityRank(sampled[j])
+			if ri != rj {
+				return ri < rj
+			}
+			return sampled[i].UpdatedAt.After(sampled[j].UpdatedAt)
+		})
+	}
+
+	return sampled[:s.Size]
+}
+
+// Describe explains how a sample was drawn, for readers of the analysis
+func (s Strategy) Describe(sampled, total int) string {
+	if sampled >= total {
+		return fmt.Sprintf("all %d issues", total)
+	}
+	switch s.Kind {
+	case StrategyRecent:
+		return fmt.Sprintf("the %d most recently created of %d issues", sampled, total)
+	case StrategyRandom:
+		return fmt.Sprintf("a random sample of %d of %d issues", sampled, total)
+	case StrategyPriority:
+		return fmt.Sprintf("the %d highest-priority of %d issues", sampled, total)
+	}
+	return fmt.Sprintf("%d of %d issues", sampled, total)
+}
+
+// priorityRank ranks an issue by its priority label; unlabeled issues sort last
+func priorityRank(issue *github.Issue) int {
+	best := len(priorityRanks)
+	for _, label := range issue.Labels {
+		name := strings.ToLower(label)
+		name = strings.TrimPrefix(name, "priority:")
+		name = strings.TrimPrefix(name, "priority-")
+		name = strings.TrimSpace(name)
+		if rank, ok := priorityRanks[name]; ok && rank < best {
+			best = rank
+		}
+	}
+	return best
+}
@@ -0,0 +1,114 @@
+package sampling
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/kaskol10/github-project-agent/github"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantKind string
+		wantSize int
+		wantErr  bool
+	}{
+		{input: "", wantKind: StrategyAll},
+		{input: "recent:100", wantKind: StrategyRecent, wantSize: 100},
+		{input: "random:50", wantKind: StrategyRandom, wantSize: 50},
+		{input: "priority", wantKind: StrategyPriority, wantSize: defaultSize},
+		{input: "Priority:20", wantKind: StrategyPriority, wantSize: 20},
+		{input: "oldest:10", wantErr: true},
+		{input: "recent:zero", wantErr: true},
+		{input: "recent:0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Kind != tt.wantKind || got.Size != tt.wantSize {
+				t.Errorf("Parse(%q) = %+v, want kind %s size %d", tt.input, got, tt.wantKind, tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	issues := []*github.Issue{
+		{Number: 1, CreatedAt: base, Labels: []string{"priority:low"}},
+		{Number: 2, CreatedAt: base.Add(72 * time.Hour)},
+		{Number: 3, CreatedAt: base.Add(24 * time.Hour), Labels: []string{"priority:critical"}},
+		{Number: 4, CreatedAt: base.Add(48 * time.Hour), Labels: []string{"bug", "priority:high"}},
+	}
+
+	numbers := func(sampled []*github.Issue) []int {
+		var result []int
+		for _, issue := range sampled {
+			result = append(result, issue.Number)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		strategy Strategy
+		want     []int
+	}{
+		{name: "all", strategy: Strategy{Kind: StrategyAll}, want: []int{1, 2, 3, 4}},
+		{name: "recent", strategy: Strategy{Kind: StrategyRecent, Size: 2}, want: []int{2, 4}},
+		{name: "priority", strategy: Strategy{Kind: StrategyPriority, Size: 3}, want: []int{3, 4, 1}},
+		{name: "larger than backlog", strategy: Strategy{Kind: StrategyRecent, Size: 10}, want: []int{1, 2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := numbers(tt.strategy.Apply(issues))
+			if len(got) != len(tt.want) {
+				t.Fatalf("Apply() = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Apply() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	random := Strategy{Kind: StrategyRandom, Size: 2, rand: rand.New(rand.NewSource(1))}
+	if got := random.Apply(issues); len(got) != 2 {
+		t.Errorf("random sample size = %d, want 2", len(got))
+	}
+	if issues[0].Number != 1 || issues[3].Number != 4 {
+		t.Error("Apply() should not reorder the input")
+	}
+}
+
+func TestPriorityRank(t *testing.T) {
+	tests := []struct {
+		labels []string
+		want   int
+	}{
+		{labels: []string{"priority:critical"}, want: 0},
+		{labels: []string{"P0"}, want: 0},
+		{labels: []string{"priority:p1"}, want: 1},
+		{labels: []string{"priority-high"}, want: 1},
+		{labels: []string{"bug", "P2"}, want: 2},
+		{labels: []string{"P3", "priority:high"}, want: 1},
+		{labels: []string{"bug"}, want: len(priorityRanks)},
+	}
+
+	for _, tt := range tests {
+		if got := priorityRank(&github.Issue{Labels: tt.labels}); got != tt.want {
+			t.Errorf("priorityRank(%v) = %d, want %d", tt.labels, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Package store persists small pieces of agent state between runs
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FileStore is a JSON-file backed key/value store.
+// Values are kept in memory and written to disk on Save.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+	data map[string]json.RawMessage
+}
+
+// NewFileStore opens the store at path, loading existing state if the file exists
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{
+		path: path,
+		data: make(map[string]json.RawMessage),
+	}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file %s: %w", path, err)
+	}
+
+	if len(content) > 0 {
+		if err := json.Unmarshal(content, &s.data); err != nil {
+			return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+		}
+	}
+
+	return s, nil
+}
+
+// Get decodes the value stored under key into v.
+// It returns false if the key does not exist.
+func (s *FileStore) Get(key string, v interface{}) (bool, error) {
+	s.mu.Lock()
+	raw, ok := s.data[key]
+	s.mu.Unlock()
+
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return false, fmt.Errorf("failed to decode state for %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// Set stores v under key
+func (s *FileStore) Set(key string, v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode state for %s: %w", key, err)
+	}
+
+	s.mu.Lock()
+	s.data[key] = raw
+	s.mu.Unlock()
+	return nil
+}
+
+// Delete removes key from the store
+func (s *FileStore) Delete(key string) {
+	s.mu.Lock()
+	delete(s.data, key)
+	s.mu.Unlock()
+}
+
+// Keys returns the stored keys starting with prefix, sorted
+func (s *FileStore) Keys(prefix string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+	for key := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Save writes the store to disk atomically
+func (s *FileStore) Save() error {
+	s.mu.Lock()
+	content, err := json.MarshalIndent(s.data, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	if dir := filepath.Dir(s.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create state directory: %w", err)
+		}
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+	return nil
+}
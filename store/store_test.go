@@ -0,0 +1,94 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type snapshot struct {
+	Checked []string `json:"checked"`
+}
+
+func TestFileStore_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := snapshot{Checked: []string{"Install control plane"}}
+	if err := s.Set("checklist:1", want); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("other", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("expected the temporary file to be renamed, got %v", err)
+	}
+
+	reloaded, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got snapshot
+	if found, err := reloaded.Get("checklist:1", &got); err != nil || !found {
+		t.Fatalf("Get() = %v, %v", found, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Get() decoded %+v, want %+v", got, want)
+	}
+	if keys := reloaded.Keys("checklist:"); !reflect.DeepEqual(keys, []string{"checklist:1"}) {
+		t.Errorf("Keys() = %v", keys)
+	}
+}
+
+func TestFileStore_MissingFile(t *testing.T) {
+	s, err := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("a missing file should open an empty store, got %v", err)
+	}
+	var got snapshot
+	if found, err := s.Get("checklist:1", &got); err != nil || found {
+		t.Errorf("Get() = %v, %v, want not found", found, err)
+	}
+}
+
+func TestFileStore_CorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileStore(path); err == nil {
+		t.Error("expected an error for a corrupt state file")
+	}
+}
+
+func TestFileStore_Delete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("checklist:1", snapshot{}); err != nil {
+		t.Fatal(err)
+	}
+	s.Delete("checklist:1")
+	s.Delete("missing") // Deleting an unknown key is a no-op
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got snapshot
+	if found, _ := reloaded.Get("checklist:1", &got); found {
+		t.Error("expected the deleted key to stay deleted after Save")
+	}
+}